@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"flag"
+	"github.com/hobbyfarm/gargantua/v3/pkg/contentarchive"
 	"github.com/hobbyfarm/gargantua/v3/pkg/preinstall"
 	"github.com/hobbyfarm/gargantua/v3/pkg/settingclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/settingserver"
@@ -17,6 +18,8 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacserver"
 	tls2 "github.com/hobbyfarm/gargantua/v3/pkg/tls"
 	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion/progress"
+	seconversion "github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion/scheduledevent"
 	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion/user"
 	"golang.org/x/sync/errgroup"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -35,10 +38,16 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
+	"github.com/hobbyfarm/gargantua/v3/pkg/archiveserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authserver"
+	"github.com/hobbyfarm/gargantua/v3/pkg/certificateserver"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/certificate"
+	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/contentsource"
+	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/learningrecord"
+	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/lti"
 	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/scheduledevent"
 	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/session"
 	"github.com/hobbyfarm/gargantua/v3/pkg/controllers/tfpcontroller"
@@ -47,13 +56,17 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/courseclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/courseserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/environmentserver"
+	"github.com/hobbyfarm/gargantua/v3/pkg/leaderboardserver"
+	"github.com/hobbyfarm/gargantua/v3/pkg/ltiserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/predefinedserviceserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/progressserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/scenarioclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/scenarioserver"
+	"github.com/hobbyfarm/gargantua/v3/pkg/searchserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/sessionserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/shell"
 	"github.com/hobbyfarm/gargantua/v3/pkg/signals"
+	"github.com/hobbyfarm/gargantua/v3/pkg/statusserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/userserver"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	"github.com/hobbyfarm/gargantua/v3/pkg/vmclaimserver"
@@ -113,6 +126,23 @@ func main() {
 		}
 	}
 
+	// export and import work on the cluster directly and exit, everything else starts gargantua
+	if flag.NArg() > 0 {
+		if !contentarchive.IsCommand(flag.Arg(0)) {
+			glog.Fatalf("unknown command %s", flag.Arg(0))
+		}
+
+		hfClient, err := hfClientset.NewForConfig(cfg)
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		if err = contentarchive.RunCommand(ctx, hfClient, flag.Args(), os.Stdin, os.Stdout); err != nil {
+			glog.Fatal(err)
+		}
+		return
+	}
+
 	namespace := util.GetReleaseNamespace()
 
 	var ca string
@@ -234,7 +264,7 @@ func main() {
 		glog.Fatal(err)
 	}
 
-	shellProxy, err := shell.NewShellProxy(authClient, vmClient, hfClient, kubeClient, ctx)
+	shellProxy, err := shell.NewShellProxy(authClient, vmClient, hfClient, hfInformerFactory, kubeClient, ctx)
 	if err != nil {
 		glog.Fatal(err)
 	}
@@ -264,7 +294,7 @@ func main() {
 		glog.Fatal(err)
 	}
 
-	progressServer, err := progressserver.NewProgressServer(authClient, hfClient, ctx)
+	progressServer, err := progressserver.NewProgressServer(authClient, hfClient, kubeClient, hfInformerFactory, ctx)
 	if err != nil {
 		glog.Fatal(err)
 	}
@@ -274,6 +304,36 @@ func main() {
 		glog.Fatal(err)
 	}
 
+	statusServer, err := statusserver.NewStatusServer(authClient, hfInformerFactory)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	archiveServer, err := archiveserver.NewArchiveServer(authClient, hfClient, ctx)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	searchServer, err := searchserver.NewSearchServer(authClient, acClient, hfInformerFactory)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	certificateServer, err := certificateserver.NewCertificateServer(authClient, hfClient, kubeClient, ctx)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	ltiServer, err := ltiserver.NewLTIServer(hfClient, hfInformerFactory, kubeClient, authServer, acClient, rbacClient, ctx)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	leaderboardServer, err := leaderboardserver.NewLeaderboardServer(authClient, hfClient, hfInformerFactory, ctx)
+	if err != nil {
+		glog.Fatal(err)
+	}
+
 	settingclient.WatchSettings(ctx, hfClient, hfInformerFactory)
 
 	if shellServer {
@@ -296,6 +356,12 @@ func main() {
 		rbacServer.SetupRoutes(r)
 		predefinedServiceServer.SetupRoutes(r)
 		settingServer.SetupRoutes(r)
+		statusServer.SetupRoutes(r)
+		archiveServer.SetupRoutes(r)
+		searchServer.SetupRoutes(r)
+		certificateServer.SetupRoutes(r)
+		ltiServer.SetupRoutes(r)
+		leaderboardServer.SetupRoutes(r)
 	}
 
 	corsHeaders := handlers.AllowedHeaders([]string{"Authorization", "Content-Type"})
@@ -324,6 +390,8 @@ func main() {
 	// shell server does not serve webhook endpoint, so don't start it
 	if !shellServer {
 		user.Init()
+		progress.Init()
+		seconversion.Init()
 		webhookRouter := mux.NewRouter()
 		conversion.New(webhookRouter, apiExtensionsClient, string(ca))
 
@@ -445,6 +513,22 @@ func bootStrapControllers(kubeClient *kubernetes.Clientset, hfClient *hfClientse
 	if err != nil {
 		return err
 	}
+	contentSourceController, err := contentsource.NewContentSourceController(hfClient, hfInformerFactory, gctx)
+	if err != nil {
+		return err
+	}
+	certificateController, err := certificate.NewCertificateController(kubeClient, hfClient, hfInformerFactory, gctx)
+	if err != nil {
+		return err
+	}
+	learningRecordController, err := learningrecord.NewLearningRecordController(kubeClient, hfClient, hfInformerFactory, gctx)
+	if err != nil {
+		return err
+	}
+	ltiController, err := lti.NewLTIController(kubeClient, hfClient, hfInformerFactory, gctx)
+	if err != nil {
+		return err
+	}
 
 	g.Go(func() error {
 		return sessionController.Run(stopCh)
@@ -466,6 +550,22 @@ func bootStrapControllers(kubeClient *kubernetes.Clientset, hfClient *hfClientse
 		return vmSetController.Run(stopCh)
 	})
 
+	g.Go(func() error {
+		return contentSourceController.Run(stopCh)
+	})
+
+	g.Go(func() error {
+		return certificateController.Run(stopCh)
+	})
+
+	g.Go(func() error {
+		return learningRecordController.Run(stopCh)
+	})
+
+	g.Go(func() error {
+		return ltiController.Run(stopCh)
+	})
+
 	g.Go(func() error {
 		return rbacControllerFactory.Start(ctx, 1)
 	})
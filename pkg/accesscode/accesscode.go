@@ -48,7 +48,7 @@ func (acc AccessCodeClient) GetAccessCodesWithOTACs(codes []string) ([]hfv1.Acce
 
 	//Append the value of onetime access codes to the list
 	for _, otac := range otacList.Items {
-		se, err := acc.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(acc.ctx, otac.Labels[util.ScheduledEventLabel], metav1.GetOptions{})
+		se, err := acc.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(acc.ctx, otac.Labels[util.ScheduledEventLabel], metav1.GetOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("error while retrieving one time access codes %v", err)
 		}
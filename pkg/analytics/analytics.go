@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+)
+
+// DefaultIdleAfter is the time without progress after which a learner counts as idle
+const DefaultIdleAfter = 10 * time.Minute
+
+// Report aggregates the progress of learners
+type Report struct {
+	Learners       int                `json:"learners"`
+	Progress       int                `json:"progress"`
+	Active         int                `json:"active"`    // learners of unfinished progress that moved recently
+	Idle           int                `json:"idle"`      // learners of unfinished progress that did not move for a while
+	Completed      int                `json:"completed"` // progress that reached the last step
+	CompletionRate float64            `json:"completion_rate"`
+	Scenarios      []ScenarioAnalytic `json:"scenarios"`
+}
+
+type ScenarioAnalytic struct {
+	Scenario       string         `json:"scenario"`
+	Progress       int            `json:"progress"`
+	Completed      int            `json:"completed"`
+	CompletionRate float64        `json:"completion_rate"`
+	Steps          []StepAnalytic `json:"steps"`
+}
+
+// StepAnalytic describes a step of a scenario, durations are in seconds
+type StepAnalytic struct {
+	Step       int     `json:"step"`
+	Samples    int     `json:"samples"` // number of progress the durations are computed from
+	Median     float64 `json:"median"`
+	P90        float64 `json:"p90"`
+	Reached    int     `json:"reached"`     // progress that got at least to this step
+	DroppedOff int     `json:"dropped_off"` // finished progress that did not get past this step
+}
+
+// Filter selects the progress an analysis is computed from
+type Filter struct {
+	From time.Time // progress started before is left out, ignored if zero
+	To   time.Time // progress started after is left out, ignored if zero
+}
+
+func (f Filter) Matches(p *hfv2.Progress) bool {
+	if f.From.IsZero() && f.To.IsZero() {
+		return true
+	}
+	started := p.Spec.Started.Time
+	if started.IsZero() {
+		return false
+	}
+	if !f.From.IsZero() && started.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && started.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// Compute aggregates progress. Learners that did not update their progress within idleAfter count as idle.
+func Compute(progress []*hfv2.Progress, now time.Time, idleAfter time.Duration) Report {
+	report := Report{Scenarios: []ScenarioAnalytic{}}
+
+	learners := map[string]bool{}
+	active := map[string]bool{}
+	idle := map[string]bool{}
+	byScenario := map[string][]*hfv2.Progress{}
+	for _, p := range progress {
+		report.Progress++
+		learners[p.Spec.UserId] = true
+		if completion.ScenarioCompleted(p.Spec) {
+			report.Completed++
+		}
+		if !p.Spec.Finished {
+			if !p.Spec.LastUpdate.IsZero() && now.Sub(p.Spec.LastUpdate.Time) <= idleAfter {
+				active[p.Spec.UserId] = true
+			} else {
+				idle[p.Spec.UserId] = true
+			}
+		}
+		byScenario[p.Spec.Scenario] = append(byScenario[p.Spec.Scenario], p)
+	}
+
+	// a learner with any recent progress is active
+	for user := range active {
+		delete(idle, user)
+	}
+
+	report.Learners = len(learners)
+	report.Active = len(active)
+	report.Idle = len(idle)
+	report.CompletionRate = rate(report.Completed, report.Progress)
+
+	for scenario, scenarioProgress := range byScenario {
+		report.Scenarios = append(report.Scenarios, scenarioAnalytic(scenario, scenarioProgress))
+	}
+	sort.Slice(report.Scenarios, func(i, j int) bool {
+		return report.Scenarios[i].Scenario < report.Scenarios[j].Scenario
+	})
+
+	return report
+}
+
+func scenarioAnalytic(scenario string, progress []*hfv2.Progress) ScenarioAnalytic {
+	analytic := ScenarioAnalytic{Scenario: scenario, Progress: len(progress)}
+
+	totalSteps := 0
+	for _, p := range progress {
+		totalSteps = max(totalSteps, p.Spec.TotalStep)
+	}
+
+	durations := make([][]float64, totalSteps)
+	reached := make([]int, totalSteps)
+	droppedOff := make([]int, totalSteps)
+	for _, p := range progress {
+		completed := completion.ScenarioCompleted(p.Spec)
+		if completed {
+			analytic.Completed++
+		}
+
+		for step := 0; step <= p.Spec.MaxStep && step < totalSteps; step++ {
+			reached[step]++
+		}
+		if p.Spec.Finished && !completed && p.Spec.MaxStep < totalSteps {
+			droppedOff[p.Spec.MaxStep]++
+		}
+
+		for _, s := range p.Spec.Steps {
+			if s.Step >= 0 && s.Step < totalSteps && s.TimeSpent.Duration > 0 {
+				durations[s.Step] = append(durations[s.Step], s.TimeSpent.Seconds())
+			}
+		}
+	}
+
+	analytic.CompletionRate = rate(analytic.Completed, analytic.Progress)
+	analytic.Steps = make([]StepAnalytic, totalSteps)
+	for step := range analytic.Steps {
+		sort.Float64s(durations[step])
+		analytic.Steps[step] = StepAnalytic{
+			Step:       step,
+			Samples:    len(durations[step]),
+			Median:     percentile(durations[step], 0.5),
+			P90:        percentile(durations[step], 0.9),
+			Reached:    reached[step],
+			DroppedOff: droppedOff[step],
+		}
+	}
+
+	return analytic
+}
+
+// percentile uses the nearest rank of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
+
+func rate(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
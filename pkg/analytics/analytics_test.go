@@ -0,0 +1,105 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Compute(t *testing.T) {
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-time.Hour)
+
+	// walk moves through the steps of a scenario, one step at each of the given minutes after start
+	walk := func(user string, scenario string, totalSteps int, minutes ...int) *hfv2.Progress {
+		p := &hfv2.Progress{Spec: hfv2.ProgressSpec{UserId: user, Scenario: scenario, TotalStep: totalSteps}}
+		for step, minute := range minutes {
+			p.Spec.Visit(step, start.Add(time.Duration(minute)*time.Minute))
+		}
+		return p
+	}
+
+	completed := walk("u1", "sc-a", 3, 0, 5, 20)
+	completed.Spec.Finished = true
+	droppedOff := walk("u2", "sc-a", 3, 0, 2)
+	droppedOff.Spec.Finished = true
+	stale := walk("u3", "sc-a", 3, 30)
+	recent := walk("u3", "sc-b", 2, 59)
+	neverMoved := &hfv2.Progress{Spec: hfv2.ProgressSpec{UserId: "u4", Scenario: "sc-a", TotalStep: 3}}
+
+	report := Compute([]*hfv2.Progress{completed, droppedOff, stale, recent, neverMoved}, now, DefaultIdleAfter)
+
+	if report.Learners != 4 || report.Progress != 5 || report.Completed != 1 || report.CompletionRate != 0.2 {
+		t.Errorf("report = %+v", report)
+	}
+	// u3 is active in sc-b even though its progress in sc-a is stale
+	if report.Active != 1 || report.Idle != 1 {
+		t.Errorf("active, idle = %d, %d, want 1, 1", report.Active, report.Idle)
+	}
+
+	if len(report.Scenarios) != 2 || report.Scenarios[0].Scenario != "sc-a" || report.Scenarios[1].Scenario != "sc-b" {
+		t.Fatalf("Scenarios = %+v", report.Scenarios)
+	}
+	a := report.Scenarios[0]
+	if a.Progress != 4 || a.Completed != 1 || a.CompletionRate != 0.25 {
+		t.Errorf("sc-a = %+v", a)
+	}
+	wantSteps := []StepAnalytic{
+		{Step: 0, Samples: 2, Median: 120, P90: 300, Reached: 4},
+		{Step: 1, Samples: 1, Median: 900, P90: 900, Reached: 2, DroppedOff: 1},
+		{Step: 2, Reached: 1}, // the last step is still open
+	}
+	if !reflect.DeepEqual(a.Steps, wantSteps) {
+		t.Errorf("sc-a steps = %+v, want %+v", a.Steps, wantSteps)
+	}
+	if b := report.Scenarios[1]; len(b.Steps) != 2 || b.Steps[0].Reached != 1 || b.Steps[1].Reached != 0 || b.CompletionRate != 0 {
+		t.Errorf("sc-b = %+v", b)
+	}
+
+	empty := Compute(nil, now, DefaultIdleAfter)
+	if empty.CompletionRate != 0 || empty.Scenarios == nil {
+		t.Errorf("Compute() without progress = %+v", empty)
+	}
+}
+
+func Test_Percentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, want := range map[float64]float64{0: 1, 0.5: 5, 0.9: 9, 0.95: 10, 1: 10} {
+		if got := percentile(values, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("percentile() of no values = %v", got)
+	}
+}
+
+func Test_FilterMatches(t *testing.T) {
+	june := func(day int) time.Time { return time.Date(2026, time.June, day, 0, 0, 0, 0, time.UTC) }
+	started := &hfv2.Progress{Spec: hfv2.ProgressSpec{Started: metav1.NewTime(june(10))}}
+	notStarted := &hfv2.Progress{}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		progress *hfv2.Progress
+		want     bool
+	}{
+		{"no filter", Filter{}, notStarted, true},
+		{"within", Filter{From: june(1), To: june(30)}, started, true},
+		{"on the boundaries", Filter{From: june(10), To: june(10)}, started, true},
+		{"before", Filter{From: june(11)}, started, false},
+		{"after", Filter{To: june(9)}, started, false},
+		{"not started", Filter{From: june(1)}, notStarted, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.progress); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -41,6 +41,20 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CourseList{},
 		&Scenario{},
 		&ScenarioList{},
+		&ScenarioRevision{},
+		&ScenarioRevisionList{},
+		&CourseRevision{},
+		&CourseRevisionList{},
+		&ContentSource{},
+		&ContentSourceList{},
+		&Certificate{},
+		&CertificateList{},
+		&LearningRecord{},
+		&LearningRecordList{},
+		&LTIPlatform{},
+		&LTIPlatformList{},
+		&LTILaunch{},
+		&LTILaunchList{},
 		&Session{},
 		&SessionList{},
 		&AccessCode{},
@@ -49,6 +63,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&UserList{},
 		&ScheduledEvent{},
 		&ScheduledEventList{},
+		&ScheduledEventSeries{},
+		&ScheduledEventSeriesList{},
 		&DynamicBindConfiguration{},
 		&DynamicBindConfigurationList{},
 		&Progress{},
@@ -87,7 +87,10 @@ type VirtualMachineClaimStatus struct {
 	StaticBindAttempts int    `json:"static_bind_attempts"`
 	Bound              bool   `json:"bound"`
 	Ready              bool   `json:"ready"`
-	Tainted            bool   `json:"tainted"` // If tainted, we should delete the VM's underneath then delete ourself...
+	Tainted            bool   `json:"tainted"`                  // If tainted, we should delete the VM's underneath then delete ourself...
+	Queued             bool   `json:"queued"`                   // waiting for capacity in the queue of its scheduled event
+	QueuePosition      int    `json:"queue_position,omitempty"` // 1 is the next claim to be served
+	QueuedTime         string `json:"queued_time,omitempty"`
 }
 
 type VirtualMachineClaimVM struct {
@@ -179,6 +182,7 @@ type EnvironmentSpec struct {
 	IPTranslationMap     map[string]string            `json:"ip_translation_map"`
 	WsEndpoint           string                       `json:"ws_endpoint"`
 	CountCapacity        map[string]int               `json:"count_capacity"`
+	Prewarm              *PrewarmPolicy               `json:"prewarm,omitempty"` // default for scheduled events that do not configure their own
 }
 
 // +genclient
@@ -239,15 +243,214 @@ type CourseList struct {
 }
 
 type CourseSpec struct {
-	Name              string              `json:"name"`
-	Description       string              `json:"description"`
-	Scenarios         []string            `json:"scenarios"`
-	Categories        []string            `json:"categories"`
-	VirtualMachines   []map[string]string `json:"virtualmachines"`
-	KeepAliveDuration string              `json:"keepalive_duration"`
-	PauseDuration     string              `json:"pause_duration"`
-	Pauseable         bool                `json:"pauseable"`
-	KeepVM            bool                `json:"keep_vm"`
+	Name              string                       `json:"name"`
+	Description       string                       `json:"description"`
+	Scenarios         []string                     `json:"scenarios"`
+	Categories        []string                     `json:"categories"`
+	VirtualMachines   []map[string]string          `json:"virtualmachines"`
+	KeepAliveDuration string                       `json:"keepalive_duration"`
+	PauseDuration     string                       `json:"pause_duration"`
+	Pauseable         bool                         `json:"pauseable"`
+	KeepVM            bool                         `json:"keep_vm"`
+	SessionPolicy     SessionPolicy                `json:"session_policy"`
+	State             ContentState                 `json:"state,omitempty"`    // draft courses are hidden from learners
+	Revision          int                          `json:"revision,omitempty"` // latest CourseRevision
+	Sequence          CourseSequence               `json:"sequence,omitempty"`
+	Prerequisites     map[string][]string          `json:"prerequisites,omitempty"` // scenario to the scenarios that have to be completed before it, used by the prerequisites sequence
+	Certificate       bool                         `json:"certificate,omitempty"`   // issue a certificate to learners who complete every scenario
+	Locale            string                       `json:"locale,omitempty"`        // locale of the name and description, e.g. en
+	Translations      map[string]CourseTranslation `json:"translations,omitempty"`  // keyed by locale, fields left empty fall back to the default locale
+}
+
+// CourseTranslation holds the translated texts of a course, encoded like the originals
+type CourseTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CourseSequence decides in which order the scenarios of a course may be started
+type CourseSequence string
+
+const (
+	CourseSequenceAny           CourseSequence = ""              // any order
+	CourseSequenceStrict        CourseSequence = "strict"        // every scenario requires the ones listed before it
+	CourseSequencePrerequisites CourseSequence = "prerequisites" // scenarios require the ones listed in Prerequisites
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Certificate is issued to a user who completed a course, it is signed so that it can be verified without an account
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CertificateSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Certificate `json:"items"`
+}
+
+type CertificateSpec struct {
+	UserId     string   `json:"user"`
+	Holder     string   `json:"holder"` // email of the user when the certificate was issued
+	Course     string   `json:"course"`
+	CourseName string   `json:"course_name"`
+	Scenarios  []string `json:"scenarios"`
+	Score      int      `json:"score"`
+	MaxScore   int      `json:"max_score"`
+	Issued     string   `json:"issued"`
+	KeyId      string   `json:"key_id"`
+	Signature  string   `json:"signature"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LearningRecord is an xAPI statement waiting in the outbox until the learning record store accepted it
+type LearningRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              LearningRecordSpec   `json:"spec"`
+	Status            LearningRecordStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type LearningRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []LearningRecord `json:"items"`
+}
+
+type LearningRecordSpec struct {
+	Verb           string `json:"verb"` // launched, progressed, completed or terminated
+	UserId         string `json:"user"`
+	Progress       string `json:"progress"`
+	Session        string `json:"session"`
+	ScheduledEvent string `json:"scheduled_event"`
+	Scenario       string `json:"scenario"`
+	Course         string `json:"course"`
+	Step           int    `json:"step"` // counted from 1
+	TotalSteps     int    `json:"total_steps"`
+	Score          int    `json:"score"`
+	MaxScore       int    `json:"max_score"`
+	Duration       string `json:"duration"` // time spent in the session, only set when terminated
+	Timestamp      string `json:"timestamp"`
+}
+
+type LearningRecordStatus struct {
+	Attempts    int    `json:"attempts"`
+	LastAttempt string `json:"last_attempt"`
+	Error       string `json:"error"`
+	Rejected    bool   `json:"rejected"` // the store refused the statement, it is not sent again
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LTIPlatform is a learning management system registered to launch scenarios and courses through LTI 1.3
+type LTIPlatform struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              LTIPlatformSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type LTIPlatformList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []LTIPlatform `json:"items"`
+}
+
+type LTIPlatformSpec struct {
+	Name              string            `json:"name"`
+	Issuer            string            `json:"issuer"`
+	ClientId          string            `json:"client_id"`
+	DeploymentIds     []string          `json:"deployment_ids"` // launches of other deployments are refused, any deployment is accepted if empty
+	AuthLoginURL      string            `json:"auth_login_url"` // oidc authorization endpoint of the platform
+	AuthTokenURL      string            `json:"auth_token_url"` // oauth2 token endpoint used for the assignment and grade services
+	KeySetURL         string            `json:"key_set_url"`
+	AccessCodes       map[string]string `json:"access_codes"` // access code granted for an lti context id
+	DefaultAccessCode string            `json:"default_access_code"`
+	LinkUsersByEmail  bool              `json:"link_users_by_email"` // launches sign in to an existing account with the email asserted by the platform
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LTILaunch links a user provisioned through LTI to the resource link it was launched from,
+// grades for the resource link are sent back to the platform
+type LTILaunch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              LTILaunchSpec   `json:"spec"`
+	Status            LTILaunchStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type LTILaunchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []LTILaunch `json:"items"`
+}
+
+type LTILaunchSpec struct {
+	Platform     string `json:"platform"`
+	UserId       string `json:"user"`
+	Subject      string `json:"subject"` // id of the user on the platform
+	Context      string `json:"context"`
+	ResourceLink string `json:"resource_link"`
+	AccessCode   string `json:"access_code"`
+	Scenario     string `json:"scenario"`
+	Course       string `json:"course"`
+	LineItem     string `json:"line_item"` // empty if the platform does not accept grades for the resource link
+	LastLaunch   string `json:"last_launch"`
+	// nonces of the id tokens launched with and when they were used, an id token can only be used once
+	Nonces map[string]string `json:"nonces,omitempty"`
+}
+
+type LTILaunchStatus struct {
+	ScoreGiven   int    `json:"score_given"`
+	ScoreMaximum int    `json:"score_maximum"`
+	Completed    bool   `json:"completed"`
+	LastSync     string `json:"last_sync"`
+	Error        string `json:"error"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CourseRevision is an immutable snapshot of a course, taken whenever the course is changed
+type CourseRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CourseRevisionSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CourseRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CourseRevision `json:"items"`
+}
+
+type CourseRevisionSpec struct {
+	Course   string     `json:"course"`
+	Revision int        `json:"revision"`
+	Creator  string     `json:"creator"`
+	Created  string     `json:"created"`
+	Snapshot CourseSpec `json:"snapshot"`
 }
 
 // +genclient
@@ -269,20 +472,186 @@ type ScenarioList struct {
 }
 
 type ScenarioSpec struct {
-	Name              string              `json:"name"`
-	Description       string              `json:"description"`
-	Steps             []ScenarioStep      `json:"steps"`
-	Categories        []string            `json:"categories"`
-	Tags              []string            `json:"tags"`
-	VirtualMachines   []map[string]string `json:"virtualmachines"`
-	KeepAliveDuration string              `json:"keepalive_duration"`
-	PauseDuration     string              `json:"pause_duration"`
-	Pauseable         bool                `json:"pauseable"`
+	Name              string                         `json:"name"`
+	Description       string                         `json:"description"`
+	Steps             []ScenarioStep                 `json:"steps"`
+	Categories        []string                       `json:"categories"`
+	Tags              []string                       `json:"tags"`
+	VirtualMachines   []map[string]string            `json:"virtualmachines"`
+	KeepAliveDuration string                         `json:"keepalive_duration"`
+	PauseDuration     string                         `json:"pause_duration"`
+	Pauseable         bool                           `json:"pauseable"`
+	SessionPolicy     SessionPolicy                  `json:"session_policy"`
+	Templated         bool                           `json:"templated,omitempty"`    // step content is rendered with the variables of the session
+	State             ContentState                   `json:"state,omitempty"`        // draft scenarios are hidden from learners
+	Revision          int                            `json:"revision,omitempty"`     // latest ScenarioRevision
+	Locale            string                         `json:"locale,omitempty"`       // locale of the name, description and steps, e.g. en
+	Translations      map[string]ScenarioTranslation `json:"translations,omitempty"` // keyed by locale, fields left empty fall back to the default locale
+}
+
+// ScenarioTranslation holds the translated texts of a scenario, encoded like the originals
+type ScenarioTranslation struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Steps       []StepTranslation `json:"steps,omitempty"` // in the order of the scenario's steps
+}
+
+type StepTranslation struct {
+	Title     string                `json:"title,omitempty"`
+	Content   string                `json:"content,omitempty"`
+	Questions []QuestionTranslation `json:"questions,omitempty"`
+}
+
+// QuestionTranslation translates what learners see of a question, choices keep their order so grading is not affected
+type QuestionTranslation struct {
+	Text    string   `json:"text,omitempty"`
+	Choices []string `json:"choices,omitempty"`
+}
+
+type ContentState string
+
+const (
+	ContentStateDraft     ContentState = "draft"
+	ContentStatePublished ContentState = "published" // an empty state is treated as published
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScenarioRevision is an immutable snapshot of a scenario, taken whenever the scenario is changed
+type ScenarioRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ScenarioRevisionSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ScenarioRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ScenarioRevision `json:"items"`
+}
+
+type ScenarioRevisionSpec struct {
+	Scenario string       `json:"scenario"`
+	Revision int          `json:"revision"`
+	Creator  string       `json:"creator"`
+	Created  string       `json:"created"`
+	Snapshot ScenarioSpec `json:"snapshot"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContentSource keeps scenarios and courses in sync with a git repository
+type ContentSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ContentSourceSpec   `json:"spec"`
+	Status            ContentSourceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ContentSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ContentSource `json:"items"`
+}
+
+type ContentSourceSpec struct {
+	URL      string `json:"url"`                // anything git can clone, including local paths and file:// urls
+	Ref      string `json:"ref,omitempty"`      // branch or tag, the default branch of the repository if empty
+	Path     string `json:"path,omitempty"`     // directory inside the repository holding the content
+	Interval string `json:"interval,omitempty"` // time between pulls, 5m if empty
+}
+
+type ContentSourceStatus struct {
+	ObservedGeneration int64    `json:"observed_generation,omitempty"`
+	LastSyncTime       string   `json:"last_sync_time,omitempty"`
+	Commit             string   `json:"commit,omitempty"`
+	Synced             bool     `json:"synced"`
+	Errors             []string `json:"errors,omitempty"`
+	Scenarios          []string `json:"scenarios,omitempty"` // scenarios created from the source
+	Courses            []string `json:"courses,omitempty"`   // courses created from the source
+}
+
+// SessionPolicy limits how far a learner can extend a session. A zero value in any field
+// means no limit. Courses, scenarios and scheduled events can each carry a policy, the
+// strictest non-zero value of all policies applying to a session wins.
+type SessionPolicy struct {
+	MaxSessionDuration string `json:"max_session_duration,omitempty"` // maximum lifetime of a session, counted from its start
+	MaxKeepAliveCount  int    `json:"max_keepalive_count,omitempty"`
+	MaxPauseCount      int    `json:"max_pause_count,omitempty"`
+	MaxPauseDuration   string `json:"max_pause_duration,omitempty"` // total time a session may spend paused
+}
+
+// LeaderboardPolicy scores the progress of learners in a scheduled event. If no points are configured
+// the default scoring of the leaderboard package is used.
+type LeaderboardPolicy struct {
+	PointsPerStep     int    `json:"points_per_step,omitempty"`     // for every step reached
+	PointsPerCheck    int    `json:"points_per_check,omitempty"`    // for every step check passed
+	QuizMultiplier    int    `json:"quiz_multiplier,omitempty"`     // quiz points are multiplied by this
+	PointsPerScenario int    `json:"points_per_scenario,omitempty"` // for every scenario completed
+	TimeBonus         int    `json:"time_bonus,omitempty"`          // for completing a scenario right at the start of the event, shrinks linearly
+	TimeBonusWindow   string `json:"time_bonus_window,omitempty"`   // time after the start of the event at which the time bonus reaches zero
+	Token             string `json:"token,omitempty"`               // grants read-only access to the leaderboard without logging in
+}
+
+// PrewarmPolicy provisions the virtual machines of a scheduled event before it starts. They are requested in
+// batches, so that large events do not run into rate limits of the provider.
+type PrewarmPolicy struct {
+	LeadTime      string `json:"lead_time,omitempty"`      // how long before the start of the event provisioning begins
+	BatchSize     int    `json:"batch_size,omitempty"`     // virtual machines requested per environment and batch, 0 requests all at once
+	BatchInterval string `json:"batch_interval,omitempty"` // time between two batches, defaults to a minute
+}
+
+// ScheduledEventReadiness counts the virtual machines of a scheduled event while they are provisioned
+type ScheduledEventReadiness struct {
+	Ready         int         `json:"ready"`     // running virtual machines
+	Requested     int         `json:"requested"` // virtual machines requested from the virtual machine sets so far
+	Total         int         `json:"total"`     // virtual machines the event gets once all batches were requested
+	LastBatchTime metav1.Time `json:"last_batch_time,omitempty"`
 }
 
 type ScenarioStep struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title     string         `json:"title"`
+	Content   string         `json:"content"`
+	Checks    []StepCheck    `json:"checks,omitempty"`
+	Gated     bool           `json:"gated,omitempty"` // learners may only move past this step once all checks passed and all questions were answered
+	Questions []StepQuestion `json:"questions,omitempty"`
+}
+
+// StepCheck verifies a step by running a command on one of the session's vms over ssh
+type StepCheck struct {
+	Name             string `json:"name,omitempty"`
+	VirtualMachine   string `json:"vm_name"` // name of the vm in the scenario's VirtualMachines
+	Command          string `json:"command"`
+	ExpectedExitCode int    `json:"expected_exit_code"`
+	ExpectedOutput   string `json:"expected_output,omitempty"` // regular expression the output has to match
+	Timeout          string `json:"timeout,omitempty"`
+}
+
+type QuestionType string
+
+const (
+	QuestionTypeSingleChoice   QuestionType = "single_choice"
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeFreeText       QuestionType = "free_text"
+)
+
+// StepQuestion is graded by the server, correct answers are never sent to learners
+type StepQuestion struct {
+	Name           string       `json:"name,omitempty"`
+	Type           QuestionType `json:"type"`
+	Text           string       `json:"text"`
+	Choices        []string     `json:"choices,omitempty"`
+	CorrectChoices []int        `json:"correct_choices,omitempty"` // indices into Choices
+	CorrectAnswers []string     `json:"correct_answers,omitempty"` // accepted free text answers, compared ignoring case and surrounding whitespace
+	Points         int          `json:"points"`
+	MaxAttempts    int          `json:"max_attempts,omitempty"` // 0 allows unlimited attempts
 }
 
 // +genclient
@@ -310,15 +679,25 @@ type SessionSpec struct {
 	UserId       string   `json:"user"`
 	VmClaimSet   []string `json:"vm_claim"`
 	AccessCode   string   `json:"access_code"`
+	Members      []string `json:"members,omitempty"`     // users sharing the session with its owner
+	InviteCode   string   `json:"invite_code,omitempty"` // lets other users join the session as members
+	// revisions of the scenario and course the session was started with, 0 for sessions older than revisions
+	ScenarioRevision int `json:"scenario_revision,omitempty"`
+	CourseRevision   int `json:"course_revision,omitempty"`
 }
 
 type SessionStatus struct {
-	Paused         bool   `json:"paused"`
-	PausedTime     string `json:"paused_time"`
-	Active         bool   `json:"active"`
-	Finished       bool   `json:"finished"`
-	StartTime      string `json:"start_time"`
-	ExpirationTime string `json:"end_time"`
+	Paused             bool   `json:"paused"`
+	PausedTime         string `json:"paused_time"`
+	Active             bool   `json:"active"`
+	Finished           bool   `json:"finished"`
+	StartTime          string `json:"start_time"`
+	ExpirationTime     string `json:"end_time"`
+	KeepAliveCount     int    `json:"keepalive_count"`
+	PauseCount         int    `json:"pause_count"`
+	PauseStartTime     string `json:"pause_start_time"`     // start of the current pause, empty if not paused
+	TotalPauseDuration string `json:"total_pause_duration"` // time spent paused so far, excluding the current pause
+	ExtendedDuration   string `json:"extended_duration"`    // extra time granted by an admin on top of max_session_duration
 }
 
 // +genclient
@@ -340,16 +719,43 @@ type ProgressList struct {
 }
 
 type ProgressSpec struct {
-	CurrentStep int            `json:"current_step"`
-	MaxStep     int            `json:"max_step"`
-	TotalStep   int            `json:"total_step"`
-	Course      string         `json:"course"`
-	Scenario    string         `json:"scenario"`
-	UserId      string         `json:"user"`
-	Started     string         `json:"started"`
-	LastUpdate  string         `json:"last_update"`
-	Finished    string         `json:"finished"`
-	Steps       []ProgressStep `json:"steps"`
+	CurrentStep     int               `json:"current_step"`
+	MaxStep         int               `json:"max_step"`
+	TotalStep       int               `json:"total_step"`
+	Course          string            `json:"course"`
+	Scenario        string            `json:"scenario"`
+	UserId          string            `json:"user"`
+	Started         string            `json:"started"`
+	LastUpdate      string            `json:"last_update"`
+	Finished        string            `json:"finished"`
+	Steps           []ProgressStep    `json:"steps"`
+	CheckResults    []StepCheckResult `json:"check_results,omitempty"` // latest result of every check that was run
+	QuestionResults []QuestionResult  `json:"question_results,omitempty"`
+	Score           int               `json:"score"`
+	MaxScore        int               `json:"max_score"`
+}
+
+// QuestionResult holds the latest graded answer to a question
+type QuestionResult struct {
+	Step      int      `json:"step"`
+	Question  int      `json:"question"`
+	Name      string   `json:"name,omitempty"`
+	Answer    []string `json:"answer"`
+	Correct   bool     `json:"correct"`
+	Points    int      `json:"points"`
+	Attempts  int      `json:"attempts"`
+	Timestamp string   `json:"timestamp"`
+}
+
+type StepCheckResult struct {
+	Step      int    `json:"step"`
+	Check     int    `json:"check"`
+	Name      string `json:"name,omitempty"`
+	Passed    bool   `json:"passed"`
+	ExitCode  int    `json:"exit_code"`
+	Output    string `json:"output,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp"`
 }
 
 type ProgressStep struct {
@@ -473,14 +879,57 @@ type ScheduledEventSpec struct {
 	Printable               bool                      `json:"printable"`
 	Scenarios               []string                  `json:"scenarios"`
 	Courses                 []string                  `json:"courses"`
+	SessionPolicy           SessionPolicy             `json:"session_policy"`
+	Leaderboard             *LeaderboardPolicy        `json:"leaderboard,omitempty"` // nil if the event has no leaderboard
+	Prewarm                 *PrewarmPolicy            `json:"prewarm,omitempty"`     // nil uses the policies of the environments
 }
 
 type ScheduledEventStatus struct {
-	VirtualMachineSets []string `json:"vmsets"`
-	Active             bool     `json:"active"`
-	Provisioned        bool     `json:"provisioned"`
-	Ready              bool     `json:"ready"`
-	Finished           bool     `json:"finished"`
+	VirtualMachineSets []string                  `json:"vmsets"`
+	Active             bool                      `json:"active"`
+	Provisioned        bool                      `json:"provisioned"`
+	Ready              bool                      `json:"ready"`
+	Finished           bool                      `json:"finished"`
+	ScenarioRevisions  map[string]int            `json:"scenario_revisions,omitempty"` // revisions pinned when the event was provisioned
+	CourseRevisions    map[string]int            `json:"course_revisions,omitempty"`
+	CapacityShortfall  map[string]map[string]int `json:"capacity_shortfall,omitempty"` // environment: vm template: count of virtual machines that did not fit
+	Readiness          *ScheduledEventReadiness  `json:"readiness,omitempty"`
+}
+
+// +genclient
+// +resourceName=scheduledeventseries
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScheduledEventSeries creates a scheduled event for every occurrence of a recurrence rule, some time before it starts.
+// Occurrences that were edited on their own are no longer updated when the series changes.
+type ScheduledEventSeries struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ScheduledEventSeriesSpec   `json:"spec"`
+	Status            ScheduledEventSeriesStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ScheduledEventSeriesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ScheduledEventSeries `json:"items"`
+}
+
+type ScheduledEventSeriesSpec struct {
+	Template          ScheduledEventSpec `json:"template"`                    // start_time and end_time are those of the first occurrence, access_code is not used
+	Recurrence        string             `json:"recurrence"`                  // RRULE, e.g. FREQ=WEEKLY;BYDAY=TU;UNTIL=20261231T000000Z
+	Exceptions        []string           `json:"exceptions,omitempty"`        // dates (2006-01-02) without an occurrence
+	AccessCodePattern string             `json:"access_code_pattern"`         // {series}, {date} and {index} are replaced for every occurrence
+	MaterializeAhead  string             `json:"materialize_ahead,omitempty"` // how long before their start occurrences are created, defaults to two weeks
+	TimeZone          string             `json:"time_zone,omitempty"`         // IANA time zone the recurrence follows, e.g. Europe/Berlin. UTC if empty.
+}
+
+type ScheduledEventSeriesStatus struct {
+	Materialized     []string `json:"materialized,omitempty"` // dates of upcoming occurrences that were created, deleting one of them does not bring it back
+	LastMaterialized string   `json:"last_materialized,omitempty"`
+	Error            string   `json:"error,omitempty"`
 }
 
 // +genclient
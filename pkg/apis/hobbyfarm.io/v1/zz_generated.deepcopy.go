@@ -111,6 +111,195 @@ func (in *AccessCodeSpec) DeepCopy() *AccessCodeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Certificate) DeepCopyInto(out *Certificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Certificate.
+func (in *Certificate) DeepCopy() *Certificate {
+	if in == nil {
+		return nil
+	}
+	out := new(Certificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Certificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateList) DeepCopyInto(out *CertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Certificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateList.
+func (in *CertificateList) DeepCopy() *CertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
+	*out = *in
+	if in.Scenarios != nil {
+		in, out := &in.Scenarios, &out.Scenarios
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSpec.
+func (in *CertificateSpec) DeepCopy() *CertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSource) DeepCopyInto(out *ContentSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSource.
+func (in *ContentSource) DeepCopy() *ContentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContentSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSourceList) DeepCopyInto(out *ContentSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSourceList.
+func (in *ContentSourceList) DeepCopy() *ContentSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContentSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSourceSpec) DeepCopyInto(out *ContentSourceSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSourceSpec.
+func (in *ContentSourceSpec) DeepCopy() *ContentSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSourceStatus) DeepCopyInto(out *ContentSourceStatus) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scenarios != nil {
+		in, out := &in.Scenarios, &out.Scenarios
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Courses != nil {
+		in, out := &in.Courses, &out.Courses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSourceStatus.
+func (in *ContentSourceStatus) DeepCopy() *ContentSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Course) DeepCopyInto(out *Course) {
 	*out = *in
@@ -131,7 +320,425 @@ func (in *Course) DeepCopy() *Course {
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Course) DeepCopyObject() runtime.Object {
+func (in *Course) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseList) DeepCopyInto(out *CourseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Course, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseList.
+func (in *CourseList) DeepCopy() *CourseList {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CourseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseRevision) DeepCopyInto(out *CourseRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseRevision.
+func (in *CourseRevision) DeepCopy() *CourseRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CourseRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseRevisionList) DeepCopyInto(out *CourseRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CourseRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseRevisionList.
+func (in *CourseRevisionList) DeepCopy() *CourseRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CourseRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseRevisionSpec) DeepCopyInto(out *CourseRevisionSpec) {
+	*out = *in
+	in.Snapshot.DeepCopyInto(&out.Snapshot)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseRevisionSpec.
+func (in *CourseRevisionSpec) DeepCopy() *CourseRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseSpec) DeepCopyInto(out *CourseSpec) {
+	*out = *in
+	if in.Scenarios != nil {
+		in, out := &in.Scenarios, &out.Scenarios
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+	out.SessionPolicy = in.SessionPolicy
+	if in.Prerequisites != nil {
+		in, out := &in.Prerequisites, &out.Prerequisites
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Translations != nil {
+		in, out := &in.Translations, &out.Translations
+		*out = make(map[string]CourseTranslation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseSpec.
+func (in *CourseSpec) DeepCopy() *CourseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CourseTranslation) DeepCopyInto(out *CourseTranslation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseTranslation.
+func (in *CourseTranslation) DeepCopy() *CourseTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(CourseTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicBindConfiguration) DeepCopyInto(out *DynamicBindConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfiguration.
+func (in *DynamicBindConfiguration) DeepCopy() *DynamicBindConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicBindConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynamicBindConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicBindConfigurationList) DeepCopyInto(out *DynamicBindConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DynamicBindConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfigurationList.
+func (in *DynamicBindConfigurationList) DeepCopy() *DynamicBindConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicBindConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DynamicBindConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicBindConfigurationSpec) DeepCopyInto(out *DynamicBindConfigurationSpec) {
+	*out = *in
+	if in.BurstCountCapacity != nil {
+		in, out := &in.BurstCountCapacity, &out.BurstCountCapacity
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfigurationSpec.
+func (in *DynamicBindConfigurationSpec) DeepCopy() *DynamicBindConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicBindConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Environment) DeepCopyInto(out *Environment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
+func (in *Environment) DeepCopy() *Environment {
+	if in == nil {
+		return nil
+	}
+	out := new(Environment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Environment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
+func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+	*out = *in
+	if in.TemplateMapping != nil {
+		in, out := &in.TemplateMapping, &out.TemplateMapping
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.EnvironmentSpecifics != nil {
+		in, out := &in.EnvironmentSpecifics, &out.EnvironmentSpecifics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IPTranslationMap != nil {
+		in, out := &in.IPTranslationMap, &out.IPTranslationMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CountCapacity != nil {
+		in, out := &in.CountCapacity, &out.CountCapacity
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Prewarm != nil {
+		in, out := &in.Prewarm, &out.Prewarm
+		*out = new(PrewarmPolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
+func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LTILaunch) DeepCopyInto(out *LTILaunch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTILaunch.
+func (in *LTILaunch) DeepCopy() *LTILaunch {
+	if in == nil {
+		return nil
+	}
+	out := new(LTILaunch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LTILaunch) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -139,13 +746,13 @@ func (in *Course) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CourseList) DeepCopyInto(out *CourseList) {
+func (in *LTILaunchList) DeepCopyInto(out *LTILaunchList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Course, len(*in))
+		*out = make([]LTILaunch, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -153,18 +760,18 @@ func (in *CourseList) DeepCopyInto(out *CourseList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseList.
-func (in *CourseList) DeepCopy() *CourseList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTILaunchList.
+func (in *LTILaunchList) DeepCopy() *LTILaunchList {
 	if in == nil {
 		return nil
 	}
-	out := new(CourseList)
+	out := new(LTILaunchList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CourseList) DeepCopyObject() runtime.Object {
+func (in *LTILaunchList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -172,46 +779,46 @@ func (in *CourseList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CourseSpec) DeepCopyInto(out *CourseSpec) {
+func (in *LTILaunchSpec) DeepCopyInto(out *LTILaunchSpec) {
 	*out = *in
-	if in.Scenarios != nil {
-		in, out := &in.Scenarios, &out.Scenarios
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Categories != nil {
-		in, out := &in.Categories, &out.Categories
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.VirtualMachines != nil {
-		in, out := &in.VirtualMachines, &out.VirtualMachines
-		*out = make([]map[string]string, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make(map[string]string, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
+	if in.Nonces != nil {
+		in, out := &in.Nonces, &out.Nonces
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CourseSpec.
-func (in *CourseSpec) DeepCopy() *CourseSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTILaunchSpec.
+func (in *LTILaunchSpec) DeepCopy() *LTILaunchSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CourseSpec)
+	out := new(LTILaunchSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicBindConfiguration) DeepCopyInto(out *DynamicBindConfiguration) {
+func (in *LTILaunchStatus) DeepCopyInto(out *LTILaunchStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTILaunchStatus.
+func (in *LTILaunchStatus) DeepCopy() *LTILaunchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LTILaunchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LTIPlatform) DeepCopyInto(out *LTIPlatform) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -219,18 +826,18 @@ func (in *DynamicBindConfiguration) DeepCopyInto(out *DynamicBindConfiguration)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfiguration.
-func (in *DynamicBindConfiguration) DeepCopy() *DynamicBindConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTIPlatform.
+func (in *LTIPlatform) DeepCopy() *LTIPlatform {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicBindConfiguration)
+	out := new(LTIPlatform)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DynamicBindConfiguration) DeepCopyObject() runtime.Object {
+func (in *LTIPlatform) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -238,13 +845,13 @@ func (in *DynamicBindConfiguration) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicBindConfigurationList) DeepCopyInto(out *DynamicBindConfigurationList) {
+func (in *LTIPlatformList) DeepCopyInto(out *LTIPlatformList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DynamicBindConfiguration, len(*in))
+		*out = make([]LTIPlatform, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -252,18 +859,18 @@ func (in *DynamicBindConfigurationList) DeepCopyInto(out *DynamicBindConfigurati
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfigurationList.
-func (in *DynamicBindConfigurationList) DeepCopy() *DynamicBindConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTIPlatformList.
+func (in *LTIPlatformList) DeepCopy() *LTIPlatformList {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicBindConfigurationList)
+	out := new(LTIPlatformList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DynamicBindConfigurationList) DeepCopyObject() runtime.Object {
+func (in *LTIPlatformList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -271,11 +878,16 @@ func (in *DynamicBindConfigurationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicBindConfigurationSpec) DeepCopyInto(out *DynamicBindConfigurationSpec) {
+func (in *LTIPlatformSpec) DeepCopyInto(out *LTIPlatformSpec) {
 	*out = *in
-	if in.BurstCountCapacity != nil {
-		in, out := &in.BurstCountCapacity, &out.BurstCountCapacity
-		*out = make(map[string]int, len(*in))
+	if in.DeploymentIds != nil {
+		in, out := &in.DeploymentIds, &out.DeploymentIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessCodes != nil {
+		in, out := &in.AccessCodes, &out.AccessCodes
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -283,37 +895,54 @@ func (in *DynamicBindConfigurationSpec) DeepCopyInto(out *DynamicBindConfigurati
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicBindConfigurationSpec.
-func (in *DynamicBindConfigurationSpec) DeepCopy() *DynamicBindConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTIPlatformSpec.
+func (in *LTIPlatformSpec) DeepCopy() *LTIPlatformSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicBindConfigurationSpec)
+	out := new(LTIPlatformSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Environment) DeepCopyInto(out *Environment) {
+func (in *LeaderboardPolicy) DeepCopyInto(out *LeaderboardPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderboardPolicy.
+func (in *LeaderboardPolicy) DeepCopy() *LeaderboardPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderboardPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LearningRecord) DeepCopyInto(out *LearningRecord) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
+	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
-func (in *Environment) DeepCopy() *Environment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearningRecord.
+func (in *LearningRecord) DeepCopy() *LearningRecord {
 	if in == nil {
 		return nil
 	}
-	out := new(Environment)
+	out := new(LearningRecord)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Environment) DeepCopyObject() runtime.Object {
+func (in *LearningRecord) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -321,13 +950,13 @@ func (in *Environment) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+func (in *LearningRecordList) DeepCopyInto(out *LearningRecordList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Environment, len(*in))
+		*out = make([]LearningRecord, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -335,18 +964,18 @@ func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
-func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearningRecordList.
+func (in *LearningRecordList) DeepCopy() *LearningRecordList {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvironmentList)
+	out := new(LearningRecordList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+func (in *LearningRecordList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -354,55 +983,33 @@ func (in *EnvironmentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+func (in *LearningRecordSpec) DeepCopyInto(out *LearningRecordSpec) {
 	*out = *in
-	if in.TemplateMapping != nil {
-		in, out := &in.TemplateMapping, &out.TemplateMapping
-		*out = make(map[string]map[string]string, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(map[string]string, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.EnvironmentSpecifics != nil {
-		in, out := &in.EnvironmentSpecifics, &out.EnvironmentSpecifics
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.IPTranslationMap != nil {
-		in, out := &in.IPTranslationMap, &out.IPTranslationMap
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.CountCapacity != nil {
-		in, out := &in.CountCapacity, &out.CountCapacity
-		*out = make(map[string]int, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearningRecordSpec.
+func (in *LearningRecordSpec) DeepCopy() *LearningRecordSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(LearningRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LearningRecordStatus) DeepCopyInto(out *LearningRecordStatus) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
-func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearningRecordStatus.
+func (in *LearningRecordStatus) DeepCopy() *LearningRecordStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvironmentSpec)
+	out := new(LearningRecordStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -543,6 +1150,22 @@ func (in *PredefinedServiceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrewarmPolicy) DeepCopyInto(out *PrewarmPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrewarmPolicy.
+func (in *PrewarmPolicy) DeepCopy() *PrewarmPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PrewarmPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Progress) DeepCopyInto(out *Progress) {
 	*out = *in
@@ -611,6 +1234,18 @@ func (in *ProgressSpec) DeepCopyInto(out *ProgressSpec) {
 		*out = make([]ProgressStep, len(*in))
 		copy(*out, *in)
 	}
+	if in.CheckResults != nil {
+		in, out := &in.CheckResults, &out.CheckResults
+		*out = make([]StepCheckResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.QuestionResults != nil {
+		in, out := &in.QuestionResults, &out.QuestionResults
+		*out = make([]QuestionResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -635,13 +1270,115 @@ func (in *ProgressStep) DeepCopy() *ProgressStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ProgressStep)
-	in.DeepCopyInto(out)
-	return out
+	out := new(ProgressStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuestionResult) DeepCopyInto(out *QuestionResult) {
+	*out = *in
+	if in.Answer != nil {
+		in, out := &in.Answer, &out.Answer
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuestionResult.
+func (in *QuestionResult) DeepCopy() *QuestionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(QuestionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuestionTranslation) DeepCopyInto(out *QuestionTranslation) {
+	*out = *in
+	if in.Choices != nil {
+		in, out := &in.Choices, &out.Choices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuestionTranslation.
+func (in *QuestionTranslation) DeepCopy() *QuestionTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(QuestionTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scenario) DeepCopyInto(out *Scenario) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scenario.
+func (in *Scenario) DeepCopy() *Scenario {
+	if in == nil {
+		return nil
+	}
+	out := new(Scenario)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Scenario) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Scenario, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioList.
+func (in *ScenarioList) DeepCopy() *ScenarioList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScenarioList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Scenario) DeepCopyInto(out *Scenario) {
+func (in *ScenarioRevision) DeepCopyInto(out *ScenarioRevision) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -649,18 +1386,18 @@ func (in *Scenario) DeepCopyInto(out *Scenario) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scenario.
-func (in *Scenario) DeepCopy() *Scenario {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioRevision.
+func (in *ScenarioRevision) DeepCopy() *ScenarioRevision {
 	if in == nil {
 		return nil
 	}
-	out := new(Scenario)
+	out := new(ScenarioRevision)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Scenario) DeepCopyObject() runtime.Object {
+func (in *ScenarioRevision) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -668,13 +1405,13 @@ func (in *Scenario) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
+func (in *ScenarioRevisionList) DeepCopyInto(out *ScenarioRevisionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Scenario, len(*in))
+		*out = make([]ScenarioRevision, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -682,31 +1419,50 @@ func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioList.
-func (in *ScenarioList) DeepCopy() *ScenarioList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioRevisionList.
+func (in *ScenarioRevisionList) DeepCopy() *ScenarioRevisionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScenarioList)
+	out := new(ScenarioRevisionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScenarioList) DeepCopyObject() runtime.Object {
+func (in *ScenarioRevisionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioRevisionSpec) DeepCopyInto(out *ScenarioRevisionSpec) {
+	*out = *in
+	in.Snapshot.DeepCopyInto(&out.Snapshot)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioRevisionSpec.
+func (in *ScenarioRevisionSpec) DeepCopy() *ScenarioRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
 	*out = *in
 	if in.Steps != nil {
 		in, out := &in.Steps, &out.Steps
 		*out = make([]ScenarioStep, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Categories != nil {
 		in, out := &in.Categories, &out.Categories
@@ -731,6 +1487,14 @@ func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
 			}
 		}
 	}
+	out.SessionPolicy = in.SessionPolicy
+	if in.Translations != nil {
+		in, out := &in.Translations, &out.Translations
+		*out = make(map[string]ScenarioTranslation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -747,6 +1511,18 @@ func (in *ScenarioSpec) DeepCopy() *ScenarioSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScenarioStep) DeepCopyInto(out *ScenarioStep) {
 	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]StepCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.Questions != nil {
+		in, out := &in.Questions, &out.Questions
+		*out = make([]StepQuestion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -760,6 +1536,29 @@ func (in *ScenarioStep) DeepCopy() *ScenarioStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioTranslation) DeepCopyInto(out *ScenarioTranslation) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]StepTranslation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioTranslation.
+func (in *ScenarioTranslation) DeepCopy() *ScenarioTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledEvent) DeepCopyInto(out *ScheduledEvent) {
 	*out = *in
@@ -821,6 +1620,127 @@ func (in *ScheduledEventList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledEventReadiness) DeepCopyInto(out *ScheduledEventReadiness) {
+	*out = *in
+	in.LastBatchTime.DeepCopyInto(&out.LastBatchTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledEventReadiness.
+func (in *ScheduledEventReadiness) DeepCopy() *ScheduledEventReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledEventReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledEventSeries) DeepCopyInto(out *ScheduledEventSeries) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledEventSeries.
+func (in *ScheduledEventSeries) DeepCopy() *ScheduledEventSeries {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledEventSeries)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledEventSeries) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledEventSeriesList) DeepCopyInto(out *ScheduledEventSeriesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledEventSeries, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledEventSeriesList.
+func (in *ScheduledEventSeriesList) DeepCopy() *ScheduledEventSeriesList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledEventSeriesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledEventSeriesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledEventSeriesSpec) DeepCopyInto(out *ScheduledEventSeriesSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Exceptions != nil {
+		in, out := &in.Exceptions, &out.Exceptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledEventSeriesSpec.
+func (in *ScheduledEventSeriesSpec) DeepCopy() *ScheduledEventSeriesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledEventSeriesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledEventSeriesStatus) DeepCopyInto(out *ScheduledEventSeriesStatus) {
+	*out = *in
+	if in.Materialized != nil {
+		in, out := &in.Materialized, &out.Materialized
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledEventSeriesStatus.
+func (in *ScheduledEventSeriesStatus) DeepCopy() *ScheduledEventSeriesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledEventSeriesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledEventSpec) DeepCopyInto(out *ScheduledEventSpec) {
 	*out = *in
@@ -851,6 +1771,17 @@ func (in *ScheduledEventSpec) DeepCopyInto(out *ScheduledEventSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.SessionPolicy = in.SessionPolicy
+	if in.Leaderboard != nil {
+		in, out := &in.Leaderboard, &out.Leaderboard
+		*out = new(LeaderboardPolicy)
+		**out = **in
+	}
+	if in.Prewarm != nil {
+		in, out := &in.Prewarm, &out.Prewarm
+		*out = new(PrewarmPolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -872,6 +1803,42 @@ func (in *ScheduledEventStatus) DeepCopyInto(out *ScheduledEventStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ScenarioRevisions != nil {
+		in, out := &in.ScenarioRevisions, &out.ScenarioRevisions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CourseRevisions != nil {
+		in, out := &in.CourseRevisions, &out.CourseRevisions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CapacityShortfall != nil {
+		in, out := &in.CapacityShortfall, &out.CapacityShortfall
+		*out = make(map[string]map[string]int, len(*in))
+		for key, val := range *in {
+			var outVal map[string]int
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]int, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ScheduledEventReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1021,6 +1988,22 @@ func (in *SessionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionPolicy) DeepCopyInto(out *SessionPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionPolicy.
+func (in *SessionPolicy) DeepCopy() *SessionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SessionSpec) DeepCopyInto(out *SessionSpec) {
 	*out = *in
@@ -1029,6 +2012,11 @@ func (in *SessionSpec) DeepCopyInto(out *SessionSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1118,6 +2106,92 @@ func (in *SettingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCheck) DeepCopyInto(out *StepCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCheck.
+func (in *StepCheck) DeepCopy() *StepCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCheckResult) DeepCopyInto(out *StepCheckResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCheckResult.
+func (in *StepCheckResult) DeepCopy() *StepCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepQuestion) DeepCopyInto(out *StepQuestion) {
+	*out = *in
+	if in.Choices != nil {
+		in, out := &in.Choices, &out.Choices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CorrectChoices != nil {
+		in, out := &in.CorrectChoices, &out.CorrectChoices
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.CorrectAnswers != nil {
+		in, out := &in.CorrectAnswers, &out.CorrectAnswers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepQuestion.
+func (in *StepQuestion) DeepCopy() *StepQuestion {
+	if in == nil {
+		return nil
+	}
+	out := new(StepQuestion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepTranslation) DeepCopyInto(out *StepTranslation) {
+	*out = *in
+	if in.Questions != nil {
+		in, out := &in.Questions, &out.Questions
+		*out = make([]QuestionTranslation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepTranslation.
+func (in *StepTranslation) DeepCopy() *StepTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(StepTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
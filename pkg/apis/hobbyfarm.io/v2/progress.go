@@ -0,0 +1,183 @@
+package v2
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProgressStepsAnnotation keeps the compacted step history on v1 progress, so that an update through v1
+// does not lose the time spent per step
+const ProgressStepsAnnotation = "hobbyfarm.io/progress-steps"
+
+type stepHistory struct {
+	CurrentStep int            `json:"current_step"`
+	Expanded    int            `json:"expanded"` // number of v1 steps the history was expanded to
+	Steps       []ProgressStep `json:"steps"`
+}
+
+// Visit moves the progress to a step, closing the visit of the current step. Staying on the current step
+// does not count as a new visit.
+func (s *ProgressSpec) Visit(step int, now time.Time) {
+	s.visit(step, now)
+	s.MaxStep = max(s.MaxStep, step)
+	s.LastUpdate = metav1.NewTime(now)
+}
+
+func (s *ProgressSpec) visit(step int, now time.Time) {
+	i := s.stepIndex(s.CurrentStep)
+	if i >= 0 && step == s.CurrentStep {
+		// still on the same step, its visit stays open
+		return
+	}
+	if i >= 0 {
+		if spent := now.Sub(s.Steps[i].LastVisit.Time); spent > 0 {
+			s.Steps[i].TimeSpent.Duration += spent
+		}
+	}
+
+	i = s.stepIndex(step)
+	if i < 0 {
+		s.Steps = append(s.Steps, ProgressStep{Step: step, FirstVisit: metav1.NewTime(now)})
+		sort.Slice(s.Steps, func(a, b int) bool { return s.Steps[a].Step < s.Steps[b].Step })
+		i = s.stepIndex(step)
+	}
+	s.Steps[i].LastVisit = metav1.NewTime(now)
+	s.Steps[i].Visits++
+	s.CurrentStep = step
+}
+
+func (s *ProgressSpec) stepIndex(step int) int {
+	for i := range s.Steps {
+		if s.Steps[i].Step == step {
+			return i
+		}
+	}
+	return -1
+}
+
+// ConvertProgressFromV1 converts a v1 progress. Steps v1 clients appended after the progress was
+// converted to v1 are added to the history kept in ProgressStepsAnnotation.
+func ConvertProgressFromV1(in *v1.Progress) *Progress {
+	out := &Progress{
+		TypeMeta:   metav1.TypeMeta{Kind: "Progress", APIVersion: SchemeGroupVersion.String()},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+	out.Spec = ProgressSpec{
+		MaxStep:         in.Spec.MaxStep,
+		TotalStep:       in.Spec.TotalStep,
+		Course:          in.Spec.Course,
+		Scenario:        in.Spec.Scenario,
+		UserId:          in.Spec.UserId,
+		Started:         parseUnixDate(in.Spec.Started),
+		LastUpdate:      parseUnixDate(in.Spec.LastUpdate),
+		Finished:        in.Spec.Finished == "true",
+		CheckResults:    in.Spec.CheckResults,
+		QuestionResults: in.Spec.QuestionResults,
+		Score:           in.Spec.Score,
+		MaxScore:        in.Spec.MaxScore,
+	}
+
+	steps := in.Spec.Steps
+	var history stepHistory
+	if raw, ok := in.Annotations[ProgressStepsAnnotation]; ok && json.Unmarshal([]byte(raw), &history) == nil {
+		out.Spec.Steps = history.Steps
+		out.Spec.CurrentStep = history.CurrentStep
+		if history.Expanded <= len(steps) {
+			steps = steps[history.Expanded:]
+		}
+	} else if len(steps) > 0 {
+		out.Spec.CurrentStep = steps[0].Step
+	}
+	delete(out.Annotations, ProgressStepsAnnotation)
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+
+	for _, step := range steps {
+		out.Spec.visit(step.Step, parseUnixDate(step.Timestamp).Time)
+	}
+	out.Spec.CurrentStep = in.Spec.CurrentStep
+
+	return out
+}
+
+// ConvertProgressToV1 converts a progress to v1. The step history is expanded to the first visit of every step
+// followed by the last visit of the current step.
+func ConvertProgressToV1(in *Progress) *v1.Progress {
+	out := &v1.Progress{
+		TypeMeta:   metav1.TypeMeta{Kind: "Progress", APIVersion: v1.SchemeGroupVersion.String()},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       ConvertProgressSpecToV1(in.Spec),
+	}
+
+	history, err := json.Marshal(stepHistory{
+		CurrentStep: in.Spec.CurrentStep,
+		Expanded:    len(out.Spec.Steps),
+		Steps:       in.Spec.Steps,
+	})
+	if err == nil {
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[ProgressStepsAnnotation] = string(history)
+	}
+
+	return out
+}
+
+// ConvertProgressSpecToV1 returns the v1 representation of a progress spec
+func ConvertProgressSpecToV1(in ProgressSpec) v1.ProgressSpec {
+	out := v1.ProgressSpec{
+		CurrentStep:     in.CurrentStep,
+		MaxStep:         in.MaxStep,
+		TotalStep:       in.TotalStep,
+		Course:          in.Course,
+		Scenario:        in.Scenario,
+		UserId:          in.UserId,
+		Started:         formatUnixDate(in.Started),
+		LastUpdate:      formatUnixDate(in.LastUpdate),
+		Finished:        strconv.FormatBool(in.Finished),
+		CheckResults:    in.CheckResults,
+		QuestionResults: in.QuestionResults,
+		Score:           in.Score,
+		MaxScore:        in.MaxScore,
+	}
+
+	visits := make([]ProgressStep, len(in.Steps))
+	copy(visits, in.Steps)
+	sort.SliceStable(visits, func(a, b int) bool { return visits[a].FirstVisit.Before(&visits[b].FirstVisit) })
+
+	out.Steps = make([]v1.ProgressStep, 0, len(visits)+1)
+	var current *ProgressStep
+	for i := range visits {
+		out.Steps = append(out.Steps, v1.ProgressStep{Step: visits[i].Step, Timestamp: formatUnixDate(visits[i].FirstVisit)})
+		if visits[i].Step == in.CurrentStep {
+			current = &visits[i]
+		}
+	}
+	if current != nil && (out.Steps[len(out.Steps)-1].Step != current.Step || current.Visits > 1) {
+		out.Steps = append(out.Steps, v1.ProgressStep{Step: current.Step, Timestamp: formatUnixDate(current.LastVisit)})
+	}
+
+	return out
+}
+
+func parseUnixDate(value string) metav1.Time {
+	t, err := time.Parse(time.UnixDate, value)
+	if err != nil {
+		return metav1.Time{}
+	}
+	return metav1.NewTime(t)
+}
+
+func formatUnixDate(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.UnixDate)
+}
@@ -0,0 +1,164 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var progressStart = time.Date(2026, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+func minutes(n int) time.Time {
+	return progressStart.Add(time.Duration(n) * time.Minute)
+}
+
+func Test_Visit(t *testing.T) {
+	var p ProgressSpec
+	p.Visit(0, minutes(0))
+	p.Visit(1, minutes(2))
+	p.Visit(1, minutes(3)) // staying on a step is not a new visit
+	p.Visit(0, minutes(7))
+	p.Visit(2, minutes(8))
+
+	if p.CurrentStep != 2 || p.MaxStep != 2 || !p.LastUpdate.Time.Equal(minutes(8)) {
+		t.Errorf("CurrentStep, MaxStep, LastUpdate = %d, %d, %v", p.CurrentStep, p.MaxStep, p.LastUpdate)
+	}
+
+	want := []struct {
+		visits int
+		spent  time.Duration
+		first  time.Time
+		last   time.Time
+	}{
+		{2, 3 * time.Minute, minutes(0), minutes(7)},
+		{1, 5 * time.Minute, minutes(2), minutes(2)},
+		{1, 0, minutes(8), minutes(8)}, // the current visit is still open
+	}
+	if len(p.Steps) != len(want) {
+		t.Fatalf("Steps = %+v, want %d steps", p.Steps, len(want))
+	}
+	for i, w := range want {
+		s := p.Steps[i]
+		if s.Step != i || s.Visits != w.visits || s.TimeSpent.Duration != w.spent ||
+			!s.FirstVisit.Time.Equal(w.first) || !s.LastVisit.Time.Equal(w.last) {
+			t.Errorf("Steps[%d] = %+v, want %+v", i, s, w)
+		}
+	}
+}
+
+func Test_ProgressRoundTrip(t *testing.T) {
+	in := &Progress{ObjectMeta: metav1.ObjectMeta{Name: "progress-1"}}
+	in.Spec.Scenario = "sc-1"
+	in.Spec.TotalStep = 4
+	in.Spec.Started = metav1.NewTime(minutes(0))
+	in.Spec.Visit(0, minutes(0))
+	in.Spec.Visit(1, minutes(4))
+	in.Spec.Visit(0, minutes(5))
+	in.Spec.Visit(1, minutes(6))
+
+	v1Progress := ConvertProgressToV1(in)
+	if v1Progress.Spec.Finished != "false" || v1Progress.Spec.Started != minutes(0).Format(time.UnixDate) {
+		t.Errorf("v1 spec = %+v", v1Progress.Spec)
+	}
+	// the first visit of every step and the last visit of the current step
+	wantSteps := []v1.ProgressStep{
+		{Step: 0, Timestamp: minutes(0).Format(time.UnixDate)},
+		{Step: 1, Timestamp: minutes(4).Format(time.UnixDate)},
+		{Step: 1, Timestamp: minutes(6).Format(time.UnixDate)},
+	}
+	if len(v1Progress.Spec.Steps) != len(wantSteps) {
+		t.Fatalf("v1 steps = %+v, want %+v", v1Progress.Spec.Steps, wantSteps)
+	}
+	for i := range wantSteps {
+		if v1Progress.Spec.Steps[i] != wantSteps[i] {
+			t.Errorf("v1 steps[%d] = %+v, want %+v", i, v1Progress.Spec.Steps[i], wantSteps[i])
+		}
+	}
+
+	out := ConvertProgressFromV1(v1Progress)
+	if out.Annotations != nil {
+		t.Errorf("Annotations = %v, want none", out.Annotations)
+	}
+	if out.Spec.CurrentStep != 1 || out.Spec.MaxStep != 1 || out.Spec.Scenario != "sc-1" || !out.Spec.Started.Equal(&in.Spec.Started) {
+		t.Errorf("spec = %+v, want %+v", out.Spec, in.Spec)
+	}
+	if len(out.Spec.Steps) != len(in.Spec.Steps) {
+		t.Fatalf("Steps = %+v, want %+v", out.Spec.Steps, in.Spec.Steps)
+	}
+	for i := range in.Spec.Steps {
+		got, want := out.Spec.Steps[i], in.Spec.Steps[i]
+		if got.Visits != want.Visits || got.TimeSpent != want.TimeSpent || !got.LastVisit.Equal(&want.LastVisit) {
+			t.Errorf("Steps[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func Test_ProgressFromV1(t *testing.T) {
+	t.Run("steps appended by a v1 client", func(t *testing.T) {
+		in := &Progress{}
+		in.Spec.Visit(0, minutes(0))
+		in.Spec.Visit(1, minutes(1))
+
+		v1Progress := ConvertProgressToV1(in)
+		v1Progress.Spec.Steps = append(v1Progress.Spec.Steps, v1.ProgressStep{Step: 2, Timestamp: minutes(10).Format(time.UnixDate)})
+		v1Progress.Spec.CurrentStep = 2
+		v1Progress.Spec.MaxStep = 2
+
+		out := ConvertProgressFromV1(v1Progress)
+		if out.Spec.CurrentStep != 2 || len(out.Spec.Steps) != 3 {
+			t.Fatalf("spec = %+v, want step 2 to be added", out.Spec)
+		}
+		if spent := out.Spec.Steps[1].TimeSpent.Duration; spent != 9*time.Minute {
+			t.Errorf("time spent on step 1 = %v, want 9m", spent)
+		}
+		if s := out.Spec.Steps[2]; s.Step != 2 || s.Visits != 1 || !s.FirstVisit.Time.Equal(minutes(10)) {
+			t.Errorf("Steps[2] = %+v", s)
+		}
+	})
+
+	t.Run("progress that was never converted", func(t *testing.T) {
+		in := &v1.Progress{Spec: v1.ProgressSpec{
+			CurrentStep: 1,
+			Finished:    "true",
+			Steps: []v1.ProgressStep{
+				{Step: 0, Timestamp: minutes(0).Format(time.UnixDate)},
+				{Step: 1, Timestamp: minutes(2).Format(time.UnixDate)},
+				{Step: 0, Timestamp: minutes(5).Format(time.UnixDate)},
+				{Step: 1, Timestamp: minutes(6).Format(time.UnixDate)},
+			},
+		}}
+
+		out := ConvertProgressFromV1(in)
+		if !out.Spec.Finished || out.Spec.CurrentStep != 1 {
+			t.Errorf("spec = %+v", out.Spec)
+		}
+		if len(out.Spec.Steps) != 2 {
+			t.Fatalf("Steps = %+v, want 2 steps", out.Spec.Steps)
+		}
+		if s := out.Spec.Steps[0]; s.Visits != 2 || s.TimeSpent.Duration != 3*time.Minute {
+			t.Errorf("Steps[0] = %+v, want 2 visits and 3m", s)
+		}
+		if s := out.Spec.Steps[1]; s.Visits != 2 || s.TimeSpent.Duration != 3*time.Minute || !s.LastVisit.Time.Equal(minutes(6)) {
+			t.Errorf("Steps[1] = %+v, want 2 visits and 3m", s)
+		}
+	})
+
+	t.Run("history annotation that is not valid", func(t *testing.T) {
+		in := &v1.Progress{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ProgressStepsAnnotation: "[", "keep": "me"}},
+			Spec: v1.ProgressSpec{Steps: []v1.ProgressStep{
+				{Step: 0, Timestamp: minutes(0).Format(time.UnixDate)},
+			}},
+		}
+
+		out := ConvertProgressFromV1(in)
+		if len(out.Spec.Steps) != 1 || out.Spec.Steps[0].Visits != 1 {
+			t.Errorf("Steps = %+v, want the v1 steps", out.Spec.Steps)
+		}
+		if len(out.Annotations) != 1 || out.Annotations["keep"] != "me" {
+			t.Errorf("Annotations = %v", out.Annotations)
+		}
+	})
+}
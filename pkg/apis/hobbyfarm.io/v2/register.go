@@ -25,6 +25,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&User{},
 		&UserList{},
+		&Progress{},
+		&ProgressList{},
+		&ScheduledEvent{},
+		&ScheduledEventList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
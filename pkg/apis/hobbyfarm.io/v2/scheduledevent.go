@@ -0,0 +1,303 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	// time zones of scheduled events do not depend on the zoneinfo of the image
+	_ "time/tzdata"
+
+	v1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ScheduledEventProvisioning is true while the virtual machines of a started event are created
+	ScheduledEventProvisioning = "Provisioning"
+	// ScheduledEventReady is true once a started event was provisioned
+	ScheduledEventReady = "Ready"
+	// ScheduledEventActive is false if the event was deactivated, it is not provisioned then
+	ScheduledEventActive = "Active"
+	// ScheduledEventFinished is true once the event ended and its virtual machines were removed
+	ScheduledEventFinished = "Finished"
+	// ScheduledEventFailed is true if the last attempt to provision the event went wrong
+	ScheduledEventFailed = "Failed"
+
+	ReasonScheduled          = "Scheduled"
+	ReasonProvisioning       = "Provisioning"
+	ReasonProvisioned        = "Provisioned"
+	ReasonEnded              = "Ended"
+	ReasonActivated          = "Activated"
+	ReasonDeactivated        = "Deactivated"
+	ReasonCapacityShortfall  = "CapacityShortfall"
+	ReasonProvisioningFailed = "ProvisioningFailed"
+	ReasonInvalidSchedule    = "InvalidSchedule"
+
+	// ScheduledEventConditionsAnnotation keeps the conditions on v1 scheduled events, so that their reasons, messages
+	// and transition times survive an update through v1
+	ScheduledEventConditionsAnnotation = "hobbyfarm.io/scheduledevent-conditions"
+	// ScheduledEventTimeZoneAnnotation keeps the time zone on v1 scheduled events
+	ScheduledEventTimeZoneAnnotation = "hobbyfarm.io/time-zone"
+)
+
+// Location returns the time zone the times of the event are displayed in
+func (s ScheduledEventSpec) Location() *time.Location {
+	return LoadLocation(s.TimeZone)
+}
+
+// LoadLocation returns the IANA time zone with the given name, UTC if it is empty or unknown
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ParseScheduledEventTime parses a time passed in by a client. RFC 3339 times carry their offset, time.UnixDate
+// times are read in the given location so that an abbreviation like CET gets the offset it has there.
+func ParseScheduledEventTime(value string, loc *time.Location) (metav1.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return metav1.NewTime(t), nil
+	}
+	t, err := time.ParseInLocation(time.UnixDate, value, loc)
+	if err != nil {
+		return metav1.Time{}, err
+	}
+	return metav1.NewTime(t), nil
+}
+
+func (s *ScheduledEventStatus) IsActive() bool {
+	return meta.IsStatusConditionTrue(s.Conditions, ScheduledEventActive)
+}
+
+// IsProvisioned returns true if provisioning the event was started, it stays true once the event is ready or finished
+func (s *ScheduledEventStatus) IsProvisioned() bool {
+	c := meta.FindStatusCondition(s.Conditions, ScheduledEventProvisioning)
+	return c != nil && (c.Status == metav1.ConditionTrue || c.Reason == ReasonProvisioned || c.Reason == ReasonEnded)
+}
+
+func (s *ScheduledEventStatus) IsReady() bool {
+	return meta.IsStatusConditionTrue(s.Conditions, ScheduledEventReady)
+}
+
+func (s *ScheduledEventStatus) IsFinished() bool {
+	return meta.IsStatusConditionTrue(s.Conditions, ScheduledEventFinished)
+}
+
+func (s *ScheduledEventStatus) IsFailed() bool {
+	return meta.IsStatusConditionTrue(s.Conditions, ScheduledEventFailed)
+}
+
+func (s *ScheduledEventStatus) SetActive(active bool) {
+	if active {
+		s.setCondition(ScheduledEventActive, true, ReasonActivated, "the event is provisioned when it starts")
+	} else {
+		s.setCondition(ScheduledEventActive, false, ReasonDeactivated, "the event was deactivated")
+	}
+}
+
+// MarkScheduled resets the event to wait for its start, e.g. after it was edited
+func (s *ScheduledEventStatus) MarkScheduled() {
+	s.setCondition(ScheduledEventProvisioning, false, ReasonScheduled, "waiting for the event to start")
+	s.setCondition(ScheduledEventReady, false, ReasonScheduled, "waiting for the event to start")
+	s.setCondition(ScheduledEventFinished, false, ReasonScheduled, "waiting for the event to start")
+}
+
+func (s *ScheduledEventStatus) MarkProvisioning() {
+	s.setCondition(ScheduledEventProvisioning, true, ReasonProvisioning, "virtual machines are being provisioned")
+	s.setCondition(ScheduledEventReady, false, ReasonProvisioning, "virtual machines are being provisioned")
+	s.setCondition(ScheduledEventFinished, false, ReasonProvisioning, "the event is running")
+}
+
+func (s *ScheduledEventStatus) MarkReady() {
+	s.setCondition(ScheduledEventProvisioning, false, ReasonProvisioned, "virtual machines were provisioned")
+	s.setCondition(ScheduledEventReady, true, ReasonProvisioned, "virtual machines were provisioned")
+	s.setCondition(ScheduledEventFinished, false, ReasonProvisioned, "the event is running")
+}
+
+func (s *ScheduledEventStatus) MarkFinished() {
+	s.setCondition(ScheduledEventProvisioning, false, ReasonEnded, "the event ended")
+	s.setCondition(ScheduledEventReady, false, ReasonEnded, "the event ended")
+	s.setCondition(ScheduledEventFinished, true, ReasonEnded, "the event ended")
+}
+
+// MarkFailed records why the event could not be provisioned, the other conditions are left as they are
+func (s *ScheduledEventStatus) MarkFailed(reason string, message string) {
+	s.setCondition(ScheduledEventFailed, true, reason, message)
+}
+
+func (s *ScheduledEventStatus) ClearFailed() {
+	s.setCondition(ScheduledEventFailed, false, ReasonProvisioned, "")
+}
+
+// SetReadiness records how many virtual machines of the event are ready, while they are provisioned the message of
+// the Ready condition shows it as well
+func (s *ScheduledEventStatus) SetReadiness(readiness v1.ScheduledEventReadiness) {
+	s.Readiness = &readiness
+	if c := meta.FindStatusCondition(s.Conditions, ScheduledEventReady); c != nil && c.Reason == ReasonProvisioning {
+		c.Message = fmt.Sprintf("%d/%d virtual machines ready", readiness.Ready, readiness.Total)
+	}
+}
+
+func (s *ScheduledEventStatus) setCondition(conditionType string, status bool, reason string, message string) {
+	condition := metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+	if status {
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&s.Conditions, condition)
+}
+
+// ConvertScheduledEventFromV1 converts a v1 scheduled event. Its times are read in the time zone kept in
+// ScheduledEventTimeZoneAnnotation. Its conditions are restored from ScheduledEventConditionsAnnotation, the booleans
+// only set conditions if there is no annotation or a v1 client changed them.
+func ConvertScheduledEventFromV1(in *v1.ScheduledEvent) *ScheduledEvent {
+	out := &ScheduledEvent{
+		TypeMeta:   metav1.TypeMeta{Kind: "ScheduledEvent", APIVersion: SchemeGroupVersion.String()},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       ConvertScheduledEventSpecFromV1(in.Spec, in.Annotations[ScheduledEventTimeZoneAnnotation]),
+		Status: ScheduledEventStatus{
+			VirtualMachineSets: in.Status.VirtualMachineSets,
+			ScenarioRevisions:  in.Status.ScenarioRevisions,
+			CourseRevisions:    in.Status.CourseRevisions,
+			CapacityShortfall:  in.Status.CapacityShortfall,
+			Readiness:          in.Status.Readiness,
+		},
+	}
+
+	restored := false
+	if raw, ok := in.Annotations[ScheduledEventConditionsAnnotation]; ok {
+		restored = json.Unmarshal([]byte(raw), &out.Status.Conditions) == nil
+	}
+	delete(out.Annotations, ScheduledEventConditionsAnnotation)
+	delete(out.Annotations, ScheduledEventTimeZoneAnnotation)
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+
+	current := ConvertScheduledEventStatusToV1(out.Status)
+	if !restored || current.Active != in.Status.Active {
+		out.Status.SetActive(in.Status.Active)
+	}
+	if restored && current.Provisioned == in.Status.Provisioned && current.Ready == in.Status.Ready && current.Finished == in.Status.Finished {
+		return out
+	}
+
+	switch {
+	case in.Status.Finished:
+		out.Status.MarkFinished()
+	case in.Status.Provisioned && in.Status.Ready:
+		out.Status.MarkReady()
+	case in.Status.Provisioned:
+		out.Status.MarkProvisioning()
+	default:
+		out.Status.MarkScheduled()
+	}
+
+	return out
+}
+
+// ConvertScheduledEventSpecFromV1 converts a v1 spec, its times are read in the given time zone
+func ConvertScheduledEventSpecFromV1(in v1.ScheduledEventSpec, timeZone string) ScheduledEventSpec {
+	loc := LoadLocation(timeZone)
+	start, _ := ParseScheduledEventTime(in.StartTime, loc)
+	end, _ := ParseScheduledEventTime(in.EndTime, loc)
+
+	return ScheduledEventSpec{
+		Creator:                 in.Creator,
+		Name:                    in.Name,
+		Description:             in.Description,
+		StartTime:               start,
+		EndTime:                 end,
+		TimeZone:                timeZone,
+		OnDemand:                in.OnDemand,
+		RequiredVirtualMachines: in.RequiredVirtualMachines,
+		AccessCode:              in.AccessCode,
+		RestrictedBind:          in.RestrictedBind,
+		RestrictedBindValue:     in.RestrictedBindValue,
+		Printable:               in.Printable,
+		Scenarios:               in.Scenarios,
+		Courses:                 in.Courses,
+		SessionPolicy:           in.SessionPolicy,
+		Leaderboard:             in.Leaderboard,
+		Prewarm:                 in.Prewarm,
+	}
+}
+
+// ConvertScheduledEventToV1 converts a scheduled event to v1. Its times are formatted in its time zone.
+func ConvertScheduledEventToV1(in *ScheduledEvent) *v1.ScheduledEvent {
+	out := &v1.ScheduledEvent{
+		TypeMeta:   metav1.TypeMeta{Kind: "ScheduledEvent", APIVersion: v1.SchemeGroupVersion.String()},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       ConvertScheduledEventSpecToV1(in.Spec),
+		Status:     ConvertScheduledEventStatusToV1(in.Status),
+	}
+
+	if out.Annotations == nil {
+		out.Annotations = map[string]string{}
+	}
+	if in.Spec.TimeZone != "" {
+		out.Annotations[ScheduledEventTimeZoneAnnotation] = in.Spec.TimeZone
+	}
+	if conditions, err := json.Marshal(in.Status.Conditions); err == nil && len(in.Status.Conditions) > 0 {
+		out.Annotations[ScheduledEventConditionsAnnotation] = string(conditions)
+	}
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+
+	return out
+}
+
+// ConvertScheduledEventSpecToV1 returns the v1 representation of a scheduled event spec
+func ConvertScheduledEventSpecToV1(in ScheduledEventSpec) v1.ScheduledEventSpec {
+	return v1.ScheduledEventSpec{
+		Creator:                 in.Creator,
+		Name:                    in.Name,
+		Description:             in.Description,
+		StartTime:               formatInLocation(in.StartTime, in.Location()),
+		EndTime:                 formatInLocation(in.EndTime, in.Location()),
+		OnDemand:                in.OnDemand,
+		RequiredVirtualMachines: in.RequiredVirtualMachines,
+		AccessCode:              in.AccessCode,
+		RestrictedBind:          in.RestrictedBind,
+		RestrictedBindValue:     in.RestrictedBindValue,
+		Printable:               in.Printable,
+		Scenarios:               in.Scenarios,
+		Courses:                 in.Courses,
+		SessionPolicy:           in.SessionPolicy,
+		Leaderboard:             in.Leaderboard,
+		Prewarm:                 in.Prewarm,
+	}
+}
+
+// ConvertScheduledEventStatusToV1 returns the v1 representation of a scheduled event status
+func ConvertScheduledEventStatusToV1(in ScheduledEventStatus) v1.ScheduledEventStatus {
+	return v1.ScheduledEventStatus{
+		VirtualMachineSets: in.VirtualMachineSets,
+		Active:             in.IsActive(),
+		Provisioned:        in.IsProvisioned(),
+		Ready:              in.IsReady(),
+		Finished:           in.IsFinished(),
+		ScenarioRevisions:  in.ScenarioRevisions,
+		CourseRevisions:    in.CourseRevisions,
+		CapacityShortfall:  in.CapacityShortfall,
+		Readiness:          in.Readiness,
+	}
+}
+
+func formatInLocation(t metav1.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.UnixDate)
+}
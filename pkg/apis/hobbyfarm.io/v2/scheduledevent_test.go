@@ -0,0 +1,153 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func provisioningEvent() *ScheduledEvent {
+	berlin := LoadLocation("Europe/Berlin")
+	se := &ScheduledEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: "se-1", Annotations: map[string]string{"owner": "ops"}},
+		Spec: ScheduledEventSpec{
+			Name:      "workshop",
+			StartTime: metav1.NewTime(time.Date(2026, time.March, 28, 9, 0, 0, 0, berlin)),
+			EndTime:   metav1.NewTime(time.Date(2026, time.March, 29, 17, 0, 0, 0, berlin)),
+			TimeZone:  "Europe/Berlin",
+		},
+	}
+	se.Status.SetActive(true)
+	se.Status.MarkProvisioning()
+	se.Status.SetReadiness(v1.ScheduledEventReadiness{Ready: 142, Requested: 200, Total: 200})
+	return se
+}
+
+func Test_ScheduledEventRoundTrip(t *testing.T) {
+	in := provisioningEvent()
+	in.Status.MarkFailed("QuotaExceeded", "environment aws-1 is full")
+
+	out := ConvertScheduledEventFromV1(ConvertScheduledEventToV1(in))
+
+	if !out.Spec.StartTime.Equal(&in.Spec.StartTime) || !out.Spec.EndTime.Equal(&in.Spec.EndTime) {
+		t.Errorf("times = %v - %v, want %v - %v", out.Spec.StartTime, out.Spec.EndTime, in.Spec.StartTime, in.Spec.EndTime)
+	}
+	if out.Spec.TimeZone != "Europe/Berlin" {
+		t.Errorf("TimeZone = %q, want Europe/Berlin", out.Spec.TimeZone)
+	}
+	if len(out.Annotations) != 1 || out.Annotations["owner"] != "ops" {
+		t.Errorf("Annotations = %v, want only the owner annotation", out.Annotations)
+	}
+
+	for _, conditionType := range []string{ScheduledEventActive, ScheduledEventProvisioning, ScheduledEventReady, ScheduledEventFinished, ScheduledEventFailed} {
+		want := meta.FindStatusCondition(in.Status.Conditions, conditionType)
+		got := meta.FindStatusCondition(out.Status.Conditions, conditionType)
+		if got == nil {
+			t.Errorf("condition %s was lost", conditionType)
+			continue
+		}
+		if got.Status != want.Status || got.Reason != want.Reason || got.Message != want.Message {
+			t.Errorf("condition %s = %s/%s/%q, want %s/%s/%q", conditionType,
+				got.Status, got.Reason, got.Message, want.Status, want.Reason, want.Message)
+		}
+	}
+}
+
+func Test_ScheduledEventFromV1Changes(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(*v1.ScheduledEvent)
+		wantActive  bool
+		wantReady   bool
+		wantMessage string // of the Ready condition
+	}{
+		{
+			name:        "unchanged",
+			change:      func(*v1.ScheduledEvent) {},
+			wantActive:  true,
+			wantMessage: "142/200 virtual machines ready",
+		},
+		{
+			name:        "deactivated through v1",
+			change:      func(se *v1.ScheduledEvent) { se.Status.Active = false },
+			wantMessage: "142/200 virtual machines ready",
+		},
+		{
+			name:        "marked ready through v1",
+			change:      func(se *v1.ScheduledEvent) { se.Status.Ready = true },
+			wantActive:  true,
+			wantReady:   true,
+			wantMessage: "virtual machines were provisioned",
+		},
+		{
+			name: "conditions annotation removed",
+			change: func(se *v1.ScheduledEvent) {
+				delete(se.Annotations, ScheduledEventConditionsAnnotation)
+			},
+			wantActive:  true,
+			wantMessage: "virtual machines are being provisioned",
+		},
+		{
+			name: "conditions annotation is not valid",
+			change: func(se *v1.ScheduledEvent) {
+				se.Annotations[ScheduledEventConditionsAnnotation] = "{"
+			},
+			wantActive:  true,
+			wantMessage: "virtual machines are being provisioned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := ConvertScheduledEventToV1(provisioningEvent())
+			tt.change(se)
+
+			out := ConvertScheduledEventFromV1(se)
+			if out.Status.IsActive() != tt.wantActive {
+				t.Errorf("IsActive() = %v, want %v", out.Status.IsActive(), tt.wantActive)
+			}
+			if out.Status.IsReady() != tt.wantReady {
+				t.Errorf("IsReady() = %v, want %v", out.Status.IsReady(), tt.wantReady)
+			}
+			if !out.Status.IsProvisioned() {
+				t.Error("IsProvisioned() = false, want true")
+			}
+			if c := meta.FindStatusCondition(out.Status.Conditions, ScheduledEventReady); c == nil || c.Message != tt.wantMessage {
+				t.Errorf("Ready condition = %+v, want message %q", c, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func Test_ScheduledEventFromV1WithoutTimeZone(t *testing.T) {
+	se := &v1.ScheduledEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: "se-legacy"},
+		Spec: v1.ScheduledEventSpec{
+			StartTime: "Sat Mar 28 09:00:00 UTC 2026",
+			EndTime:   "2026-03-29T17:00:00+02:00",
+		},
+		Status: v1.ScheduledEventStatus{Active: true, Provisioned: true, Ready: true},
+	}
+
+	out := ConvertScheduledEventFromV1(se)
+	if want := time.Date(2026, time.March, 28, 9, 0, 0, 0, time.UTC); !out.Spec.StartTime.Time.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", out.Spec.StartTime, want)
+	}
+	if want := time.Date(2026, time.March, 29, 15, 0, 0, 0, time.UTC); !out.Spec.EndTime.Time.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", out.Spec.EndTime, want)
+	}
+	if !out.Status.IsActive() || !out.Status.IsReady() || out.Status.IsFinished() {
+		t.Errorf("conditions = %+v, want active and ready", out.Status.Conditions)
+	}
+	if out.Annotations != nil {
+		t.Errorf("Annotations = %v, want none", out.Annotations)
+	}
+
+	back := ConvertScheduledEventToV1(out).Status
+	if !back.Active || !back.Provisioned || !back.Ready || back.Finished {
+		t.Errorf("status after round trip = %+v, want %+v", back, se.Status)
+	}
+}
@@ -1,6 +1,9 @@
 package v2
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	v1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // +genclient
 // +genclient:noStatus
@@ -26,3 +29,95 @@ type UserSpec struct {
 	AccessCodes []string          `json:"access_codes"`
 	Settings    map[string]string `json:"settings"`
 }
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type Progress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ProgressSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ProgressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Progress `json:"items"`
+}
+
+type ProgressSpec struct {
+	CurrentStep     int                  `json:"current_step"`
+	MaxStep         int                  `json:"max_step"`
+	TotalStep       int                  `json:"total_step"`
+	Course          string               `json:"course"`
+	Scenario        string               `json:"scenario"`
+	UserId          string               `json:"user"`
+	Started         metav1.Time          `json:"started"`
+	LastUpdate      metav1.Time          `json:"last_update"`
+	Finished        bool                 `json:"finished"`
+	Steps           []ProgressStep       `json:"steps"`                   // one entry per visited step, ordered by step
+	CheckResults    []v1.StepCheckResult `json:"check_results,omitempty"` // latest result of every check that was run
+	QuestionResults []v1.QuestionResult  `json:"question_results,omitempty"`
+	Score           int                  `json:"score"`
+	MaxScore        int                  `json:"max_score"`
+}
+
+// ProgressStep sums up all visits of a step. The visit to the current step is still open,
+// its time is added to TimeSpent once the user moves on.
+type ProgressStep struct {
+	Step       int             `json:"step"`
+	FirstVisit metav1.Time     `json:"first_visit"`
+	LastVisit  metav1.Time     `json:"last_visit"`
+	Visits     int             `json:"visits"`
+	TimeSpent  metav1.Duration `json:"time_spent"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ScheduledEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ScheduledEventSpec   `json:"spec"`
+	Status            ScheduledEventStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ScheduledEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ScheduledEvent `json:"items"`
+}
+
+type ScheduledEventSpec struct {
+	Creator                 string                    `json:"creator"`
+	Name                    string                    `json:"event_name"`
+	Description             string                    `json:"description"`
+	StartTime               metav1.Time               `json:"start_time"`
+	EndTime                 metav1.Time               `json:"end_time"`
+	TimeZone                string                    `json:"time_zone,omitempty"` // IANA time zone the times are displayed in, e.g. Europe/Berlin. UTC if empty.
+	OnDemand                bool                      `json:"on_demand"`           // whether or not to provision VMs on-demand
+	RequiredVirtualMachines map[string]map[string]int `json:"required_vms"`        // map of environment to a map of strings it should be environment: vm template: count
+	AccessCode              string                    `json:"access_code"`
+	RestrictedBind          bool                      `json:"restricted_bind"` // if restricted_bind is true, we need to make the scenario sessions when they get created only bind to vmsets that are created by this scheduledevent
+	RestrictedBindValue     string                    `json:"restricted_bind_value"`
+	Printable               bool                      `json:"printable"`
+	Scenarios               []string                  `json:"scenarios"`
+	Courses                 []string                  `json:"courses"`
+	SessionPolicy           v1.SessionPolicy          `json:"session_policy"`
+	Leaderboard             *v1.LeaderboardPolicy     `json:"leaderboard,omitempty"` // nil if the event has no leaderboard
+	Prewarm                 *v1.PrewarmPolicy         `json:"prewarm,omitempty"`     // nil uses the policies of the environments
+}
+
+type ScheduledEventStatus struct {
+	VirtualMachineSets []string                    `json:"vmsets"`
+	Conditions         []metav1.Condition          `json:"conditions,omitempty"`         // Provisioning, Ready, Active, Finished and Failed
+	ScenarioRevisions  map[string]int              `json:"scenario_revisions,omitempty"` // revisions pinned when the event was provisioned
+	CourseRevisions    map[string]int              `json:"course_revisions,omitempty"`
+	CapacityShortfall  map[string]map[string]int   `json:"capacity_shortfall,omitempty"` // environment: vm template: count of virtual machines that did not fit
+	Readiness          *v1.ScheduledEventReadiness `json:"readiness,omitempty"`
+}
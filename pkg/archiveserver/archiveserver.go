@@ -0,0 +1,212 @@
+package archiveserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/contentarchive"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	maxArchiveSize = 64 * 1024 * 1024
+)
+
+type ArchiveServer struct {
+	auth        *authclient.AuthClient
+	hfClientSet hfClientset.Interface
+	ctx         context.Context
+}
+
+func NewArchiveServer(authClient *authclient.AuthClient, hfClientset hfClientset.Interface, ctx context.Context) (*ArchiveServer, error) {
+	a := ArchiveServer{}
+
+	a.auth = authClient
+	a.hfClientSet = hfClientset
+	a.ctx = ctx
+
+	return &a, nil
+}
+
+func (a ArchiveServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/a/archive/export", a.ExportFunc).Methods("POST")
+	r.HandleFunc("/a/archive/import", a.ImportFunc).Methods("POST")
+	glog.V(2).Infof("set up routes for archive server")
+}
+
+// ExportFunc returns an archive of the selected content as a download. Ids are passed as JSON arrays.
+func (a ArchiveServer) ExportFunc(w http.ResponseWriter, r *http.Request) {
+	selection := contentarchive.Selection{}
+
+	all := r.PostFormValue("all")
+	if all != "" {
+		var err error
+		if selection.All, err = strconv.ParseBool(all); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid value for all")
+			return
+		}
+	}
+
+	dependencies := true
+	if raw := r.PostFormValue("dependencies"); raw != "" {
+		var err error
+		if dependencies, err = strconv.ParseBool(raw); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid value for dependencies")
+			return
+		}
+	}
+
+	for field, ids := range map[string]*[]string{
+		contentarchive.KindScenario:               &selection.Scenarios,
+		contentarchive.KindCourse:                 &selection.Courses,
+		contentarchive.KindVirtualMachineTemplate: &selection.VirtualMachineTemplates,
+		contentarchive.KindPredefinedService:      &selection.PredefinedServices,
+		contentarchive.KindEnvironment:            &selection.Environments,
+	} {
+		raw := r.PostFormValue(field)
+		if raw == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(raw), ids); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", fmt.Sprintf("invalid %s, expected a list of ids", field))
+			return
+		}
+	}
+
+	if selection.Empty() {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "nothing selected for export")
+		return
+	}
+
+	_, err := a.auth.AuthGrant(exportPermissions(selection, dependencies), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to export content")
+		return
+	}
+
+	archive, err := contentarchive.Export(a.ctx, a.hfClientSet, selection, dependencies)
+	if err != nil {
+		glog.Errorf("error exporting content: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", err.Error())
+		return
+	}
+
+	encoded, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		glog.Errorf("error marshalling archive: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error exporting content")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"hobbyfarm-%s.json\"", time.Now().Format("20060102-150405")))
+	util.ReturnHTTPRaw(w, r, string(encoded))
+
+	glog.V(2).Infof("exported %d scenarios, %d courses, %d vm templates, %d predefined services and %d environments",
+		len(archive.Scenarios), len(archive.Courses), len(archive.VirtualMachineTemplates), len(archive.PredefinedServices), len(archive.Environments))
+}
+
+// ImportFunc imports the archive passed in the form value archive. With dry_run only the planned actions are returned.
+func (a ArchiveServer) ImportFunc(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+
+	archive, err := contentarchive.Decode([]byte(r.PostFormValue("archive")))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	conflict, err := contentarchive.ParseConflict(r.PostFormValue("conflict"))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	dryRun := false
+	if raw := r.PostFormValue("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid value for dry_run")
+			return
+		}
+	}
+
+	request := rbacclient.RbacRequest()
+	for _, kind := range archive.Kinds() {
+		request.HobbyfarmPermission(kind, rbacclient.VerbCreate)
+		if conflict == contentarchive.ConflictOverwrite {
+			request.HobbyfarmPermission(kind, rbacclient.VerbUpdate)
+		}
+	}
+	user, err := a.auth.AuthGrant(request, w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to import content")
+		return
+	}
+
+	result, err := contentarchive.Import(a.ctx, a.hfClientSet, archive, contentarchive.Options{
+		Conflict: conflict,
+		DryRun:   dryRun,
+		Creator:  user.Name,
+	})
+	if err != nil {
+		glog.Errorf("error importing archive for user %s: %v", user.Name, err)
+		if result == nil {
+			// nothing was written, the archive does not fit this cluster
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		} else {
+			util.ReturnHTTPMessage(w, r, 500, "error", err.Error())
+		}
+		return
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encoded)
+
+	glog.V(2).Infof("user %s imported %d objects (dry run: %t)", user.Name, len(result.Actions), dryRun)
+}
+
+// exportPermissions requires get on everything that ends up in the archive, exporting all content requires list
+func exportPermissions(selection contentarchive.Selection, dependencies bool) *rbacclient.Request {
+	request := rbacclient.RbacRequest()
+
+	if selection.All {
+		for _, kind := range []string{contentarchive.KindScenario, contentarchive.KindCourse, contentarchive.KindVirtualMachineTemplate,
+			contentarchive.KindPredefinedService, contentarchive.KindEnvironment} {
+			request.HobbyfarmPermission(kind, rbacclient.VerbList)
+		}
+		return request
+	}
+
+	kinds := map[string]bool{
+		contentarchive.KindScenario:               len(selection.Scenarios) > 0,
+		contentarchive.KindCourse:                 len(selection.Courses) > 0,
+		contentarchive.KindVirtualMachineTemplate: len(selection.VirtualMachineTemplates) > 0,
+		contentarchive.KindPredefinedService:      len(selection.PredefinedServices) > 0,
+		contentarchive.KindEnvironment:            len(selection.Environments) > 0,
+	}
+	if dependencies {
+		kinds[contentarchive.KindScenario] = kinds[contentarchive.KindScenario] || kinds[contentarchive.KindCourse]
+		kinds[contentarchive.KindVirtualMachineTemplate] = kinds[contentarchive.KindVirtualMachineTemplate] ||
+			kinds[contentarchive.KindScenario] || kinds[contentarchive.KindEnvironment]
+	}
+
+	for _, kind := range []string{contentarchive.KindScenario, contentarchive.KindCourse, contentarchive.KindVirtualMachineTemplate,
+		contentarchive.KindPredefinedService, contentarchive.KindEnvironment} {
+		if kinds[kind] {
+			request.HobbyfarmPermission(kind, rbacclient.VerbGet)
+		}
+	}
+	return request
+}
@@ -0,0 +1,162 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	keySecretName = "hobbyfarm-certificate-signing-key"
+	keySecretData = "seed"
+)
+
+// Payload is the signed part of a certificate
+type Payload struct {
+	ID         string   `json:"id"`
+	Holder     string   `json:"holder"`
+	Course     string   `json:"course"`
+	CourseName string   `json:"course_name"`
+	Scenarios  []string `json:"scenarios"`
+	Score      int      `json:"score"`
+	MaxScore   int      `json:"max_score"`
+	Issued     string   `json:"issued"`
+}
+
+// Document is a certificate as handed out to its holder
+type Document struct {
+	Certificate Payload `json:"certificate"`
+	KeyId       string  `json:"key_id"`
+	Signature   string  `json:"signature"`
+}
+
+// Signer signs certificates with an ed25519 key kept in a secret of the release namespace.
+// The key is created the first time it is needed.
+type Signer struct {
+	kubeClient kubernetes.Interface
+	ctx        context.Context
+
+	mu    sync.Mutex
+	key   ed25519.PrivateKey
+	keyId string
+}
+
+func NewSigner(kubeClient kubernetes.Interface, ctx context.Context) *Signer {
+	return &Signer{
+		kubeClient: kubeClient,
+		ctx:        ctx,
+	}
+}
+
+// Sign signs a certificate and stores the signature in its spec
+func (s *Signer) Sign(cert *hfv1.Certificate) error {
+	key, keyId, err := s.loadKey()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(NewPayload(cert))
+	if err != nil {
+		return err
+	}
+
+	cert.Spec.KeyId = keyId
+	cert.Spec.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// Verify checks the signature of a document
+func (s *Signer) Verify(doc Document) error {
+	key, keyId, err := s.loadKey()
+	if err != nil {
+		return err
+	}
+
+	if doc.KeyId != keyId {
+		return fmt.Errorf("certificate was signed with an unknown key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(doc.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+
+	payload, err := json.Marshal(doc.Certificate)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(key.Public().(ed25519.PublicKey), payload, signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// PublicKey returns the key certificates are verified with and its id
+func (s *Signer) PublicKey() (ed25519.PublicKey, string, error) {
+	key, keyId, err := s.loadKey()
+	if err != nil {
+		return nil, "", err
+	}
+	return key.Public().(ed25519.PublicKey), keyId, nil
+}
+
+func (s *Signer) loadKey() (ed25519.PrivateKey, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key != nil {
+		return s.key, s.keyId, nil
+	}
+
+	seed, err := util.SigningKey(s.kubeClient, keySecretName, keySecretData, func() ([]byte, error) {
+		seed := make([]byte, ed25519.SeedSize)
+		_, err := rand.Read(seed)
+		return seed, err
+	}, s.ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("error retrieving certificate signing key: %v", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, "", fmt.Errorf("secret %s holds no valid signing key", keySecretName)
+	}
+
+	s.key = ed25519.NewKeyFromSeed(seed)
+	hash := sha256.Sum256(s.key.Public().(ed25519.PublicKey))
+	s.keyId = hex.EncodeToString(hash[:8])
+
+	return s.key, s.keyId, nil
+}
+
+// NewPayload returns the signed part of a certificate
+func NewPayload(cert *hfv1.Certificate) Payload {
+	return Payload{
+		ID:         cert.Name,
+		Holder:     cert.Spec.Holder,
+		Course:     cert.Spec.Course,
+		CourseName: cert.Spec.CourseName,
+		Scenarios:  cert.Spec.Scenarios,
+		Score:      cert.Spec.Score,
+		MaxScore:   cert.Spec.MaxScore,
+		Issued:     cert.Spec.Issued,
+	}
+}
+
+// NewDocument returns the document handed out for a signed certificate
+func NewDocument(cert *hfv1.Certificate) Document {
+	return Document{
+		Certificate: NewPayload(cert),
+		KeyId:       cert.Spec.KeyId,
+		Signature:   cert.Spec.Signature,
+	}
+}
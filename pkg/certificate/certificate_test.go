@@ -0,0 +1,66 @@
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_SignVerify(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	signer := NewSigner(kubeClient, context.Background())
+
+	cert := &hfv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-1"},
+		Spec: hfv1.CertificateSpec{
+			Holder:     "learner@example.com",
+			Course:     "course-1",
+			CourseName: "Kubernetes Basics",
+			Scenarios:  []string{"intro", "install"},
+			Score:      8,
+			MaxScore:   10,
+			Issued:     "Mon Jun  1 09:00:00 UTC 2026",
+		},
+	}
+	if err := signer.Sign(cert); err != nil {
+		t.Fatal(err)
+	}
+	if cert.Spec.KeyId == "" || cert.Spec.Signature == "" {
+		t.Fatalf("Sign() left spec %+v without a signature", cert.Spec)
+	}
+
+	// another replica loads the key from the secret
+	replica := NewSigner(kubeClient, context.Background())
+	if err := replica.Verify(NewDocument(cert)); err != nil {
+		t.Errorf("Verify() of a signed certificate = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(doc *Document)
+	}{
+		{"changed score", func(doc *Document) { doc.Certificate.Score = 10 }},
+		{"changed holder", func(doc *Document) { doc.Certificate.Holder = "someone@example.com" }},
+		{"dropped scenario", func(doc *Document) { doc.Certificate.Scenarios = doc.Certificate.Scenarios[:1] }},
+		{"unknown key", func(doc *Document) { doc.KeyId = "0123456789abcdef" }},
+		{"signature that is not base64", func(doc *Document) { doc.Signature = "not a signature!" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewDocument(cert)
+			tt.tamper(&doc)
+			if err := replica.Verify(doc); err == nil {
+				t.Error("Verify() of a tampered certificate succeeded")
+			}
+		})
+	}
+
+	// a signer with a key of its own does not accept the certificate
+	other := NewSigner(fake.NewSimpleClientset(), context.Background())
+	if err := other.Verify(NewDocument(cert)); err == nil {
+		t.Error("Verify() with another key succeeded")
+	}
+}
@@ -0,0 +1,270 @@
+package certificateserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/certificate"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	resourcePlural = "certificates"
+)
+
+type CertificateServer struct {
+	auth        *authclient.AuthClient
+	hfClientSet hfClientset.Interface
+	signer      *certificate.Signer
+	ctx         context.Context
+}
+
+type PreparedCertificate struct {
+	ID string `json:"id"`
+	hfv1.CertificateSpec
+}
+
+type PreparedVerification struct {
+	Valid       bool                 `json:"valid"`
+	Reason      string               `json:"reason,omitempty"`
+	Certificate *certificate.Payload `json:"certificate,omitempty"`
+}
+
+type PreparedKey struct {
+	KeyId     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
+
+func NewCertificateServer(authClient *authclient.AuthClient, hfClientset hfClientset.Interface, kubeClient kubernetes.Interface, ctx context.Context) (*CertificateServer, error) {
+	c := CertificateServer{}
+
+	c.auth = authClient
+	c.hfClientSet = hfClientset
+	c.signer = certificate.NewSigner(kubeClient, ctx)
+	c.ctx = ctx
+
+	return &c, nil
+}
+
+func (c CertificateServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/certificate/list", c.ListForUserFunc).Methods("GET")
+	r.HandleFunc("/certificate/key", c.KeyFunc).Methods("GET")
+	r.HandleFunc("/certificate/verify", c.VerifyDocumentFunc).Methods("POST")
+	r.HandleFunc("/certificate/verify/{id}", c.VerifyFunc).Methods("GET")
+	r.HandleFunc("/certificate/{id}", c.GetFunc).Methods("GET")
+	r.HandleFunc("/a/certificate/list", c.ListFunc).Methods("GET")
+	r.HandleFunc("/a/certificate/{id}", c.DeleteFunc).Methods("DELETE")
+	glog.V(2).Infof("set up routes for certificate server")
+}
+
+// ListForUserFunc lists the certificates of the caller
+func (c CertificateServer) ListForUserFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := c.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list certificates")
+		return
+	}
+
+	c.list(w, r, fmt.Sprintf("%s=%s", util.UserLabel, user.Name))
+}
+
+// ListFunc lists all certificates, optionally those of a course or user passed as query parameters
+func (c CertificateServer) ListFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list certificates")
+		return
+	}
+
+	selector := labelSelector(map[string]string{
+		util.CourseLabel: r.URL.Query().Get("course"),
+		util.UserLabel:   r.URL.Query().Get("user"),
+	})
+
+	c.list(w, r, selector)
+}
+
+func (c CertificateServer) list(w http.ResponseWriter, r *http.Request, selector string) {
+	certificates, err := c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{
+		LabelSelector: selector})
+	if err != nil {
+		glog.Errorf("error while retrieving certificates %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving certificates")
+		return
+	}
+
+	prepared := []PreparedCertificate{}
+	for _, cert := range certificates.Items {
+		prepared = append(prepared, PreparedCertificate{cert.Name, cert.Spec})
+	}
+
+	encodedCertificates, err := json.Marshal(prepared)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedCertificates)
+}
+
+// GetFunc returns the signed certificate document of the caller as a download
+func (c CertificateServer) GetFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := c.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to certificates")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	cert, err := c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).Get(c.ctx, id, metav1.GetOptions{})
+	if err != nil || cert.Spec.UserId != user.Name {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "certificate not found")
+		return
+	}
+
+	encoded, err := json.MarshalIndent(certificate.NewDocument(cert), "", "  ")
+	if err != nil {
+		glog.Errorf("error marshalling certificate %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving certificate")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"certificate-%s.json\"", cert.Name))
+	util.ReturnHTTPRaw(w, r, string(encoded))
+}
+
+// VerifyFunc verifies a certificate by its id. It needs no authentication so that anyone can check a certificate.
+func (c CertificateServer) VerifyFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	cert, err := c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).Get(c.ctx, id, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		c.returnVerification(w, r, PreparedVerification{Reason: "certificate does not exist or was revoked"})
+		return
+	}
+	if err != nil {
+		glog.Errorf("error retrieving certificate %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error verifying certificate")
+		return
+	}
+
+	c.verify(w, r, certificate.NewDocument(cert))
+}
+
+// VerifyDocumentFunc verifies a certificate document passed in the form value certificate.
+// The document has to be signed by this installation and match a certificate that was not revoked.
+func (c CertificateServer) VerifyDocumentFunc(w http.ResponseWriter, r *http.Request) {
+	doc := certificate.Document{}
+	if err := json.Unmarshal([]byte(r.PostFormValue("certificate")), &doc); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid certificate")
+		return
+	}
+
+	cert, err := c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).Get(c.ctx, doc.Certificate.ID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		c.returnVerification(w, r, PreparedVerification{Reason: "certificate does not exist or was revoked"})
+		return
+	}
+	if err != nil {
+		glog.Errorf("error retrieving certificate %s: %v", doc.Certificate.ID, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error verifying certificate")
+		return
+	}
+
+	if !equality.Semantic.DeepEqual(doc, certificate.NewDocument(cert)) {
+		c.returnVerification(w, r, PreparedVerification{Reason: "certificate does not match the issued one"})
+		return
+	}
+
+	c.verify(w, r, doc)
+}
+
+func (c CertificateServer) verify(w http.ResponseWriter, r *http.Request, doc certificate.Document) {
+	if err := c.signer.Verify(doc); err != nil {
+		c.returnVerification(w, r, PreparedVerification{Reason: err.Error()})
+		return
+	}
+
+	c.returnVerification(w, r, PreparedVerification{Valid: true, Certificate: &doc.Certificate})
+}
+
+func (c CertificateServer) returnVerification(w http.ResponseWriter, r *http.Request, verification PreparedVerification) {
+	encoded, err := json.Marshal(verification)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encoded)
+}
+
+// KeyFunc returns the public key certificates are signed with, so that documents can be verified offline
+func (c CertificateServer) KeyFunc(w http.ResponseWriter, r *http.Request) {
+	key, keyId, err := c.signer.PublicKey()
+	if err != nil {
+		glog.Errorf("error retrieving certificate key: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving key")
+		return
+	}
+
+	encoded, err := json.Marshal(PreparedKey{
+		KeyId:     keyId,
+		Algorithm: "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encoded)
+}
+
+// DeleteFunc revokes a certificate, it no longer verifies afterwards
+func (c CertificateServer) DeleteFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbDelete), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to delete certificates")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	err = c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).Delete(c.ctx, id, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "certificate not found")
+		return
+	}
+	if err != nil {
+		glog.Errorf("error deleting certificate %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error deleting certificate")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "deleted", id)
+	glog.V(4).Infof("deleted certificate %s", id)
+}
+
+func labelSelector(values map[string]string) string {
+	selector := ""
+	for label, value := range values {
+		if value == "" {
+			continue
+		}
+		if selector != "" {
+			selector += ","
+		}
+		selector += fmt.Sprintf("%s=%s", label, value)
+	}
+	return selector
+}
@@ -32,10 +32,22 @@ func (c *FakeHobbyfarmV1) AccessCodes(namespace string) v1.AccessCodeInterface {
 	return &FakeAccessCodes{c, namespace}
 }
 
+func (c *FakeHobbyfarmV1) Certificates(namespace string) v1.CertificateInterface {
+	return &FakeCertificates{c, namespace}
+}
+
+func (c *FakeHobbyfarmV1) ContentSources(namespace string) v1.ContentSourceInterface {
+	return &FakeContentSources{c, namespace}
+}
+
 func (c *FakeHobbyfarmV1) Courses(namespace string) v1.CourseInterface {
 	return &FakeCourses{c, namespace}
 }
 
+func (c *FakeHobbyfarmV1) CourseRevisions(namespace string) v1.CourseRevisionInterface {
+	return &FakeCourseRevisions{c, namespace}
+}
+
 func (c *FakeHobbyfarmV1) DynamicBindConfigurations(namespace string) v1.DynamicBindConfigurationInterface {
 	return &FakeDynamicBindConfigurations{c, namespace}
 }
@@ -44,6 +56,18 @@ func (c *FakeHobbyfarmV1) Environments(namespace string) v1.EnvironmentInterface
 	return &FakeEnvironments{c, namespace}
 }
 
+func (c *FakeHobbyfarmV1) LTILaunches(namespace string) v1.LTILaunchInterface {
+	return &FakeLTILaunches{c, namespace}
+}
+
+func (c *FakeHobbyfarmV1) LTIPlatforms(namespace string) v1.LTIPlatformInterface {
+	return &FakeLTIPlatforms{c, namespace}
+}
+
+func (c *FakeHobbyfarmV1) LearningRecords(namespace string) v1.LearningRecordInterface {
+	return &FakeLearningRecords{c, namespace}
+}
+
 func (c *FakeHobbyfarmV1) OneTimeAccessCodes(namespace string) v1.OneTimeAccessCodeInterface {
 	return &FakeOneTimeAccessCodes{c, namespace}
 }
@@ -60,10 +84,18 @@ func (c *FakeHobbyfarmV1) Scenarios(namespace string) v1.ScenarioInterface {
 	return &FakeScenarios{c, namespace}
 }
 
+func (c *FakeHobbyfarmV1) ScenarioRevisions(namespace string) v1.ScenarioRevisionInterface {
+	return &FakeScenarioRevisions{c, namespace}
+}
+
 func (c *FakeHobbyfarmV1) ScheduledEvents(namespace string) v1.ScheduledEventInterface {
 	return &FakeScheduledEvents{c, namespace}
 }
 
+func (c *FakeHobbyfarmV1) ScheduledEventSerieses(namespace string) v1.ScheduledEventSeriesInterface {
+	return &FakeScheduledEventSerieses{c, namespace}
+}
+
 func (c *FakeHobbyfarmV1) Scopes(namespace string) v1.ScopeInterface {
 	return &FakeScopes{c, namespace}
 }
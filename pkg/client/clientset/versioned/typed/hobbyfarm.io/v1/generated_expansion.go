@@ -20,12 +20,24 @@ package v1
 
 type AccessCodeExpansion interface{}
 
+type CertificateExpansion interface{}
+
+type ContentSourceExpansion interface{}
+
 type CourseExpansion interface{}
 
+type CourseRevisionExpansion interface{}
+
 type DynamicBindConfigurationExpansion interface{}
 
 type EnvironmentExpansion interface{}
 
+type LTILaunchExpansion interface{}
+
+type LTIPlatformExpansion interface{}
+
+type LearningRecordExpansion interface{}
+
 type OneTimeAccessCodeExpansion interface{}
 
 type PredefinedServiceExpansion interface{}
@@ -34,8 +46,12 @@ type ProgressExpansion interface{}
 
 type ScenarioExpansion interface{}
 
+type ScenarioRevisionExpansion interface{}
+
 type ScheduledEventExpansion interface{}
 
+type ScheduledEventSeriesExpansion interface{}
+
 type ScopeExpansion interface{}
 
 type SessionExpansion interface{}
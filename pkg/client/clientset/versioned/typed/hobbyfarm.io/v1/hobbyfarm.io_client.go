@@ -29,14 +29,22 @@ import (
 type HobbyfarmV1Interface interface {
 	RESTClient() rest.Interface
 	AccessCodesGetter
+	CertificatesGetter
+	ContentSourcesGetter
 	CoursesGetter
+	CourseRevisionsGetter
 	DynamicBindConfigurationsGetter
 	EnvironmentsGetter
+	LTILaunchesGetter
+	LTIPlatformsGetter
+	LearningRecordsGetter
 	OneTimeAccessCodesGetter
 	PredefinedServicesGetter
 	ProgressesGetter
 	ScenariosGetter
+	ScenarioRevisionsGetter
 	ScheduledEventsGetter
+	ScheduledEventSeriesesGetter
 	ScopesGetter
 	SessionsGetter
 	SettingsGetter
@@ -56,10 +64,22 @@ func (c *HobbyfarmV1Client) AccessCodes(namespace string) AccessCodeInterface {
 	return newAccessCodes(c, namespace)
 }
 
+func (c *HobbyfarmV1Client) Certificates(namespace string) CertificateInterface {
+	return newCertificates(c, namespace)
+}
+
+func (c *HobbyfarmV1Client) ContentSources(namespace string) ContentSourceInterface {
+	return newContentSources(c, namespace)
+}
+
 func (c *HobbyfarmV1Client) Courses(namespace string) CourseInterface {
 	return newCourses(c, namespace)
 }
 
+func (c *HobbyfarmV1Client) CourseRevisions(namespace string) CourseRevisionInterface {
+	return newCourseRevisions(c, namespace)
+}
+
 func (c *HobbyfarmV1Client) DynamicBindConfigurations(namespace string) DynamicBindConfigurationInterface {
 	return newDynamicBindConfigurations(c, namespace)
 }
@@ -68,6 +88,18 @@ func (c *HobbyfarmV1Client) Environments(namespace string) EnvironmentInterface
 	return newEnvironments(c, namespace)
 }
 
+func (c *HobbyfarmV1Client) LTILaunches(namespace string) LTILaunchInterface {
+	return newLTILaunches(c, namespace)
+}
+
+func (c *HobbyfarmV1Client) LTIPlatforms(namespace string) LTIPlatformInterface {
+	return newLTIPlatforms(c, namespace)
+}
+
+func (c *HobbyfarmV1Client) LearningRecords(namespace string) LearningRecordInterface {
+	return newLearningRecords(c, namespace)
+}
+
 func (c *HobbyfarmV1Client) OneTimeAccessCodes(namespace string) OneTimeAccessCodeInterface {
 	return newOneTimeAccessCodes(c, namespace)
 }
@@ -84,10 +116,18 @@ func (c *HobbyfarmV1Client) Scenarios(namespace string) ScenarioInterface {
 	return newScenarios(c, namespace)
 }
 
+func (c *HobbyfarmV1Client) ScenarioRevisions(namespace string) ScenarioRevisionInterface {
+	return newScenarioRevisions(c, namespace)
+}
+
 func (c *HobbyfarmV1Client) ScheduledEvents(namespace string) ScheduledEventInterface {
 	return newScheduledEvents(c, namespace)
 }
 
+func (c *HobbyfarmV1Client) ScheduledEventSerieses(namespace string) ScheduledEventSeriesInterface {
+	return newScheduledEventSerieses(c, namespace)
+}
+
 func (c *HobbyfarmV1Client) Scopes(namespace string) ScopeInterface {
 	return newScopes(c, namespace)
 }
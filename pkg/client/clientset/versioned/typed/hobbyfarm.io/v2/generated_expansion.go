@@ -18,4 +18,8 @@ limitations under the License.
 
 package v2
 
+type ProgressExpansion interface{}
+
+type ScheduledEventExpansion interface{}
+
 type UserExpansion interface{}
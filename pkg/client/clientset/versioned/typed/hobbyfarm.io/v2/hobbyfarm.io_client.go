@@ -28,6 +28,8 @@ import (
 
 type HobbyfarmV2Interface interface {
 	RESTClient() rest.Interface
+	ProgressesGetter
+	ScheduledEventsGetter
 	UsersGetter
 }
 
@@ -36,6 +38,14 @@ type HobbyfarmV2Client struct {
 	restClient rest.Interface
 }
 
+func (c *HobbyfarmV2Client) Progresses(namespace string) ProgressInterface {
+	return newProgresses(c, namespace)
+}
+
+func (c *HobbyfarmV2Client) ScheduledEvents(namespace string) ScheduledEventInterface {
+	return newScheduledEvents(c, namespace)
+}
+
 func (c *HobbyfarmV2Client) Users(namespace string) UserInterface {
 	return newUsers(c, namespace)
 }
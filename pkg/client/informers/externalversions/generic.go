@@ -57,12 +57,24 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=hobbyfarm.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("accesscodes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().AccessCodes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("certificates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Certificates().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("contentsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().ContentSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("courses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Courses().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("courserevisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().CourseRevisions().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("dynamicbindconfigurations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().DynamicBindConfigurations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("environments"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Environments().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ltilaunches"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().LTILaunches().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ltiplatforms"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().LTIPlatforms().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("learningrecords"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().LearningRecords().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("onetimeaccesscodes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().OneTimeAccessCodes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("predefinedservices"):
@@ -71,8 +83,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Progresses().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("scenarios"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Scenarios().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("scenariorevisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().ScenarioRevisions().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("scheduledevents"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().ScheduledEvents().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("scheduledeventseries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().ScheduledEventSerieses().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("scopes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().Scopes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("sessions"):
@@ -91,6 +107,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V1().VirtualMachineTemplates().Informer()}, nil
 
 		// Group=hobbyfarm.io, Version=v2
+	case v2.SchemeGroupVersion.WithResource("progresses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V2().Progresses().Informer()}, nil
+	case v2.SchemeGroupVersion.WithResource("scheduledevents"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V2().ScheduledEvents().Informer()}, nil
 	case v2.SchemeGroupVersion.WithResource("users"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Hobbyfarm().V2().Users().Informer()}, nil
 
@@ -26,12 +26,24 @@ import (
 type Interface interface {
 	// AccessCodes returns a AccessCodeInformer.
 	AccessCodes() AccessCodeInformer
+	// Certificates returns a CertificateInformer.
+	Certificates() CertificateInformer
+	// ContentSources returns a ContentSourceInformer.
+	ContentSources() ContentSourceInformer
 	// Courses returns a CourseInformer.
 	Courses() CourseInformer
+	// CourseRevisions returns a CourseRevisionInformer.
+	CourseRevisions() CourseRevisionInformer
 	// DynamicBindConfigurations returns a DynamicBindConfigurationInformer.
 	DynamicBindConfigurations() DynamicBindConfigurationInformer
 	// Environments returns a EnvironmentInformer.
 	Environments() EnvironmentInformer
+	// LTILaunches returns a LTILaunchInformer.
+	LTILaunches() LTILaunchInformer
+	// LTIPlatforms returns a LTIPlatformInformer.
+	LTIPlatforms() LTIPlatformInformer
+	// LearningRecords returns a LearningRecordInformer.
+	LearningRecords() LearningRecordInformer
 	// OneTimeAccessCodes returns a OneTimeAccessCodeInformer.
 	OneTimeAccessCodes() OneTimeAccessCodeInformer
 	// PredefinedServices returns a PredefinedServiceInformer.
@@ -40,8 +52,12 @@ type Interface interface {
 	Progresses() ProgressInformer
 	// Scenarios returns a ScenarioInformer.
 	Scenarios() ScenarioInformer
+	// ScenarioRevisions returns a ScenarioRevisionInformer.
+	ScenarioRevisions() ScenarioRevisionInformer
 	// ScheduledEvents returns a ScheduledEventInformer.
 	ScheduledEvents() ScheduledEventInformer
+	// ScheduledEventSerieses returns a ScheduledEventSeriesInformer.
+	ScheduledEventSerieses() ScheduledEventSeriesInformer
 	// Scopes returns a ScopeInformer.
 	Scopes() ScopeInformer
 	// Sessions returns a SessionInformer.
@@ -76,11 +92,26 @@ func (v *version) AccessCodes() AccessCodeInformer {
 	return &accessCodeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// Certificates returns a CertificateInformer.
+func (v *version) Certificates() CertificateInformer {
+	return &certificateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ContentSources returns a ContentSourceInformer.
+func (v *version) ContentSources() ContentSourceInformer {
+	return &contentSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Courses returns a CourseInformer.
 func (v *version) Courses() CourseInformer {
 	return &courseInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CourseRevisions returns a CourseRevisionInformer.
+func (v *version) CourseRevisions() CourseRevisionInformer {
+	return &courseRevisionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DynamicBindConfigurations returns a DynamicBindConfigurationInformer.
 func (v *version) DynamicBindConfigurations() DynamicBindConfigurationInformer {
 	return &dynamicBindConfigurationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -91,6 +122,21 @@ func (v *version) Environments() EnvironmentInformer {
 	return &environmentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// LTILaunches returns a LTILaunchInformer.
+func (v *version) LTILaunches() LTILaunchInformer {
+	return &lTILaunchInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// LTIPlatforms returns a LTIPlatformInformer.
+func (v *version) LTIPlatforms() LTIPlatformInformer {
+	return &lTIPlatformInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// LearningRecords returns a LearningRecordInformer.
+func (v *version) LearningRecords() LearningRecordInformer {
+	return &learningRecordInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // OneTimeAccessCodes returns a OneTimeAccessCodeInformer.
 func (v *version) OneTimeAccessCodes() OneTimeAccessCodeInformer {
 	return &oneTimeAccessCodeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -111,11 +157,21 @@ func (v *version) Scenarios() ScenarioInformer {
 	return &scenarioInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ScenarioRevisions returns a ScenarioRevisionInformer.
+func (v *version) ScenarioRevisions() ScenarioRevisionInformer {
+	return &scenarioRevisionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ScheduledEvents returns a ScheduledEventInformer.
 func (v *version) ScheduledEvents() ScheduledEventInformer {
 	return &scheduledEventInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ScheduledEventSerieses returns a ScheduledEventSeriesInformer.
+func (v *version) ScheduledEventSerieses() ScheduledEventSeriesInformer {
+	return &scheduledEventSeriesInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Scopes returns a ScopeInformer.
 func (v *version) Scopes() ScopeInformer {
 	return &scopeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
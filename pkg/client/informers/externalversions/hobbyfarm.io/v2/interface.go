@@ -24,6 +24,10 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Progresses returns a ProgressInformer.
+	Progresses() ProgressInformer
+	// ScheduledEvents returns a ScheduledEventInformer.
+	ScheduledEvents() ScheduledEventInformer
 	// Users returns a UserInformer.
 	Users() UserInformer
 }
@@ -39,6 +43,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Progresses returns a ProgressInformer.
+func (v *version) Progresses() ProgressInformer {
+	return &progressInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ScheduledEvents returns a ScheduledEventInformer.
+func (v *version) ScheduledEvents() ScheduledEventInformer {
+	return &scheduledEventInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Users returns a UserInformer.
 func (v *version) Users() UserInformer {
 	return &userInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
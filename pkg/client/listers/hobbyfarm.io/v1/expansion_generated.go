@@ -26,6 +26,22 @@ type AccessCodeListerExpansion interface{}
 // AccessCodeNamespaceLister.
 type AccessCodeNamespaceListerExpansion interface{}
 
+// CertificateListerExpansion allows custom methods to be added to
+// CertificateLister.
+type CertificateListerExpansion interface{}
+
+// CertificateNamespaceListerExpansion allows custom methods to be added to
+// CertificateNamespaceLister.
+type CertificateNamespaceListerExpansion interface{}
+
+// ContentSourceListerExpansion allows custom methods to be added to
+// ContentSourceLister.
+type ContentSourceListerExpansion interface{}
+
+// ContentSourceNamespaceListerExpansion allows custom methods to be added to
+// ContentSourceNamespaceLister.
+type ContentSourceNamespaceListerExpansion interface{}
+
 // CourseListerExpansion allows custom methods to be added to
 // CourseLister.
 type CourseListerExpansion interface{}
@@ -34,6 +50,14 @@ type CourseListerExpansion interface{}
 // CourseNamespaceLister.
 type CourseNamespaceListerExpansion interface{}
 
+// CourseRevisionListerExpansion allows custom methods to be added to
+// CourseRevisionLister.
+type CourseRevisionListerExpansion interface{}
+
+// CourseRevisionNamespaceListerExpansion allows custom methods to be added to
+// CourseRevisionNamespaceLister.
+type CourseRevisionNamespaceListerExpansion interface{}
+
 // DynamicBindConfigurationListerExpansion allows custom methods to be added to
 // DynamicBindConfigurationLister.
 type DynamicBindConfigurationListerExpansion interface{}
@@ -50,6 +74,30 @@ type EnvironmentListerExpansion interface{}
 // EnvironmentNamespaceLister.
 type EnvironmentNamespaceListerExpansion interface{}
 
+// LTILaunchListerExpansion allows custom methods to be added to
+// LTILaunchLister.
+type LTILaunchListerExpansion interface{}
+
+// LTILaunchNamespaceListerExpansion allows custom methods to be added to
+// LTILaunchNamespaceLister.
+type LTILaunchNamespaceListerExpansion interface{}
+
+// LTIPlatformListerExpansion allows custom methods to be added to
+// LTIPlatformLister.
+type LTIPlatformListerExpansion interface{}
+
+// LTIPlatformNamespaceListerExpansion allows custom methods to be added to
+// LTIPlatformNamespaceLister.
+type LTIPlatformNamespaceListerExpansion interface{}
+
+// LearningRecordListerExpansion allows custom methods to be added to
+// LearningRecordLister.
+type LearningRecordListerExpansion interface{}
+
+// LearningRecordNamespaceListerExpansion allows custom methods to be added to
+// LearningRecordNamespaceLister.
+type LearningRecordNamespaceListerExpansion interface{}
+
 // OneTimeAccessCodeListerExpansion allows custom methods to be added to
 // OneTimeAccessCodeLister.
 type OneTimeAccessCodeListerExpansion interface{}
@@ -82,6 +130,14 @@ type ScenarioListerExpansion interface{}
 // ScenarioNamespaceLister.
 type ScenarioNamespaceListerExpansion interface{}
 
+// ScenarioRevisionListerExpansion allows custom methods to be added to
+// ScenarioRevisionLister.
+type ScenarioRevisionListerExpansion interface{}
+
+// ScenarioRevisionNamespaceListerExpansion allows custom methods to be added to
+// ScenarioRevisionNamespaceLister.
+type ScenarioRevisionNamespaceListerExpansion interface{}
+
 // ScheduledEventListerExpansion allows custom methods to be added to
 // ScheduledEventLister.
 type ScheduledEventListerExpansion interface{}
@@ -90,6 +146,14 @@ type ScheduledEventListerExpansion interface{}
 // ScheduledEventNamespaceLister.
 type ScheduledEventNamespaceListerExpansion interface{}
 
+// ScheduledEventSeriesListerExpansion allows custom methods to be added to
+// ScheduledEventSeriesLister.
+type ScheduledEventSeriesListerExpansion interface{}
+
+// ScheduledEventSeriesNamespaceListerExpansion allows custom methods to be added to
+// ScheduledEventSeriesNamespaceLister.
+type ScheduledEventSeriesNamespaceListerExpansion interface{}
+
 // ScopeListerExpansion allows custom methods to be added to
 // ScopeLister.
 type ScopeListerExpansion interface{}
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v2
 
+// ProgressListerExpansion allows custom methods to be added to
+// ProgressLister.
+type ProgressListerExpansion interface{}
+
+// ProgressNamespaceListerExpansion allows custom methods to be added to
+// ProgressNamespaceLister.
+type ProgressNamespaceListerExpansion interface{}
+
+// ScheduledEventListerExpansion allows custom methods to be added to
+// ScheduledEventLister.
+type ScheduledEventListerExpansion interface{}
+
+// ScheduledEventNamespaceListerExpansion allows custom methods to be added to
+// ScheduledEventNamespaceLister.
+type ScheduledEventNamespaceListerExpansion interface{}
+
 // UserListerExpansion allows custom methods to be added to
 // UserLister.
 type UserListerExpansion interface{}
@@ -0,0 +1,125 @@
+package completion
+
+import (
+	"fmt"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+// ScenarioCompleted returns true once the last step of a scenario was reached
+func ScenarioCompleted(p hfv2.ProgressSpec) bool {
+	return p.TotalStep > 0 && p.MaxStep >= p.TotalStep-1
+}
+
+// CompletedScenarios returns the scenarios of a course that were completed within that course
+func CompletedScenarios(progress []hfv2.Progress, course string) map[string]bool {
+	completed := map[string]bool{}
+	for _, p := range progress {
+		if p.Spec.Course == course && ScenarioCompleted(p.Spec) {
+			completed[p.Spec.Scenario] = true
+		}
+	}
+	return completed
+}
+
+// CourseCompleted returns true if every scenario of a course was completed
+func CourseCompleted(course hfv1.CourseSpec, completed map[string]bool) bool {
+	if len(course.Scenarios) == 0 {
+		return false
+	}
+	for _, s := range course.Scenarios {
+		if !completed[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// Required returns the scenarios that have to be completed before a scenario of a course may be started.
+// With the strict sequence, scenarios that are not listed, like the ones added by category, come after all listed ones.
+func Required(course hfv1.CourseSpec, scenario string) []string {
+	switch course.Sequence {
+	case hfv1.CourseSequenceStrict:
+		required := []string{}
+		for _, s := range course.Scenarios {
+			if s == scenario {
+				return required
+			}
+			required = append(required, s)
+		}
+		return required
+	case hfv1.CourseSequencePrerequisites:
+		return course.Prerequisites[scenario]
+	}
+	return nil
+}
+
+// Missing returns the required scenarios that were not completed yet
+func Missing(course hfv1.CourseSpec, scenario string, completed map[string]bool) []string {
+	missing := []string{}
+	for _, s := range Required(course, scenario) {
+		if !completed[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// Validate checks that the sequence of a course refers to its scenarios and can be completed
+func Validate(course hfv1.CourseSpec) error {
+	switch course.Sequence {
+	case hfv1.CourseSequenceAny, hfv1.CourseSequenceStrict:
+		if len(course.Prerequisites) > 0 {
+			return fmt.Errorf("prerequisites are only used with the %s sequence", hfv1.CourseSequencePrerequisites)
+		}
+		return nil
+	case hfv1.CourseSequencePrerequisites:
+	default:
+		return fmt.Errorf("unknown sequence %q", course.Sequence)
+	}
+
+	for scenario, required := range course.Prerequisites {
+		if !util.StringInSlice(scenario, course.Scenarios) {
+			return fmt.Errorf("scenario %s has prerequisites but is not part of the course", scenario)
+		}
+		for _, r := range required {
+			if r == scenario {
+				return fmt.Errorf("scenario %s requires itself", scenario)
+			}
+			if !util.StringInSlice(r, course.Scenarios) {
+				return fmt.Errorf("prerequisite %s of scenario %s is not part of the course", r, scenario)
+			}
+		}
+	}
+
+	// every scenario has to be reachable, prerequisites must not form a cycle
+	visiting := map[string]bool{}
+	done := map[string]bool{}
+	var visit func(scenario string) error
+	visit = func(scenario string) error {
+		if done[scenario] {
+			return nil
+		}
+		if visiting[scenario] {
+			return fmt.Errorf("prerequisites of scenario %s form a cycle", scenario)
+		}
+		visiting[scenario] = true
+		for _, r := range course.Prerequisites[scenario] {
+			if err := visit(r); err != nil {
+				return err
+			}
+		}
+		visiting[scenario] = false
+		done[scenario] = true
+		return nil
+	}
+	for _, s := range course.Scenarios {
+		if err := visit(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package completion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+)
+
+func Test_Validate(t *testing.T) {
+	scenarios := []string{"intro", "install", "operate"}
+
+	tests := []struct {
+		name          string
+		sequence      hfv1.CourseSequence
+		prerequisites map[string][]string
+		wantErr       string // part of the error, empty if the course is valid
+	}{
+		{"any order", hfv1.CourseSequenceAny, nil, ""},
+		{"strict order", hfv1.CourseSequenceStrict, nil, ""},
+		{"prerequisites", hfv1.CourseSequencePrerequisites, map[string][]string{"install": {"intro"}, "operate": {"intro", "install"}}, ""},
+		{"prerequisites without the sequence", hfv1.CourseSequenceStrict, map[string][]string{"install": {"intro"}}, "only used with"},
+		{"unknown sequence", "random", nil, "unknown sequence"},
+		{"scenario outside of the course", hfv1.CourseSequencePrerequisites, map[string][]string{"cleanup": {"intro"}}, "not part of the course"},
+		{"prerequisite outside of the course", hfv1.CourseSequencePrerequisites, map[string][]string{"install": {"basics"}}, "prerequisite basics"},
+		{"scenario requiring itself", hfv1.CourseSequencePrerequisites, map[string][]string{"intro": {"intro"}}, "requires itself"},
+		{"cycle", hfv1.CourseSequencePrerequisites, map[string][]string{"intro": {"operate"}, "install": {"intro"}, "operate": {"install"}}, "form a cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(hfv1.CourseSpec{Scenarios: scenarios, Sequence: tt.sequence, Prerequisites: tt.prerequisites})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Missing(t *testing.T) {
+	strict := hfv1.CourseSpec{Scenarios: []string{"intro", "install", "operate"}, Sequence: hfv1.CourseSequenceStrict}
+	prerequisites := hfv1.CourseSpec{
+		Scenarios:     []string{"intro", "install", "operate"},
+		Sequence:      hfv1.CourseSequencePrerequisites,
+		Prerequisites: map[string][]string{"operate": {"install"}},
+	}
+	completed := map[string]bool{"intro": true}
+
+	tests := []struct {
+		name     string
+		course   hfv1.CourseSpec
+		scenario string
+		want     []string
+	}{
+		{"first scenario of a strict course", strict, "intro", []string{}},
+		{"strict course", strict, "operate", []string{"install"}},
+		{"scenario added by category comes last", strict, "extra", []string{"install", "operate"}},
+		{"scenario without prerequisites", prerequisites, "install", []string{}},
+		{"prerequisites", prerequisites, "operate", []string{"install"}},
+		{"any order", hfv1.CourseSpec{Scenarios: strict.Scenarios}, "operate", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Missing(tt.course, tt.scenario, completed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Missing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CourseCompleted(t *testing.T) {
+	progress := []hfv2.Progress{
+		{Spec: hfv2.ProgressSpec{Course: "course-1", Scenario: "intro", MaxStep: 2, TotalStep: 3}},
+		{Spec: hfv2.ProgressSpec{Course: "course-1", Scenario: "install", MaxStep: 1, TotalStep: 3}},
+		// completed outside of the course, it does not count
+		{Spec: hfv2.ProgressSpec{Scenario: "install", MaxStep: 2, TotalStep: 3}},
+	}
+	course := hfv1.CourseSpec{Scenarios: []string{"intro", "install"}}
+
+	completed := CompletedScenarios(progress, "course-1")
+	if !completed["intro"] || completed["install"] {
+		t.Errorf("CompletedScenarios() = %v, want only intro", completed)
+	}
+	if CourseCompleted(course, completed) {
+		t.Error("CourseCompleted() = true with install missing")
+	}
+
+	progress = append(progress, hfv2.Progress{Spec: hfv2.ProgressSpec{Course: "course-1", Scenario: "install", MaxStep: 2, TotalStep: 3}})
+	if !CourseCompleted(course, CompletedScenarios(progress, "course-1")) {
+		t.Error("CourseCompleted() = false after every scenario was completed")
+	}
+	if CourseCompleted(hfv1.CourseSpec{}, completed) {
+		t.Error("CourseCompleted() = true for a course without scenarios")
+	}
+}
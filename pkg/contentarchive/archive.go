@@ -0,0 +1,388 @@
+// Package contentarchive exports content into a portable archive and imports it into another cluster.
+//
+// Objects keep their ids in an archive. Ids are hash or random based when content is created through the
+// api, so the same content created in two clusters ends up with different ids. An archive is therefore
+// matched against a cluster by id only: importing it again finds the objects created by the first import.
+// Every imported object records the id it had in the archive in the hobbyfarm.io/origin annotation.
+//
+// Secrets are never exported. Environment specifics that look like credentials are emptied and listed in
+// Redacted, importing over an existing environment keeps the values it already has for them.
+package contentarchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ArchiveKind    = "HobbyfarmArchive"
+	ArchiveVersion = "v1"
+
+	OriginAnnotation = "hobbyfarm.io/origin"
+
+	KindScenario               = "scenarios"
+	KindCourse                 = "courses"
+	KindVirtualMachineTemplate = "virtualmachinetemplates"
+	KindPredefinedService      = "predefinedservices"
+	KindEnvironment            = "environments"
+)
+
+// sensitiveKeys mark environment specifics that hold credentials rather than references to them
+var sensitiveKeys = []string{"password", "token", "access_key", "secret_key", "private_key", "api_key", "apikey"}
+
+// Archive is the versioned export format. Texts of scenarios and courses stay base64 encoded, as they are stored.
+type Archive struct {
+	Kind                    string                   `json:"kind"`
+	Version                 string                   `json:"version"`
+	Exported                string                   `json:"exported"`
+	Scenarios               []Scenario               `json:"scenarios"`
+	Courses                 []Course                 `json:"courses"`
+	VirtualMachineTemplates []VirtualMachineTemplate `json:"virtualmachinetemplates"`
+	PredefinedServices      []PredefinedService      `json:"predefinedservices"`
+	Environments            []Environment            `json:"environments"`
+	Redacted                []string                 `json:"redacted,omitempty"` // <environment>/<key> of every emptied environment specific
+}
+
+type Scenario struct {
+	ID   string            `json:"id"`
+	Spec hfv1.ScenarioSpec `json:"spec"`
+}
+
+type Course struct {
+	ID   string          `json:"id"`
+	Spec hfv1.CourseSpec `json:"spec"`
+}
+
+type VirtualMachineTemplate struct {
+	ID   string                          `json:"id"`
+	Spec hfv1.VirtualMachineTemplateSpec `json:"spec"`
+}
+
+type PredefinedService struct {
+	ID   string           `json:"id"`
+	Spec hfv1.ServiceSpec `json:"spec"`
+}
+
+type Environment struct {
+	ID   string               `json:"id"`
+	Spec hfv1.EnvironmentSpec `json:"spec"`
+}
+
+// Selection names the objects to export. All exports everything, regardless of the ids given.
+type Selection struct {
+	All                     bool
+	Scenarios               []string
+	Courses                 []string
+	VirtualMachineTemplates []string
+	PredefinedServices      []string
+	Environments            []string
+}
+
+// Empty returns true if nothing is selected
+func (s Selection) Empty() bool {
+	return !s.All && len(s.Scenarios) == 0 && len(s.Courses) == 0 && len(s.VirtualMachineTemplates) == 0 &&
+		len(s.PredefinedServices) == 0 && len(s.Environments) == 0
+}
+
+// Kinds returns the kinds of objects contained in an archive
+func (a *Archive) Kinds() []string {
+	kinds := []string{}
+	if len(a.Scenarios) > 0 {
+		kinds = append(kinds, KindScenario)
+	}
+	if len(a.Courses) > 0 {
+		kinds = append(kinds, KindCourse)
+	}
+	if len(a.VirtualMachineTemplates) > 0 {
+		kinds = append(kinds, KindVirtualMachineTemplate)
+	}
+	if len(a.PredefinedServices) > 0 {
+		kinds = append(kinds, KindPredefinedService)
+	}
+	if len(a.Environments) > 0 {
+		kinds = append(kinds, KindEnvironment)
+	}
+	return kinds
+}
+
+// Decode reads an archive and checks that this version of hobbyfarm understands it
+func Decode(raw []byte) (*Archive, error) {
+	archive := &Archive{}
+	if err := json.Unmarshal(raw, archive); err != nil {
+		return nil, fmt.Errorf("invalid archive: %v", err)
+	}
+	if archive.Kind != ArchiveKind {
+		return nil, fmt.Errorf("not a hobbyfarm archive")
+	}
+	if archive.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %s, expected %s", archive.Version, ArchiveVersion)
+	}
+	return archive, nil
+}
+
+// Export collects the selected objects. With dependencies, the scenarios of selected courses and the
+// vm templates used by selected scenarios, courses and environments are exported as well.
+func Export(ctx context.Context, hfClientSet hfClientset.Interface, selection Selection, dependencies bool) (*Archive, error) {
+	e := exporter{
+		ctx:         ctx,
+		hfClientSet: hfClientSet,
+		archive: &Archive{
+			Kind:                    ArchiveKind,
+			Version:                 ArchiveVersion,
+			Exported:                time.Now().Format(time.UnixDate),
+			Scenarios:               []Scenario{},
+			Courses:                 []Course{},
+			VirtualMachineTemplates: []VirtualMachineTemplate{},
+			PredefinedServices:      []PredefinedService{},
+			Environments:            []Environment{},
+		},
+		seen: map[string]bool{},
+	}
+
+	if selection.All {
+		var err error
+		if selection, err = e.everything(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range selection.Courses {
+		if err := e.course(id, dependencies); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range selection.Scenarios {
+		if err := e.scenario(id, dependencies); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range selection.Environments {
+		if err := e.environment(id, dependencies); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range selection.VirtualMachineTemplates {
+		if err := e.vmTemplate(id); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range selection.PredefinedServices {
+		if err := e.predefinedService(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.archive, nil
+}
+
+type exporter struct {
+	ctx         context.Context
+	hfClientSet hfClientset.Interface
+	archive     *Archive
+	seen        map[string]bool
+}
+
+// first returns true the first time it is called for an object
+func (e *exporter) first(kind string, id string) bool {
+	key := kind + "/" + id
+	if e.seen[key] {
+		return false
+	}
+	e.seen[key] = true
+	return true
+}
+
+func (e *exporter) everything() (Selection, error) {
+	selection := Selection{}
+	ns := util.GetReleaseNamespace()
+
+	scenarios, err := e.hfClientSet.HobbyfarmV1().Scenarios(ns).List(e.ctx, metav1.ListOptions{})
+	if err != nil {
+		return selection, fmt.Errorf("error listing scenarios: %v", err)
+	}
+	for _, s := range scenarios.Items {
+		selection.Scenarios = append(selection.Scenarios, s.Name)
+	}
+
+	courses, err := e.hfClientSet.HobbyfarmV1().Courses(ns).List(e.ctx, metav1.ListOptions{})
+	if err != nil {
+		return selection, fmt.Errorf("error listing courses: %v", err)
+	}
+	for _, c := range courses.Items {
+		selection.Courses = append(selection.Courses, c.Name)
+	}
+
+	vmts, err := e.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(ns).List(e.ctx, metav1.ListOptions{})
+	if err != nil {
+		return selection, fmt.Errorf("error listing vm templates: %v", err)
+	}
+	for _, vmt := range vmts.Items {
+		selection.VirtualMachineTemplates = append(selection.VirtualMachineTemplates, vmt.Name)
+	}
+
+	services, err := e.hfClientSet.HobbyfarmV1().PredefinedServices(ns).List(e.ctx, metav1.ListOptions{})
+	if err != nil {
+		return selection, fmt.Errorf("error listing predefined services: %v", err)
+	}
+	for _, ps := range services.Items {
+		selection.PredefinedServices = append(selection.PredefinedServices, ps.Name)
+	}
+
+	environments, err := e.hfClientSet.HobbyfarmV1().Environments(ns).List(e.ctx, metav1.ListOptions{})
+	if err != nil {
+		return selection, fmt.Errorf("error listing environments: %v", err)
+	}
+	for _, env := range environments.Items {
+		selection.Environments = append(selection.Environments, env.Name)
+	}
+
+	return selection, nil
+}
+
+func (e *exporter) course(id string, dependencies bool) error {
+	if !e.first(KindCourse, id) {
+		return nil
+	}
+
+	course, err := e.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(e.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving course %s: %v", id, err)
+	}
+
+	spec := *course.Spec.DeepCopy()
+	spec.Revision = 0
+	e.archive.Courses = append(e.archive.Courses, Course{ID: id, Spec: spec})
+
+	if !dependencies {
+		return nil
+	}
+	for _, scenario := range spec.Scenarios {
+		if err := e.scenario(scenario, dependencies); err != nil {
+			return err
+		}
+	}
+	return e.vmTemplates(spec.VirtualMachines)
+}
+
+func (e *exporter) scenario(id string, dependencies bool) error {
+	if !e.first(KindScenario, id) {
+		return nil
+	}
+
+	scenario, err := e.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(e.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving scenario %s: %v", id, err)
+	}
+
+	spec := *scenario.Spec.DeepCopy()
+	spec.Revision = 0
+	e.archive.Scenarios = append(e.archive.Scenarios, Scenario{ID: id, Spec: spec})
+
+	if !dependencies {
+		return nil
+	}
+	return e.vmTemplates(spec.VirtualMachines)
+}
+
+func (e *exporter) environment(id string, dependencies bool) error {
+	if !e.first(KindEnvironment, id) {
+		return nil
+	}
+
+	env, err := e.hfClientSet.HobbyfarmV1().Environments(util.GetReleaseNamespace()).Get(e.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving environment %s: %v", id, err)
+	}
+
+	spec := *env.Spec.DeepCopy()
+	keys := []string{}
+	for key := range spec.EnvironmentSpecifics {
+		if sensitive(key) {
+			keys = append(keys, key)
+			spec.EnvironmentSpecifics[key] = ""
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.archive.Redacted = append(e.archive.Redacted, id+"/"+key)
+	}
+	e.archive.Environments = append(e.archive.Environments, Environment{ID: id, Spec: spec})
+
+	if !dependencies {
+		return nil
+	}
+	templates := []string{}
+	for template := range spec.TemplateMapping {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+	for _, template := range templates {
+		if err := e.vmTemplate(template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *exporter) vmTemplates(virtualMachines []map[string]string) error {
+	for _, vmSet := range virtualMachines {
+		names := []string{}
+		for name := range vmSet {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := e.vmTemplate(vmSet[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) vmTemplate(id string) error {
+	if !e.first(KindVirtualMachineTemplate, id) {
+		return nil
+	}
+
+	vmt, err := e.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(util.GetReleaseNamespace()).Get(e.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving vm template %s: %v", id, err)
+	}
+
+	e.archive.VirtualMachineTemplates = append(e.archive.VirtualMachineTemplates, VirtualMachineTemplate{ID: id, Spec: *vmt.Spec.DeepCopy()})
+	return nil
+}
+
+func (e *exporter) predefinedService(id string) error {
+	if !e.first(KindPredefinedService, id) {
+		return nil
+	}
+
+	ps, err := e.hfClientSet.HobbyfarmV1().PredefinedServices(util.GetReleaseNamespace()).Get(e.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving predefined service %s: %v", id, err)
+	}
+
+	e.archive.PredefinedServices = append(e.archive.PredefinedServices, PredefinedService{ID: id, Spec: ps.Spec})
+	return nil
+}
+
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
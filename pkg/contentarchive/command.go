@@ -0,0 +1,144 @@
+package contentarchive
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+)
+
+const (
+	CommandExport = "export"
+	CommandImport = "import"
+
+	commandCreator = "gargantua-cli"
+)
+
+// IsCommand returns true if name is one of the subcommands handled by RunCommand
+func IsCommand(name string) bool {
+	return name == CommandExport || name == CommandImport
+}
+
+// RunCommand runs the export and import subcommands of gargantua against the cluster it is configured for:
+//
+//	gargantua [flags] export [-all] [-scenarios ids] [-courses ids] [-vmtemplates ids] [-predefinedservices ids] [-environments ids] [-dependencies=false] [-o file]
+//	gargantua [flags] import [-f file] [-conflict rename|overwrite] [-dry-run]
+//
+// Ids are comma separated. Archives are written to stdout and read from stdin unless a file is given.
+func RunCommand(ctx context.Context, hfClientSet hfClientset.Interface, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || !IsCommand(args[0]) {
+		return fmt.Errorf("expected %s or %s", CommandExport, CommandImport)
+	}
+
+	if args[0] == CommandExport {
+		return runExport(ctx, hfClientSet, args[1:], stdout)
+	}
+	return runImport(ctx, hfClientSet, args[1:], stdin, stdout)
+}
+
+func runExport(ctx context.Context, hfClientSet hfClientset.Interface, args []string, stdout io.Writer) error {
+	var scenarios, courses, vmTemplates, services, environments, out string
+	selection := Selection{}
+	dependencies := true
+
+	fs := flag.NewFlagSet(CommandExport, flag.ContinueOnError)
+	fs.BoolVar(&selection.All, "all", false, "Export all content")
+	fs.StringVar(&scenarios, "scenarios", "", "Comma separated ids of scenarios to export")
+	fs.StringVar(&courses, "courses", "", "Comma separated ids of courses to export")
+	fs.StringVar(&vmTemplates, "vmtemplates", "", "Comma separated ids of vm templates to export")
+	fs.StringVar(&services, "predefinedservices", "", "Comma separated ids of predefined services to export")
+	fs.StringVar(&environments, "environments", "", "Comma separated ids of environments to export")
+	fs.BoolVar(&dependencies, "dependencies", true, "Also export the scenarios and vm templates referenced by exported content")
+	fs.StringVar(&out, "o", "", "File to write the archive to, stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	selection.Scenarios = splitIds(scenarios)
+	selection.Courses = splitIds(courses)
+	selection.VirtualMachineTemplates = splitIds(vmTemplates)
+	selection.PredefinedServices = splitIds(services)
+	selection.Environments = splitIds(environments)
+	if selection.Empty() {
+		return fmt.Errorf("nothing selected for export")
+	}
+
+	archive, err := Export(ctx, hfClientSet, selection, dependencies)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = stdout.Write(append(encoded, '\n'))
+		return err
+	}
+	return os.WriteFile(out, encoded, 0600)
+}
+
+func runImport(ctx context.Context, hfClientSet hfClientset.Interface, args []string, stdin io.Reader, stdout io.Writer) error {
+	var file, conflict string
+	opts := Options{Creator: commandCreator}
+
+	fs := flag.NewFlagSet(CommandImport, flag.ContinueOnError)
+	fs.StringVar(&file, "f", "", "Archive to import, stdin if empty")
+	fs.StringVar(&conflict, "conflict", ConflictRename, "What to do with content that already exists, rename or overwrite")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Only show what would be imported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if opts.Conflict, err = ParseConflict(conflict); err != nil {
+		return err
+	}
+
+	var raw []byte
+	if file == "" {
+		raw, err = io.ReadAll(stdin)
+	} else {
+		raw, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	archive, err := Decode(raw)
+	if err != nil {
+		return err
+	}
+
+	result, importErr := Import(ctx, hfClientSet, archive, opts)
+	if result != nil {
+		for _, w := range result.Warnings {
+			fmt.Fprintf(stdout, "warning: %s\n", w)
+		}
+		for _, a := range result.Actions {
+			fmt.Fprintf(stdout, "%s\t%s/%s\t%s\n", a.Action, a.Kind, a.ID, a.Target)
+		}
+		if result.DryRun {
+			fmt.Fprintln(stdout, "dry run, nothing was imported")
+		}
+	}
+
+	return importErr
+}
+
+func splitIds(raw string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,600 @@
+package contentarchive
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	ConflictRename    = "rename"    // objects that already exist are imported under a new id
+	ConflictOverwrite = "overwrite" // objects that already exist are replaced
+
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionRename    = "rename"
+	ActionUnchanged = "unchanged"
+
+	renameAttempts = 5
+)
+
+// Options control how an archive is imported
+type Options struct {
+	Conflict string
+	DryRun   bool
+	Creator  string // recorded in the revisions of imported scenarios and courses
+}
+
+// Action is what an import does, or would do in a dry run, with a single object
+type Action struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`     // id in the archive
+	Target string `json:"target"` // id in this cluster
+	Action string `json:"action"`
+}
+
+type Result struct {
+	DryRun   bool     `json:"dry_run"`
+	Actions  []Action `json:"actions"`
+	Warnings []string `json:"warnings"`
+}
+
+// ParseConflict reads a conflict strategy, renaming is the default since it never replaces anything
+func ParseConflict(raw string) (string, error) {
+	switch raw {
+	case "", ConflictRename:
+		return ConflictRename, nil
+	case ConflictOverwrite:
+		return ConflictOverwrite, nil
+	}
+	return "", fmt.Errorf("invalid conflict strategy %s, has to be %s or %s", raw, ConflictRename, ConflictOverwrite)
+}
+
+type planned struct {
+	action Action
+	apply  func() error
+}
+
+type importer struct {
+	ctx         context.Context
+	hfClientSet hfClientset.Interface
+	opts        Options
+
+	targets map[string]string // <kind>/<archive id> to the id in this cluster
+	plan    []planned
+}
+
+// Import plans the import of every object of an archive first, resolving references from courses to
+// scenarios and from scenarios, courses and environments to vm templates. References to objects that are
+// not part of the archive have to exist in this cluster already. Only when the whole archive can be
+// imported are the objects written, unless this is a dry run.
+func Import(ctx context.Context, hfClientSet hfClientset.Interface, archive *Archive, opts Options) (*Result, error) {
+	if opts.Conflict != ConflictRename && opts.Conflict != ConflictOverwrite {
+		return nil, fmt.Errorf("invalid conflict strategy %s", opts.Conflict)
+	}
+
+	im := &importer{
+		ctx:         ctx,
+		hfClientSet: hfClientSet,
+		opts:        opts,
+		targets:     map[string]string{},
+	}
+
+	result := &Result{
+		DryRun:   opts.DryRun,
+		Actions:  []Action{},
+		Warnings: []string{},
+	}
+	for _, redacted := range archive.Redacted {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("environment specific %s was not exported and has to be set by hand", redacted))
+	}
+
+	// vm templates come before everything referencing them, scenarios before the courses containing them
+	steps := []func(*Archive) error{
+		im.planPredefinedServices,
+		im.planVirtualMachineTemplates,
+		im.planEnvironments,
+		im.planScenarios,
+		im.planCourses,
+	}
+	for _, step := range steps {
+		if err := step(archive); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range im.plan {
+		result.Actions = append(result.Actions, p.action)
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, p := range im.plan {
+		if p.action.Action == ActionUnchanged {
+			continue
+		}
+		if err := p.apply(); err != nil {
+			return result, fmt.Errorf("error importing %s %s as %s: %v", p.action.Kind, p.action.ID, p.action.Target, err)
+		}
+	}
+
+	return result, nil
+}
+
+// decide returns the action for an archived object and records the id it ends up with
+func (im *importer) decide(kind string, id string, exists bool, unchanged bool) (Action, error) {
+	action := Action{Kind: kind, ID: id, Target: id}
+
+	switch {
+	case !exists:
+		action.Action = ActionCreate
+	case unchanged:
+		action.Action = ActionUnchanged
+	case im.opts.Conflict == ConflictOverwrite:
+		action.Action = ActionOverwrite
+	default:
+		target, err := im.rename(kind, id)
+		if err != nil {
+			return action, err
+		}
+		action.Action = ActionRename
+		action.Target = target
+	}
+
+	im.targets[kind+"/"+id] = action.Target
+	return action, nil
+}
+
+func (im *importer) rename(kind string, id string) (string, error) {
+	for i := 0; i < renameAttempts; i++ {
+		target := id + "-" + strings.ToLower(util.RandStringRunes(5))
+		exists, err := im.exists(kind, target)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return target, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find a free id for %s %s", kind, id)
+}
+
+// reference resolves a reference to another object, either to where it is imported to or to an object in this cluster
+func (im *importer) reference(kind string, id string) (string, error) {
+	if target, ok := im.targets[kind+"/"+id]; ok {
+		return target, nil
+	}
+
+	exists, err := im.exists(kind, id)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%s %s is neither part of the archive nor of this cluster", kind, id)
+	}
+	return id, nil
+}
+
+func (im *importer) exists(kind string, id string) (bool, error) {
+	ns := util.GetReleaseNamespace()
+
+	var err error
+	switch kind {
+	case KindScenario:
+		_, err = im.hfClientSet.HobbyfarmV1().Scenarios(ns).Get(im.ctx, id, metav1.GetOptions{})
+	case KindCourse:
+		_, err = im.hfClientSet.HobbyfarmV1().Courses(ns).Get(im.ctx, id, metav1.GetOptions{})
+	case KindVirtualMachineTemplate:
+		_, err = im.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(ns).Get(im.ctx, id, metav1.GetOptions{})
+	case KindPredefinedService:
+		_, err = im.hfClientSet.HobbyfarmV1().PredefinedServices(ns).Get(im.ctx, id, metav1.GetOptions{})
+	case KindEnvironment:
+		_, err = im.hfClientSet.HobbyfarmV1().Environments(ns).Get(im.ctx, id, metav1.GetOptions{})
+	default:
+		return false, fmt.Errorf("unknown kind %s", kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error retrieving %s %s: %v", kind, id, err)
+	}
+	return true, nil
+}
+
+func (im *importer) add(action Action, apply func() error) {
+	im.plan = append(im.plan, planned{action: action, apply: apply})
+}
+
+func (im *importer) planPredefinedServices(archive *Archive) error {
+	client := im.hfClientSet.HobbyfarmV1().PredefinedServices(util.GetReleaseNamespace())
+
+	for _, ps := range archive.PredefinedServices {
+		ps := ps
+		existing, err := client.Get(im.ctx, ps.ID, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error retrieving predefined service %s: %v", ps.ID, err)
+		}
+		exists := err == nil
+
+		action, err := im.decide(KindPredefinedService, ps.ID, exists, exists && reflect.DeepEqual(existing.Spec, ps.Spec))
+		if err != nil {
+			return err
+		}
+
+		im.add(action, func() error {
+			if action.Action == ActionOverwrite {
+				return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+					obj, err := client.Get(im.ctx, action.Target, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					obj.Spec = ps.Spec
+					annotate(&obj.ObjectMeta, ps.ID)
+					_, err = client.Update(im.ctx, obj, metav1.UpdateOptions{})
+					return err
+				})
+			}
+
+			obj := &hfv1.PredefinedService{Spec: ps.Spec}
+			obj.Name = action.Target
+			annotate(&obj.ObjectMeta, ps.ID)
+			_, err := client.Create(im.ctx, obj, metav1.CreateOptions{})
+			return err
+		})
+	}
+
+	return nil
+}
+
+func (im *importer) planVirtualMachineTemplates(archive *Archive) error {
+	client := im.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(util.GetReleaseNamespace())
+
+	for _, vmt := range archive.VirtualMachineTemplates {
+		vmt := vmt
+		existing, err := client.Get(im.ctx, vmt.ID, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error retrieving vm template %s: %v", vmt.ID, err)
+		}
+		exists := err == nil
+
+		action, err := im.decide(KindVirtualMachineTemplate, vmt.ID, exists, exists && reflect.DeepEqual(existing.Spec, vmt.Spec))
+		if err != nil {
+			return err
+		}
+
+		im.add(action, func() error {
+			if action.Action == ActionOverwrite {
+				return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+					obj, err := client.Get(im.ctx, action.Target, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					obj.Spec = vmt.Spec
+					annotate(&obj.ObjectMeta, vmt.ID)
+					_, err = client.Update(im.ctx, obj, metav1.UpdateOptions{})
+					return err
+				})
+			}
+
+			obj := &hfv1.VirtualMachineTemplate{Spec: vmt.Spec}
+			obj.Name = action.Target
+			annotate(&obj.ObjectMeta, vmt.ID)
+			_, err := client.Create(im.ctx, obj, metav1.CreateOptions{})
+			return err
+		})
+	}
+
+	return nil
+}
+
+func (im *importer) planEnvironments(archive *Archive) error {
+	client := im.hfClientSet.HobbyfarmV1().Environments(util.GetReleaseNamespace())
+
+	redacted := map[string]bool{}
+	for _, r := range archive.Redacted {
+		redacted[r] = true
+	}
+
+	for _, env := range archive.Environments {
+		env := env
+		spec := *env.Spec.DeepCopy()
+
+		if env.Spec.TemplateMapping != nil {
+			spec.TemplateMapping = make(map[string]map[string]string, len(env.Spec.TemplateMapping))
+		}
+		for template, mapping := range env.Spec.TemplateMapping {
+			target, err := im.reference(KindVirtualMachineTemplate, template)
+			if err != nil {
+				return fmt.Errorf("environment %s: %v", env.ID, err)
+			}
+			spec.TemplateMapping[target] = mapping
+		}
+		if env.Spec.CountCapacity != nil {
+			spec.CountCapacity = make(map[string]int, len(env.Spec.CountCapacity))
+		}
+		for template, count := range env.Spec.CountCapacity {
+			target, err := im.reference(KindVirtualMachineTemplate, template)
+			if err != nil {
+				return fmt.Errorf("environment %s: %v", env.ID, err)
+			}
+			spec.CountCapacity[target] = count
+		}
+
+		existing, err := client.Get(im.ctx, env.ID, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error retrieving environment %s: %v", env.ID, err)
+		}
+		exists := err == nil
+
+		// credentials were not exported, keep the ones this cluster has
+		keepRedacted := func(spec *hfv1.EnvironmentSpec, existing *hfv1.Environment) {
+			for key := range spec.EnvironmentSpecifics {
+				if redacted[env.ID+"/"+key] {
+					spec.EnvironmentSpecifics[key] = existing.Spec.EnvironmentSpecifics[key]
+				}
+			}
+		}
+
+		unchanged := false
+		if exists {
+			compare := *spec.DeepCopy()
+			keepRedacted(&compare, existing)
+			unchanged = reflect.DeepEqual(existing.Spec, compare)
+		}
+
+		action, err := im.decide(KindEnvironment, env.ID, exists, unchanged)
+		if err != nil {
+			return err
+		}
+
+		im.add(action, func() error {
+			if action.Action == ActionOverwrite {
+				return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+					obj, err := client.Get(im.ctx, action.Target, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					newSpec := *spec.DeepCopy()
+					keepRedacted(&newSpec, obj)
+					obj.Spec = newSpec
+					annotate(&obj.ObjectMeta, env.ID)
+					_, err = client.Update(im.ctx, obj, metav1.UpdateOptions{})
+					return err
+				})
+			}
+
+			obj := &hfv1.Environment{Spec: spec}
+			obj.Name = action.Target
+			annotate(&obj.ObjectMeta, env.ID)
+			_, err := client.Create(im.ctx, obj, metav1.CreateOptions{})
+			return err
+		})
+	}
+
+	return nil
+}
+
+func (im *importer) planScenarios(archive *Archive) error {
+	client := im.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace())
+
+	for _, s := range archive.Scenarios {
+		s := s
+		spec := *s.Spec.DeepCopy()
+
+		vms, err := im.vmReferences(spec.VirtualMachines)
+		if err != nil {
+			return fmt.Errorf("scenario %s: %v", s.ID, err)
+		}
+		spec.VirtualMachines = vms
+
+		existing, err := client.Get(im.ctx, s.ID, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error retrieving scenario %s: %v", s.ID, err)
+		}
+		exists := err == nil
+
+		unchanged := exists && !revision.ScenarioChanged(existing.Spec, spec) && existing.Spec.State == spec.State
+		action, err := im.decide(KindScenario, s.ID, exists, unchanged)
+		if err != nil {
+			return err
+		}
+
+		im.add(action, func() error {
+			if action.Action == ActionOverwrite {
+				return im.overwriteScenario(action.Target, s.ID, spec)
+			}
+
+			obj := &hfv1.Scenario{Spec: spec}
+			obj.Name = action.Target
+			obj.Spec.Revision = 1
+			annotate(&obj.ObjectMeta, s.ID)
+			created, err := client.Create(im.ctx, obj, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			return revision.CreateScenarioRevision(im.ctx, im.hfClientSet, created, im.opts.Creator)
+		})
+	}
+
+	return nil
+}
+
+func (im *importer) overwriteScenario(id string, origin string, spec hfv1.ScenarioSpec) error {
+	client := im.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scenario, err := client.Get(im.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// make sure the revision about to be replaced is recorded
+		if scenario.Spec.Revision == 0 {
+			scenario.Spec.Revision = 1
+		}
+		if err = revision.CreateScenarioRevision(im.ctx, im.hfClientSet, scenario, ""); err != nil {
+			return err
+		}
+
+		newSpec := *spec.DeepCopy()
+		newSpec.Revision = scenario.Spec.Revision
+		if revision.ScenarioChanged(scenario.Spec, newSpec) {
+			newSpec.Revision++
+		}
+		scenario.Spec = newSpec
+		annotate(&scenario.ObjectMeta, origin)
+
+		updated, err := client.Update(im.ctx, scenario, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		return revision.CreateScenarioRevision(im.ctx, im.hfClientSet, updated, im.opts.Creator)
+	})
+}
+
+func (im *importer) planCourses(archive *Archive) error {
+	client := im.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace())
+
+	for _, c := range archive.Courses {
+		c := c
+		spec := *c.Spec.DeepCopy()
+
+		vms, err := im.vmReferences(spec.VirtualMachines)
+		if err != nil {
+			return fmt.Errorf("course %s: %v", c.ID, err)
+		}
+		spec.VirtualMachines = vms
+
+		if c.Spec.Scenarios != nil {
+			spec.Scenarios = make([]string, 0, len(c.Spec.Scenarios))
+		}
+		for _, scenario := range c.Spec.Scenarios {
+			target, err := im.reference(KindScenario, scenario)
+			if err != nil {
+				return fmt.Errorf("course %s: %v", c.ID, err)
+			}
+			spec.Scenarios = append(spec.Scenarios, target)
+		}
+
+		if c.Spec.Prerequisites != nil {
+			spec.Prerequisites = make(map[string][]string, len(c.Spec.Prerequisites))
+		}
+		for scenario, required := range c.Spec.Prerequisites {
+			target, err := im.reference(KindScenario, scenario)
+			if err != nil {
+				return fmt.Errorf("course %s: %v", c.ID, err)
+			}
+			targets := make([]string, 0, len(required))
+			for _, r := range required {
+				t, err := im.reference(KindScenario, r)
+				if err != nil {
+					return fmt.Errorf("course %s: %v", c.ID, err)
+				}
+				targets = append(targets, t)
+			}
+			spec.Prerequisites[target] = targets
+		}
+
+		existing, err := client.Get(im.ctx, c.ID, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error retrieving course %s: %v", c.ID, err)
+		}
+		exists := err == nil
+
+		unchanged := exists && !revision.CourseChanged(existing.Spec, spec) && existing.Spec.State == spec.State
+		action, err := im.decide(KindCourse, c.ID, exists, unchanged)
+		if err != nil {
+			return err
+		}
+
+		im.add(action, func() error {
+			if action.Action == ActionOverwrite {
+				return im.overwriteCourse(action.Target, c.ID, spec)
+			}
+
+			obj := &hfv1.Course{Spec: spec}
+			obj.Name = action.Target
+			obj.Spec.Revision = 1
+			annotate(&obj.ObjectMeta, c.ID)
+			created, err := client.Create(im.ctx, obj, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			return revision.CreateCourseRevision(im.ctx, im.hfClientSet, created, im.opts.Creator)
+		})
+	}
+
+	return nil
+}
+
+func (im *importer) overwriteCourse(id string, origin string, spec hfv1.CourseSpec) error {
+	client := im.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		course, err := client.Get(im.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// make sure the revision about to be replaced is recorded
+		if course.Spec.Revision == 0 {
+			course.Spec.Revision = 1
+		}
+		if err = revision.CreateCourseRevision(im.ctx, im.hfClientSet, course, ""); err != nil {
+			return err
+		}
+
+		newSpec := *spec.DeepCopy()
+		newSpec.Revision = course.Spec.Revision
+		if revision.CourseChanged(course.Spec, newSpec) {
+			newSpec.Revision++
+		}
+		course.Spec = newSpec
+		annotate(&course.ObjectMeta, origin)
+
+		updated, err := client.Update(im.ctx, course, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		return revision.CreateCourseRevision(im.ctx, im.hfClientSet, updated, im.opts.Creator)
+	})
+}
+
+func (im *importer) vmReferences(virtualMachines []map[string]string) ([]map[string]string, error) {
+	if virtualMachines == nil {
+		return nil, nil
+	}
+
+	resolved := []map[string]string{}
+	for _, vmSet := range virtualMachines {
+		set := map[string]string{}
+		for name, template := range vmSet {
+			target, err := im.reference(KindVirtualMachineTemplate, template)
+			if err != nil {
+				return nil, err
+			}
+			set[name] = target
+		}
+		resolved = append(resolved, set)
+	}
+	return resolved, nil
+}
+
+func annotate(meta *metav1.ObjectMeta, origin string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[OriginAnnotation] = origin
+}
@@ -0,0 +1,258 @@
+package contentarchive
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testArchive() *Archive {
+	return &Archive{
+		Kind:    ArchiveKind,
+		Version: ArchiveVersion,
+		VirtualMachineTemplates: []VirtualMachineTemplate{
+			{ID: "vmt-ubuntu", Spec: hfv1.VirtualMachineTemplateSpec{Name: "ubuntu", Image: "ubuntu-22.04"}},
+		},
+		Environments: []Environment{
+			{ID: "env-aws", Spec: hfv1.EnvironmentSpec{
+				DisplayName:          "aws",
+				TemplateMapping:      map[string]map[string]string{"vmt-ubuntu": {"image": "ami-1"}},
+				CountCapacity:        map[string]int{"vmt-ubuntu": 10},
+				EnvironmentSpecifics: map[string]string{"region": "eu-west-1", "secret_key": ""},
+			}},
+		},
+		Scenarios: []Scenario{
+			{ID: "s-intro", Spec: hfv1.ScenarioSpec{Name: "Intro", VirtualMachines: []map[string]string{{"node": "vmt-ubuntu"}}}},
+		},
+		Courses: []Course{
+			{ID: "c-basics", Spec: hfv1.CourseSpec{
+				Name:          "Basics",
+				Scenarios:     []string{"s-intro"},
+				Prerequisites: map[string][]string{"s-intro": {}},
+			}},
+		},
+		Redacted: []string{"env-aws/secret_key"},
+	}
+}
+
+func actions(result *Result) map[string]Action {
+	byId := map[string]Action{}
+	for _, a := range result.Actions {
+		byId[a.Kind+"/"+a.ID] = a
+	}
+	return byId
+}
+
+func Test_ParseConflict(t *testing.T) {
+	for raw, want := range map[string]string{"": ConflictRename, "rename": ConflictRename, "overwrite": ConflictOverwrite} {
+		got, err := ParseConflict(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseConflict(%q) = %s, %v, want %s", raw, got, err, want)
+		}
+	}
+	if _, err := ParseConflict("skip"); err == nil {
+		t.Error("ParseConflict(skip) did not fail")
+	}
+}
+
+func Test_ImportDryRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	result, err := Import(ctx, client, testArchive(), Options{Conflict: ConflictRename, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// referenced objects are planned before the objects referencing them
+	order := []string{"virtualmachinetemplates/vmt-ubuntu", "environments/env-aws", "scenarios/s-intro", "courses/c-basics"}
+	if len(result.Actions) != len(order) {
+		t.Fatalf("planned %d actions, want %d", len(result.Actions), len(order))
+	}
+	for i, a := range result.Actions {
+		if a.Kind+"/"+a.ID != order[i] || a.Action != ActionCreate || a.Target != a.ID {
+			t.Errorf("action %d is %+v, want creating %s", i, a, order[i])
+		}
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "env-aws/secret_key") {
+		t.Errorf("warnings = %v, want one for the redacted secret key", result.Warnings)
+	}
+
+	scenarios, err := client.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios.Items) != 0 {
+		t.Errorf("dry run created %d scenarios", len(scenarios.Items))
+	}
+}
+
+func Test_ImportAndReimport(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	if _, err := Import(ctx, client, testArchive(), Options{Conflict: ConflictRename, Creator: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	scenario, err := client.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(ctx, "s-intro", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scenario.Spec.Revision != 1 || scenario.Annotations[OriginAnnotation] != "s-intro" {
+		t.Errorf("imported scenario has revision %d and origin %s", scenario.Spec.Revision, scenario.Annotations[OriginAnnotation])
+	}
+	revisions, err := revision.ListScenarioRevisions(ctx, client, "s-intro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 {
+		t.Errorf("imported scenario has %d revisions, want 1", len(revisions))
+	}
+
+	// importing the same archive again finds everything in place
+	result, err := Import(ctx, client, testArchive(), Options{Conflict: ConflictRename})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range result.Actions {
+		if a.Action != ActionUnchanged {
+			t.Errorf("reimport of %s %s is %s, want %s", a.Kind, a.ID, a.Action, ActionUnchanged)
+		}
+	}
+}
+
+func Test_ImportRename(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	existing := &hfv1.Scenario{Spec: hfv1.ScenarioSpec{Name: "Something else"}}
+	existing.Name = "s-intro"
+	if _, err := client.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Create(ctx, existing, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(ctx, client, testArchive(), Options{Conflict: ConflictRename})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := actions(result)["scenarios/s-intro"]
+	if renamed.Action != ActionRename || !strings.HasPrefix(renamed.Target, "s-intro-") {
+		t.Fatalf("scenario action is %+v, want a rename", renamed)
+	}
+
+	course, err := client.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(ctx, "c-basics", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(course.Spec.Scenarios) != 1 || course.Spec.Scenarios[0] != renamed.Target {
+		t.Errorf("course scenarios = %v, want the renamed %s", course.Spec.Scenarios, renamed.Target)
+	}
+	if _, ok := course.Spec.Prerequisites[renamed.Target]; !ok {
+		t.Errorf("course prerequisites = %v, want them keyed by the renamed %s", course.Spec.Prerequisites, renamed.Target)
+	}
+
+	untouched, err := client.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(ctx, "s-intro", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if untouched.Spec.Name != "Something else" {
+		t.Errorf("rename replaced the existing scenario with %s", untouched.Spec.Name)
+	}
+}
+
+func Test_ImportOverwrite(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	ns := util.GetReleaseNamespace()
+
+	existing := &hfv1.Scenario{Spec: hfv1.ScenarioSpec{Name: "Old intro", Revision: 3}}
+	existing.Name = "s-intro"
+	if _, err := client.HobbyfarmV1().Scenarios(ns).Create(ctx, existing, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	env := &hfv1.Environment{Spec: hfv1.EnvironmentSpec{
+		DisplayName:          "aws",
+		EnvironmentSpecifics: map[string]string{"region": "us-east-1", "secret_key": "kept"},
+	}}
+	env.Name = "env-aws"
+	if _, err := client.HobbyfarmV1().Environments(ns).Create(ctx, env, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(ctx, client, testArchive(), Options{Conflict: ConflictOverwrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byId := actions(result)
+	for _, id := range []string{"scenarios/s-intro", "environments/env-aws"} {
+		if byId[id].Action != ActionOverwrite || byId[id].Target != byId[id].ID {
+			t.Errorf("%s action is %+v, want an overwrite", id, byId[id])
+		}
+	}
+
+	scenario, err := client.HobbyfarmV1().Scenarios(ns).Get(ctx, "s-intro", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scenario.Spec.Name != "Intro" || scenario.Spec.Revision != 4 {
+		t.Errorf("overwritten scenario is %s at revision %d, want Intro at revision 4", scenario.Spec.Name, scenario.Spec.Revision)
+	}
+
+	environment, err := client.HobbyfarmV1().Environments(ns).Get(ctx, "env-aws", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if environment.Spec.EnvironmentSpecifics["region"] != "eu-west-1" || environment.Spec.EnvironmentSpecifics["secret_key"] != "kept" {
+		t.Errorf("environment specifics = %v, want the archived region and the kept secret key", environment.Spec.EnvironmentSpecifics)
+	}
+}
+
+func Test_ImportMissingReference(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	archive := testArchive()
+	archive.Courses[0].Spec.Scenarios = append(archive.Courses[0].Spec.Scenarios, "s-missing")
+
+	if _, err := Import(ctx, client, archive, Options{Conflict: ConflictRename}); err == nil || !strings.Contains(err.Error(), "s-missing") {
+		t.Fatalf("Import() error = %v, want one naming the missing scenario", err)
+	}
+
+	// nothing is written unless the whole archive can be imported
+	templates, err := client.HobbyfarmV1().VirtualMachineTemplates(util.GetReleaseNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates.Items) != 0 {
+		t.Errorf("failed import created %d vm templates", len(templates.Items))
+	}
+}
+
+func Test_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{"valid", `{"kind":"HobbyfarmArchive","version":"v1"}`, false},
+		{"not json", `scenarios: []`, true},
+		{"other kind", `{"kind":"Something","version":"v1"}`, true},
+		{"newer version", `{"kind":"HobbyfarmArchive","version":"v2"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.raw)); (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package contenttemplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	maxOutputLength = 1024 * 1024
+	delimLeft       = "{{"
+)
+
+type webinterface struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+	Path string `json:"path"`
+}
+
+// Builder collects the variables available to the content of a scenario
+type Builder struct {
+	hfClientSet hfClientset.Interface
+	ctx         context.Context
+}
+
+func NewBuilder(hfClientSet hfClientset.Interface, ctx context.Context) *Builder {
+	return &Builder{
+		hfClientSet: hfClientSet,
+		ctx:         ctx,
+	}
+}
+
+// ForSession returns the variables of a session as seen by the given user:
+//
+//	.vm.<name>.{id,public_ip,private_ip,hostname,ssh_username,webinterfaces.<name>}
+//	.user.{id,email}
+//	.session.id
+//	.scheduledevent.{id,name,description}
+func (b *Builder) ForSession(ss *hfv1.Session, user hfv2.User) (map[string]interface{}, error) {
+	vms := map[string]interface{}{}
+	for _, vmcId := range ss.Spec.VmClaimSet {
+		vmc, err := b.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(b.ctx, vmcId, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving vmclaim %s: %v", vmcId, err)
+		}
+
+		for name, claimed := range vmc.Spec.VirtualMachines {
+			if claimed.VirtualMachineId == "" {
+				continue
+			}
+
+			vm, err := b.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(b.ctx, claimed.VirtualMachineId, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving vm %s: %v", claimed.VirtualMachineId, err)
+			}
+
+			vms[name] = b.vmVariables(vm)
+		}
+	}
+
+	vars := map[string]interface{}{
+		"vm": vms,
+		"user": map[string]interface{}{
+			"id":    user.Name,
+			"email": user.Spec.Email,
+		},
+		"session": map[string]interface{}{
+			"id": ss.Name,
+		},
+	}
+
+	seId := ss.Labels[util.ScheduledEventLabel]
+	if seId != "" {
+		se, err := b.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(b.ctx, seId, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving scheduled event %s: %v", seId, err)
+		}
+
+		vars["scheduledevent"] = map[string]interface{}{
+			"id":          se.Name,
+			"name":        se.Spec.Name,
+			"description": se.Spec.Description,
+		}
+	}
+
+	return vars, nil
+}
+
+// Sample returns placeholder variables for the vms of a scenario, used to preview and check content without a session.
+// The web interfaces are those of the vm templates the scenario uses.
+func (b *Builder) Sample(scenario *hfv1.Scenario) map[string]interface{} {
+	vms := map[string]interface{}{}
+	for _, vmSet := range scenario.Spec.VirtualMachines {
+		for name, templateId := range vmSet {
+			vms[name] = map[string]interface{}{
+				"id":            placeholder(name, "id"),
+				"public_ip":     placeholder(name, "public_ip"),
+				"private_ip":    placeholder(name, "private_ip"),
+				"hostname":      placeholder(name, "hostname"),
+				"ssh_username":  placeholder(name, "ssh_username"),
+				"webinterfaces": b.webinterfaces(templateId, placeholder(name, "id")),
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"vm": vms,
+		"user": map[string]interface{}{
+			"id":    "<user.id>",
+			"email": "<user.email>",
+		},
+		"session": map[string]interface{}{
+			"id": "<session.id>",
+		},
+		"scheduledevent": map[string]interface{}{
+			"id":          "<scheduledevent.id>",
+			"name":        "<scheduledevent.name>",
+			"description": "<scheduledevent.description>",
+		},
+	}
+}
+
+// Render fills the variables into content. Only the builtin template functions are available and
+// the variables are plain maps, so templates cannot reach anything but the values they are given.
+// Referencing a variable that does not exist is an error, literal braces are written as {{"{{"}}.
+func Render(content string, vars map[string]interface{}) (string, error) {
+	if !strings.Contains(content, delimLeft) {
+		return content, nil
+	}
+
+	tmpl, err := template.New("content").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	out := &limitedBuffer{limit: maxOutputLength}
+	if err := tmpl.Execute(out, vars); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+func (b *Builder) vmVariables(vm *hfv1.VirtualMachine) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            vm.Name,
+		"public_ip":     vm.Status.PublicIP,
+		"private_ip":    vm.Status.PrivateIP,
+		"hostname":      vm.Status.Hostname,
+		"ssh_username":  vm.Spec.SshUsername,
+		"webinterfaces": b.webinterfaces(vm.Spec.VirtualMachineTemplateId, vm.Name),
+	}
+}
+
+// webinterfaces returns the paths the web interfaces of a vm template are served on for the given vm
+func (b *Builder) webinterfaces(templateId string, vmId string) map[string]interface{} {
+	webinterfaces := map[string]interface{}{}
+
+	vmt, err := b.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(util.GetReleaseNamespace()).Get(b.ctx, templateId, metav1.GetOptions{})
+	if err != nil {
+		glog.V(4).Infof("error retrieving vm template %s for vm %s: %v", templateId, vmId, err)
+		return webinterfaces
+	}
+
+	raw, ok := vmt.Spec.ConfigMap["webinterfaces"]
+	if !ok {
+		return webinterfaces
+	}
+	services := []webinterface{}
+	if err := json.Unmarshal([]byte(raw), &services); err != nil {
+		glog.V(4).Infof("error unmarshaling webinterfaces of vm template %s: %v", vmt.Name, err)
+	}
+	for _, service := range services {
+		// same path the shell proxy serves web interfaces on
+		webinterfaces[service.Name] = "/p/" + vmId + "/" + strconv.Itoa(service.Port) + service.Path
+	}
+
+	return webinterfaces
+}
+
+func placeholder(vm string, field string) string {
+	return fmt.Sprintf("<vm.%s.%s>", vm, field)
+}
+
+// limitedBuffer stops templates from producing unbounded output
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if l.Len()+len(p) > l.limit {
+		return 0, fmt.Errorf("rendered content exceeds %d bytes", l.limit)
+	}
+	return l.Buffer.Write(p)
+}
@@ -0,0 +1,117 @@
+package contenttemplate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	webTemplate = &hfv1.VirtualMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmt-web", Namespace: util.GetReleaseNamespace()},
+		Spec: hfv1.VirtualMachineTemplateSpec{
+			ConfigMap: map[string]string{"webinterfaces": `[{"name":"ui","port":8080,"path":"/dashboard"}]`},
+		},
+	}
+	plainTemplate = &hfv1.VirtualMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmt-plain", Namespace: util.GetReleaseNamespace()},
+	}
+)
+
+func Test_Sample(t *testing.T) {
+	builder := NewBuilder(fake.NewSimpleClientset(webTemplate, plainTemplate), context.Background())
+	scenario := &hfv1.Scenario{Spec: hfv1.ScenarioSpec{
+		VirtualMachines: []map[string]string{{"cp": "vmt-web", "worker": "vmt-plain"}},
+	}}
+	vars := builder.Sample(scenario)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"plain content", "no variables", "no variables", false},
+		{"vm fields", "ssh {{ .vm.worker.ssh_username }}@{{ .vm.worker.public_ip }}", "ssh <vm.worker.ssh_username>@<vm.worker.public_ip>", false},
+		{"web interface of the vm template", "open {{ .vm.cp.webinterfaces.ui }}", "open /p/<vm.cp.id>/8080/dashboard", false},
+		{"session variables", "{{ .user.email }} {{ .session.id }} {{ .scheduledevent.name }}", "<user.email> <session.id> <scheduledevent.name>", false},
+		{"literal braces", `{{"{{"}} .vm }}`, "{{ .vm }}", false},
+		{"web interface the template does not have", "{{ .vm.worker.webinterfaces.ui }}", "", true},
+		{"unknown vm", "{{ .vm.db.hostname }}", "", true},
+		{"invalid template", "{{ .vm.cp.hostname", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ForSession(t *testing.T) {
+	ns := util.GetReleaseNamespace()
+	objects := []runtime.Object{
+		webTemplate,
+		&hfv1.VirtualMachineClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmc-1", Namespace: ns},
+			Spec: hfv1.VirtualMachineClaimSpec{VirtualMachines: map[string]hfv1.VirtualMachineClaimVM{
+				"cp":      {Template: "vmt-web", VirtualMachineId: "vm-1"},
+				"pending": {Template: "vmt-web"},
+			}},
+		},
+		&hfv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm-1", Namespace: ns},
+			Spec:       hfv1.VirtualMachineSpec{VirtualMachineTemplateId: "vmt-web", SshUsername: "ubuntu"},
+			Status:     hfv1.VirtualMachineStatus{PublicIP: "203.0.113.10", Hostname: "cp-1"},
+		},
+	}
+	builder := NewBuilder(fake.NewSimpleClientset(objects...), context.Background())
+
+	ss := &hfv1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: "ss-1"},
+		Spec:       hfv1.SessionSpec{VmClaimSet: []string{"vmc-1"}},
+	}
+	user := hfv2.User{ObjectMeta: metav1.ObjectMeta{Name: "u-1"}, Spec: hfv2.UserSpec{Email: "learner@example.com"}}
+
+	vars, err := builder.ForSession(ss, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Render("{{ .vm.cp.ssh_username }}@{{ .vm.cp.hostname }} {{ .vm.cp.webinterfaces.ui }} {{ .user.email }}", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ubuntu@cp-1 /p/vm-1/8080/dashboard learner@example.com"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	// vms that are not provisioned yet and scheduled events of sessions without one are not available
+	for _, content := range []string{"{{ .vm.pending.hostname }}", "{{ .scheduledevent.name }}"} {
+		if _, err := Render(content, vars); err == nil {
+			t.Errorf("Render(%q) did not fail", content)
+		}
+	}
+}
+
+func Test_RenderOutputLimit(t *testing.T) {
+	content := `{{ range .items }}` + strings.Repeat("x", 1024) + `{{ end }}`
+	vars := map[string]interface{}{"items": make([]int, maxOutputLength/1024+1)}
+
+	if _, err := Render(content, vars); err == nil {
+		t.Error("Render() of oversized output did not fail")
+	}
+}
@@ -0,0 +1,258 @@
+package certificate
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/certificate"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	hfListersV2 "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// CertificateController issues a certificate as soon as the progress of a user completes a course that offers one
+type CertificateController struct {
+	hfClientSet hfClientset.Interface
+	signer      *certificate.Signer
+
+	progressWorkqueue workqueue.Interface
+
+	progressLister    hfListersV2.ProgressLister
+	courseLister      hfListers.CourseLister
+	scenarioLister    hfListers.ScenarioLister
+	certificateLister hfListers.CertificateLister
+
+	progressSynced    cache.InformerSynced
+	courseSynced      cache.InformerSynced
+	scenarioSynced    cache.InformerSynced
+	certificateSynced cache.InformerSynced
+	ctx               context.Context
+}
+
+func NewCertificateController(kubeClient kubernetes.Interface, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*CertificateController, error) {
+	certController := CertificateController{}
+	certController.ctx = ctx
+	certController.hfClientSet = hfClientSet
+	certController.signer = certificate.NewSigner(kubeClient, ctx)
+	certController.progressSynced = hfInformerFactory.Hobbyfarm().V2().Progresses().Informer().HasSynced
+	certController.courseSynced = hfInformerFactory.Hobbyfarm().V1().Courses().Informer().HasSynced
+	certController.scenarioSynced = hfInformerFactory.Hobbyfarm().V1().Scenarios().Informer().HasSynced
+	certController.certificateSynced = hfInformerFactory.Hobbyfarm().V1().Certificates().Informer().HasSynced
+
+	certController.progressWorkqueue = workqueue.NewNamed("cc-progress")
+	certController.progressLister = hfInformerFactory.Hobbyfarm().V2().Progresses().Lister()
+	certController.courseLister = hfInformerFactory.Hobbyfarm().V1().Courses().Lister()
+	certController.scenarioLister = hfInformerFactory.Hobbyfarm().V1().Scenarios().Lister()
+	certController.certificateLister = hfInformerFactory.Hobbyfarm().V1().Certificates().Lister()
+
+	progressInformer := hfInformerFactory.Hobbyfarm().V2().Progresses().Informer()
+
+	progressInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: certController.enqueueProgress,
+		UpdateFunc: func(old, new interface{}) {
+			certController.enqueueProgress(new)
+		},
+	})
+
+	return &certController, nil
+}
+
+func (c *CertificateController) enqueueProgress(obj interface{}) {
+	p, ok := obj.(*hfv2.Progress)
+	if !ok || p.Spec.Course == "" || !completion.ScenarioCompleted(p.Spec) {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		return
+	}
+	glog.V(8).Infof("Enqueueing progress %s", key)
+	c.progressWorkqueue.Add(key)
+}
+
+func (c *CertificateController) Run(stopCh <-chan struct{}) error {
+	defer c.progressWorkqueue.ShutDown()
+
+	glog.V(4).Infof("Starting Certificate controller")
+	glog.Info("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.progressSynced, c.courseSynced, c.scenarioSynced, c.certificateSynced); !ok {
+		return fmt.Errorf("failed to wait for progress, course, scenario and certificate caches to sync")
+	}
+	glog.Info("Starting certificate controller workers")
+	go wait.Until(c.runProgressWorker, time.Second, stopCh)
+	glog.Info("Started certificate controller workers")
+	<-stopCh
+	return nil
+}
+
+func (c *CertificateController) runProgressWorker() {
+	glog.V(6).Infof("Starting certificate worker")
+	for c.processNextProgress() {
+
+	}
+}
+
+func (c *CertificateController) processNextProgress() bool {
+	obj, shutdown := c.progressWorkqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.progressWorkqueue.Done(obj)
+	glog.V(8).Infof("processing progress in certificate controller: %v", obj)
+	_, objName, err := cache.SplitMetaNamespaceKey(obj.(string))
+	if err != nil {
+		glog.Errorf("error while splitting meta namespace key %v", err)
+		return true
+	}
+
+	if err = c.reconcileProgress(objName); err != nil {
+		glog.Error(err)
+	}
+	glog.V(8).Infof("progress processed by certificate controller %v", objName)
+
+	return true
+}
+
+func (c *CertificateController) reconcileProgress(progressName string) error {
+	ns := util.GetReleaseNamespace()
+
+	p, err := c.progressLister.Progresses(ns).Get(progressName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	course, err := c.courseLister.Courses(ns).Get(p.Spec.Course)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !course.Spec.Certificate {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{
+		util.UserLabel:   p.Spec.UserId,
+		util.CourseLabel: course.Name,
+	})
+	existing, err := c.certificateLister.Certificates(ns).List(selector)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	progress, err := c.progressLister.Progresses(ns).List(labels.SelectorFromSet(labels.Set{util.UserLabel: p.Spec.UserId}))
+	if err != nil {
+		return err
+	}
+
+	completed := completion.CompletedScenarios(derefProgress(progress), course.Name)
+	if !completion.CourseCompleted(course.Spec, completed) {
+		return nil
+	}
+
+	// the cache may not have seen a certificate issued moments ago
+	issued, err := c.hfClientSet.HobbyfarmV1().Certificates(ns).List(c.ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	if len(issued.Items) > 0 {
+		return nil
+	}
+
+	return c.issue(course, p.Spec.UserId, progress)
+}
+
+func (c *CertificateController) issue(course *hfv1.Course, userId string, progress []*hfv2.Progress) error {
+	ns := util.GetReleaseNamespace()
+
+	user, err := c.hfClientSet.HobbyfarmV2().Users(ns).Get(c.ctx, userId, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving user %s: %v", userId, err)
+	}
+
+	cert := &hfv1.Certificate{}
+	cert.Name = util.GenerateResourceName("cert", util.RandStringRunes(16), 16)
+	cert.Labels = map[string]string{
+		util.UserLabel:   userId,
+		util.CourseLabel: course.Name,
+	}
+	cert.Spec.UserId = userId
+	cert.Spec.Holder = user.Spec.Email
+	cert.Spec.Course = course.Name
+	cert.Spec.CourseName = decode(course.Spec.Name)
+	cert.Spec.Scenarios = []string{}
+	cert.Spec.Issued = time.Now().Format(time.UnixDate)
+
+	for _, id := range course.Spec.Scenarios {
+		name := id
+		if scenario, err := c.scenarioLister.Scenarios(ns).Get(id); err == nil {
+			name = decode(scenario.Spec.Name)
+		}
+		cert.Spec.Scenarios = append(cert.Spec.Scenarios, name)
+
+		// the best attempt at every scenario counts
+		score, maxScore := 0, 0
+		for _, p := range progress {
+			if p.Spec.Course == course.Name && p.Spec.Scenario == id && completion.ScenarioCompleted(p.Spec) && p.Spec.Score >= score {
+				score, maxScore = p.Spec.Score, p.Spec.MaxScore
+			}
+		}
+		cert.Spec.Score += score
+		cert.Spec.MaxScore += maxScore
+	}
+
+	if err = c.signer.Sign(cert); err != nil {
+		return fmt.Errorf("error signing certificate for user %s and course %s: %v", userId, course.Name, err)
+	}
+
+	_, err = c.hfClientSet.HobbyfarmV1().Certificates(ns).Create(c.ctx, cert, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating certificate for user %s and course %s: %v", userId, course.Name, err)
+	}
+
+	glog.V(2).Infof("issued certificate %s to user %s for course %s", cert.Name, userId, course.Name)
+	return nil
+}
+
+func derefProgress(progress []*hfv2.Progress) []hfv2.Progress {
+	out := make([]hfv2.Progress, 0, len(progress))
+	for _, p := range progress {
+		out = append(out, *p)
+	}
+	return out
+}
+
+// decode returns the plain text of a base64 encoded name, names that are not encoded are returned as they are
+func decode(name string) string {
+	decoded, err := base64.StdEncoding.DecodeString(name)
+	if err != nil {
+		return name
+	}
+	return string(decoded)
+}
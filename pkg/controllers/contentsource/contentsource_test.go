@@ -0,0 +1,134 @@
+package contentsource
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func createRepo(t *testing.T, files map[string]string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	writeFiles(t, repo, files)
+
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "content"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	return repo
+}
+
+func Test_FetchAndLoad(t *testing.T) {
+	repo := createRepo(t, map[string]string{
+		"content/scenarios/intro/scenario.md":         "---\nname: Intro\nvirtualmachines:\n  - node: ubuntu\nkeepalive_duration: 10m\n---\nLearn the basics.\n",
+		"content/scenarios/intro/steps/01-welcome.md": "Hello {{ .user.email }}\n",
+		"content/scenarios/intro/steps/02-check.md":   "---\ntitle: Check it\ngated: true\nchecks:\n  - vm_name: node\n    command: \"true\"\n---\nRun it.\n",
+		"content/scenarios/broken/scenario.md":        "---\nname: Broken\nnot_a_field: true\n---\n",
+		"content/courses/basics.yaml":                 "name: Basics\nscenarios:\n  - intro\nkeep_vm: true\n",
+	})
+
+	for _, url := range []string{repo, "file://" + repo} {
+		dir := filepath.Join(t.TempDir(), "repo")
+		commit, err := Fetch(context.Background(), url, "main", dir)
+		if err != nil {
+			t.Fatalf("error fetching %s: %v", url, err)
+		}
+		if len(commit) != 40 {
+			t.Errorf("expected a commit hash, got %s", commit)
+		}
+
+		root, err := contentRoot(dir, "content")
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := Load(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(content.Scenarios) != 1 {
+			t.Fatalf("expected 1 scenario, got %d", len(content.Scenarios))
+		}
+		s := content.Scenarios[0]
+		if s.Dir != "intro" || s.Spec.Name != "Intro" || s.Spec.Description != "Learn the basics.\n" || s.Spec.KeepAliveDuration != "10m" {
+			t.Errorf("unexpected scenario %+v", s)
+		}
+		if len(s.Spec.Steps) != 2 {
+			t.Fatalf("expected 2 steps, got %d", len(s.Spec.Steps))
+		}
+		if s.Spec.Steps[0].Title != "welcome" || s.Spec.Steps[0].Content != "Hello {{ .user.email }}\n" {
+			t.Errorf("unexpected first step %+v", s.Spec.Steps[0])
+		}
+		if s.Spec.Steps[1].Title != "Check it" || !s.Spec.Steps[1].Gated || len(s.Spec.Steps[1].Checks) != 1 {
+			t.Errorf("unexpected second step %+v", s.Spec.Steps[1])
+		}
+
+		if len(content.Errors) != 1 {
+			t.Errorf("expected the broken scenario to be reported, got %v", content.Errors)
+		}
+
+		if len(content.Courses) != 1 || content.Courses[0].File != "basics" || !content.Courses[0].Spec.KeepVM ||
+			len(content.Courses[0].Spec.Scenarios) != 1 || content.Courses[0].Spec.Scenarios[0] != "intro" {
+			t.Errorf("unexpected courses %+v", content.Courses)
+		}
+	}
+}
+
+func Test_ContentRootStaysInRepository(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Symlink(os.TempDir(), filepath.Join(repo, "outside")); err != nil {
+		t.Fatal(err)
+	}
+
+	if root, err := contentRoot(repo, "../.."); err != nil || filepath.Base(root) != filepath.Base(repo) {
+		t.Errorf("expected ../.. to be cleaned to the repository, got %s %v", root, err)
+	}
+	if _, err := contentRoot(repo, "outside"); err == nil {
+		t.Error("expected a path leaving the repository to be rejected")
+	}
+}
+
+func Test_SymlinkedFilesAreNotRead(t *testing.T) {
+	root := t.TempDir()
+	secret := filepath.Join(t.TempDir(), "secret")
+	writeFiles(t, filepath.Dir(secret), map[string]string{"secret": "---\nname: secret\n---\n"})
+	if err := os.MkdirAll(filepath.Join(root, scenariosDir, "s"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, scenariosDir, "s", scenarioFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content.Scenarios) != 0 || len(content.Errors) != 1 {
+		t.Errorf("expected the linked scenario to be rejected, got %+v", content)
+	}
+}
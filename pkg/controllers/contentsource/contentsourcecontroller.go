@@ -0,0 +1,526 @@
+package contentsource
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	defaultInterval  = 5 * time.Minute
+	minInterval      = 30 * time.Second
+	dueCheckInterval = 30 * time.Second
+)
+
+type ContentSourceController struct {
+	hfClientSet hfClientset.Interface
+
+	csWorkqueue workqueue.Interface
+
+	csLister       hfListers.ContentSourceLister
+	scenarioLister hfListers.ScenarioLister
+	courseLister   hfListers.CourseLister
+
+	csSynced       cache.InformerSynced
+	scenarioSynced cache.InformerSynced
+	courseSynced   cache.InformerSynced
+	ctx            context.Context
+}
+
+func NewContentSourceController(hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*ContentSourceController, error) {
+	csController := ContentSourceController{}
+	csController.ctx = ctx
+	csController.hfClientSet = hfClientSet
+	csController.csSynced = hfInformerFactory.Hobbyfarm().V1().ContentSources().Informer().HasSynced
+	csController.scenarioSynced = hfInformerFactory.Hobbyfarm().V1().Scenarios().Informer().HasSynced
+	csController.courseSynced = hfInformerFactory.Hobbyfarm().V1().Courses().Informer().HasSynced
+
+	csController.csWorkqueue = workqueue.NewNamed("csc-cs")
+	csController.csLister = hfInformerFactory.Hobbyfarm().V1().ContentSources().Lister()
+	csController.scenarioLister = hfInformerFactory.Hobbyfarm().V1().Scenarios().Lister()
+	csController.courseLister = hfInformerFactory.Hobbyfarm().V1().Courses().Lister()
+
+	csInformer := hfInformerFactory.Hobbyfarm().V1().ContentSources().Informer()
+
+	csInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: csController.enqueueCS,
+		UpdateFunc: func(old, new interface{}) {
+			// status updates are our own doing, only a changed spec needs a sync right away
+			if old.(*hfv1.ContentSource).Generation != new.(*hfv1.ContentSource).Generation {
+				csController.enqueueCS(new)
+			}
+		},
+	})
+
+	return &csController, nil
+}
+
+func (c *ContentSourceController) enqueueCS(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		return
+	}
+	glog.V(8).Infof("Enqueueing cs %s", key)
+	c.csWorkqueue.Add(key)
+}
+
+func (c *ContentSourceController) Run(stopCh <-chan struct{}) error {
+	defer c.csWorkqueue.ShutDown()
+
+	glog.V(4).Infof("Starting ContentSource controller")
+	glog.Info("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.csSynced, c.scenarioSynced, c.courseSynced); !ok {
+		return fmt.Errorf("failed to wait for cs, scenario and course caches to sync")
+	}
+	glog.Info("Starting cs controller workers")
+	go wait.Until(c.runCSWorker, time.Second, stopCh)
+	go wait.Until(c.enqueueDue, dueCheckInterval, stopCh)
+	glog.Info("Started cs controller workers")
+	<-stopCh
+	return nil
+}
+
+func (c *ContentSourceController) runCSWorker() {
+	glog.V(6).Infof("Starting content source worker")
+	for c.processNextContentSource() {
+
+	}
+}
+
+func (c *ContentSourceController) processNextContentSource() bool {
+	obj, shutdown := c.csWorkqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.csWorkqueue.Done(obj)
+	glog.V(8).Infof("processing cs in cs controller: %v", obj)
+	_, objName, err := cache.SplitMetaNamespaceKey(obj.(string))
+	if err != nil {
+		glog.Errorf("error while splitting meta namespace key %v", err)
+		return true
+	}
+
+	if err = c.reconcileContentSource(objName); err != nil {
+		glog.Error(err)
+	}
+	glog.V(8).Infof("cs processed by content source controller %v", objName)
+
+	return true
+}
+
+// enqueueDue queues every content source whose interval has passed since its last sync
+func (c *ContentSourceController) enqueueDue() {
+	sources, err := c.csLister.ContentSources(util.GetReleaseNamespace()).List(labels.Everything())
+	if err != nil {
+		glog.Errorf("error listing content sources: %v", err)
+		return
+	}
+
+	for _, cs := range sources {
+		interval, _ := parseInterval(cs.Spec.Interval)
+		if due(cs, interval) {
+			c.enqueueCS(cs)
+		}
+	}
+}
+
+func (c *ContentSourceController) reconcileContentSource(csName string) error {
+	glog.V(4).Infof("reconciling content source %s", csName)
+
+	cs, err := c.csLister.ContentSources(util.GetReleaseNamespace()).Get(csName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	interval, intervalErr := parseInterval(cs.Spec.Interval)
+	if !due(cs, interval) {
+		return nil
+	}
+
+	status := c.sync(cs)
+	if intervalErr != nil {
+		status.Errors = append([]string{intervalErr.Error()}, status.Errors...)
+		status.Synced = false
+	}
+
+	return c.updateStatus(cs.Name, status)
+}
+
+// sync pulls the repository of a content source and applies its content
+func (c *ContentSourceController) sync(cs *hfv1.ContentSource) hfv1.ContentSourceStatus {
+	status := hfv1.ContentSourceStatus{
+		ObservedGeneration: cs.Generation,
+		LastSyncTime:       time.Now().Format(time.UnixDate),
+		Commit:             cs.Status.Commit,
+		Scenarios:          cs.Status.Scenarios,
+		Courses:            cs.Status.Courses,
+	}
+
+	tmp, err := os.MkdirTemp("", "contentsource-")
+	if err != nil {
+		status.Errors = []string{fmt.Sprintf("error creating working directory: %v", err)}
+		return status
+	}
+	defer os.RemoveAll(tmp)
+
+	repo := filepath.Join(tmp, "repo")
+	commit, err := Fetch(c.ctx, cs.Spec.URL, cs.Spec.Ref, repo)
+	if err != nil {
+		status.Errors = []string{err.Error()}
+		return status
+	}
+
+	// nothing changed since the last successful sync
+	if commit == cs.Status.Commit && cs.Status.Synced && cs.Status.ObservedGeneration == cs.Generation {
+		status.Synced = true
+		return status
+	}
+	status.Commit = commit
+
+	root, err := contentRoot(repo, cs.Spec.Path)
+	if err != nil {
+		status.Errors = []string{err.Error()}
+		return status
+	}
+
+	content, err := Load(root)
+	if err != nil {
+		status.Errors = []string{err.Error()}
+		return status
+	}
+
+	errs := content.Errors
+	status.Scenarios = []string{}
+	status.Courses = []string{}
+
+	scenarioNames := map[string]string{}
+	for _, s := range content.Scenarios {
+		name := scenarioName(cs, s.Dir)
+		if err := c.applyScenario(cs, name, encodeScenario(s.Spec)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", scenariosDir, s.Dir, err))
+			continue
+		}
+		scenarioNames[s.Dir] = name
+		status.Scenarios = append(status.Scenarios, name)
+	}
+
+	for _, course := range content.Courses {
+		spec := encodeCourse(course.Spec)
+		spec.Scenarios = []string{}
+		missing := []string{}
+		for _, dir := range course.Spec.Scenarios {
+			if name, ok := scenarioNames[dir]; ok {
+				spec.Scenarios = append(spec.Scenarios, name)
+			} else {
+				missing = append(missing, dir)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Sprintf("%s/%s: unknown or invalid scenarios %s", coursesDir, course.File, strings.Join(missing, ", ")))
+			continue
+		}
+		if course.Spec.Prerequisites != nil {
+			// every prerequisite is part of the course, which was just resolved
+			spec.Prerequisites = map[string][]string{}
+			for dir, required := range course.Spec.Prerequisites {
+				names := []string{}
+				for _, r := range required {
+					names = append(names, scenarioNames[r])
+				}
+				spec.Prerequisites[scenarioNames[dir]] = names
+			}
+		}
+
+		name := courseName(cs, course.File)
+		if err := c.applyCourse(cs, name, spec); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", coursesDir, course.File, err))
+			continue
+		}
+		status.Courses = append(status.Courses, name)
+	}
+
+	errs = append(errs, c.retire(cs, status.Scenarios, status.Courses)...)
+
+	status.Errors = errs
+	status.Synced = len(errs) == 0
+	return status
+}
+
+// applyScenario creates or updates a scenario owned by the content source, recording a revision whenever its content changes
+func (c *ContentSourceController) applyScenario(cs *hfv1.ContentSource, name string, spec hfv1.ScenarioSpec) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scenario, err := c.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(c.ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			scenario = &hfv1.Scenario{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						util.ContentSourceLabel: cs.Name,
+					},
+				},
+				Spec: spec,
+			}
+			scenario.Spec.Revision = 1
+
+			created, err := c.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Create(c.ctx, scenario, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			glog.V(4).Infof("content source %s created scenario %s", cs.Name, name)
+			return revision.CreateScenarioRevision(c.ctx, c.hfClientSet, created, creator(cs))
+		}
+		if err != nil {
+			return err
+		}
+
+		if scenario.Labels[util.ContentSourceLabel] != cs.Name {
+			return fmt.Errorf("scenario %s already exists and is not managed by this content source", name)
+		}
+
+		changed := revision.ScenarioChanged(scenario.Spec, spec)
+		if !changed && scenario.Spec.State == spec.State {
+			return nil
+		}
+
+		if scenario.Spec.Revision == 0 {
+			scenario.Spec.Revision = 1
+		}
+		if err = revision.CreateScenarioRevision(c.ctx, c.hfClientSet, scenario, ""); err != nil {
+			return err
+		}
+
+		spec.Revision = scenario.Spec.Revision
+		if changed {
+			spec.Revision++
+		}
+		scenario.Spec = spec
+
+		updated, err := c.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Update(c.ctx, scenario, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		glog.V(4).Infof("content source %s updated scenario %s", cs.Name, name)
+		return revision.CreateScenarioRevision(c.ctx, c.hfClientSet, updated, creator(cs))
+	})
+}
+
+// applyCourse creates or updates a course owned by the content source, recording a revision whenever its content changes
+func (c *ContentSourceController) applyCourse(cs *hfv1.ContentSource, name string, spec hfv1.CourseSpec) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		course, err := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(c.ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			course = &hfv1.Course{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						util.ContentSourceLabel: cs.Name,
+					},
+				},
+				Spec: spec,
+			}
+			course.Spec.Revision = 1
+
+			created, err := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Create(c.ctx, course, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			glog.V(4).Infof("content source %s created course %s", cs.Name, name)
+			return revision.CreateCourseRevision(c.ctx, c.hfClientSet, created, creator(cs))
+		}
+		if err != nil {
+			return err
+		}
+
+		if course.Labels[util.ContentSourceLabel] != cs.Name {
+			return fmt.Errorf("course %s already exists and is not managed by this content source", name)
+		}
+
+		changed := revision.CourseChanged(course.Spec, spec)
+		if !changed && course.Spec.State == spec.State {
+			return nil
+		}
+
+		if course.Spec.Revision == 0 {
+			course.Spec.Revision = 1
+		}
+		if err = revision.CreateCourseRevision(c.ctx, c.hfClientSet, course, ""); err != nil {
+			return err
+		}
+
+		spec.Revision = course.Spec.Revision
+		if changed {
+			spec.Revision++
+		}
+		course.Spec = spec
+
+		updated, err := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Update(c.ctx, course, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		glog.V(4).Infof("content source %s updated course %s", cs.Name, name)
+		return revision.CreateCourseRevision(c.ctx, c.hfClientSet, updated, creator(cs))
+	})
+}
+
+// retire turns scenarios and courses that were removed from the repository into drafts.
+// They are not deleted, sessions and scheduled events may still be using them.
+func (c *ContentSourceController) retire(cs *hfv1.ContentSource, scenarios []string, courses []string) []string {
+	errs := []string{}
+	owned := labels.SelectorFromSet(labels.Set{util.ContentSourceLabel: cs.Name})
+
+	ownedScenarios, err := c.scenarioLister.Scenarios(util.GetReleaseNamespace()).List(owned)
+	if err != nil {
+		return []string{fmt.Sprintf("error listing scenarios: %v", err)}
+	}
+	for _, s := range ownedScenarios {
+		if util.StringInSlice(s.Name, scenarios) || s.Spec.State == hfv1.ContentStateDraft {
+			continue
+		}
+		spec := *s.Spec.DeepCopy()
+		spec.State = hfv1.ContentStateDraft
+		if err := c.applyScenario(cs, s.Name, spec); err != nil {
+			errs = append(errs, fmt.Sprintf("error retiring scenario %s: %v", s.Name, err))
+		}
+	}
+
+	ownedCourses, err := c.courseLister.Courses(util.GetReleaseNamespace()).List(owned)
+	if err != nil {
+		return append(errs, fmt.Sprintf("error listing courses: %v", err))
+	}
+	for _, course := range ownedCourses {
+		if util.StringInSlice(course.Name, courses) || course.Spec.State == hfv1.ContentStateDraft {
+			continue
+		}
+		spec := *course.Spec.DeepCopy()
+		spec.State = hfv1.ContentStateDraft
+		if err := c.applyCourse(cs, course.Name, spec); err != nil {
+			errs = append(errs, fmt.Sprintf("error retiring course %s: %v", course.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func (c *ContentSourceController) updateStatus(csName string, status hfv1.ContentSourceStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cs, err := c.hfClientSet.HobbyfarmV1().ContentSources(util.GetReleaseNamespace()).Get(c.ctx, csName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		cs.Status = status
+		_, err = c.hfClientSet.HobbyfarmV1().ContentSources(util.GetReleaseNamespace()).UpdateStatus(c.ctx, cs, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// contentRoot resolves the content path of a source inside the repository, it may not point outside of it
+func contentRoot(repo string, path string) (string, error) {
+	repo, err := filepath.EvalSymlinks(repo)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.EvalSymlinks(filepath.Join(repo, filepath.Clean("/"+path)))
+	if err != nil {
+		return "", fmt.Errorf("path %s not found in repository", path)
+	}
+	if root != repo && !strings.HasPrefix(root, repo+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s points outside of the repository", path)
+	}
+
+	return root, nil
+}
+
+func parseInterval(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultInterval, nil
+	}
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultInterval, fmt.Errorf("invalid interval %s, using %s: %v", raw, defaultInterval, err)
+	}
+	if interval < minInterval {
+		return minInterval, fmt.Errorf("interval %s is shorter than %s, using %s", raw, minInterval, minInterval)
+	}
+
+	return interval, nil
+}
+
+// due returns true if a content source changed or was last synced at least interval ago
+func due(cs *hfv1.ContentSource, interval time.Duration) bool {
+	if cs.Status.ObservedGeneration != cs.Generation || cs.Status.LastSyncTime == "" {
+		return true
+	}
+
+	lastSync, err := time.Parse(time.UnixDate, cs.Status.LastSyncTime)
+	if err != nil {
+		return true
+	}
+
+	return time.Since(lastSync) >= interval
+}
+
+func scenarioName(cs *hfv1.ContentSource, dir string) string {
+	return util.GenerateResourceName("s", cs.Name+"/"+dir, 10)
+}
+
+func courseName(cs *hfv1.ContentSource, file string) string {
+	return util.GenerateResourceName("c", cs.Name+"/"+file, 10)
+}
+
+func creator(cs *hfv1.ContentSource) string {
+	return "contentsource/" + cs.Name
+}
+
+// encodeScenario base64 encodes the texts of a scenario, the same way the admin ui stores them
+func encodeScenario(spec hfv1.ScenarioSpec) hfv1.ScenarioSpec {
+	spec.Name = encode(spec.Name)
+	spec.Description = encode(spec.Description)
+
+	steps := []hfv1.ScenarioStep{}
+	for _, step := range spec.Steps {
+		step.Title = encode(step.Title)
+		step.Content = encode(step.Content)
+		steps = append(steps, step)
+	}
+	spec.Steps = steps
+
+	return spec
+}
+
+func encodeCourse(spec hfv1.CourseSpec) hfv1.CourseSpec {
+	spec.Name = encode(spec.Name)
+	spec.Description = encode(spec.Description)
+	return spec
+}
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
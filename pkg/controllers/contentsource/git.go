@@ -0,0 +1,60 @@
+package contentsource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	gitTimeout = 2 * time.Minute
+	// transports git may use, this keeps helpers like ext:: that run arbitrary commands out
+	gitProtocols = "file:git:http:https:ssh"
+)
+
+// Fetch clones the given ref of a repository into dir and returns the commit that was checked out.
+// Local paths and file:// urls work as well as remote repositories.
+func Fetch(ctx context.Context, url string, ref string, dir string) (string, error) {
+	args := []string{"clone", "--quiet", "--depth", "1", "--no-tags"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", url, dir)
+
+	if _, err := git(ctx, "", args...); err != nil {
+		return "", err
+	}
+
+	commit, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(commit), nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL="+gitProtocols,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
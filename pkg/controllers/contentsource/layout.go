@@ -0,0 +1,388 @@
+// Package contentsource keeps scenarios and courses in sync with a git repository.
+//
+// A content source points at a directory inside a repository that is laid out like this:
+//
+//	scenarios/
+//	  <scenario>/
+//	    scenario.md        front-matter holds the scenario fields, the body is the description
+//	    steps/
+//	      01-intro.md      one file per step in file name order, front-matter holds title, checks, gated and questions
+//	      02-next.md
+//	courses/
+//	  <course>.yaml        course fields, scenarios are listed by their directory name
+//
+// Front-matter is YAML between two "---" lines at the very top of a file, using the same field names as
+// the API, e.g.
+//
+//	---
+//	name: Getting started
+//	virtualmachines:
+//	  - node: ubuntu-template
+//	keepalive_duration: 10m
+//	pauseable: true
+//	---
+//	Everything after the front-matter is Markdown.
+//
+// Steps without a title are named after their file, without the leading number and extension.
+// Unknown fields are rejected so that typos do not go unnoticed. Symbolic links are not followed.
+//
+// Scenarios and courses created by a content source carry its name in the hobbyfarm.io/contentsource label.
+// Only objects with that label are ever changed. Content removed from the repository is turned into a draft
+// instead of being deleted, since sessions and scheduled events may still be using it.
+package contentsource
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/quiz"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/stepcheck"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	scenariosDir = "scenarios"
+	coursesDir   = "courses"
+	stepsDir     = "steps"
+	scenarioFile = "scenario.md"
+)
+
+var (
+	frontMatterDelim = []byte("---")
+	stepPrefix       = regexp.MustCompile(`^[0-9]+[-_ .]*`)
+)
+
+type scenarioMeta struct {
+	Name              string              `json:"name"`
+	Description       string              `json:"description,omitempty"`
+	Categories        []string            `json:"categories,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	VirtualMachines   []map[string]string `json:"virtualmachines,omitempty"`
+	KeepAliveDuration string              `json:"keepalive_duration,omitempty"`
+	PauseDuration     string              `json:"pause_duration,omitempty"`
+	Pauseable         bool                `json:"pauseable,omitempty"`
+	SessionPolicy     hfv1.SessionPolicy  `json:"session_policy,omitempty"`
+	State             hfv1.ContentState   `json:"state,omitempty"`
+}
+
+type stepMeta struct {
+	Title     string              `json:"title,omitempty"`
+	Checks    []hfv1.StepCheck    `json:"checks,omitempty"`
+	Gated     bool                `json:"gated,omitempty"`
+	Questions []hfv1.StepQuestion `json:"questions,omitempty"`
+}
+
+type courseMeta struct {
+	Name              string              `json:"name"`
+	Description       string              `json:"description,omitempty"`
+	Scenarios         []string            `json:"scenarios,omitempty"`
+	Categories        []string            `json:"categories,omitempty"`
+	VirtualMachines   []map[string]string `json:"virtualmachines,omitempty"`
+	KeepAliveDuration string              `json:"keepalive_duration,omitempty"`
+	PauseDuration     string              `json:"pause_duration,omitempty"`
+	Pauseable         bool                `json:"pauseable,omitempty"`
+	KeepVM            bool                `json:"keep_vm,omitempty"`
+	SessionPolicy     hfv1.SessionPolicy  `json:"session_policy,omitempty"`
+	State             hfv1.ContentState   `json:"state,omitempty"`
+	Sequence          hfv1.CourseSequence `json:"sequence,omitempty"`
+	Prerequisites     map[string][]string `json:"prerequisites,omitempty"`
+	Certificate       bool                `json:"certificate,omitempty"`
+}
+
+// Scenario is a scenario read from a repository, Dir is the name of its directory
+type Scenario struct {
+	Dir  string
+	Spec hfv1.ScenarioSpec
+}
+
+// Course is a course read from a repository, its Scenarios and Prerequisites still refer to scenario directories
+type Course struct {
+	File string
+	Spec hfv1.CourseSpec
+}
+
+// Content is everything read from a repository. Errors holds the problems of single scenarios or courses,
+// which are skipped while the rest of the content is still usable.
+type Content struct {
+	Scenarios []Scenario
+	Courses   []Course
+	Errors    []string
+}
+
+// Load reads the content below root. Texts are returned as written, they are not base64 encoded yet.
+func Load(root string) (*Content, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("content directory not found: %v", err)
+	}
+
+	content := &Content{}
+
+	scenarioDirs, err := readDir(filepath.Join(root, scenariosDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range scenarioDirs {
+		if !entry.IsDir() {
+			continue
+		}
+		scenario, err := loadScenario(filepath.Join(root, scenariosDir, entry.Name()))
+		if err != nil {
+			content.Errors = append(content.Errors, fmt.Sprintf("%s/%s: %v", scenariosDir, entry.Name(), err))
+			continue
+		}
+		content.Scenarios = append(content.Scenarios, *scenario)
+	}
+
+	courseFiles, err := readDir(filepath.Join(root, coursesDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range courseFiles {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		course, err := loadCourse(filepath.Join(root, coursesDir, entry.Name()))
+		if err != nil {
+			content.Errors = append(content.Errors, fmt.Sprintf("%s/%s: %v", coursesDir, entry.Name(), err))
+			continue
+		}
+		content.Courses = append(content.Courses, *course)
+	}
+
+	return content, nil
+}
+
+// readDir lists a directory sorted by name, a missing directory is empty
+func readDir(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", dir, err)
+	}
+	return entries, nil
+}
+
+// readFile reads a regular file, links could point at files outside of the repository
+func readFile(file string) ([]byte, error) {
+	info, err := os.Lstat(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is missing", filepath.Base(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", filepath.Base(file))
+	}
+	return os.ReadFile(file)
+}
+
+func loadScenario(dir string) (*Scenario, error) {
+	raw, err := readFile(filepath.Join(dir, scenarioFile))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := scenarioMeta{}
+	body, err := splitFrontMatter(raw, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", scenarioFile, err)
+	}
+	if meta.Name == "" {
+		return nil, fmt.Errorf("%s: name is missing", scenarioFile)
+	}
+	if meta.Description == "" {
+		meta.Description = body
+	}
+	state, err := revision.ParseState(string(meta.State))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", scenarioFile, err)
+	}
+
+	stepFiles, err := readDir(filepath.Join(dir, stepsDir))
+	if err != nil {
+		return nil, err
+	}
+	// entries are sorted by file name, which numbers the steps
+	steps := []hfv1.ScenarioStep{}
+	for _, entry := range stepFiles {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		step, err := loadStep(filepath.Join(dir, stepsDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %v", stepsDir, entry.Name(), err)
+		}
+		steps = append(steps, *step)
+	}
+
+	if err := validateChecks(steps, meta.VirtualMachines); err != nil {
+		return nil, err
+	}
+
+	return &Scenario{
+		Dir: filepath.Base(dir),
+		Spec: hfv1.ScenarioSpec{
+			Name:              meta.Name,
+			Description:       meta.Description,
+			Steps:             steps,
+			Categories:        nonNil(meta.Categories),
+			Tags:              nonNil(meta.Tags),
+			VirtualMachines:   nonNilVMs(meta.VirtualMachines),
+			KeepAliveDuration: meta.KeepAliveDuration,
+			PauseDuration:     meta.PauseDuration,
+			Pauseable:         meta.Pauseable,
+			SessionPolicy:     meta.SessionPolicy,
+			State:             state,
+		},
+	}, nil
+}
+
+func loadStep(file string) (*hfv1.ScenarioStep, error) {
+	raw, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := stepMeta{}
+	body, err := splitFrontMatter(raw, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	title := meta.Title
+	if title == "" {
+		title = stepPrefix.ReplaceAllString(strings.TrimSuffix(filepath.Base(file), ".md"), "")
+	}
+
+	return &hfv1.ScenarioStep{
+		Title:     title,
+		Content:   body,
+		Checks:    meta.Checks,
+		Gated:     meta.Gated,
+		Questions: meta.Questions,
+	}, nil
+}
+
+func loadCourse(file string) (*Course, error) {
+	raw, err := readFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := courseMeta{}
+	if err := yaml.UnmarshalStrict(raw, &meta); err != nil {
+		return nil, err
+	}
+	if meta.Name == "" {
+		return nil, fmt.Errorf("name is missing")
+	}
+	state, err := revision.ParseState(string(meta.State))
+	if err != nil {
+		return nil, err
+	}
+
+	course := &Course{
+		File: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+		Spec: hfv1.CourseSpec{
+			Name:              meta.Name,
+			Description:       meta.Description,
+			Scenarios:         nonNil(meta.Scenarios),
+			Categories:        nonNil(meta.Categories),
+			VirtualMachines:   nonNilVMs(meta.VirtualMachines),
+			KeepAliveDuration: meta.KeepAliveDuration,
+			PauseDuration:     meta.PauseDuration,
+			Pauseable:         meta.Pauseable,
+			KeepVM:            meta.KeepVM,
+			SessionPolicy:     meta.SessionPolicy,
+			State:             state,
+			Sequence:          meta.Sequence,
+			Prerequisites:     meta.Prerequisites,
+			Certificate:       meta.Certificate,
+		},
+	}
+	if err := completion.Validate(course.Spec); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// splitFrontMatter unmarshals the front-matter of a Markdown file into meta and returns the body.
+// A file without front-matter is all body.
+func splitFrontMatter(raw []byte, meta interface{}) (string, error) {
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(raw, append(frontMatterDelim, '\n')) {
+		return string(raw), nil
+	}
+
+	rest := raw[len(frontMatterDelim)+1:]
+	end := bytes.Index(rest, append(append([]byte("\n"), frontMatterDelim...), '\n'))
+	var front, body []byte
+	switch {
+	case end >= 0:
+		front, body = rest[:end+1], rest[end+len(frontMatterDelim)+2:]
+	case bytes.HasSuffix(rest, append([]byte("\n"), frontMatterDelim...)):
+		front = rest[:len(rest)-len(frontMatterDelim)]
+	default:
+		return "", fmt.Errorf("front-matter is not terminated by ---")
+	}
+
+	if err := yaml.UnmarshalStrict(front, meta); err != nil {
+		return "", fmt.Errorf("invalid front-matter: %v", err)
+	}
+
+	return strings.TrimLeft(string(body), "\n"), nil
+}
+
+// validateChecks applies the same rules to checks and questions as the scenario server does
+func validateChecks(steps []hfv1.ScenarioStep, virtualMachines []map[string]string) error {
+	vmNames := map[string]bool{}
+	for _, vmSet := range virtualMachines {
+		for name := range vmSet {
+			vmNames[name] = true
+		}
+	}
+
+	for i, step := range steps {
+		for j, check := range step.Checks {
+			if err := stepcheck.Validate(check); err != nil {
+				return fmt.Errorf("step %d check %d: %v", i, j, err)
+			}
+			if !vmNames[check.VirtualMachine] {
+				return fmt.Errorf("step %d check %d: vm %s is not part of the scenario", i, j, check.VirtualMachine)
+			}
+		}
+		for j, question := range step.Questions {
+			if err := quiz.Validate(question); err != nil {
+				return fmt.Errorf("step %d question %d: %v", i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nonNilVMs(vms []map[string]string) []map[string]string {
+	if vms == nil {
+		return []map[string]string{}
+	}
+	return vms
+}
@@ -0,0 +1,237 @@
+package learningrecord
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"github.com/hobbyfarm/gargantua/v3/pkg/xapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+var errDisabled = fmt.Errorf("no learning record store configured")
+
+// LearningRecordController drains the xAPI outbox. Every learning record is sent to the learning record store
+// and deleted once it was accepted, failed attempts are retried with an increasing delay.
+type LearningRecordController struct {
+	hfClientSet hfClientset.Interface
+	kubeClient  kubernetes.Interface
+
+	recordWorkqueue workqueue.RateLimitingInterface
+
+	recordLister hfListers.LearningRecordLister
+
+	recordSynced cache.InformerSynced
+	ctx          context.Context
+}
+
+func NewLearningRecordController(kubeClient kubernetes.Interface, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*LearningRecordController, error) {
+	recordController := LearningRecordController{}
+	recordController.ctx = ctx
+	recordController.hfClientSet = hfClientSet
+	recordController.kubeClient = kubeClient
+	recordController.recordSynced = hfInformerFactory.Hobbyfarm().V1().LearningRecords().Informer().HasSynced
+
+	recordController.recordWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(retryBaseDelay, retryMaxDelay), "lrc-learningrecord")
+	recordController.recordLister = hfInformerFactory.Hobbyfarm().V1().LearningRecords().Lister()
+
+	recordInformer := hfInformerFactory.Hobbyfarm().V1().LearningRecords().Informer()
+
+	// status updates of failed attempts are not enqueued again, the rate limited workqueue schedules the retry
+	recordInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: recordController.enqueueRecord,
+	})
+
+	return &recordController, nil
+}
+
+func (c *LearningRecordController) enqueueRecord(obj interface{}) {
+	record, ok := obj.(*hfv1.LearningRecord)
+	if !ok || record.Status.Rejected {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		return
+	}
+	glog.V(8).Infof("Enqueueing learning record %s", key)
+	c.recordWorkqueue.Add(key)
+}
+
+func (c *LearningRecordController) Run(stopCh <-chan struct{}) error {
+	defer c.recordWorkqueue.ShutDown()
+
+	glog.V(4).Infof("Starting Learning Record controller")
+	glog.Info("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.recordSynced); !ok {
+		return fmt.Errorf("failed to wait for learning record caches to sync")
+	}
+	glog.Info("Starting learning record controller workers")
+	go wait.Until(c.runRecordWorker, time.Second, stopCh)
+	glog.Info("Started learning record controller workers")
+	<-stopCh
+	return nil
+}
+
+func (c *LearningRecordController) runRecordWorker() {
+	glog.V(6).Infof("Starting learning record worker")
+	for c.processNextRecord() {
+
+	}
+}
+
+func (c *LearningRecordController) processNextRecord() bool {
+	obj, shutdown := c.recordWorkqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.recordWorkqueue.Done(obj)
+	glog.V(8).Infof("processing learning record in learning record controller: %v", obj)
+	_, objName, err := cache.SplitMetaNamespaceKey(obj.(string))
+	if err != nil {
+		glog.Errorf("error while splitting meta namespace key %v", err)
+		c.recordWorkqueue.Forget(obj)
+		return true
+	}
+
+	err = c.reconcileRecord(objName)
+	if err != nil && xapi.Retryable(err) {
+		glog.V(4).Infof("error sending learning record %s, retrying: %v", objName, err)
+		c.recordWorkqueue.AddRateLimited(obj)
+		return true
+	}
+	if err != nil {
+		glog.Errorf("learning record %s was rejected: %v", objName, err)
+	}
+
+	c.recordWorkqueue.Forget(obj)
+	glog.V(8).Infof("learning record processed by learning record controller %v", objName)
+
+	return true
+}
+
+func (c *LearningRecordController) reconcileRecord(recordName string) error {
+	ns := util.GetReleaseNamespace()
+
+	record, err := c.recordLister.LearningRecords(ns).Get(recordName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.Status.Rejected {
+		return nil
+	}
+
+	// records are kept while xapi is disabled, they are sent once a store is configured again
+	endpoint := xapi.Endpoint()
+	if endpoint == "" {
+		return errDisabled
+	}
+
+	sendErr := c.send(endpoint, record)
+	if sendErr == nil {
+		err = c.hfClientSet.HobbyfarmV1().LearningRecords(ns).Delete(c.ctx, record.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			glog.Errorf("error deleting sent learning record %s: %v", record.Name, err)
+		}
+		return nil
+	}
+
+	if err = c.updateStatus(record.Name, sendErr); err != nil {
+		glog.Errorf("error updating status of learning record %s: %v", record.Name, err)
+	}
+
+	return sendErr
+}
+
+func (c *LearningRecordController) send(endpoint string, record *hfv1.LearningRecord) error {
+	ns := util.GetReleaseNamespace()
+	activityBase := xapi.ActivityBase()
+
+	username, password, err := c.credentials()
+	if err != nil {
+		return err
+	}
+
+	email := ""
+	user, err := c.hfClientSet.HobbyfarmV2().Users(ns).Get(c.ctx, record.Spec.UserId, metav1.GetOptions{})
+	if err == nil {
+		email = user.Spec.Email
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error retrieving user %s: %v", record.Spec.UserId, err)
+	}
+
+	scenarioName := ""
+	scenario, err := c.hfClientSet.HobbyfarmV1().Scenarios(ns).Get(c.ctx, record.Spec.Scenario, metav1.GetOptions{})
+	if err == nil {
+		scenarioName = decode(scenario.Spec.Name)
+	}
+
+	statement, err := xapi.NewStatement(record, xapi.NewAgent(activityBase, record.Spec.UserId, email), activityBase, scenarioName)
+	if err != nil {
+		return &xapi.StatusError{StatusCode: 400, Body: err.Error()}
+	}
+
+	return xapi.NewClient(endpoint, username, password).Send(c.ctx, statement)
+}
+
+// credentials are optional, a store that does not require authentication needs no secret
+func (c *LearningRecordController) credentials() (string, string, error) {
+	secret, err := c.kubeClient.CoreV1().Secrets(util.GetReleaseNamespace()).Get(c.ctx, xapi.CredentialsSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("error retrieving learning record store credentials: %v", err)
+	}
+	return string(secret.Data[xapi.CredentialsUsername]), string(secret.Data[xapi.CredentialsPassword]), nil
+}
+
+func (c *LearningRecordController) updateStatus(recordName string, sendErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		record, err := c.hfClientSet.HobbyfarmV1().LearningRecords(util.GetReleaseNamespace()).Get(c.ctx, recordName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		record.Status.Attempts++
+		record.Status.LastAttempt = time.Now().Format(time.UnixDate)
+		record.Status.Error = sendErr.Error()
+		record.Status.Rejected = !xapi.Retryable(sendErr)
+
+		_, err = c.hfClientSet.HobbyfarmV1().LearningRecords(util.GetReleaseNamespace()).UpdateStatus(c.ctx, record, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func decode(name string) string {
+	decoded, err := base64.StdEncoding.DecodeString(name)
+	if err != nil {
+		return name
+	}
+	return string(decoded)
+}
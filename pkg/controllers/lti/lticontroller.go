@@ -0,0 +1,236 @@
+package lti
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	hfListersV2 "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/lti"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = 30 * time.Minute
+)
+
+// LTIController sends grades back to the platforms users were launched from. Whenever the progress of a user changes
+// the scores of the user's lti launches are computed and published if they changed.
+type LTIController struct {
+	hfClientSet hfClientset.Interface
+	grades      *lti.GradeService
+
+	userWorkqueue workqueue.RateLimitingInterface
+
+	progressLister hfListersV2.ProgressLister
+	launchLister   hfListers.LTILaunchLister
+	platformLister hfListers.LTIPlatformLister
+	courseLister   hfListers.CourseLister
+
+	progressSynced cache.InformerSynced
+	launchSynced   cache.InformerSynced
+	platformSynced cache.InformerSynced
+	courseSynced   cache.InformerSynced
+	ctx            context.Context
+}
+
+func NewLTIController(kubeClient kubernetes.Interface, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*LTIController, error) {
+	ltiController := LTIController{}
+	ltiController.ctx = ctx
+	ltiController.hfClientSet = hfClientSet
+	ltiController.grades = lti.NewGradeService(lti.NewKeyStore(kubeClient, ctx))
+	ltiController.progressSynced = hfInformerFactory.Hobbyfarm().V2().Progresses().Informer().HasSynced
+	ltiController.launchSynced = hfInformerFactory.Hobbyfarm().V1().LTILaunches().Informer().HasSynced
+	ltiController.platformSynced = hfInformerFactory.Hobbyfarm().V1().LTIPlatforms().Informer().HasSynced
+	ltiController.courseSynced = hfInformerFactory.Hobbyfarm().V1().Courses().Informer().HasSynced
+
+	ltiController.userWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(retryBaseDelay, retryMaxDelay), "ltic-user")
+	ltiController.progressLister = hfInformerFactory.Hobbyfarm().V2().Progresses().Lister()
+	ltiController.launchLister = hfInformerFactory.Hobbyfarm().V1().LTILaunches().Lister()
+	ltiController.platformLister = hfInformerFactory.Hobbyfarm().V1().LTIPlatforms().Lister()
+	ltiController.courseLister = hfInformerFactory.Hobbyfarm().V1().Courses().Lister()
+
+	hfInformerFactory.Hobbyfarm().V2().Progresses().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ltiController.enqueueUser,
+		UpdateFunc: func(old, new interface{}) {
+			ltiController.enqueueUser(new)
+		},
+	})
+	hfInformerFactory.Hobbyfarm().V1().LTILaunches().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ltiController.enqueueUser,
+	})
+
+	return &ltiController, nil
+}
+
+func (c *LTIController) enqueueUser(obj interface{}) {
+	var userId string
+	switch o := obj.(type) {
+	case *hfv2.Progress:
+		userId = o.Spec.UserId
+	case *hfv1.LTILaunch:
+		if o.Spec.LineItem == "" {
+			return
+		}
+		userId = o.Spec.UserId
+	}
+	if userId == "" {
+		return
+	}
+
+	glog.V(8).Infof("Enqueueing user %s", userId)
+	c.userWorkqueue.Add(userId)
+}
+
+func (c *LTIController) Run(stopCh <-chan struct{}) error {
+	defer c.userWorkqueue.ShutDown()
+
+	glog.V(4).Infof("Starting LTI controller")
+	glog.Info("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.progressSynced, c.launchSynced, c.platformSynced, c.courseSynced); !ok {
+		return fmt.Errorf("failed to wait for progress, lti launch, lti platform and course caches to sync")
+	}
+	glog.Info("Starting lti controller workers")
+	go wait.Until(c.runUserWorker, time.Second, stopCh)
+	glog.Info("Started lti controller workers")
+	<-stopCh
+	return nil
+}
+
+func (c *LTIController) runUserWorker() {
+	glog.V(6).Infof("Starting lti worker")
+	for c.processNextUser() {
+
+	}
+}
+
+func (c *LTIController) processNextUser() bool {
+	obj, shutdown := c.userWorkqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.userWorkqueue.Done(obj)
+	glog.V(8).Infof("processing user in lti controller: %v", obj)
+
+	if err := c.reconcileUser(obj.(string)); err != nil {
+		glog.Errorf("error sending lti grades of user %v: %v", obj, err)
+		c.userWorkqueue.AddRateLimited(obj)
+		return true
+	}
+
+	c.userWorkqueue.Forget(obj)
+	glog.V(8).Infof("user processed by lti controller %v", obj)
+
+	return true
+}
+
+func (c *LTIController) reconcileUser(userId string) error {
+	ns := util.GetReleaseNamespace()
+	selector := labels.SelectorFromSet(labels.Set{util.UserLabel: userId})
+
+	launches, err := c.launchLister.LTILaunches(ns).List(selector)
+	if err != nil {
+		return err
+	}
+	if len(launches) == 0 {
+		return nil
+	}
+
+	progress, err := c.progressLister.Progresses(ns).List(selector)
+	if err != nil {
+		return err
+	}
+	userProgress := make([]hfv2.Progress, 0, len(progress))
+	for _, p := range progress {
+		userProgress = append(userProgress, *p)
+	}
+
+	var lastErr error
+	for _, launch := range launches {
+		if launch.Spec.LineItem == "" {
+			continue
+		}
+		if err = c.publish(launch, userProgress); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (c *LTIController) publish(launch *hfv1.LTILaunch, progress []hfv2.Progress) error {
+	ns := util.GetReleaseNamespace()
+
+	var score lti.Score
+	var started bool
+	switch {
+	case launch.Spec.Course != "":
+		course, err := c.courseLister.Courses(ns).Get(launch.Spec.Course)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		score, started = lti.CourseScore(launch.Spec.Subject, course, progress)
+	case launch.Spec.Scenario != "":
+		score, started = lti.ScenarioScore(launch.Spec.Subject, launch.Spec.Scenario, progress)
+	}
+
+	completed := score.ActivityProgress == lti.ActivityProgressCompleted
+	if !started || (launch.Status.LastSync != "" && launch.Status.Error == "" &&
+		launch.Status.ScoreGiven == score.ScoreGiven && launch.Status.ScoreMaximum == score.ScoreMaximum && launch.Status.Completed == completed) {
+		return nil
+	}
+
+	platform, err := c.platformLister.LTIPlatforms(ns).Get(launch.Spec.Platform)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	publishErr := c.grades.Publish(c.ctx, platform, launch.Spec.LineItem, score)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := c.hfClientSet.HobbyfarmV1().LTILaunches(ns).Get(c.ctx, launch.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		latest.Status.LastSync = time.Now().Format(time.UnixDate)
+		if publishErr != nil {
+			latest.Status.Error = publishErr.Error()
+		} else {
+			latest.Status.Error = ""
+			latest.Status.ScoreGiven = score.ScoreGiven
+			latest.Status.ScoreMaximum = score.ScoreMaximum
+			latest.Status.Completed = completed
+		}
+
+		_, err = c.hfClientSet.HobbyfarmV1().LTILaunches(ns).UpdateStatus(c.ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		glog.Errorf("error updating status of lti launch %s: %v", launch.Name, err)
+	}
+
+	return publishErr
+}
@@ -0,0 +1,115 @@
+package scheduledevent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// ReadinessRefreshPeriod is how often the ready virtual machines of an event are counted while it is provisioned
+const ReadinessRefreshPeriod = 10 * time.Second
+
+// provisionTime returns when provisioning of a scheduled event begins, pre-warmed events are provisioned before they start
+func (s ScheduledEventController) provisionTime(se *hfv2.ScheduledEvent) (time.Time, error) {
+	schedules, err := util.PrewarmSchedules(s.hfClientSet, se, s.ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return prewarm.ProvisionTime(se.Spec.StartTime.Time, schedules), nil
+}
+
+// rampScheduledEvent requests the next batch of virtual machines once the batch interval passed and counts the
+// virtual machines that are ready. Batches of all environments are requested together, the shortest interval counts.
+// It returns the vmsets of the event if a batch was requested.
+func (s ScheduledEventController) rampScheduledEvent(se *hfv2.ScheduledEvent) (hfv1.ScheduledEventReadiness, []string, error) {
+	readiness := hfv1.ScheduledEventReadiness{}
+	if se.Status.Readiness != nil {
+		readiness = *se.Status.Readiness
+	}
+
+	var vmSets []string
+	if !se.Spec.OnDemand && readiness.Requested < readiness.Total {
+		schedules, err := util.PrewarmSchedules(s.hfClientSet, se, s.ctx)
+		if err != nil {
+			return readiness, nil, err
+		}
+
+		var interval time.Duration
+		for _, schedule := range schedules {
+			if interval == 0 || schedule.BatchInterval < interval {
+				interval = schedule.BatchInterval
+			}
+		}
+
+		if !time.Now().Before(readiness.LastBatchTime.Add(interval)) {
+			readiness = hfv1.ScheduledEventReadiness{LastBatchTime: metav1.Now()}
+			vmSets = []string{}
+			for envName := range se.Spec.RequiredVirtualMachines {
+				env, err := s.hfClientSet.HobbyfarmV1().Environments(util.GetReleaseNamespace()).Get(s.ctx, envName, metav1.GetOptions{})
+				if err != nil {
+					return readiness, nil, err
+				}
+				envVMSets, _, err := s.requestVMSetBatch(se, env, schedules[envName], &readiness)
+				if err != nil {
+					return readiness, nil, err
+				}
+				vmSets = append(vmSets, envVMSets...)
+			}
+			glog.V(4).Infof("requested %d of %d virtual machines for scheduled event %s", readiness.Requested, readiness.Total, se.Name)
+		}
+	}
+
+	ready, err := s.countReadyVirtualMachines(se)
+	if err != nil {
+		return readiness, nil, err
+	}
+	readiness.Ready = ready
+
+	return readiness, vmSets, nil
+}
+
+// countReadyVirtualMachines counts the running virtual machines of a scheduled event
+func (s ScheduledEventController) countReadyVirtualMachines(se *hfv2.ScheduledEvent) (int, error) {
+	vmList, err := s.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ready := 0
+	for _, vm := range vmList.Items {
+		if vm.DeletionTimestamp == nil && !vm.Status.Tainted && vm.Status.Status == hfv1.VmStatusRunning {
+			ready++
+		}
+	}
+	return ready, nil
+}
+
+// updateReadiness records the progress of a scheduled event that is not ready yet, vmSets is nil if they did not change
+func (s ScheduledEventController) updateReadiness(seName string, readiness hfv1.ScheduledEventReadiness, vmSets []string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if seToUpdate.Status.Readiness != nil && *seToUpdate.Status.Readiness == readiness && vmSets == nil {
+			return nil
+		}
+		seToUpdate.Status.SetReadiness(readiness)
+		if vmSets != nil {
+			seToUpdate.Status.VirtualMachineSets = vmSets
+		}
+
+		_, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -10,8 +10,11 @@ import (
 
 	"github.com/golang/glog"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
 	"github.com/hobbyfarm/gargantua/v3/pkg/settingclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -28,7 +31,10 @@ type ScheduledEventController struct {
 	//seWorkqueue workqueue.RateLimitingInterface
 	seWorkqueue workqueue.DelayingInterface
 	seSynced    cache.InformerSynced
-	ctx         context.Context
+
+	seriesWorkqueue workqueue.DelayingInterface
+	seriesSynced    cache.InformerSynced
+	ctx             context.Context
 }
 
 var baseNameScheduledPrefix string
@@ -59,11 +65,11 @@ func NewScheduledEventController(hfClientSet hfClientset.Interface, hfInformerFa
 	seController := ScheduledEventController{}
 	seController.ctx = ctx
 	seController.hfClientSet = hfClientSet
-	seController.seSynced = hfInformerFactory.Hobbyfarm().V1().ScheduledEvents().Informer().HasSynced
+	seController.seSynced = hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Informer().HasSynced
 
 	//seController.seWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ScheduledEvent")
 	seController.seWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(ScheduledEventBaseDelay, ScheduledEventMaxDelay), "sec-se")
-	seInformer := hfInformerFactory.Hobbyfarm().V1().ScheduledEvents().Informer()
+	seInformer := hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Informer()
 
 	seInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
 		AddFunc: seController.enqueueSE,
@@ -73,6 +79,21 @@ func NewScheduledEventController(hfClientSet hfClientset.Interface, hfInformerFa
 		DeleteFunc: seController.enqueueSE,
 	}, time.Minute*30)
 
+	seController.seriesSynced = hfInformerFactory.Hobbyfarm().V1().ScheduledEventSerieses().Informer().HasSynced
+	seController.seriesWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(ScheduledEventBaseDelay, ScheduledEventMaxDelay), "sec-series")
+	seriesInformer := hfInformerFactory.Hobbyfarm().V1().ScheduledEventSerieses().Informer()
+
+	seriesInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc: seController.enqueueSeries,
+		UpdateFunc: func(old, new interface{}) {
+			// status updates of the controller itself do not change the generation
+			if old.(*hfv1.ScheduledEventSeries).Generation != new.(*hfv1.ScheduledEventSeries).Generation {
+				seController.enqueueSeries(new)
+			}
+		},
+		DeleteFunc: seController.enqueueSeries,
+	}, time.Minute*30)
+
 	return &seController, nil
 }
 
@@ -90,14 +111,16 @@ func (s *ScheduledEventController) enqueueSE(obj interface{}) {
 
 func (s *ScheduledEventController) Run(stopCh <-chan struct{}) error {
 	defer s.seWorkqueue.ShutDown()
+	defer s.seriesWorkqueue.ShutDown()
 
 	glog.V(4).Infof("Starting Scheduled Event controller")
 	glog.Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, s.seSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, s.seSynced, s.seriesSynced); !ok {
 		return fmt.Errorf("failed to wait for vm, vmc, and ss caches to sync")
 	}
 	glog.Info("Starting se controller workers")
 	go wait.Until(s.runSEWorker, time.Second, stopCh)
+	go wait.Until(s.runSeriesWorker, time.Second, stopCh)
 	glog.Info("Started se controller workers")
 	//if ok := cache.WaitForCacheSync(stopCh, )
 	<-stopCh
@@ -148,7 +171,7 @@ func (s *ScheduledEventController) processNextScheduledEvent() bool {
 	return true
 }
 
-func (s ScheduledEventController) completeScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) completeScheduledEvent(se *hfv2.ScheduledEvent) error {
 	glog.V(6).Infof("ScheduledEvent %s is done, deleting corresponding VMSets and marking as finished", se.Name)
 	// scheduled event is finished, we need to set the scheduled event to finished and delete the vm's
 
@@ -166,17 +189,15 @@ func (s ScheduledEventController) completeScheduledEvent(se *hfv1.ScheduledEvent
 
 	// update the scheduled event and set the various flags accordingly (provisioned, ready, finished)
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		seToUpdate, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
 
 		if err != nil {
 			return err
 		}
 
-		seToUpdate.Status.Provisioned = true
-		seToUpdate.Status.Ready = false
-		seToUpdate.Status.Finished = true
+		seToUpdate.Status.MarkFinished()
 
-		_, updateErr := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
 		glog.V(4).Infof("updated result for scheduled event")
 
 		return updateErr
@@ -189,10 +210,10 @@ func (s ScheduledEventController) completeScheduledEvent(se *hfv1.ScheduledEvent
 	return nil // break (return) here because we're done with this SE.
 }
 
-func (s ScheduledEventController) deleteScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) deleteScheduledEvent(se *hfv2.ScheduledEvent) error {
 	glog.V(6).Infof("ScheduledEvent %s is done and retention time is over, deleting SE finally", se.Name)
 
-	if !se.Status.Finished {
+	if !se.Status.IsFinished() {
 		return fmt.Errorf("error attempting to delete SE that is not finished")
 	}
 
@@ -202,7 +223,7 @@ func (s ScheduledEventController) deleteScheduledEvent(se *hfv1.ScheduledEvent)
 		return err
 	}
 
-	err = s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Delete(s.ctx, se.Name, metav1.DeleteOptions{})
+	err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Delete(s.ctx, se.Name, metav1.DeleteOptions{})
 
 	if err != nil {
 		return err
@@ -210,7 +231,7 @@ func (s ScheduledEventController) deleteScheduledEvent(se *hfv1.ScheduledEvent)
 	return nil // break (return) here because we're done with this SE.
 }
 
-func (s ScheduledEventController) deleteVMSetsFromScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) deleteVMSetsFromScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// for each vmset that belongs to this to-be-stopped scheduled event, delete that vmset
 	err := s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
@@ -222,9 +243,9 @@ func (s ScheduledEventController) deleteVMSetsFromScheduledEvent(se *hfv1.Schedu
 	return nil
 }
 
-func (s ScheduledEventController) deleteProgressFromScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) deleteProgressFromScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// for each vmset that belongs to this to-be-stopped scheduled event, delete that vmset
-	err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+	err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
 	})
 	if err != nil {
@@ -234,7 +255,7 @@ func (s ScheduledEventController) deleteProgressFromScheduledEvent(se *hfv1.Sche
 	return nil
 }
 
-func (s ScheduledEventController) deleteAccessCode(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) deleteAccessCode(se *hfv2.ScheduledEvent) error {
 	// delete the access code for the corresponding ScheduledEvent
 	err := s.hfClientSet.HobbyfarmV1().AccessCodes(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
@@ -246,7 +267,7 @@ func (s ScheduledEventController) deleteAccessCode(se *hfv1.ScheduledEvent) erro
 	return nil
 }
 
-func (s ScheduledEventController) finishSessionsFromScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) finishSessionsFromScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// get a list of sessions for the user
 	sessionList, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.AccessCodeLabel, se.Spec.AccessCode),
@@ -278,10 +299,12 @@ func (s ScheduledEventController) finishSessionsFromScheduledEvent(se *hfv1.Sche
 	return nil
 }
 
-func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.VirtualMachineTemplateList, se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.VirtualMachineTemplateList, se *hfv2.ScheduledEvent) error {
 	glog.V(6).Infof("ScheduledEvent %s is ready to be provisioned", se.Name)
 	// start creating resources related to this
 	vmSets := []string{}
+	shortfall := map[string]map[string]int{}
+	readiness := hfv1.ScheduledEventReadiness{LastBatchTime: metav1.Now()}
 
 	/**
 	The general flow here is to calculate how much resources (cpu, mem, storage) are currently
@@ -290,6 +313,11 @@ func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.Virtua
 		e.g. --> glog.Errorf("we are overprovisioning this environment %s by CPU...
 	*/
 
+	schedules, err := util.PrewarmSchedules(s.hfClientSet, se, s.ctx)
+	if err != nil {
+		return err
+	}
+
 	// begin by calculating what is currently being used in the environment
 	for envName, vmtMap := range se.Spec.RequiredVirtualMachines {
 		// get the environment we're provisioning into (envName)
@@ -299,75 +327,15 @@ func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.Virtua
 			return err
 		}
 
-		// TODO: actually check for capacity usage
-
-		// create virtualmachinesets if not on demand
+		// create virtualmachinesets if not on demand, pre-warmed events start with their first batch
 		if !se.Spec.OnDemand {
-			for templateName, count := range vmtMap {
-				if count > 0 { // only setup vmsets if >0 VMs are requested, and they aren't ondemand
-					//1. Find existing VMset that match this SE and the current environment
-					existingVMSets, err := s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
-						LabelSelector: fmt.Sprintf("%s=%s,%s=%s,virtualmachinetemplate.hobbyfarm.io/%s=true", util.ScheduledEventLabel, se.Name, util.EnvironmentLabel, envName, templateName),
-					})
-
-					if err != nil || len(existingVMSets.Items) == 0 { // create new vmset if no existing one was found
-						vmsRand := fmt.Sprintf("%s-%08x", baseNameScheduledPrefix, rand.Uint32())
-						vmsName := strings.Join([]string{"se", se.Name, "vms", vmsRand}, "-")
-						vmSets = append(vmSets, vmsName)
-						vms := &hfv1.VirtualMachineSet{
-							ObjectMeta: metav1.ObjectMeta{
-								Name: vmsName,
-								OwnerReferences: []metav1.OwnerReference{
-									{
-										APIVersion: "hobbyfarm.io/v1",
-										Kind:       "ScheduledEvent",
-										Name:       se.Name,
-										UID:        se.UID,
-									},
-								},
-								Labels: map[string]string{
-									util.EnvironmentLabel:    env.Name,
-									util.ScheduledEventLabel: se.Name,
-									fmt.Sprintf("virtualmachinetemplate.hobbyfarm.io/%s", templateName): "true",
-								},
-							},
-							Spec: hfv1.VirtualMachineSetSpec{
-								Count:       count,
-								Environment: envName,
-								VMTemplate:  templateName,
-								BaseName:    vmsRand,
-							},
-						}
-						if se.Spec.RestrictedBind {
-							vms.Spec.RestrictedBind = true
-							vms.Spec.RestrictedBindValue = se.Spec.RestrictedBindValue
-						} else {
-							vms.Spec.RestrictedBind = false
-						}
-						_, err = s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).Create(s.ctx, vms, metav1.CreateOptions{})
-						if err != nil {
-							glog.Error(err)
-							return err
-						}
-					} else { // update existing vmset
-						// Todo support multiple VM Sets
-						existingVMSet := existingVMSets.Items[0]
-						vmSets = append(vmSets, existingVMSet.Name)
-
-						existingVMSet.Labels[util.EnvironmentLabel] = env.Name
-						existingVMSet.Spec.Count = count
-						if se.Spec.RestrictedBind {
-							existingVMSet.Spec.RestrictedBind = true
-						} else {
-							existingVMSet.Spec.RestrictedBind = false
-						}
-						_, err = s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).Update(s.ctx, &existingVMSet, metav1.UpdateOptions{})
-						if err != nil {
-							glog.Errorf("error updating vmset config %s", err.Error())
-							return err
-						}
-					}
-				}
+			envVMSets, envShortfall, err := s.requestVMSetBatch(se, env, schedules[envName], &readiness)
+			if err != nil {
+				return err
+			}
+			vmSets = append(vmSets, envVMSets...)
+			if len(envShortfall) > 0 {
+				shortfall[envName] = envShortfall
 			}
 		}
 
@@ -422,7 +390,7 @@ func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.Virtua
 	}
 
 	// Delete AccessCode if it exists
-	_, err := s.hfClientSet.HobbyfarmV1().AccessCodes(util.GetReleaseNamespace()).Get(s.ctx, se.Spec.AccessCode, metav1.GetOptions{})
+	_, err = s.hfClientSet.HobbyfarmV1().AccessCodes(util.GetReleaseNamespace()).Get(s.ctx, se.Spec.AccessCode, metav1.GetOptions{})
 	if err == nil {
 		err = s.deleteAccessCode(se)
 		if err != nil {
@@ -430,25 +398,44 @@ func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.Virtua
 		}
 	}
 
-	err = s.createAccessCode(se)
+	// pre-warmed events open their doors when they begin, verifyScheduledEvent creates the access code then
+	if !time.Now().Before(se.Spec.StartTime.Time) {
+		err = s.createAccessCode(se)
+		if err != nil {
+			return err
+		}
+	}
+
+	// learners get the content as it was when the event started, later edits only affect new events
+	scenarioRevisions, courseRevisions, err := revision.Pin(s.ctx, s.hfClientSet, se.Spec.Scenarios, se.Spec.Courses)
 	if err != nil {
-		return err
+		glog.Errorf("error pinning revisions for scheduled event %s: %v", se.Name, err)
 	}
 
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 
-		seToUpdate, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
 
 		if err != nil {
 			return err
 		}
 
-		seToUpdate.Status.Provisioned = true
+		if seToUpdate.Status.ScenarioRevisions == nil && seToUpdate.Status.CourseRevisions == nil {
+			seToUpdate.Status.ScenarioRevisions = scenarioRevisions
+			seToUpdate.Status.CourseRevisions = courseRevisions
+		}
+		seToUpdate.Status.MarkProvisioning()
+		seToUpdate.Status.SetReadiness(readiness)
 		seToUpdate.Status.VirtualMachineSets = vmSets
-		seToUpdate.Status.Ready = false
-		seToUpdate.Status.Finished = false
+		seToUpdate.Status.CapacityShortfall = nil
+		if len(shortfall) > 0 {
+			seToUpdate.Status.CapacityShortfall = shortfall
+			seToUpdate.Status.MarkFailed(hfv2.ReasonCapacityShortfall, "not enough capacity, missing "+util.DescribeShortfall(shortfall))
+		} else {
+			seToUpdate.Status.ClearFailed()
+		}
 
-		_, updateErr := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
 		glog.V(4).Infof("updated result for scheduled event")
 
 		return updateErr
@@ -460,7 +447,113 @@ func (s ScheduledEventController) provisionScheduledEvent(templates *hfv1.Virtua
 	return nil
 }
 
-func (s ScheduledEventController) createAccessCode(se *hfv1.ScheduledEvent) error {
+// requestVMSetBatch grows the vmsets of a scheduled event in an environment by one batch towards the count the
+// capacity of the environment allows. It returns the names of the vmsets and what did not fit into the environment.
+func (s ScheduledEventController) requestVMSetBatch(se *hfv2.ScheduledEvent, env *hfv1.Environment, schedule prewarm.Schedule, readiness *hfv1.ScheduledEventReadiness) ([]string, map[string]int, error) {
+	vmSets := []string{}
+	shortfall := map[string]int{}
+
+	// vmsets of other scheduled events already use part of the capacity
+	envVMSets, err := s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.EnvironmentLabel, env.Name),
+	})
+	if err != nil {
+		glog.Errorf("error listing vmsets of environment %s %s", env.Name, err.Error())
+		return nil, nil, err
+	}
+	usedCount := calculateUsedCapacity(env, envVMSets, se)
+
+	target := map[string]int{}
+	current := map[string]int{}
+	existing := map[string]hfv1.VirtualMachineSet{}
+	for templateName, count := range se.Spec.RequiredVirtualMachines[env.Name] {
+		if available := max(env.Spec.CountCapacity[templateName]-usedCount[templateName], 0); count > available {
+			glog.Errorf("environment %s only has capacity for %d of %d %s required by scheduled event %s", env.Name, available, count, templateName, se.Name)
+			shortfall[templateName] = count - available
+			count = available
+		}
+		target[templateName] = count
+
+		//1. Find existing VMset that match this SE and the current environment
+		for _, vms := range envVMSets.Items {
+			if vms.Labels[util.ScheduledEventLabel] == se.Name && vms.Labels[fmt.Sprintf("virtualmachinetemplate.hobbyfarm.io/%s", templateName)] == "true" {
+				// Todo support multiple VM Sets
+				existing[templateName] = vms
+				current[templateName] = vms.Spec.Count
+				break
+			}
+		}
+	}
+
+	for templateName, count := range schedule.Batch(current, target) {
+		readiness.Total += target[templateName]
+		readiness.Requested += count
+		if count <= 0 { // only setup vmsets if >0 VMs are requested
+			continue
+		}
+
+		existingVMSet, ok := existing[templateName]
+		if !ok { // create new vmset if no existing one was found
+			vmsRand := fmt.Sprintf("%s-%08x", baseNameScheduledPrefix, rand.Uint32())
+			vmsName := strings.Join([]string{"se", se.Name, "vms", vmsRand}, "-")
+			vmSets = append(vmSets, vmsName)
+			vms := &hfv1.VirtualMachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: vmsName,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "hobbyfarm.io/v1",
+							Kind:       "ScheduledEvent",
+							Name:       se.Name,
+							UID:        se.UID,
+						},
+					},
+					Labels: map[string]string{
+						util.EnvironmentLabel:    env.Name,
+						util.ScheduledEventLabel: se.Name,
+						fmt.Sprintf("virtualmachinetemplate.hobbyfarm.io/%s", templateName): "true",
+					},
+				},
+				Spec: hfv1.VirtualMachineSetSpec{
+					Count:       count,
+					Environment: env.Name,
+					VMTemplate:  templateName,
+					BaseName:    vmsRand,
+				},
+			}
+			if se.Spec.RestrictedBind {
+				vms.Spec.RestrictedBind = true
+				vms.Spec.RestrictedBindValue = se.Spec.RestrictedBindValue
+			} else {
+				vms.Spec.RestrictedBind = false
+			}
+			_, err = s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).Create(s.ctx, vms, metav1.CreateOptions{})
+			if err != nil {
+				glog.Error(err)
+				return nil, nil, err
+			}
+			continue
+		}
+
+		// update existing vmset
+		vmSets = append(vmSets, existingVMSet.Name)
+		if existingVMSet.Spec.Count == count && existingVMSet.Spec.RestrictedBind == se.Spec.RestrictedBind {
+			continue
+		}
+		existingVMSet.Labels[util.EnvironmentLabel] = env.Name
+		existingVMSet.Spec.Count = count
+		existingVMSet.Spec.RestrictedBind = se.Spec.RestrictedBind
+		_, err = s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).Update(s.ctx, &existingVMSet, metav1.UpdateOptions{})
+		if err != nil {
+			glog.Errorf("error updating vmset config %s", err.Error())
+			return nil, nil, err
+		}
+	}
+
+	return vmSets, shortfall, nil
+}
+
+func (s ScheduledEventController) createAccessCode(se *hfv2.ScheduledEvent) error {
 	ac := &hfv1.AccessCode{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: se.Spec.AccessCode,
@@ -482,7 +575,7 @@ func (s ScheduledEventController) createAccessCode(se *hfv1.ScheduledEvent) erro
 			Description: "Generated by ScheduledEventController",
 			Scenarios:   se.Spec.Scenarios,
 			Courses:     se.Spec.Courses,
-			Expiration:  se.Spec.EndTime,
+			Expiration:  se.Spec.EndTime.UTC().Format(time.UnixDate),
 		},
 	}
 
@@ -507,9 +600,14 @@ func (s ScheduledEventController) createAccessCode(se *hfv1.ScheduledEvent) erro
 	return nil
 }
 
-func (s ScheduledEventController) verifyScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventController) verifyScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// check the state of the vmset and mark the sevent as ready if everything is OK
 	glog.V(6).Infof("ScheduledEvent %s is in provisioned status, checking status of VMSet Provisioning", se.Name)
+	readiness, vmSets, err := s.rampScheduledEvent(se)
+	if err != nil {
+		return err
+	}
+
 	vmsList, err := s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
 	})
@@ -517,51 +615,66 @@ func (s ScheduledEventController) verifyScheduledEvent(se *hfv1.ScheduledEvent)
 		return err
 	}
 
+	ready := readiness.Requested >= readiness.Total
 	for _, vms := range vmsList.Items {
 		if vms.Status.ProvisionedCount < vms.Spec.Count {
-			return fmt.Errorf("scheduled event is not ready yet")
+			ready = false
 		}
 	}
 
-	// Validate AccessCode existence and has label set
-	ac, err := s.hfClientSet.HobbyfarmV1().AccessCodes(util.GetReleaseNamespace()).Get(s.ctx, se.Spec.AccessCode, metav1.GetOptions{})
-	if err != nil {
-		err = s.createAccessCode(se)
+	if !ready {
+		// instructors follow the progress in the status until all batches are requested and running
+		glog.V(6).Infof("scheduled event %s is not ready yet, %d/%d virtual machines ready", se.Name, readiness.Ready, readiness.Total)
+		s.seWorkqueue.AddAfter(se.Name, ReadinessRefreshPeriod)
+		return s.updateReadiness(se.Name, readiness, vmSets)
+	}
 
+	// pre-warmed events open their doors when they begin
+	if now := time.Now(); now.Before(se.Spec.StartTime.Time) {
+		s.seWorkqueue.AddAfter(se.Name, se.Spec.StartTime.Sub(now))
+	} else {
+		// Validate AccessCode existence and has label set
+		ac, err := s.hfClientSet.HobbyfarmV1().AccessCodes(util.GetReleaseNamespace()).Get(s.ctx, se.Spec.AccessCode, metav1.GetOptions{})
 		if err != nil {
-			return err
-		}
+			err = s.createAccessCode(se)
 
-	} else if ac.Labels[util.AccessCodeLabel] != ac.Spec.Code {
-		err = s.deleteAccessCode(se)
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		err = s.createAccessCode(se)
+		} else if ac.Labels[util.AccessCodeLabel] != ac.Spec.Code {
+			err = s.deleteAccessCode(se)
+			if err != nil {
+				return err
+			}
 
-		if err != nil {
-			return err
+			err = s.createAccessCode(se)
+
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 
-		seToUpdate, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
 
 		if err != nil {
 			return err
 		}
 
-		if seToUpdate.Status.Provisioned == false || seToUpdate.Status.Finished == true {
+		if !seToUpdate.Status.IsProvisioned() || seToUpdate.Status.IsFinished() {
 			return fmt.Errorf("scheduled event is not provisioned. Maybe changed recently")
 		}
 
-		seToUpdate.Status.Provisioned = true
-		seToUpdate.Status.Ready = true
-		seToUpdate.Status.Finished = false
+		seToUpdate.Status.MarkReady()
+		seToUpdate.Status.SetReadiness(readiness)
+		if vmSets != nil {
+			seToUpdate.Status.VirtualMachineSets = vmSets
+		}
 
-		_, updateErr := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
 		glog.V(4).Infof("updated result for scheduled event")
 
 		return updateErr
@@ -577,7 +690,7 @@ func (s *ScheduledEventController) reconcileScheduledEvent(seName string) error
 	glog.V(4).Infof("reconciling scheduled event %s", seName)
 
 	// fetch the scheduled event
-	se, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seName, metav1.GetOptions{})
+	se, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -589,34 +702,48 @@ func (s *ScheduledEventController) reconcileScheduledEvent(seName string) error
 	}
 	now := time.Now()
 
-	beginTime, err := time.Parse(time.UnixDate, se.Spec.StartTime)
-	if err != nil {
-		return err
-	}
-	endTime, err := time.Parse(time.UnixDate, se.Spec.EndTime)
-
-	if err != nil {
-		return err
+	beginTime := se.Spec.StartTime.Time
+	endTime := se.Spec.EndTime.Time
+	if beginTime.IsZero() || !endTime.After(beginTime) {
+		return s.markScheduledEventFailed(se.Name, hfv2.ReasonInvalidSchedule, "the event has to end after it starts")
 	}
 
 	// this means that the scheduled event has ended (endtime.Before(now)), but the status of the event is not finished
 	// and it is still marked as active. this means we need to finish and deactivate the SE.
-	if endTime.Before(now) && !se.Status.Finished && se.Status.Active {
+	if endTime.Before(now) && !se.Status.IsFinished() && se.Status.IsActive() {
 		return s.completeScheduledEvent(se)
 	}
 
-	// if this scheduled event has begun (beginTime.Before(now)), and we haven't already provisioned
+	// pre-warmed events are provisioned before they begin
+	provisionTime := beginTime
+	if se.Status.IsActive() && !se.Status.IsFinished() {
+		provisionTime, err = s.provisionTime(se)
+		if err != nil {
+			return err
+		}
+		if now.Before(provisionTime) {
+			s.seWorkqueue.AddAfter(se.Name, provisionTime.Sub(now))
+		}
+	}
+
+	// if provisioning this scheduled event has begun (provisionTime.Before(now)), and we haven't already provisioned
 	// this SE, let's do so
-	if beginTime.Before(now) && !se.Status.Provisioned && se.Status.Active {
-		return s.provisionScheduledEvent(templates, se)
+	if provisionTime.Before(now) && !se.Status.IsProvisioned() && se.Status.IsActive() {
+		err = s.provisionScheduledEvent(templates, se)
+		if err != nil {
+			if markErr := s.markScheduledEventFailed(se.Name, hfv2.ReasonProvisioningFailed, err.Error()); markErr != nil {
+				glog.Errorf("error marking scheduled event %s as failed %v", se.Name, markErr)
+			}
+		}
+		return err
 	}
 
 	// the SE is ongoing and we should just verify things are good
-	if beginTime.Before(now) && se.Status.Provisioned && !se.Status.Finished && se.Status.Active {
+	if provisionTime.Before(now) && se.Status.IsProvisioned() && !se.Status.IsFinished() && se.Status.IsActive() {
 		return s.verifyScheduledEvent(se)
 	}
 
-	if endTime.Before(now) && se.Status.Finished {
+	if endTime.Before(now) && se.Status.IsFinished() {
 		// scheduled event is finished and nothing to do
 
 		if set := settingclient.GetSetting(settingclient.ScheduledEventRetentionTime); set == nil {
@@ -635,7 +762,7 @@ func (s *ScheduledEventController) reconcileScheduledEvent(seName string) error
 	if se.Spec.OnDemand && len(se.Status.VirtualMachineSets) > 0 {
 		vmSets := []string{}
 		se.Status.VirtualMachineSets = vmSets
-		_, updateErr := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, se, metav1.UpdateOptions{})
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, se, metav1.UpdateOptions{})
 		s.deleteVMSetsFromScheduledEvent(se)
 		return updateErr
 	}
@@ -643,14 +770,30 @@ func (s *ScheduledEventController) reconcileScheduledEvent(seName string) error
 	return nil
 }
 
-func calculateUsedCapacity(env *hfv1.Environment, vmsList *hfv1.VirtualMachineSetList, templates *hfv1.VirtualMachineTemplateList) map[string]int {
+// markScheduledEventFailed records why a scheduled event could not be provisioned
+func (s *ScheduledEventController) markScheduledEventFailed(seName string, reason string, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		seToUpdate.Status.MarkFailed(reason, message)
+
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		return updateErr
+	})
+}
+
+// calculateUsedCapacity counts the virtual machines of the vmsets in an environment per template, leaving out the
+// vmsets of the scheduled event that is provisioned
+func calculateUsedCapacity(env *hfv1.Environment, vmsList *hfv1.VirtualMachineSetList, se *hfv2.ScheduledEvent) map[string]int {
 	usedCount := map[string]int{}
 	for _, vms := range vmsList.Items {
-		for _, t := range templates.Items {
-			if t.Name == vms.Spec.VMTemplate {
-				usedCount[t.Name] = usedCount[t.Name] + vms.Spec.Count
-			}
+		if vms.Spec.Environment != env.Name || vms.Labels[util.ScheduledEventLabel] == se.Name {
+			continue
 		}
+		usedCount[vms.Spec.VMTemplate] = usedCount[vms.Spec.VMTemplate] + vms.Spec.Count
 	}
 	return usedCount
 }
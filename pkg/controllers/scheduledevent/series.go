@@ -0,0 +1,306 @@
+package scheduledevent
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/eventseries"
+	"github.com/hobbyfarm/gargantua/v3/pkg/recurrence"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+// SeriesResyncPeriod is how often a series looks for occurrences that entered its materialization window
+const SeriesResyncPeriod = time.Hour
+
+func (s *ScheduledEventController) enqueueSeries(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err != nil {
+		return
+	}
+	glog.V(8).Infof("Enqueueing series %s", key)
+	s.seriesWorkqueue.Add(key)
+}
+
+func (s *ScheduledEventController) runSeriesWorker() {
+	glog.V(6).Infof("Starting scheduled event series worker")
+	for s.processNextScheduledEventSeries() {
+
+	}
+}
+
+func (s *ScheduledEventController) processNextScheduledEventSeries() bool {
+	obj, shutdown := s.seriesWorkqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer s.seriesWorkqueue.Done(obj)
+	_, objName, err := cache.SplitMetaNamespaceKey(obj.(string))
+	if err != nil {
+		glog.Errorf("error while splitting meta namespace key %v", err)
+		return true
+	}
+
+	err = s.reconcileScheduledEventSeries(objName)
+	if err != nil {
+		glog.Error(err)
+		s.seriesWorkqueue.AddAfter(obj, time.Minute)
+		return true
+	}
+	s.seriesWorkqueue.AddAfter(obj, SeriesResyncPeriod)
+	glog.V(8).Infof("series processed by scheduled event controller %v", objName)
+
+	return true
+}
+
+// reconcileScheduledEventSeries creates the scheduled events of a series that start within its materialization window.
+// Occurrences that have not started yet follow changes of the series, unless they were edited on their own.
+func (s *ScheduledEventController) reconcileScheduledEventSeries(seriesName string) error {
+	glog.V(4).Infof("reconciling scheduled event series %s", seriesName)
+
+	series, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Get(s.ctx, seriesName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// the series is gone, occurrences that already started are kept
+		_, err = s.deleteUpcomingOccurrences(seriesName, func(se *hfv2.ScheduledEvent) bool { return true })
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = eventseries.Validate(series); err != nil {
+		glog.Errorf("invalid scheduled event series %s: %v", seriesName, err)
+		return s.updateSeriesStatus(seriesName, func(status *hfv1.ScheduledEventSeriesStatus) {
+			status.Error = err.Error()
+		})
+	}
+
+	now := time.Now()
+	ahead, _ := eventseries.MaterializeAhead(series)
+	occurrences, err := eventseries.Occurrences(series, now, now.Add(ahead))
+	if err != nil {
+		return err
+	}
+	wanted := map[string]recurrence.Occurrence{}
+	for _, o := range occurrences {
+		wanted[o.Date()] = o
+	}
+
+	existing, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventSeriesLabel, seriesName),
+	})
+	if err != nil {
+		return err
+	}
+
+	materialized := map[string]bool{}
+	for _, se := range existing.Items {
+		materialized[se.Annotations[eventseries.OccurrenceAnnotation]] = true
+	}
+	for _, date := range series.Status.Materialized {
+		materialized[date] = true
+	}
+
+	// occurrences that are excepted or no longer part of the rule are removed, so that they come back if the change is undone
+	removed, err := s.deleteUpcomingOccurrences(seriesName, func(se *hfv2.ScheduledEvent) bool {
+		date := se.Annotations[eventseries.OccurrenceAnnotation]
+		if eventseries.IsException(series, date) {
+			return true
+		}
+		_, ok := wanted[date]
+		return !ok && !eventseries.Edited(se) && startsBefore(se, now.Add(ahead))
+	})
+	if err != nil {
+		return err
+	}
+	for _, date := range removed {
+		delete(materialized, date)
+	}
+
+	for i := range existing.Items {
+		se := &existing.Items[i]
+		o, ok := wanted[se.Annotations[eventseries.OccurrenceAnnotation]]
+		if !ok || !upcoming(se, now) || eventseries.Edited(se) {
+			continue
+		}
+		if err = s.updateOccurrence(series, se.Name, o); err != nil {
+			return err
+		}
+	}
+
+	for date, o := range wanted {
+		if materialized[date] {
+			continue
+		}
+		if err = s.createOccurrence(series, o); err != nil {
+			return err
+		}
+		materialized[date] = true
+	}
+
+	today := now.Format(recurrence.DateFormat)
+	return s.updateSeriesStatus(seriesName, func(status *hfv1.ScheduledEventSeriesStatus) {
+		status.Materialized = []string{}
+		for date := range materialized {
+			// past dates can not be materialized again, there is no need to remember them
+			if date != "" && date >= today {
+				status.Materialized = append(status.Materialized, date)
+			}
+		}
+		sort.Strings(status.Materialized)
+		status.LastMaterialized = now.Format(time.UnixDate)
+		status.Error = ""
+	})
+}
+
+// renderOccurrence returns the spec of the scheduled event of an occurrence, restricted bind works like it does
+// for scheduled events created through the api
+func renderOccurrence(series *hfv1.ScheduledEventSeries, seName string, o recurrence.Occurrence) (hfv2.ScheduledEventSpec, error) {
+	spec, err := eventseries.Render(series, o)
+	if err != nil {
+		return spec, err
+	}
+	if spec.RestrictedBind {
+		spec.RestrictedBindValue = seName
+	}
+	return spec, nil
+}
+
+func (s *ScheduledEventController) createOccurrence(series *hfv1.ScheduledEventSeries, o recurrence.Occurrence) error {
+	// the name is derived from the occurrence, so a lost status update does not create it twice
+	name := "se-" + util.GenerateResourceName("se", series.Name+"/"+o.Date(), 10)
+	spec, err := renderOccurrence(series, name, o)
+	if err != nil {
+		return err
+	}
+
+	se := &hfv2.ScheduledEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				util.ScheduledEventSeriesLabel: series.Name,
+			},
+			Annotations: map[string]string{
+				eventseries.OccurrenceAnnotation:   o.Date(),
+				eventseries.TemplateHashAnnotation: eventseries.TemplateHash(spec),
+			},
+		},
+		Spec: spec,
+	}
+
+	se, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Create(s.ctx, se, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	glog.V(4).Infof("created scheduled event %s for %s of series %s", se.Name, o.Date(), series.Name)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		seToUpdate, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, se.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		seToUpdate.Status.SetActive(true)
+		seToUpdate.Status.MarkScheduled()
+		seToUpdate.Status.VirtualMachineSets = []string{}
+
+		_, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, seToUpdate, metav1.UpdateOptions{})
+		return updateErr
+	})
+}
+
+// updateOccurrence applies changes of the series to an occurrence that has not been edited on its own
+func (s *ScheduledEventController) updateOccurrence(series *hfv1.ScheduledEventSeries, seName string, o recurrence.Occurrence) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		se, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if se.Status.IsProvisioned() || eventseries.Edited(se) {
+			return nil
+		}
+
+		spec, err := renderOccurrence(series, se.Name, o)
+		if err != nil {
+			return err
+		}
+		hash := eventseries.TemplateHash(spec)
+		if hash == se.Annotations[eventseries.TemplateHashAnnotation] {
+			return nil
+		}
+
+		if se.Spec.Leaderboard != nil && spec.Leaderboard != nil {
+			// a token shared for this occurrence stays valid
+			spec.Leaderboard.Token = se.Spec.Leaderboard.Token
+		}
+		se.Spec = spec
+		se.Annotations[eventseries.TemplateHashAnnotation] = hash
+
+		_, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Update(s.ctx, se, metav1.UpdateOptions{})
+		glog.V(4).Infof("updated scheduled event %s for %s of series %s", se.Name, o.Date(), series.Name)
+		return err
+	})
+}
+
+// deleteUpcomingOccurrences deletes the occurrences of a series that have neither started nor been provisioned and match
+// the filter. It returns the dates of the deleted occurrences.
+func (s *ScheduledEventController) deleteUpcomingOccurrences(seriesName string, filter func(se *hfv2.ScheduledEvent) bool) ([]string, error) {
+	seList, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventSeriesLabel, seriesName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var deleted []string
+	for i := range seList.Items {
+		se := &seList.Items[i]
+		if se.Status.IsProvisioned() || !upcoming(se, now) || !filter(se) {
+			continue
+		}
+		err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Delete(s.ctx, se.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return deleted, err
+		}
+		glog.V(4).Infof("deleted scheduled event %s of series %s", se.Name, seriesName)
+		deleted = append(deleted, se.Annotations[eventseries.OccurrenceAnnotation])
+	}
+
+	return deleted, nil
+}
+
+func (s *ScheduledEventController) updateSeriesStatus(seriesName string, update func(status *hfv1.ScheduledEventSeriesStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		series, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Get(s.ctx, seriesName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		update(&series.Status)
+
+		_, err = s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).UpdateStatus(s.ctx, series, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func upcoming(se *hfv2.ScheduledEvent, now time.Time) bool {
+	return se.Spec.StartTime.After(now)
+}
+
+func startsBefore(se *hfv2.ScheduledEvent, t time.Time) bool {
+	return se.Spec.StartTime.Time.Before(t)
+}
@@ -6,10 +6,16 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
 	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	hfListersV2 "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"github.com/hobbyfarm/gargantua/v3/pkg/xapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -27,14 +33,20 @@ type SessionController struct {
 	//ssWorkqueue workqueue.RateLimitingInterface
 	ssWorkqueue workqueue.DelayingInterface
 
-	vmLister  hfListers.VirtualMachineLister
-	vmcLister hfListers.VirtualMachineClaimLister
-	ssLister  hfListers.SessionLister
-
-	vmSynced  cache.InformerSynced
-	vmcSynced cache.InformerSynced
-	ssSynced  cache.InformerSynced
-	ctx       context.Context
+	vmLister       hfListers.VirtualMachineLister
+	vmcLister      hfListers.VirtualMachineClaimLister
+	ssLister       hfListers.SessionLister
+	courseLister   hfListers.CourseLister
+	scenarioLister hfListers.ScenarioLister
+	seLister       hfListersV2.ScheduledEventLister
+
+	vmSynced       cache.InformerSynced
+	vmcSynced      cache.InformerSynced
+	ssSynced       cache.InformerSynced
+	courseSynced   cache.InformerSynced
+	scenarioSynced cache.InformerSynced
+	seSynced       cache.InformerSynced
+	ctx            context.Context
 }
 
 func NewSessionController(hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*SessionController, error) {
@@ -44,12 +56,18 @@ func NewSessionController(hfClientSet hfClientset.Interface, hfInformerFactory h
 	ssController.vmSynced = hfInformerFactory.Hobbyfarm().V1().VirtualMachines().Informer().HasSynced
 	ssController.vmcSynced = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Informer().HasSynced
 	ssController.ssSynced = hfInformerFactory.Hobbyfarm().V1().Sessions().Informer().HasSynced
+	ssController.courseSynced = hfInformerFactory.Hobbyfarm().V1().Courses().Informer().HasSynced
+	ssController.scenarioSynced = hfInformerFactory.Hobbyfarm().V1().Scenarios().Informer().HasSynced
+	ssController.seSynced = hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Informer().HasSynced
 
 	//ssController.ssWorkqueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Session")
 	ssController.ssWorkqueue = workqueue.NewNamedDelayingQueue("ssc-ss")
 	ssController.vmLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachines().Lister()
 	ssController.vmcLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
 	ssController.ssLister = hfInformerFactory.Hobbyfarm().V1().Sessions().Lister()
+	ssController.courseLister = hfInformerFactory.Hobbyfarm().V1().Courses().Lister()
+	ssController.scenarioLister = hfInformerFactory.Hobbyfarm().V1().Scenarios().Lister()
+	ssController.seLister = hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Lister()
 
 	ssInformer := hfInformerFactory.Hobbyfarm().V1().Sessions().Informer()
 
@@ -81,8 +99,8 @@ func (s *SessionController) Run(stopCh <-chan struct{}) error {
 
 	glog.V(4).Infof("Starting Session controller")
 	glog.Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, s.vmSynced, s.vmcSynced, s.ssSynced); !ok {
-		return fmt.Errorf("failed to wait for vm, vmc, and ss caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, s.vmSynced, s.vmcSynced, s.ssSynced, s.courseSynced, s.scenarioSynced, s.seSynced); !ok {
+		return fmt.Errorf("failed to wait for vm, vmc, ss, course, scenario and se caches to sync")
 	}
 	glog.Info("Starting ss controller workers")
 	go wait.Until(s.runSSWorker, time.Second, stopCh)
@@ -167,13 +185,23 @@ func (s *SessionController) reconcileSession(ssName string) error {
 		glog.V(6).Infof("deleted old session %s", ss.Name)
 
 		s.FinishProgress(ss.Name, ss.Spec.UserId)
+		for _, member := range ss.Spec.Members {
+			s.FinishProgress(ss.Name, member)
+		}
 
 		return nil
 	}
 
-	if expires.Before(now) && !ss.Status.Finished {
+	// a session that outlived the maximum duration of its policies is cleaned up, even if it was paused or kept alive
+	limits := s.sessionLimits(ss)
+	lifetimeExceeded := limits.Exceeded(ss.Status, now)
+	if deadline, bounded := limits.Deadline(ss.Status); bounded && deadline.Before(expires) {
+		timeUntilExpires = deadline.Sub(now)
+	}
+
+	if (expires.Before(now) || lifetimeExceeded) && !ss.Status.Finished {
 		// we need to set the session to finished and delete the vm's
-		if ss.Status.Active && ss.Status.Paused && ss.Status.PausedTime != "" {
+		if ss.Status.Active && ss.Status.Paused && ss.Status.PausedTime != "" && !lifetimeExceeded {
 			pausedExpiration, err := time.Parse(time.UnixDate, ss.Status.PausedTime)
 			if err != nil {
 				glog.Error(err)
@@ -181,11 +209,16 @@ func (s *SessionController) reconcileSession(ssName string) error {
 
 			if pausedExpiration.After(now) {
 				glog.V(4).Infof("Session %s was paused, and the pause expiration is after now, skipping clean up.", ss.Name)
+				s.ssWorkqueue.AddAfter(ssName, limits.CapExpiration(ss.Status, pausedExpiration).Sub(now))
 				return nil
 			}
 
 			glog.V(4).Infof("Session %s was paused, but the pause expiration was before now, so cleaning up.", ss.Name)
 		}
+
+		if lifetimeExceeded {
+			glog.V(4).Infof("Session %s reached its maximum duration of %s, cleaning up.", ss.Name, limits.MaxSessionDuration)
+		}
 		for _, vmc := range ss.Spec.VmClaimSet {
 			vmcObj, err := s.vmcLister.VirtualMachineClaims(util.GetReleaseNamespace()).Get(vmc)
 
@@ -246,6 +279,47 @@ func (s *SessionController) reconcileSession(ssName string) error {
 	return nil
 }
 
+// sessionLimits merges the session policies of the course, scenario and scheduled event of a session.
+// Objects that no longer exist and invalid policies are ignored.
+func (s *SessionController) sessionLimits(ss *hfv1.Session) sessionpolicy.Limits {
+	var policies []hfv1.SessionPolicy
+
+	if ss.Spec.CourseId != "" {
+		course, err := s.courseLister.Courses(util.GetReleaseNamespace()).Get(ss.Spec.CourseId)
+		if err == nil {
+			policies = append(policies, course.Spec.SessionPolicy)
+		} else if !apierrors.IsNotFound(err) {
+			glog.Errorf("error retrieving course %s for session %s: %v", ss.Spec.CourseId, ss.Name, err)
+		}
+	}
+
+	if ss.Spec.ScenarioId != "" {
+		scenario, err := s.scenarioLister.Scenarios(util.GetReleaseNamespace()).Get(ss.Spec.ScenarioId)
+		if err == nil {
+			policies = append(policies, scenario.Spec.SessionPolicy)
+		} else if !apierrors.IsNotFound(err) {
+			glog.Errorf("error retrieving scenario %s for session %s: %v", ss.Spec.ScenarioId, ss.Name, err)
+		}
+	}
+
+	if seName, ok := ss.Labels[util.ScheduledEventLabel]; ok {
+		se, err := s.seLister.ScheduledEvents(util.GetReleaseNamespace()).Get(seName)
+		if err == nil {
+			policies = append(policies, se.Spec.SessionPolicy)
+		} else if !apierrors.IsNotFound(err) {
+			glog.Errorf("error retrieving scheduled event %s for session %s: %v", seName, ss.Name, err)
+		}
+	}
+
+	limits, err := sessionpolicy.Merge(policies...)
+	if err != nil {
+		glog.Errorf("error merging session policies for session %s: %v", ss.Name, err)
+		return sessionpolicy.Limits{}
+	}
+
+	return limits
+}
+
 func (s *SessionController) taintVM(vmName string) error {
 	glog.V(5).Infof("tainting VM %s", vmName)
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -305,7 +379,7 @@ func (s *SessionController) taintVMC(vmcName string) error {
 func (s *SessionController) FinishProgress(sessionId string, userId string) {
 	now := time.Now()
 
-	progress, err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s,%s=%s,finished=false", util.SessionLabel, sessionId, util.UserLabel, userId)})
 
 	if err != nil {
@@ -314,12 +388,14 @@ func (s *SessionController) FinishProgress(sessionId string, userId string) {
 	}
 
 	for _, p := range progress.Items {
+		var updated *hfv2.Progress
 		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var updateErr error
 			p.Labels["finished"] = "true"
-			p.Spec.LastUpdate = now.Format(time.UnixDate)
-			p.Spec.Finished = "true"
+			p.Spec.LastUpdate = metav1.NewTime(now)
+			p.Spec.Finished = true
 
-			_, updateErr := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).Update(s.ctx, &p, metav1.UpdateOptions{})
+			updated, updateErr = s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(s.ctx, &p, metav1.UpdateOptions{})
 			glog.V(4).Infof("updated progress with ID %s", p.Name)
 
 			return updateErr
@@ -328,5 +404,7 @@ func (s *SessionController) FinishProgress(sessionId string, userId string) {
 			glog.Errorf("error finishing progress %v", err)
 			return
 		}
+
+		xapi.Record(s.ctx, s.hfClientSet, xapi.VerbTerminated, updated)
 	}
 }
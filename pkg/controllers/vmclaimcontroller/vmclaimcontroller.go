@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/golang/glog"
@@ -12,6 +13,7 @@ import (
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
 	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/settingclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,8 +27,27 @@ import (
 const (
 	StaticBindAttemptThreshold  int = 3
 	DynamicBindAttemptThreshold int = 2
+
+	queueRetryInterval  = 15 * time.Second
+	defaultQueueTimeout = 30 * time.Minute
+	// how far the expiration of a session is pushed back while its claim is queued
+	queuedSessionKeepalive = 5 * time.Minute
 )
 
+// capacityError is returned when a claim can not be satisfied right now, but might be once capacity frees up
+type capacityError struct {
+	msg string
+}
+
+func (c capacityError) Error() string {
+	return c.msg
+}
+
+func isCapacityError(err error) bool {
+	_, ok := err.(capacityError)
+	return ok
+}
+
 type VMClaimController struct {
 	hfClientSet hfClientset.Interface
 
@@ -34,7 +55,7 @@ type VMClaimController struct {
 	vmClaimLister hfListers.VirtualMachineClaimLister
 	vmtLister     hfListers.VirtualMachineTemplateLister
 
-	vmClaimWorkqueue workqueue.Interface
+	vmClaimWorkqueue workqueue.DelayingInterface
 
 	vmWorkqueue workqueue.Interface
 
@@ -54,7 +75,7 @@ func NewVMClaimController(hfClientSet hfClientset.Interface, hfInformerFactory h
 	vmClaimController.vmClaimLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
 	vmClaimController.vmtLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineTemplates().Lister()
 
-	vmClaimController.vmClaimWorkqueue = workqueue.New()
+	vmClaimController.vmClaimWorkqueue = workqueue.NewDelayingQueue()
 	vmClaimController.vmWorkqueue = workqueue.New()
 
 	vmClaimInformer := hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Informer()
@@ -227,8 +248,17 @@ func (v *VMClaimController) processNextVMClaim() bool {
 func (v *VMClaimController) updateVMClaimStatus(bound bool, ready bool, vmc *hfv1.VirtualMachineClaim) error {
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		newestVmc, err := v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(v.ctx, vmc.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
 		newestVmc.Status.Bound = bound
 		newestVmc.Status.Ready = ready
+		if bound {
+			// a bound claim leaves the queue
+			newestVmc.Status.Queued = false
+			newestVmc.Status.QueuePosition = 0
+			newestVmc.Status.QueuedTime = ""
+		}
 		newestVmc, err = v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).UpdateStatus(v.ctx, newestVmc, metav1.UpdateOptions{})
 		if err != nil {
 			return err
@@ -251,32 +281,46 @@ func (v *VMClaimController) updateVMClaimStatus(bound bool, ready bool, vmc *hfv
 func (v *VMClaimController) processVMClaim(vmc *hfv1.VirtualMachineClaim) (err error) {
 	if vmc.Status.Tainted {
 		glog.Infof("vmclaim %v is tainted.. cleaning it up", vmc.Name)
-		return v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Delete(v.ctx, vmc.Name, metav1.DeleteOptions{})
+		err = v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Delete(v.ctx, vmc.Name, metav1.DeleteOptions{})
+		if vmc.Status.Queued {
+			v.serveQueue(vmc.Labels[util.ScheduledEventLabel])
+		}
+		return err
 	}
 
 	if !vmc.Status.Bound && !vmc.Status.Ready {
+		// claims that came first are served first
+		if v.waitingInQueue(vmc) {
+			return v.queueVMClaim(vmc)
+		}
+
 		// submit VM requests //
 		// update status
 		if vmc.Status.BindMode == "dynamic" {
 			err = v.submitVirtualMachines(vmc)
-			if err != nil {
-				// VirtualMachines could not be submitted. Delete Session
-				glog.Errorf("error processing vmc %s, taint session: %v", vmc.Name, err)
-				return v.taintSession(vmc.Labels[util.SessionLabel])
-			}
 		} else if vmc.Status.BindMode == "static" {
 			err = v.findVirtualMachines(vmc)
-			if err != nil {
-				// VirtualMachines could not be bound. Delete Session
-				glog.Errorf("error processing vmc %s, taint session: %v", vmc.Name, err)
-				return v.taintSession(vmc.Labels[util.SessionLabel])
-			}
 		} else {
 			glog.Errorf("vmc bind mode needs to be either dynamic or static.. ignoring this object %s", vmc.Name)
 			return nil
 		}
 
-		return v.updateVMClaimStatus(true, false, vmc)
+		if isCapacityError(err) {
+			glog.V(4).Infof("no capacity for vmc %s, queueing: %v", vmc.Name, err)
+			return v.queueVMClaim(vmc)
+		}
+
+		if err != nil {
+			// VirtualMachines could not be submitted or bound. Delete Session
+			glog.Errorf("error processing vmc %s, taint session: %v", vmc.Name, err)
+			return v.taintSession(vmc.Labels[util.SessionLabel])
+		}
+
+		err = v.updateVMClaimStatus(true, false, vmc)
+		if vmc.Status.Queued {
+			v.serveQueue(vmc.Labels[util.ScheduledEventLabel])
+		}
+		return err
 	}
 
 	if vmc.Status.Bound && !vmc.Status.Ready {
@@ -322,7 +366,7 @@ func (v *VMClaimController) taintSession(session string) (err error) {
 	}
 
 	// Remove outstanding Progresses as there was an error with this session
-	err = v.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).DeleteCollection(v.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+	err = v.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).DeleteCollection(v.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s,finished=false", util.SessionLabel, session)})
 
 	return err
@@ -346,9 +390,20 @@ func (v *VMClaimController) submitVirtualMachines(vmc *hfv1.VirtualMachineClaim)
 		return err
 	}
 
+	// only provision vms that are missing, e.g. after a vm of a live session was replaced
+	vmMap := make(map[string]hfv1.VirtualMachineClaimVM)
+	pendingVMs := make(map[string]hfv1.VirtualMachineClaimVM)
+	for vmName, vmDetails := range vmc.Spec.VirtualMachines {
+		if vmDetails.VirtualMachineId != "" {
+			vmMap[vmName] = vmDetails
+		} else {
+			pendingVMs[vmName] = vmDetails
+		}
+	}
+
 	// Calculate required VMs per template
 	requiredTemplateCount := make(map[string]int)
-	for _, vmDetails := range vmc.Spec.VirtualMachines {
+	for _, vmDetails := range pendingVMs {
 		if count, found := requiredTemplateCount[vmDetails.Template]; found {
 			requiredTemplateCount[vmDetails.Template] = count + 1
 		} else {
@@ -371,7 +426,7 @@ func (v *VMClaimController) submitVirtualMachines(vmc *hfv1.VirtualMachineClaim)
 			}
 			reservedCapacity[environment.Name] = reserved
 		}
-		for vmName, vmDetails := range vmc.Spec.VirtualMachines {
+		for vmName, vmDetails := range pendingVMs {
 			env, dbc, err := v.findSuitableEnvironmentForVMTemplate(environments, dbcList, vmDetails.Template, reservedCapacity, vmc.Labels[util.ScheduledEventLabel])
 			if err != nil {
 				glog.Errorf("no suitable environment for %s (%s): %v", vmName, vmDetails.Template, err)
@@ -389,13 +444,12 @@ func (v *VMClaimController) submitVirtualMachines(vmc *hfv1.VirtualMachineClaim)
 				break
 			}
 		}
-		for vmName, _ := range vmc.Spec.VirtualMachines {
+		for vmName, _ := range pendingVMs {
 			environmentMap[vmName] = VMEnvironment{enviroment, bestDBC}
 		}
 	}
 
-	vmMap := make(map[string]hfv1.VirtualMachineClaimVM)
-	for vmName, vmDetails := range vmc.Spec.VirtualMachines {
+	for vmName, vmDetails := range pendingVMs {
 		genName := fmt.Sprintf("%s-%08x", vmc.Spec.BaseName, rand.Uint32())
 		environment := environmentMap[vmName].Environment
 		dbc := environmentMap[vmName].DynamicBindConfiguration
@@ -633,7 +687,7 @@ func (v *VMClaimController) findSuitableEnvironmentForVMTemplate(environments []
 
 	}
 
-	return hfv1.Environment{}, hfv1.DynamicBindConfiguration{}, fmt.Errorf("no suitable environment found. capacity reached")
+	return hfv1.Environment{}, hfv1.DynamicBindConfiguration{}, capacityError{"no suitable environment found. capacity reached"}
 }
 
 func (v *VMClaimController) checkVMStatus(vmc *hfv1.VirtualMachineClaim) (ready bool, err error) {
@@ -659,7 +713,7 @@ func (v *VMClaimController) findScheduledEvent(accessCode string) (schedEvent st
 		return schedEvent, environments, err
 	}
 
-	se, err := v.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(v.ctx, ac.Labels[util.ScheduledEventLabel], metav1.GetOptions{})
+	se, err := v.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(v.ctx, ac.Labels[util.ScheduledEventLabel], metav1.GetOptions{})
 	if err != nil {
 		return schedEvent, environments, err
 	}
@@ -683,21 +737,26 @@ func (v *VMClaimController) findVirtualMachines(vmc *hfv1.VirtualMachineClaim) (
 	}
 
 	vmMap := make(map[string]hfv1.VirtualMachineClaimVM)
+	var assigned []string
 	for name, vmStruct := range vmc.Spec.VirtualMachines {
 		if vmStruct.VirtualMachineId == "" {
 			glog.Info("assigning a vm")
 			vmID, err := v.assignNextFreeVM(vmc.Name, vmc.Spec.UserId, environments, vmStruct.Template, vmc.Spec.RestrictedBind, vmc.Spec.RestrictedBindValue)
 			if err != nil {
 				// If we run into any issue assigning a VM we need to unassign the previously assigned VMs
-				for _, vm := range vmMap {
-					v.unassignVM(vm.VirtualMachineId)
+				for _, vmId := range assigned {
+					v.unassignVM(vmId)
 				}
 				return err
 			}
+			assigned = append(assigned, vmID)
 			vmMap[name] = hfv1.VirtualMachineClaimVM{
 				Template:         vmStruct.Template,
 				VirtualMachineId: vmID,
 			}
+		} else {
+			// already bound, e.g. when only one vm of a live session was replaced
+			vmMap[name] = vmStruct
 		}
 	}
 	vmc.Spec.VirtualMachines = vmMap
@@ -806,7 +865,7 @@ func (v *VMClaimController) assignNextFreeVM(vmClaimId string, user string, envi
 	}
 
 	if len(vms) == 0 {
-		return "", fmt.Errorf("No static VMs matching template: %s. All static VMs are in use.", template)
+		return "", capacityError{fmt.Sprintf("No static VMs matching template: %s. All static VMs are in use.", template)}
 	}
 
 	assigned := false
@@ -845,6 +904,166 @@ func (v *VMClaimController) assignNextFreeVM(vmClaimId string, user string, envi
 		return vmId, nil
 	}
 
-	return vmId, fmt.Errorf("unknown error while assigning next free vm")
+	return vmId, capacityError{fmt.Sprintf("no free vm matching template %s in the environments of the scheduled event", template)}
+
+}
+
+// queueVMClaim puts a claim into the queue of its scheduled event, or keeps it there.
+// Claims that waited longer than the queue timeout end their session.
+func (v *VMClaimController) queueVMClaim(vmc *hfv1.VirtualMachineClaim) error {
+	now := time.Now()
+	seName := vmc.Labels[util.ScheduledEventLabel]
+
+	if vmc.Status.Queued {
+		queuedTime, err := time.Parse(time.UnixDate, vmc.Status.QueuedTime)
+		if err == nil && now.Sub(queuedTime) > queueTimeout() {
+			glog.Infof("vmc %s waited for capacity since %s, taint session", vmc.Name, vmc.Status.QueuedTime)
+			return v.taintSession(vmc.Labels[util.SessionLabel])
+		}
+	} else {
+		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(v.ctx, vmc.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			result.Status.Queued = true
+			result.Status.QueuedTime = now.Format(time.UnixDate)
+
+			result, updateErr := v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).UpdateStatus(v.ctx, result, metav1.UpdateOptions{})
+			if updateErr != nil {
+				return updateErr
+			}
+
+			return util.VerifyVMClaim(v.vmClaimLister, result)
+		})
+		if retryErr != nil {
+			return fmt.Errorf("error queueing vmclaim %s: %v", vmc.Name, retryErr)
+		}
+	}
+
+	if err := v.keepQueuedSessionAlive(vmc.Labels[util.SessionLabel], now); err != nil {
+		glog.Errorf("error keeping session of queued vmc %s alive: %v", vmc.Name, err)
+	}
+
+	v.updateQueuePositions(seName)
+
+	// capacity frees up without us being told, so keep trying
+	v.vmClaimWorkqueue.AddAfter(vmc.Name, queueRetryInterval)
+	return nil
+}
+
+// keepQueuedSessionAlive pushes back the expiration of a session while its claim waits in the queue, the queue timeout
+// decides how long a session may wait. Once the claim is bound, the session has to be kept alive as usual again.
+func (v *VMClaimController) keepQueuedSessionAlive(session string, now time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := v.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(v.ctx, session, metav1.GetOptions{})
+		if errors.IsNotFound(getErr) {
+			return nil
+		}
+		if getErr != nil {
+			return getErr
+		}
+		if result.Status.Finished {
+			return nil
+		}
+
+		// the claim is revisited every retry interval, only write when the expiration comes close
+		expires, err := time.Parse(time.UnixDate, result.Status.ExpirationTime)
+		if err == nil && expires.Sub(now) > queuedSessionKeepalive/2 {
+			return nil
+		}
+
+		result.Status.ExpirationTime = now.Add(queuedSessionKeepalive).Format(time.UnixDate)
+
+		_, updateErr := v.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(v.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+}
+
+// queuedVMClaims returns the claims waiting for capacity in a scheduled event, in the order they are served
+func (v *VMClaimController) queuedVMClaims(seName string) []*hfv1.VirtualMachineClaim {
+	if seName == "" {
+		return nil
+	}
+
+	vmcs, err := v.vmClaimLister.List(labels.Set{util.ScheduledEventLabel: seName}.AsSelector())
+	if err != nil {
+		glog.Errorf("error listing vmclaims of scheduled event %s: %v", seName, err)
+		return nil
+	}
+
+	queue := []*hfv1.VirtualMachineClaim{}
+	for _, vmc := range vmcs {
+		if vmc.Status.Queued && !vmc.Status.Bound && !vmc.Status.Tainted && vmc.DeletionTimestamp.IsZero() {
+			queue = append(queue, vmc)
+		}
+	}
+
+	sort.Slice(queue, func(i, j int) bool {
+		return queuedBefore(queue[i], queue[j])
+	})
+
+	return queue
+}
+
+func queuedBefore(a, b *hfv1.VirtualMachineClaim) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// waitingInQueue returns true if another claim of the same scheduled event is waiting longer than this one
+func (v *VMClaimController) waitingInQueue(vmc *hfv1.VirtualMachineClaim) bool {
+	for _, queued := range v.queuedVMClaims(vmc.Labels[util.ScheduledEventLabel]) {
+		if queued.Name != vmc.Name && queuedBefore(queued, vmc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *VMClaimController) updateQueuePositions(seName string) {
+	for i, queued := range v.queuedVMClaims(seName) {
+		position := i + 1
+		if queued.Status.QueuePosition == position {
+			continue
+		}
+
+		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(v.ctx, queued.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			if !result.Status.Queued {
+				return nil
+			}
+
+			result.Status.QueuePosition = position
+
+			_, updateErr := v.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).UpdateStatus(v.ctx, result, metav1.UpdateOptions{})
+			return updateErr
+		})
+		if retryErr != nil {
+			glog.Errorf("error updating queue position of vmclaim %s: %v", queued.Name, retryErr)
+		}
+	}
+}
+
+// serveQueue gives the next claim in the queue of a scheduled event a chance as soon as a claim left the queue
+func (v *VMClaimController) serveQueue(seName string) {
+	queue := v.queuedVMClaims(seName)
+	if len(queue) > 0 {
+		v.vmClaimWorkqueue.Add(queue[0].Name)
+	}
+}
+
+func queueTimeout() time.Duration {
+	if minutes, ok := settingclient.GetSetting(settingclient.VMClaimQueueTimeout).(int); ok && minutes > 0 {
+		return time.Minute * time.Duration(minutes)
+	}
 
+	return defaultQueueTimeout
 }
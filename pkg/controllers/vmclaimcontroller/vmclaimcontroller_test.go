@@ -0,0 +1,203 @@
+package vmclaimcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var queueStart = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func testVMClaim(name string, se string, created time.Duration, status hfv1.VirtualMachineClaimStatus) *hfv1.VirtualMachineClaim {
+	return &hfv1.VirtualMachineClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         util.GetReleaseNamespace(),
+			CreationTimestamp: metav1.NewTime(queueStart.Add(created)),
+			Labels:            map[string]string{util.ScheduledEventLabel: se},
+		},
+		Status: status,
+	}
+}
+
+func newTestController(t *testing.T, vmcs ...*hfv1.VirtualMachineClaim) *VMClaimController {
+	t.Helper()
+
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	for _, vmc := range vmcs {
+		if _, err := client.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Create(ctx, vmc, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	factory := hfInformers.NewSharedInformerFactory(client, 0)
+	controller, err := NewVMClaimController(client, factory, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	return controller
+}
+
+func Test_QueuedVMClaims(t *testing.T) {
+	queued := hfv1.VirtualMachineClaimStatus{Queued: true}
+
+	controller := newTestController(t,
+		testVMClaim("vmc-late", "se-1", 3*time.Minute, queued),
+		testVMClaim("vmc-early", "se-1", time.Minute, queued),
+		testVMClaim("vmc-tie-b", "se-1", 2*time.Minute, queued),
+		testVMClaim("vmc-tie-a", "se-1", 2*time.Minute, queued),
+		testVMClaim("vmc-bound", "se-1", 0, hfv1.VirtualMachineClaimStatus{Queued: true, Bound: true}),
+		testVMClaim("vmc-tainted", "se-1", 0, hfv1.VirtualMachineClaimStatus{Queued: true, Tainted: true}),
+		testVMClaim("vmc-waiting", "se-1", 0, hfv1.VirtualMachineClaimStatus{}),
+		testVMClaim("vmc-other", "se-2", 0, queued),
+	)
+
+	got := controller.queuedVMClaims("se-1")
+	want := []string{"vmc-early", "vmc-tie-a", "vmc-tie-b", "vmc-late"}
+	if len(got) != len(want) {
+		t.Fatalf("queue has %d claims, want %d", len(got), len(want))
+	}
+	for i, vmc := range got {
+		if vmc.Name != want[i] {
+			t.Errorf("position %d is %s, want %s", i+1, vmc.Name, want[i])
+		}
+	}
+
+	if queue := controller.queuedVMClaims(""); len(queue) != 0 {
+		t.Errorf("claims without a scheduled event have a queue of %d", len(queue))
+	}
+}
+
+func Test_WaitingInQueue(t *testing.T) {
+	queued := hfv1.VirtualMachineClaimStatus{Queued: true}
+
+	controller := newTestController(t,
+		testVMClaim("vmc-first", "se-1", time.Minute, queued),
+		testVMClaim("vmc-second", "se-1", 2*time.Minute, queued),
+		testVMClaim("vmc-other", "se-2", 0, queued),
+	)
+
+	tests := []struct {
+		name string
+		vmc  *hfv1.VirtualMachineClaim
+		want bool
+	}{
+		{"head of the queue", testVMClaim("vmc-first", "se-1", time.Minute, queued), false},
+		{"behind another claim", testVMClaim("vmc-second", "se-1", 2*time.Minute, queued), true},
+		{"new claim behind the queue", testVMClaim("vmc-new", "se-1", 5*time.Minute, hfv1.VirtualMachineClaimStatus{}), true},
+		{"claim older than the queue", testVMClaim("vmc-old", "se-1", 0, hfv1.VirtualMachineClaimStatus{}), false},
+		{"queue of another scheduled event", testVMClaim("vmc-new", "se-3", 5*time.Minute, hfv1.VirtualMachineClaimStatus{}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := controller.waitingInQueue(tt.vmc); got != tt.want {
+				t.Errorf("waitingInQueue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UpdateQueuePositions(t *testing.T) {
+	controller := newTestController(t,
+		testVMClaim("vmc-first", "se-1", time.Minute, hfv1.VirtualMachineClaimStatus{Queued: true, QueuePosition: 2}),
+		testVMClaim("vmc-second", "se-1", 2*time.Minute, hfv1.VirtualMachineClaimStatus{Queued: true, QueuePosition: 3}),
+		testVMClaim("vmc-third", "se-1", 3*time.Minute, hfv1.VirtualMachineClaimStatus{Queued: true}),
+		testVMClaim("vmc-bound", "se-1", 0, hfv1.VirtualMachineClaimStatus{Bound: true, QueuePosition: 1}),
+	)
+
+	controller.updateQueuePositions("se-1")
+
+	want := map[string]int{
+		"vmc-first":  1,
+		"vmc-second": 2,
+		"vmc-third":  3,
+		"vmc-bound":  1, // claims that left the queue are not touched
+	}
+	for name, position := range want {
+		vmc, err := controller.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vmc.Status.QueuePosition != position {
+			t.Errorf("%s has queue position %d, want %d", name, vmc.Status.QueuePosition, position)
+		}
+	}
+}
+
+func Test_QueueVMClaimKeepsSessionAlive(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		queuedFor     time.Duration // zero if the claim enters the queue
+		expiresIn     time.Duration
+		wantExpiresIn time.Duration // zero if the session is ended
+	}{
+		{"entering the queue", 0, time.Minute, queuedSessionKeepalive},
+		{"waiting in the queue", 10 * time.Minute, 30 * time.Second, queuedSessionKeepalive},
+		{"expiration far enough away", 10 * time.Minute, 4 * time.Minute, 4 * time.Minute},
+		{"queue timeout", defaultQueueTimeout + time.Minute, 4 * time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := hfv1.VirtualMachineClaimStatus{}
+			if tt.queuedFor > 0 {
+				status = hfv1.VirtualMachineClaimStatus{Queued: true, QueuedTime: now.Add(-tt.queuedFor).Format(time.UnixDate)}
+			}
+			vmc := testVMClaim("vmc-1", "se-1", 0, status)
+			vmc.Labels[util.SessionLabel] = "ss-1"
+
+			controller := newTestController(t, vmc)
+			ss := &hfv1.Session{
+				ObjectMeta: metav1.ObjectMeta{Name: "ss-1", Namespace: util.GetReleaseNamespace()},
+				Status:     hfv1.SessionStatus{Active: true, ExpirationTime: now.Add(tt.expiresIn).Format(time.UnixDate)},
+			}
+			sessions := controller.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace())
+			if _, err := sessions.Create(context.Background(), ss, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := controller.queueVMClaim(vmc); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := sessions.Get(context.Background(), "ss-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expires, err := time.Parse(time.UnixDate, result.Status.ExpirationTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// timestamps are stored with second precision
+			if got := expires.Sub(now); got < tt.wantExpiresIn-2*time.Second || got > tt.wantExpiresIn+time.Second {
+				t.Errorf("session expires in %s, want %s", got, tt.wantExpiresIn)
+			}
+			if result.Status.Active != (tt.wantExpiresIn > 0) {
+				t.Errorf("session active = %v", result.Status.Active)
+			}
+		})
+	}
+}
+
+func Test_QueueTimeoutDefault(t *testing.T) {
+	// without the setting being loaded the default applies
+	if got := queueTimeout(); got != defaultQueueTimeout {
+		t.Errorf("queueTimeout() = %s, want %s", got, defaultQueueTimeout)
+	}
+}
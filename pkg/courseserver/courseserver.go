@@ -7,19 +7,22 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
 	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/locale"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 )
@@ -43,6 +46,27 @@ type PreparedCourse struct {
 	hfv1.CourseSpec
 }
 
+type PreparedCompletion struct {
+	Course      string              `json:"course"`
+	Completed   []string            `json:"completed"`
+	Locked      map[string][]string `json:"locked"` // scenario to the scenarios that have to be completed first
+	Finished    bool                `json:"finished"`
+	Certificate string              `json:"certificate,omitempty"`
+}
+
+type PreparedTranslations struct {
+	ID           string          `json:"id"`
+	Locale       string          `json:"locale"`
+	Translations []locale.Report `json:"translations"`
+}
+
+type PreparedRevision struct {
+	Revision int               `json:"revision"`
+	Creator  string            `json:"creator"`
+	Created  string            `json:"created"`
+	State    hfv1.ContentState `json:"state"`
+}
+
 func NewCourseServer(authClient *authclient.AuthClient, acClient *accesscode.AccessCodeClient, hfClientset hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*CourseServer, error) {
 	course := CourseServer{}
 
@@ -66,21 +90,33 @@ func NewCourseServer(authClient *authclient.AuthClient, acClient *accesscode.Acc
 func (c CourseServer) SetupRoutes(r *mux.Router) {
 	r.HandleFunc("/course/list/{access_code}", c.ListCoursesForAccesscode).Methods("GET")
 	r.HandleFunc("/course/{course_id}", c.GetCourse).Methods("GET")
+	r.HandleFunc("/course/{course_id}/completion", c.GetCompletionFunc).Methods("GET")
 	r.HandleFunc("/a/course/list", c.ListFunc).Methods("GET")
 	r.HandleFunc("/a/course/new", c.CreateFunc).Methods("POST")
-	r.HandleFunc("/a/course/{course_id}", c.GetCourse).Methods("GET")
+	r.HandleFunc("/a/course/translations", c.ListTranslationsFunc).Methods("GET")
+	r.HandleFunc("/a/course/{course_id}", c.AdminGetFunc).Methods("GET")
 	r.HandleFunc("/a/course/{id}", c.UpdateFunc).Methods("PUT")
 	r.HandleFunc("/a/course/{id}", c.DeleteFunc).Methods("DELETE")
 	r.HandleFunc("/a/course/previewDynamicScenarios", c.previewDynamicScenarios).Methods("POST")
+	r.HandleFunc("/a/course/{id}/translations", c.GetTranslationsFunc).Methods("GET")
+	r.HandleFunc("/a/course/{id}/revisions", c.ListRevisionsFunc).Methods("GET")
+	r.HandleFunc("/a/course/{id}/revision/{revision:[0-9]+}", c.GetRevisionFunc).Methods("GET")
+	r.HandleFunc("/a/course/{id}/revision/{revision:[0-9]+}/rollback", c.RollbackFunc).Methods("POST")
+	r.HandleFunc("/a/course/{id}/diff/{from:[0-9]+}/{to:[0-9]+}", c.DiffFunc).Methods("GET")
 }
 
-func (c CourseServer) getPreparedCourseById(id string) (PreparedCourse, error) {
+// getPreparedCourseById returns a course, drafts are only returned to administrators editing them
+func (c CourseServer) getPreparedCourseById(id string, drafts bool) (PreparedCourse, error) {
 	course, err := c.GetCourseById(id)
 
 	if err != nil {
 		return PreparedCourse{}, fmt.Errorf("error while retrieving course %v", err)
 	}
 
+	if !drafts && !revision.Published(course.Spec.State) {
+		return PreparedCourse{}, fmt.Errorf("course %s is a draft", id)
+	}
+
 	preparedCourse := PreparedCourse{course.Name, course.Spec}
 
 	return preparedCourse, nil
@@ -118,6 +154,14 @@ func (c CourseServer) ListFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c CourseServer) GetCourse(w http.ResponseWriter, r *http.Request) {
+	c.getCourse(w, r, false)
+}
+
+func (c CourseServer) AdminGetFunc(w http.ResponseWriter, r *http.Request) {
+	c.getCourse(w, r, true)
+}
+
+func (c CourseServer) getCourse(w http.ResponseWriter, r *http.Request, drafts bool) {
 	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
@@ -126,7 +170,7 @@ func (c CourseServer) GetCourse(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 
-	course, err := c.getPreparedCourseById(vars["course_id"])
+	course, err := c.getPreparedCourseById(vars["course_id"], drafts)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("error retrieving course: %v", err))
 		return
@@ -142,8 +186,65 @@ func (c CourseServer) GetCourse(w http.ResponseWriter, r *http.Request) {
 	util.ReturnHTTPContent(w, r, 200, "success", encodedCourse)
 }
 
+// GetCompletionFunc returns which scenarios of a course the caller completed and which ones are still locked
+func (c CourseServer) GetCompletionFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := c.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	course, err := c.GetCourseById(vars["course_id"])
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", "course not found")
+		return
+	}
+
+	progress, err := c.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.UserLabel, user.Name)})
+	if err != nil {
+		glog.Errorf("error retrieving progress of user %s: %v", user.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error retrieving progress")
+		return
+	}
+
+	completed := completion.CompletedScenarios(progress.Items, course.Name)
+	prepared := PreparedCompletion{
+		Course:    course.Name,
+		Completed: []string{},
+		Locked:    map[string][]string{},
+		Finished:  completion.CourseCompleted(course.Spec, completed),
+	}
+	for _, scenario := range course.Spec.Scenarios {
+		if completed[scenario] {
+			prepared.Completed = append(prepared.Completed, scenario)
+		}
+		if missing := completion.Missing(course.Spec, scenario, completed); len(missing) > 0 {
+			prepared.Locked[scenario] = missing
+		}
+	}
+
+	if prepared.Finished && course.Spec.Certificate {
+		certificates, err := c.hfClientSet.HobbyfarmV1().Certificates(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s=%s", util.UserLabel, user.Name, util.CourseLabel, course.Name)})
+		if err != nil {
+			glog.Errorf("error retrieving certificates of user %s: %v", user.Name, err)
+		} else if len(certificates.Items) > 0 {
+			prepared.Certificate = certificates.Items[0].Name
+		}
+	}
+
+	encodedCompletion, err := json.Marshal(prepared)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedCompletion)
+}
+
 func (c CourseServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbCreate), w, r)
+	user, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbCreate), w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to create courses")
 		return
@@ -214,6 +315,60 @@ func (c CourseServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sessionPolicy := hfv1.SessionPolicy{}
+	rawSessionPolicy := r.PostFormValue("session_policy")
+	if rawSessionPolicy != "" {
+		err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshalling session policy %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return
+		}
+	}
+
+	state, err := revision.ParseState(r.PostFormValue("state"))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	prerequisites := map[string][]string{}
+	rawPrerequisites := r.PostFormValue("prerequisites")
+	if rawPrerequisites != "" {
+		err = json.Unmarshal([]byte(rawPrerequisites), &prerequisites)
+		if err != nil {
+			glog.Errorf("error while unmarshalling prerequisites %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+	}
+
+	certificate := false
+	rawCertificate := r.PostFormValue("certificate")
+	if rawCertificate != "" {
+		certificate, err = strconv.ParseBool(rawCertificate)
+		if err != nil {
+			glog.Errorf("error while parsing bool: %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+	}
+
+	translations := map[string]hfv1.CourseTranslation{}
+	rawTranslations := r.PostFormValue("translations")
+	if rawTranslations != "" {
+		err = json.Unmarshal([]byte(rawTranslations), &translations)
+		if err != nil {
+			glog.Errorf("error while unmarshalling translations %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+	}
+
 	course := &hfv1.Course{}
 
 	generatedName := util.GenerateResourceName("c", name, 10)
@@ -233,6 +388,28 @@ func (c CourseServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		course.Spec.PauseDuration = pauseDuration
 	}
 	course.Spec.KeepVM = keepVM
+	course.Spec.SessionPolicy = sessionPolicy
+	course.Spec.State = state
+	course.Spec.Revision = 1
+	course.Spec.Sequence = hfv1.CourseSequence(r.PostFormValue("sequence"))
+	if len(prerequisites) > 0 {
+		course.Spec.Prerequisites = prerequisites
+	}
+	course.Spec.Certificate = certificate
+	course.Spec.Locale = r.PostFormValue("locale")
+	if len(translations) > 0 {
+		course.Spec.Translations = translations
+	}
+
+	if err = completion.Validate(course.Spec); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	if err = locale.ValidateCourse(course.Spec); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
 
 	course, err = c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Create(c.ctx, course, metav1.CreateOptions{})
 	if err != nil {
@@ -241,13 +418,18 @@ func (c CourseServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = revision.CreateCourseRevision(c.ctx, c.hfClientSet, course, user.Name)
+	if err != nil {
+		glog.Errorf("error creating revision of course %s: %v", course.Name, err)
+	}
+
 	util.ReturnHTTPMessage(w, r, 201, "created", course.Name)
 	glog.V(4).Infof("Created course %s", course.Name)
 	return
 }
 
 func (c CourseServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
+	user, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scenarios")
 		return
@@ -268,6 +450,16 @@ func (c CourseServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			util.ReturnHTTPMessage(w, r, http.StatusNotFound, "badrequest", "no course found with given ID")
 			return fmt.Errorf("bad")
 		}
+
+		// make sure the revision about to be replaced is recorded, courses from before revisions start at 1
+		if course.Spec.Revision == 0 {
+			course.Spec.Revision = 1
+		}
+		if err = revision.CreateCourseRevision(c.ctx, c.hfClientSet, course, ""); err != nil {
+			return err
+		}
+		previous := *course.Spec.DeepCopy()
+
 		// name, description, scenarios, virtualmachines, keepaliveduration, pauseduration, pauseable
 
 		name := r.PostFormValue("name")
@@ -279,6 +471,13 @@ func (c CourseServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 		pauseDuration := r.PostFormValue("pause_duration")
 		pauseableRaw := r.PostFormValue("pauseable")
 		keepVMRaw := r.PostFormValue("keep_vm")
+		rawSessionPolicy := r.PostFormValue("session_policy")
+		rawState := r.PostFormValue("state")
+		rawSequence, sequenceSet := r.PostForm["sequence"]
+		rawPrerequisites := r.PostFormValue("prerequisites")
+		rawCertificate := r.PostFormValue("certificate")
+		rawLocale := r.PostFormValue("locale")
+		rawTranslations := r.PostFormValue("translations")
 
 		if name != "" {
 			course.Spec.Name = name
@@ -353,8 +552,98 @@ func (c CourseServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			course.Spec.KeepVM = keepVM
 		}
 
-		_, updateErr := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Update(c.ctx, course, metav1.UpdateOptions{})
-		return updateErr
+		if rawSessionPolicy != "" {
+			sessionPolicy := hfv1.SessionPolicy{}
+			err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshalling session policy %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return fmt.Errorf("bad")
+			}
+
+			course.Spec.SessionPolicy = sessionPolicy
+		}
+
+		if rawState != "" {
+			course.Spec.State, err = revision.ParseState(rawState)
+			if err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return fmt.Errorf("bad")
+			}
+		}
+
+		// an empty sequence is valid, it allows any order
+		if sequenceSet {
+			course.Spec.Sequence = hfv1.CourseSequence(rawSequence[0])
+		}
+
+		if rawPrerequisites != "" {
+			prerequisites := map[string][]string{}
+			err = json.Unmarshal([]byte(rawPrerequisites), &prerequisites)
+			if err != nil {
+				glog.Errorf("error while unmarshalling prerequisites %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			course.Spec.Prerequisites = nil
+			if len(prerequisites) > 0 {
+				course.Spec.Prerequisites = prerequisites
+			}
+		}
+
+		if rawCertificate != "" {
+			certificate, err := strconv.ParseBool(rawCertificate)
+			if err != nil {
+				glog.Errorf("error while parsing bool: %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+
+			course.Spec.Certificate = certificate
+		}
+
+		if rawLocale != "" {
+			course.Spec.Locale = rawLocale
+		}
+
+		if rawTranslations != "" {
+			translations := map[string]hfv1.CourseTranslation{}
+			err = json.Unmarshal([]byte(rawTranslations), &translations)
+			if err != nil {
+				glog.Errorf("error while unmarshalling translations %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			course.Spec.Translations = nil
+			if len(translations) > 0 {
+				course.Spec.Translations = translations
+			}
+		}
+
+		if err = completion.Validate(course.Spec); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if err = locale.ValidateCourse(course.Spec); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if revision.CourseChanged(previous, course.Spec) {
+			course.Spec.Revision = previous.Revision + 1
+		}
+
+		updated, updateErr := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Update(c.ctx, course, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return revision.CreateCourseRevision(c.ctx, c.hfClientSet, updated, user.Name)
 	})
 
 	if retryErr != nil {
@@ -386,7 +675,7 @@ func (c CourseServer) DeleteFunc(w http.ResponseWriter, r *http.Request) {
 	// 1. when there are no active scheduled events using the course
 	// 2. when there are no sessions using the course
 
-	seList, err := c.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{})
+	seList, err := c.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{})
 	if err != nil {
 		glog.Errorf("error retrieving scheduledevent list: %v", err)
 		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error while deleting course")
@@ -478,13 +767,39 @@ func (c CourseServer) ListCoursesForAccesscode(w http.ResponseWriter, r *http.Re
 
 	courseIds = util.UniqueStringSlice(courseIds)
 
+	// learners see the revisions pinned by the scheduled event
+	pinned := map[string]int{}
+	ac, err := c.acClient.GetAccessCodeWithOTACs(accessCode)
+	if err != nil {
+		glog.Errorf("error retrieving access code: %s %v", accessCode, err)
+	} else if seId := ac.Labels[util.ScheduledEventLabel]; seId != "" {
+		se, err := c.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(c.ctx, seId, metav1.GetOptions{})
+		if err != nil {
+			glog.Errorf("error retrieving scheduled event %s: %v", seId, err)
+		} else {
+			pinned = se.Status.CourseRevisions
+		}
+	}
+
+	preferred := locale.Preferred(r, user.Spec.Settings)
+
 	var courses []PreparedCourse
 	for _, courseId := range courseIds {
 		course, err := c.GetCourseById(courseId)
 		if err != nil {
 			glog.Errorf("error retrieving course %v", err)
 		} else {
+			course.Spec, err = revision.CourseSpec(c.ctx, c.hfClientSet, course, revision.Pinned(pinned, course.Name, course.Spec.Revision))
+			if err != nil {
+				glog.Errorf("error retrieving course revision %v", err)
+				continue
+			}
+			if !revision.Published(course.Spec.State) {
+				continue
+			}
+
 			course.Spec.Scenarios = c.AppendDynamicScenariosByCategories(course.Spec.Scenarios, course.Spec.Categories)
+			course.Spec, _ = locale.Course(course.Spec, preferred)
 
 			pCourse := PreparedCourse{course.Name, course.Spec}
 			courses = append(courses, pCourse)
@@ -498,6 +813,56 @@ func (c CourseServer) ListCoursesForAccesscode(w http.ResponseWriter, r *http.Re
 	util.ReturnHTTPContent(w, r, 200, "success", encodedCourses)
 }
 
+// ListTranslationsFunc reports how complete the translations of every course are
+func (c CourseServer) ListTranslationsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list courses")
+		return
+	}
+
+	courses, err := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		glog.Errorf("error listing courses: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error listing courses")
+		return
+	}
+
+	preparedTranslations := []PreparedTranslations{}
+	for _, course := range courses.Items {
+		preparedTranslations = append(preparedTranslations, PreparedTranslations{course.Name, course.Spec.Locale, locale.CourseCompleteness(course.Spec)})
+	}
+
+	encodedTranslations, err := json.Marshal(preparedTranslations)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedTranslations)
+}
+
+// GetTranslationsFunc reports how complete the translations of a course are, listing the fields that are missing
+func (c CourseServer) GetTranslationsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	course, err := c.GetCourseById(id)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("course %s not found", id))
+		return
+	}
+
+	encodedTranslations, err := json.Marshal(PreparedTranslations{course.Name, course.Spec.Locale, locale.CourseCompleteness(course.Spec)})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedTranslations)
+}
+
 func (c CourseServer) previewDynamicScenarios(w http.ResponseWriter, r *http.Request) {
 	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission("scenarios", rbacclient.VerbList), w, r)
 	if err != nil {
@@ -529,21 +894,9 @@ func (c CourseServer) previewDynamicScenarios(w http.ResponseWriter, r *http.Req
 
 func (c CourseServer) AppendDynamicScenariosByCategories(scenariosList []string, categories []string) []string {
 	for _, categoryQuery := range categories {
-		categorySelectors := []string{}
-		categoryQueryParts := strings.Split(categoryQuery, "&")
-		for _, categoryQueryPart := range categoryQueryParts {
-			operator := "in"
-			if strings.HasPrefix(categoryQueryPart, "!") {
-				operator = "notin"
-				categoryQueryPart = categoryQueryPart[1:]
-			}
-			categorySelectors = append(categorySelectors, fmt.Sprintf("category-%s %s (true)", categoryQueryPart, operator))
-		}
-		categorySelectorString := strings.Join(categorySelectors, ",")
-
-		selector, err := labels.Parse(categorySelectorString)
+		selector, err := util.CategorySelector(categoryQuery)
 		if err != nil {
-			glog.Errorf("error while parsing label selector %s: %v", categorySelectorString, err)
+			glog.Errorf("error while parsing category query %s: %v", categoryQuery, err)
 			continue
 		}
 
@@ -586,10 +939,10 @@ func (c CourseServer) GetCourseById(id string) (hfv1.Course, error) {
 }
 
 // Filter a ScheduledEventList to find SEs that are a) active and b) using the course specified
-func filterScheduledEvents(course string, seList *hfv1.ScheduledEventList) *[]hfv1.ScheduledEvent {
-	outList := make([]hfv1.ScheduledEvent, 0)
+func filterScheduledEvents(course string, seList *hfv2.ScheduledEventList) *[]hfv2.ScheduledEvent {
+	outList := make([]hfv2.ScheduledEvent, 0)
 	for _, se := range seList.Items {
-		if se.Status.Finished == true {
+		if se.Status.IsFinished() {
 			continue
 		}
 
@@ -622,3 +975,170 @@ func idIndexer(obj interface{}) ([]string, error) {
 	}
 	return []string{course.Name}, nil
 }
+
+func (c CourseServer) ListRevisionsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	revisions, err := revision.ListCourseRevisions(c.ctx, c.hfClientSet, id)
+	if err != nil {
+		glog.Errorf("error listing revisions of course %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error listing revisions")
+		return
+	}
+
+	preparedRevisions := []PreparedRevision{}
+	for _, rev := range revisions {
+		preparedRevisions = append(preparedRevisions, PreparedRevision{
+			Revision: rev.Spec.Revision,
+			Creator:  rev.Spec.Creator,
+			Created:  rev.Spec.Created,
+			State:    rev.Spec.Snapshot.State,
+		})
+	}
+
+	encodedRevisions, err := json.Marshal(preparedRevisions)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedRevisions)
+}
+
+func (c CourseServer) GetRevisionFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	spec, ok := c.getRevisionSpec(w, r, vars["id"], vars["revision"])
+	if !ok {
+		return
+	}
+
+	encodedCourse, err := json.Marshal(PreparedCourse{vars["id"], spec})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedCourse)
+}
+
+func (c CourseServer) DiffFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to courses")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	from, ok := c.getRevisionSpec(w, r, vars["id"], vars["from"])
+	if !ok {
+		return
+	}
+	to, ok := c.getRevisionSpec(w, r, vars["id"], vars["to"])
+	if !ok {
+		return
+	}
+
+	changes, err := revision.Diff(from, to)
+	if err != nil {
+		glog.Errorf("error comparing revisions of course %s: %v", vars["id"], err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error comparing revisions")
+		return
+	}
+
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedChanges)
+}
+
+// RollbackFunc restores the content of an older revision. The restored content becomes a new revision, history is never rewritten.
+func (c CourseServer) RollbackFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := c.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update courses")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	target, ok := c.getRevisionSpec(w, r, id, vars["revision"])
+	if !ok {
+		return
+	}
+
+	var restored int
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		course, err := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(c.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if course.Spec.Revision == 0 {
+			course.Spec.Revision = 1
+		}
+		if err = revision.CreateCourseRevision(c.ctx, c.hfClientSet, course, ""); err != nil {
+			return err
+		}
+
+		spec := *target.DeepCopy()
+		spec.State = course.Spec.State
+		spec.Revision = course.Spec.Revision + 1
+		course.Spec = spec
+
+		updated, updateErr := c.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Update(c.ctx, course, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		restored = updated.Spec.Revision
+		return revision.CreateCourseRevision(c.ctx, c.hfClientSet, updated, user.Name)
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error rolling back course %s: %v", id, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error attempting to roll back")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", strconv.Itoa(restored))
+	glog.V(4).Infof("Rolled back course %s to revision %s", id, vars["revision"])
+}
+
+func (c CourseServer) getRevisionSpec(w http.ResponseWriter, r *http.Request, id string, rawRevision string) (hfv1.CourseSpec, bool) {
+	rev, err := strconv.Atoi(rawRevision)
+	if err != nil || rev < 1 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid revision")
+		return hfv1.CourseSpec{}, false
+	}
+
+	course, err := c.GetCourseById(id)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("course %s not found", id))
+		return hfv1.CourseSpec{}, false
+	}
+
+	if rev > course.Spec.Revision && rev > 1 {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("revision %d of course %s not found", rev, id))
+		return hfv1.CourseSpec{}, false
+	}
+
+	spec, err := revision.CourseSpec(c.ctx, c.hfClientSet, course, rev)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("revision %d of course %s not found", rev, id))
+		return hfv1.CourseSpec{}, false
+	}
+
+	return spec, true
+}
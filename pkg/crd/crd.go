@@ -65,11 +65,94 @@ func GenerateCRDs(caBundle string, reference ServiceReference) []crder.CRD {
 			c.
 				IsNamespaced(true).
 				AddVersion("v1", &v1.Course{}, nil)
+			c.AddValidation("courses.hobbyfarm.io", validated("courses", caBundle, reference))
 		}),
 		hobbyfarmCRD(&v1.Scenario{}, func(c *crder.CRD) {
 			c.
 				IsNamespaced(true).
 				AddVersion("v1", &v1.Scenario{}, nil)
+			c.AddValidation("scenarios.hobbyfarm.io", validated("scenarios", caBundle, reference))
+		}),
+		hobbyfarmCRD(&v1.ScenarioRevision{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.ScenarioRevision{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Scenario", ".spec.scenario").
+						WithColumn("Revision", ".spec.revision").
+						WithColumn("Creator", ".spec.creator").
+						WithColumn("Created", ".spec.created")
+				})
+			c.AddValidation("scenariorevisions.hobbyfarm.io", immutable("scenariorevisions", caBundle, reference))
+		}),
+		hobbyfarmCRD(&v1.CourseRevision{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.CourseRevision{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Course", ".spec.course").
+						WithColumn("Revision", ".spec.revision").
+						WithColumn("Creator", ".spec.creator").
+						WithColumn("Created", ".spec.created")
+				})
+			c.AddValidation("courserevisions.hobbyfarm.io", immutable("courserevisions", caBundle, reference))
+		}),
+		hobbyfarmCRD(&v1.ContentSource{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.ContentSource{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("URL", ".spec.url").
+						WithColumn("Synced", ".status.synced").
+						WithColumn("Commit", ".status.commit").
+						WithColumn("LastSync", ".status.last_sync_time").
+						WithStatus()
+				})
+		}),
+		hobbyfarmCRD(&v1.Certificate{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.Certificate{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("User", ".spec.user").
+						WithColumn("Course", ".spec.course").
+						WithColumn("Issued", ".spec.issued")
+				})
+		}),
+		hobbyfarmCRD(&v1.LearningRecord{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.LearningRecord{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Verb", ".spec.verb").
+						WithColumn("User", ".spec.user").
+						WithColumn("Attempts", ".status.attempts").
+						WithColumn("Rejected", ".status.rejected").
+						WithStatus()
+				})
+		}),
+		hobbyfarmCRD(&v1.LTIPlatform{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.LTIPlatform{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Name", ".spec.name").
+						WithColumn("Issuer", ".spec.issuer").
+						WithColumn("ClientId", ".spec.client_id")
+				})
+		}),
+		hobbyfarmCRD(&v1.LTILaunch{}, func(c *crder.CRD) {
+			c.
+				IsNamespaced(true).
+				AddVersion("v1", &v1.LTILaunch{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Platform", ".spec.platform").
+						WithColumn("User", ".spec.user").
+						WithColumn("Scenario", ".spec.scenario").
+						WithColumn("Course", ".spec.course").
+						WithColumn("LastSync", ".status.last_sync").
+						WithStatus()
+				})
 		}),
 		hobbyfarmCRD(&v1.Session{}, func(c *crder.CRD) {
 			c.
@@ -96,6 +179,29 @@ func GenerateCRDs(caBundle string, reference ServiceReference) []crder.CRD {
 						WithColumn("User", ".spec.user").
 						WithColumn("Started", ".spec.started").
 						WithColumn("LastUpdate", ".spec.last_update")
+
+					cv.IsServed(true)
+					cv.IsStored(false)
+				}).
+				AddVersion("v2", &v2.Progress{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("CurrentStep", ".spec.current_step").
+						WithColumn("Course", ".spec.course").
+						WithColumn("Scenario", ".spec.scenario").
+						WithColumn("User", ".spec.user").
+						WithColumn("Finished", ".spec.finished").
+						WithColumn("Started", ".spec.started").
+						WithColumn("LastUpdate", ".spec.last_update")
+
+					cv.IsServed(true)
+					cv.IsStored(true)
+				}).
+				WithConversion(func(cc *crder.Conversion) {
+					cc.
+						StrategyWebhook().
+						WithCABundle(caBundle).
+						WithService(reference.Toapiextv1WithPath("/conversion/progresses.hobbyfarm.io")).
+						WithVersions("v2", "v1")
 				})
 		}),
 		hobbyfarmCRD(&v1.AccessCode{}, func(c *crder.CRD) {
@@ -149,6 +255,43 @@ func GenerateCRDs(caBundle string, reference ServiceReference) []crder.CRD {
 						WithColumn("Active", ".status.active").
 						WithColumn("Finished", ".status.finished").
 						WithStatus()
+
+					cv.IsServed(true)
+					cv.IsStored(false)
+				}).
+				AddVersion("v2", &v2.ScheduledEvent{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("AccessCode", ".spec.access_code").
+						WithColumn("Start", ".spec.start_time").
+						WithColumn("TimeZone", ".spec.time_zone").
+						WithColumn("Ready", ".status.conditions[?(@.type==\"Ready\")].status").
+						WithColumn("VMsReady", ".status.readiness.ready").
+						WithColumn("VMsTotal", ".status.readiness.total").
+						WithColumn("Finished", ".status.conditions[?(@.type==\"Finished\")].status").
+						WithColumn("Failed", ".status.conditions[?(@.type==\"Failed\")].reason").
+						WithStatus()
+
+					cv.IsServed(true)
+					cv.IsStored(true)
+				}).
+				WithConversion(func(cc *crder.Conversion) {
+					cc.
+						StrategyWebhook().
+						WithCABundle(caBundle).
+						WithService(reference.Toapiextv1WithPath("/conversion/scheduledevents.hobbyfarm.io")).
+						WithVersions("v2", "v1")
+				})
+		}),
+		hobbyfarmCRD(&v1.ScheduledEventSeries{}, func(c *crder.CRD) {
+			c.
+				WithNames("scheduledeventseries", "scheduledeventseries").
+				IsNamespaced(true).
+				AddVersion("v1", &v1.ScheduledEventSeries{}, func(cv *crder.Version) {
+					cv.
+						WithColumn("Recurrence", ".spec.recurrence").
+						WithColumn("AccessCodePattern", ".spec.access_code_pattern").
+						WithColumn("LastMaterialized", ".status.last_materialized").
+						WithStatus()
 				})
 		}),
 		hobbyfarmCRD(&v1.PredefinedService{}, func(c *crder.CRD) {
@@ -232,6 +375,38 @@ func GenerateCRDs(caBundle string, reference ServiceReference) []crder.CRD {
 	}
 }
 
+// immutable rejects every update of a resource through the validation webhook
+func immutable(resource string, caBundle string, reference ServiceReference) func(vv *crder.Validation) {
+	return validation(resource, []v12.OperationType{v12.Update}, caBundle, reference)
+}
+
+// validated passes every created or updated resource through the validation webhook
+func validated(resource string, caBundle string, reference ServiceReference) func(vv *crder.Validation) {
+	return validation(resource, []v12.OperationType{v12.Create, v12.Update}, caBundle, reference)
+}
+
+func validation(resource string, operations []v12.OperationType, caBundle string, reference ServiceReference) func(vv *crder.Validation) {
+	return func(vv *crder.Validation) {
+		vv.AddRules(v12.RuleWithOperations{
+			Operations: operations,
+			Rule: v12.Rule{
+				APIGroups:   []string{v1.SchemeGroupVersion.Group},
+				APIVersions: []string{v1.SchemeGroupVersion.Version},
+				Resources:   []string{resource},
+			},
+		})
+		vv.WithCABundle(caBundle)
+		vv.WithService(reference.ToadmissionRegistrationv1WithPath(fmt.Sprintf("/validation/hobbyfarm.io/v1/%s", resource)))
+		vv.WithVersions("v1")
+		vv.SetNamespaceSelector(v13.LabelSelector{
+			MatchLabels: map[string]string{
+				namespaceNameLabel: util.GetReleaseNamespace(),
+			},
+		})
+		vv.MatchPolicyExact()
+	}
+}
+
 func hobbyfarmCRD(obj interface{}, customize func(c *crder.CRD)) crder.CRD {
 	return *crder.NewCRD(obj, "hobbyfarm.io", customize)
 }
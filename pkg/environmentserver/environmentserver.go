@@ -11,6 +11,7 @@ import (
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -231,6 +232,22 @@ func (e EnvironmentServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var prewarmPolicy *hfv1.PrewarmPolicy
+	if rawPrewarm := r.PostFormValue("prewarm"); rawPrewarm != "" {
+		err = json.Unmarshal([]byte(rawPrewarm), &prewarmPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshaling prewarm (create environment) %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if prewarmPolicy != nil {
+			if err = prewarm.Validate(*prewarmPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return
+			}
+		}
+	}
+
 	environment := &hfv1.Environment{}
 	hasher := sha256.New()
 	hasher.Write([]byte(time.Now().String())) // generate random name
@@ -245,6 +262,7 @@ func (e EnvironmentServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 	environment.Spec.IPTranslationMap = ipTranslationUnmarshaled
 	environment.Spec.WsEndpoint = wsEndpoint
 	environment.Spec.CountCapacity = countCapacityUnmarshaled
+	environment.Spec.Prewarm = prewarmPolicy
 
 	environment, err = e.hfClientSet.HobbyfarmV1().Environments(util.GetReleaseNamespace()).Create(e.ctx, environment, metav1.CreateOptions{})
 	if err != nil {
@@ -288,6 +306,7 @@ func (e EnvironmentServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 		ipTranslationMap := r.PostFormValue("ip_translation_map")
 		wsEndpoint := r.PostFormValue("ws_endpoint")
 		countCapacity := r.PostFormValue("count_capacity")
+		rawPrewarm := r.PostFormValue("prewarm")
 
 		if len(displayName) > 0 {
 			environment.Spec.DisplayName = displayName
@@ -349,6 +368,23 @@ func (e EnvironmentServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			environment.Spec.WsEndpoint = wsEndpoint
 		}
 
+		if len(rawPrewarm) > 0 {
+			var prewarmPolicy *hfv1.PrewarmPolicy
+			err = json.Unmarshal([]byte(rawPrewarm), &prewarmPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshaling prewarm (update environment) %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			if prewarmPolicy != nil {
+				if err = prewarm.Validate(*prewarmPolicy); err != nil {
+					util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+					return err
+				}
+			}
+			environment.Spec.Prewarm = prewarmPolicy
+		}
+
 		_, updateErr := e.hfClientSet.HobbyfarmV1().Environments(util.GetReleaseNamespace()).Update(e.ctx, &environment, metav1.UpdateOptions{})
 		return updateErr
 	})
@@ -0,0 +1,170 @@
+package eventseries
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/recurrence"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// OccurrenceAnnotation is the date of the occurrence a scheduled event was created for
+	OccurrenceAnnotation = "hobbyfarm.io/occurrence"
+	// TemplateHashAnnotation is the hash of the spec the series created the scheduled event with. If the spec of the
+	// scheduled event no longer matches it, the occurrence was edited on its own and the series leaves it alone.
+	TemplateHashAnnotation = "hobbyfarm.io/series-template-hash"
+
+	DefaultMaterializeAhead = 14 * 24 * time.Hour
+)
+
+// Validate checks a series before it is stored
+func Validate(series *hfv1.ScheduledEventSeries) error {
+	if series.Spec.TimeZone != "" {
+		if _, err := time.LoadLocation(series.Spec.TimeZone); err != nil {
+			return fmt.Errorf("unknown time zone %s", series.Spec.TimeZone)
+		}
+	}
+
+	if _, err := recurrence.Parse(series.Spec.Recurrence, hfv2.LoadLocation(series.Spec.TimeZone)); err != nil {
+		return err
+	}
+
+	start, end, err := templateTimes(series)
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end time must be after start time")
+	}
+
+	for _, exception := range series.Spec.Exceptions {
+		if _, err := time.Parse(recurrence.DateFormat, exception); err != nil {
+			return fmt.Errorf("invalid exception %s, expected a date like %s", exception, recurrence.DateFormat)
+		}
+	}
+
+	pattern := series.Spec.AccessCodePattern
+	if !strings.Contains(pattern, "{date}") && !strings.Contains(pattern, "{index}") {
+		return fmt.Errorf("access code pattern must contain {date} or {index}")
+	}
+	code := AccessCode(series, recurrence.Occurrence{Start: start})
+	if errs := validation.IsDNS1123Subdomain(code); len(errs) > 0 {
+		return fmt.Errorf("access code pattern renders invalid access code %s: %s", code, strings.Join(errs, ", "))
+	}
+
+	if _, err := MaterializeAhead(series); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MaterializeAhead returns how long before their start occurrences are created
+func MaterializeAhead(series *hfv1.ScheduledEventSeries) (time.Duration, error) {
+	if series.Spec.MaterializeAhead == "" {
+		return DefaultMaterializeAhead, nil
+	}
+	ahead, err := time.ParseDuration(series.Spec.MaterializeAhead)
+	if err != nil || ahead <= 0 {
+		return 0, fmt.Errorf("invalid materialize_ahead %s", series.Spec.MaterializeAhead)
+	}
+	return ahead, nil
+}
+
+// Occurrences returns the occurrences of a series that start within [from, to], without exceptions
+func Occurrences(series *hfv1.ScheduledEventSeries, from time.Time, to time.Time) ([]recurrence.Occurrence, error) {
+	rule, err := recurrence.Parse(series.Spec.Recurrence, hfv2.LoadLocation(series.Spec.TimeZone))
+	if err != nil {
+		return nil, err
+	}
+	first, _, err := templateTimes(series)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []recurrence.Occurrence
+	for _, o := range rule.Between(first, from, to) {
+		if !IsException(series, o.Date()) {
+			occurrences = append(occurrences, o)
+		}
+	}
+	return occurrences, nil
+}
+
+func IsException(series *hfv1.ScheduledEventSeries, date string) bool {
+	for _, exception := range series.Spec.Exceptions {
+		if exception == date {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessCode renders the access code pattern for an occurrence. {index} counts from 1.
+func AccessCode(series *hfv1.ScheduledEventSeries, o recurrence.Occurrence) string {
+	return strings.NewReplacer(
+		"{series}", series.Name,
+		"{date}", o.Start.Format("20060102"),
+		"{index}", strconv.Itoa(o.Index+1),
+	).Replace(series.Spec.AccessCodePattern)
+}
+
+// Render returns the spec of the scheduled event for an occurrence. It lasts as long as the template and gets its own
+// access code, restricted bind is set by the caller since it depends on the name of the scheduled event.
+func Render(series *hfv1.ScheduledEventSeries, o recurrence.Occurrence) (hfv2.ScheduledEventSpec, error) {
+	start, end, err := templateTimes(series)
+	if err != nil {
+		return hfv2.ScheduledEventSpec{}, err
+	}
+
+	spec := hfv2.ConvertScheduledEventSpecFromV1(*series.Spec.Template.DeepCopy(), series.Spec.TimeZone)
+	spec.StartTime = metav1.NewTime(o.Start)
+	spec.EndTime = metav1.NewTime(o.Start.Add(end.Sub(start)))
+	spec.AccessCode = AccessCode(series, o)
+	if spec.Leaderboard != nil {
+		// every occurrence gets its own token when it is shared
+		spec.Leaderboard.Token = ""
+	}
+	return spec, nil
+}
+
+// TemplateHash identifies a rendered spec, the leaderboard token is left out as rotating it is not an edit
+func TemplateHash(spec hfv2.ScheduledEventSpec) string {
+	spec = *spec.DeepCopy()
+	if spec.Leaderboard != nil {
+		spec.Leaderboard.Token = ""
+	}
+	raw, _ := json.Marshal(spec)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Edited returns true if an occurrence was changed on its own since the series created or last updated it
+func Edited(se *hfv2.ScheduledEvent) bool {
+	return se.Annotations[TemplateHashAnnotation] != TemplateHash(se.Spec)
+}
+
+// templateTimes returns the times of the first occurrence in the time zone of the series, so that later occurrences
+// keep their local time of day across daylight saving time changes
+func templateTimes(series *hfv1.ScheduledEventSeries) (time.Time, time.Time, error) {
+	loc := hfv2.LoadLocation(series.Spec.TimeZone)
+	template := series.Spec.Template
+	start, err := hfv2.ParseScheduledEventTime(template.StartTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time %s", template.StartTime)
+	}
+	end, err := hfv2.ParseScheduledEventTime(template.EndTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time %s", template.EndTime)
+	}
+	return start.In(loc), end.In(loc), nil
+}
@@ -0,0 +1,164 @@
+package eventseries
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/recurrence"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testSeries(recurrenceRule string, timeZone string) *hfv1.ScheduledEventSeries {
+	return &hfv1.ScheduledEventSeries{
+		ObjectMeta: metav1.ObjectMeta{Name: "ses-weekly"},
+		Spec: hfv1.ScheduledEventSeriesSpec{
+			Template: hfv1.ScheduledEventSpec{
+				Name:      "Weekly lab",
+				StartTime: "Tue Mar  5 09:00:00 UTC 2024",
+				EndTime:   "Tue Mar  5 11:00:00 UTC 2024",
+			},
+			Recurrence:        recurrenceRule,
+			AccessCodePattern: "{series}-{date}",
+			TimeZone:          timeZone,
+		},
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(series *hfv1.ScheduledEventSeries)
+		wantErr bool
+	}{
+		{"valid", func(series *hfv1.ScheduledEventSeries) {}, false},
+		{"invalid recurrence", func(series *hfv1.ScheduledEventSeries) { series.Spec.Recurrence = "FREQ=HOURLY" }, true},
+		{"unknown time zone", func(series *hfv1.ScheduledEventSeries) { series.Spec.TimeZone = "Mars/Olympus_Mons" }, true},
+		{"end before start", func(series *hfv1.ScheduledEventSeries) {
+			series.Spec.Template.EndTime = "Tue Mar  5 08:00:00 UTC 2024"
+		}, true},
+		{"invalid exception", func(series *hfv1.ScheduledEventSeries) { series.Spec.Exceptions = []string{"12/03/2024"} }, true},
+		{"pattern without placeholder", func(series *hfv1.ScheduledEventSeries) { series.Spec.AccessCodePattern = "lab" }, true},
+		{"pattern renders invalid code", func(series *hfv1.ScheduledEventSeries) { series.Spec.AccessCodePattern = "Lab_{index}" }, true},
+		{"invalid materialize ahead", func(series *hfv1.ScheduledEventSeries) { series.Spec.MaterializeAhead = "-1h" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := testSeries("FREQ=WEEKLY;COUNT=4", "Europe/Berlin")
+			tt.modify(series)
+			if err := Validate(series); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Occurrences(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		timeZone   string
+		exceptions []string
+		want       []string // starts formatted as time.RFC3339
+		wantIndex  []int
+	}{
+		{
+			name:      "utc",
+			rule:      "FREQ=WEEKLY;COUNT=3",
+			want:      []string{"2024-03-05T09:00:00Z", "2024-03-12T09:00:00Z", "2024-03-19T09:00:00Z"},
+			wantIndex: []int{0, 1, 2},
+		},
+		{
+			name:     "local time of day is kept across daylight saving time",
+			rule:     "FREQ=WEEKLY;COUNT=5",
+			timeZone: "Europe/Berlin",
+			// 09:00 utc is 10:00 in berlin, which switches to summer time on march 31st
+			want:      []string{"2024-03-05T10:00:00+01:00", "2024-03-12T10:00:00+01:00", "2024-03-19T10:00:00+01:00", "2024-03-26T10:00:00+01:00", "2024-04-02T10:00:00+02:00"},
+			wantIndex: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:       "exceptions keep the index of later occurrences",
+			rule:       "FREQ=WEEKLY;COUNT=3",
+			exceptions: []string{"2024-03-12"},
+			want:       []string{"2024-03-05T09:00:00Z", "2024-03-19T09:00:00Z"},
+			wantIndex:  []int{0, 2},
+		},
+		{
+			name:     "date-only until ends with the local day",
+			rule:     "FREQ=DAILY;UNTIL=20240307",
+			timeZone: "Pacific/Auckland",
+			// 09:00 utc is 22:00 in auckland, the last occurrence is on march 7th there
+			want:      []string{"2024-03-05T22:00:00+13:00", "2024-03-06T22:00:00+13:00", "2024-03-07T22:00:00+13:00"},
+			wantIndex: []int{0, 1, 2},
+		},
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := testSeries(tt.rule, tt.timeZone)
+			series.Spec.Exceptions = tt.exceptions
+
+			got, err := Occurrences(series, from, to)
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i, o := range got {
+				if start := o.Start.Format(time.RFC3339); start != tt.want[i] {
+					t.Errorf("occurrence %d starts %s, want %s", i, start, tt.want[i])
+				}
+				if o.Index != tt.wantIndex[i] {
+					t.Errorf("occurrence %d has index %d, want %d", i, o.Index, tt.wantIndex[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_AccessCode(t *testing.T) {
+	series := testSeries("FREQ=WEEKLY", "")
+	series.Spec.AccessCodePattern = "{series}-{date}-{index}"
+
+	o := recurrence.Occurrence{Index: 2, Start: time.Date(2024, 3, 19, 9, 0, 0, 0, time.UTC)}
+	if got, want := AccessCode(series, o), "ses-weekly-20240319-3"; got != want {
+		t.Errorf("AccessCode() = %s, want %s", got, want)
+	}
+}
+
+func Test_Render(t *testing.T) {
+	series := testSeries("FREQ=WEEKLY", "Europe/Berlin")
+	series.Spec.Template.Leaderboard = &hfv1.LeaderboardPolicy{Token: "shared"}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := recurrence.Occurrence{Index: 4, Start: time.Date(2024, 4, 2, 10, 0, 0, 0, berlin)}
+
+	spec, err := Render(series, o)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !spec.StartTime.Time.Equal(o.Start) {
+		t.Errorf("start = %s, want %s", spec.StartTime, o.Start)
+	}
+	if got := spec.EndTime.Sub(spec.StartTime.Time); got != 2*time.Hour {
+		t.Errorf("duration = %s, want 2h", got)
+	}
+	if spec.AccessCode != "ses-weekly-20240402" {
+		t.Errorf("access code = %s, want ses-weekly-20240402", spec.AccessCode)
+	}
+	if spec.Leaderboard == nil || spec.Leaderboard.Token != "" {
+		t.Errorf("leaderboard token was not reset: %+v", spec.Leaderboard)
+	}
+	if series.Spec.Template.Leaderboard.Token != "shared" {
+		t.Error("rendering changed the template of the series")
+	}
+}
@@ -0,0 +1,214 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+)
+
+const (
+	DefaultPointsPerStep     = 10
+	DefaultPointsPerCheck    = 25
+	DefaultQuizMultiplier    = 1
+	DefaultPointsPerScenario = 100
+)
+
+// Scoring is a LeaderboardPolicy with its durations parsed and defaults applied
+type Scoring struct {
+	PointsPerStep     int
+	PointsPerCheck    int
+	QuizMultiplier    int
+	PointsPerScenario int
+	TimeBonus         int
+	TimeBonusWindow   time.Duration
+}
+
+// Entry is the standing of a single learner
+type Entry struct {
+	Rank      int    `json:"rank"`
+	User      string `json:"user"`
+	Name      string `json:"name"`
+	Points    int    `json:"points"`
+	Steps     int    `json:"steps"`
+	Checks    int    `json:"checks"`
+	Quiz      int    `json:"quiz"`
+	Scenarios int    `json:"scenarios"`
+	TimeBonus int    `json:"time_bonus"`
+	LastScore string `json:"last_score,omitempty"` // when the points were reached, earlier wins a tie
+
+	lastScore time.Time
+}
+
+// Board is the leaderboard of a scheduled event
+type Board struct {
+	ScheduledEvent string  `json:"scheduled_event"`
+	Name           string  `json:"name"`
+	Entries        []Entry `json:"entries"`
+}
+
+// Validate checks a policy before it is stored on a scheduled event
+func Validate(policy hfv1.LeaderboardPolicy) error {
+	_, err := NewScoring(policy)
+	return err
+}
+
+// NewScoring applies the default points if the policy does not configure any
+func NewScoring(policy hfv1.LeaderboardPolicy) (Scoring, error) {
+	if policy.PointsPerStep < 0 || policy.PointsPerCheck < 0 || policy.QuizMultiplier < 0 || policy.PointsPerScenario < 0 || policy.TimeBonus < 0 {
+		return Scoring{}, fmt.Errorf("leaderboard points may not be negative")
+	}
+
+	s := Scoring{
+		PointsPerStep:     policy.PointsPerStep,
+		PointsPerCheck:    policy.PointsPerCheck,
+		QuizMultiplier:    policy.QuizMultiplier,
+		PointsPerScenario: policy.PointsPerScenario,
+		TimeBonus:         policy.TimeBonus,
+	}
+	if s.PointsPerStep == 0 && s.PointsPerCheck == 0 && s.QuizMultiplier == 0 && s.PointsPerScenario == 0 {
+		s.PointsPerStep = DefaultPointsPerStep
+		s.PointsPerCheck = DefaultPointsPerCheck
+		s.QuizMultiplier = DefaultQuizMultiplier
+		s.PointsPerScenario = DefaultPointsPerScenario
+	}
+
+	if policy.TimeBonusWindow != "" {
+		window, err := time.ParseDuration(policy.TimeBonusWindow)
+		if err != nil || window <= 0 {
+			return Scoring{}, fmt.Errorf("invalid time_bonus_window %s", policy.TimeBonusWindow)
+		}
+		s.TimeBonusWindow = window
+	}
+	if s.TimeBonus > 0 && s.TimeBonusWindow == 0 {
+		return Scoring{}, fmt.Errorf("time_bonus requires a time_bonus_window")
+	}
+
+	return s, nil
+}
+
+// Compute ranks the learners of a scheduled event. Only the best attempt of every scenario counts, so restarting
+// a scenario does not earn points twice. Names are looked up by user id, the user id is shown if a name is missing.
+func Compute(se *hfv2.ScheduledEvent, scoring Scoring, progress []*hfv2.Progress, names map[string]string) Board {
+	start := se.Spec.StartTime.Time
+
+	best := map[string]Entry{}
+	for _, p := range progress {
+		if p.Spec.UserId == "" {
+			continue
+		}
+		key := p.Spec.UserId + "/" + p.Spec.Scenario
+		attempt := scoring.score(p, start)
+		if current, ok := best[key]; !ok || ahead(attempt, current) {
+			best[key] = attempt
+		}
+	}
+
+	byUser := map[string]*Entry{}
+	for _, attempt := range best {
+		e, ok := byUser[attempt.User]
+		if !ok {
+			e = &Entry{User: attempt.User, Name: names[attempt.User]}
+			if e.Name == "" {
+				e.Name = attempt.User
+			}
+			byUser[attempt.User] = e
+		}
+		e.Points += attempt.Points
+		e.Steps += attempt.Steps
+		e.Checks += attempt.Checks
+		e.Quiz += attempt.Quiz
+		e.Scenarios += attempt.Scenarios
+		e.TimeBonus += attempt.TimeBonus
+		if attempt.lastScore.After(e.lastScore) {
+			e.lastScore = attempt.lastScore
+		}
+	}
+
+	board := Board{ScheduledEvent: se.Name, Name: se.Spec.Name, Entries: []Entry{}}
+	for _, e := range byUser {
+		if !e.lastScore.IsZero() {
+			e.LastScore = e.lastScore.Format(time.UnixDate)
+		}
+		board.Entries = append(board.Entries, *e)
+	}
+	sort.Slice(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Points != b.Points || !a.lastScore.Equal(b.lastScore) {
+			return ahead(a, b)
+		}
+		return a.User < b.User
+	})
+
+	// learners with the same points and time share a rank
+	for i := range board.Entries {
+		board.Entries[i].Rank = i + 1
+		if i > 0 && !ahead(board.Entries[i-1], board.Entries[i]) {
+			board.Entries[i].Rank = board.Entries[i-1].Rank
+		}
+	}
+
+	return board
+}
+
+// ahead returns true if a ranks before b, more points win and the one who got them first wins a tie
+func ahead(a Entry, b Entry) bool {
+	if a.Points != b.Points {
+		return a.Points > b.Points
+	}
+	return a.lastScore.Before(b.lastScore)
+}
+
+func (s Scoring) score(p *hfv2.Progress, start time.Time) Entry {
+	e := Entry{User: p.Spec.UserId, lastScore: p.Spec.LastUpdate.Time}
+
+	e.Steps = min(p.Spec.MaxStep+1, p.Spec.TotalStep)
+	for _, result := range p.Spec.CheckResults {
+		if result.Passed {
+			e.Checks++
+		}
+	}
+	e.Quiz = p.Spec.Score * s.QuizMultiplier
+	e.Points = e.Steps*s.PointsPerStep + e.Checks*s.PointsPerCheck + e.Quiz
+
+	if completion.ScenarioCompleted(p.Spec) {
+		e.Scenarios = 1
+		e.Points += s.PointsPerScenario
+		e.TimeBonus = s.timeBonus(start, completedAt(p))
+		e.Points += e.TimeBonus
+	}
+
+	return e
+}
+
+// timeBonus shrinks linearly from the full bonus at the start of the event to zero at the end of the window
+func (s Scoring) timeBonus(start time.Time, completed time.Time) int {
+	if s.TimeBonus == 0 || start.IsZero() || completed.IsZero() {
+		return 0
+	}
+	elapsed := max(completed.Sub(start), 0)
+	if elapsed >= s.TimeBonusWindow {
+		return 0
+	}
+	return int(float64(s.TimeBonus) * float64(s.TimeBonusWindow-elapsed) / float64(s.TimeBonusWindow))
+}
+
+// completedAt is the first time the last step of the scenario was opened
+func completedAt(p *hfv2.Progress) time.Time {
+	for _, step := range p.Spec.Steps {
+		if step.Step == p.Spec.TotalStep-1 {
+			return step.FirstVisit.Time
+		}
+	}
+	return p.Spec.LastUpdate.Time
+}
+
+// DisplayName keeps the email address of a learner off the projector
+func DisplayName(email string) string {
+	name, _, _ := strings.Cut(email, "@")
+	return name
+}
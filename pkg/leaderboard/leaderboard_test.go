@@ -0,0 +1,155 @@
+package leaderboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NewScoring(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  hfv1.LeaderboardPolicy
+		want    Scoring
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			policy: hfv1.LeaderboardPolicy{},
+			want:   Scoring{DefaultPointsPerStep, DefaultPointsPerCheck, DefaultQuizMultiplier, DefaultPointsPerScenario, 0, 0},
+		},
+		{
+			name:   "only steps count",
+			policy: hfv1.LeaderboardPolicy{PointsPerStep: 1},
+			want:   Scoring{PointsPerStep: 1},
+		},
+		{
+			name:   "time bonus",
+			policy: hfv1.LeaderboardPolicy{PointsPerScenario: 50, TimeBonus: 20, TimeBonusWindow: "30m"},
+			want:   Scoring{PointsPerScenario: 50, TimeBonus: 20, TimeBonusWindow: 30 * time.Minute},
+		},
+		{name: "negative points", policy: hfv1.LeaderboardPolicy{PointsPerCheck: -1}, wantErr: "may not be negative"},
+		{name: "time bonus without a window", policy: hfv1.LeaderboardPolicy{TimeBonus: 20}, wantErr: "requires a time_bonus_window"},
+		{name: "invalid window", policy: hfv1.LeaderboardPolicy{TimeBonus: 20, TimeBonusWindow: "-5m"}, wantErr: "invalid time_bonus_window"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewScoring(tt.policy)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NewScoring() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NewScoring() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Compute(t *testing.T) {
+	start := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC)
+	after := func(minutes int) metav1.Time { return metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute)) }
+	passed := func(n int) []hfv1.StepCheckResult {
+		results := []hfv1.StepCheckResult{{Passed: false}}
+		for i := 0; i < n; i++ {
+			results = append(results, hfv1.StepCheckResult{Check: i + 1, Passed: true})
+		}
+		return results
+	}
+
+	se := &hfv2.ScheduledEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: "se-1"},
+		Spec:       hfv2.ScheduledEventSpec{Name: "Workshop", StartTime: metav1.NewTime(start)},
+	}
+	scoring := Scoring{PointsPerStep: 10, PointsPerCheck: 5, QuizMultiplier: 2, PointsPerScenario: 100, TimeBonus: 60, TimeBonusWindow: time.Hour}
+
+	progress := []*hfv2.Progress{
+		// a first attempt of alice, only her best attempt counts: 20 for steps and 5 for a check
+		{Spec: hfv2.ProgressSpec{UserId: "u-a", Scenario: "sc-1", TotalStep: 3, MaxStep: 1, CheckResults: passed(1), LastUpdate: after(10)}},
+		// 30 for steps, 8 for the quiz, 100 for completing and half of the time bonus
+		{Spec: hfv2.ProgressSpec{UserId: "u-a", Scenario: "sc-1", TotalStep: 3, MaxStep: 2, Score: 4, LastUpdate: after(35),
+			Steps: []hfv2.ProgressStep{{Step: 2, FirstVisit: after(30)}}}},
+		// completed after the time bonus window, 130 and 10 for the first step of another scenario
+		{Spec: hfv2.ProgressSpec{UserId: "u-b", Scenario: "sc-1", TotalStep: 3, MaxStep: 2, LastUpdate: after(90),
+			Steps: []hfv2.ProgressStep{{Step: 2, FirstVisit: after(90)}}}},
+		{Spec: hfv2.ProgressSpec{UserId: "u-b", Scenario: "sc-2", TotalStep: 2, LastUpdate: after(95)}},
+		// 130 and 10 for checks, carol got her points at the same time as bob and dave got them earlier
+		{Spec: hfv2.ProgressSpec{UserId: "u-c", Scenario: "sc-1", TotalStep: 3, MaxStep: 2, CheckResults: passed(2), LastUpdate: after(95)}},
+		{Spec: hfv2.ProgressSpec{UserId: "u-d", Scenario: "sc-1", TotalStep: 3, MaxStep: 2, CheckResults: passed(2), LastUpdate: after(94)}},
+		// progress without a user is left out
+		{Spec: hfv2.ProgressSpec{Scenario: "sc-1", TotalStep: 3, MaxStep: 2}},
+	}
+	names := map[string]string{"u-a": "alice", "u-b": "bob", "u-c": "carol"}
+
+	board := Compute(se, scoring, progress, names)
+	if board.ScheduledEvent != "se-1" || board.Name != "Workshop" {
+		t.Errorf("board = %s %s", board.ScheduledEvent, board.Name)
+	}
+
+	want := []struct {
+		rank   int
+		name   string
+		points int
+	}{
+		{1, "alice", 168},
+		{2, "u-d", 140},
+		{3, "bob", 140},
+		{3, "carol", 140},
+	}
+	if len(board.Entries) != len(want) {
+		t.Fatalf("Entries = %+v, want %d entries", board.Entries, len(want))
+	}
+	for i, w := range want {
+		e := board.Entries[i]
+		if e.Rank != w.rank || e.Name != w.name || e.Points != w.points {
+			t.Errorf("Entries[%d] = %d %s %d, want %d %s %d", i, e.Rank, e.Name, e.Points, w.rank, w.name, w.points)
+		}
+	}
+
+	alice := board.Entries[0]
+	if alice.Steps != 3 || alice.Checks != 0 || alice.Quiz != 8 || alice.Scenarios != 1 || alice.TimeBonus != 30 {
+		t.Errorf("alice = %+v", alice)
+	}
+	if alice.LastScore != start.Add(35*time.Minute).Format(time.UnixDate) {
+		t.Errorf("alice scored last at %s", alice.LastScore)
+	}
+	if bob := board.Entries[2]; bob.Scenarios != 1 || bob.Steps != 4 || bob.TimeBonus != 0 {
+		t.Errorf("bob = %+v", bob)
+	}
+}
+
+func Test_TimeBonus(t *testing.T) {
+	start := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC)
+	s := Scoring{TimeBonus: 100, TimeBonusWindow: 40 * time.Minute}
+
+	for completed, want := range map[time.Time]int{
+		start.Add(-5 * time.Minute): 100, // sessions of pre-warmed events may finish before the start
+		start:                       100,
+		start.Add(10 * time.Minute): 75,
+		start.Add(40 * time.Minute): 0,
+		{}:                          0,
+	} {
+		if got := s.timeBonus(start, completed); got != want {
+			t.Errorf("timeBonus(%v) = %d, want %d", completed, got, want)
+		}
+	}
+	if got := (Scoring{}).timeBonus(start, start); got != 0 {
+		t.Errorf("timeBonus() without a bonus = %d", got)
+	}
+}
+
+func Test_DisplayName(t *testing.T) {
+	if got := DisplayName("jane.doe@example.com"); got != "jane.doe" {
+		t.Errorf("DisplayName() = %q", got)
+	}
+	if got := DisplayName("admin"); got != "admin" {
+		t.Errorf("DisplayName() without a domain = %q", got)
+	}
+}
@@ -0,0 +1,419 @@
+package leaderboardserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListersV2 "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/leaderboard"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	scheduledEventPlural = "scheduledevents"
+	progressPlural       = "progresses"
+
+	tokenLength   = 32
+	pingInterval  = 30 * time.Second
+	writeTimeout  = 10 * time.Second
+	minPushPeriod = time.Second // progress changes in bursts, the board is pushed at most this often
+)
+
+var (
+	errNoLeaderboard = fmt.Errorf("scheduled event has no leaderboard")
+	errInvalidToken  = fmt.Errorf("invalid leaderboard token")
+)
+
+type watcher struct {
+	scheduledEvent string
+	changed        chan struct{}
+}
+
+type LeaderboardServer struct {
+	auth        *authclient.AuthClient
+	hfClientSet hfClientset.Interface
+	ctx         context.Context
+
+	scheduledEventLister hfListersV2.ScheduledEventLister
+	progressLister       hfListersV2.ProgressLister
+	userLister           hfListersV2.UserLister
+
+	mu       sync.RWMutex
+	watchers map[*watcher]struct{}
+}
+
+type PreparedToken struct {
+	Token string `json:"token"`
+}
+
+func NewLeaderboardServer(authClient *authclient.AuthClient, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*LeaderboardServer, error) {
+	s := LeaderboardServer{}
+
+	s.auth = authClient
+	s.hfClientSet = hfClientSet
+	s.ctx = ctx
+	s.watchers = map[*watcher]struct{}{}
+
+	s.scheduledEventLister = hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Lister()
+	s.progressLister = hfInformerFactory.Hobbyfarm().V2().Progresses().Lister()
+	s.userLister = hfInformerFactory.Hobbyfarm().V2().Users().Lister()
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: s.notify,
+		UpdateFunc: func(old, new interface{}) {
+			s.notify(new)
+		},
+		DeleteFunc: s.notify,
+	}
+	hfInformerFactory.Hobbyfarm().V2().Progresses().Informer().AddEventHandler(handler)
+	hfInformerFactory.Hobbyfarm().V2().ScheduledEvents().Informer().AddEventHandler(handler)
+
+	return &s, nil
+}
+
+func (s *LeaderboardServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/leaderboard/{se_id}", s.GetFunc).Methods("GET")
+	r.HandleFunc("/leaderboard/{se_id}/watch", s.WatchFunc).Methods("GET")
+	r.HandleFunc("/a/leaderboard/{se_id}", s.AdminGetFunc).Methods("GET")
+	r.HandleFunc("/a/leaderboard/{se_id}/watch", s.AdminWatchFunc).Methods("GET")
+	r.HandleFunc("/a/leaderboard/{se_id}/token", s.CreateTokenFunc).Methods("PUT")
+	r.HandleFunc("/a/leaderboard/{se_id}/token", s.RevokeTokenFunc).Methods("DELETE")
+	glog.V(2).Infof("set up routes for leaderboard server")
+}
+
+/*
+Leaderboard of a scheduled event, for screens nobody is logged in on
+
+	Vars:
+	- se_id : The scheduled event id
+	Query:
+	- token : The leaderboard token of the scheduled event
+*/
+func (s *LeaderboardServer) GetFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["se_id"]
+	board, err := s.board(id, r.URL.Query().Get("token"), true)
+	if err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	encodedBoard, err := json.Marshal(board)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedBoard)
+}
+
+// WatchFunc streams the leaderboard of a scheduled event, authorized by its leaderboard token
+func (s *LeaderboardServer) WatchFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["se_id"]
+	token := r.URL.Query().Get("token")
+	if _, err := s.board(id, token, true); err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	s.serve(w, r, id, token, true)
+
+	glog.V(4).Infof("stopped leaderboard watch for scheduled event %s", id)
+}
+
+func (s *LeaderboardServer) AdminGetFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(
+		rbacclient.RbacRequest().
+			HobbyfarmPermission(scheduledEventPlural, rbacclient.VerbGet).
+			HobbyfarmPermission(progressPlural, rbacclient.VerbList),
+		w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get leaderboard")
+		return
+	}
+
+	id := mux.Vars(r)["se_id"]
+	board, err := s.board(id, "", false)
+	if err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	encodedBoard, err := json.Marshal(board)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedBoard)
+}
+
+func (s *LeaderboardServer) AdminWatchFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrantWS(
+		rbacclient.RbacRequest().
+			HobbyfarmPermission(scheduledEventPlural, rbacclient.VerbGet).
+			HobbyfarmPermission(progressPlural, rbacclient.VerbList),
+		w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to watch leaderboard")
+		return
+	}
+
+	id := mux.Vars(r)["se_id"]
+	if _, err = s.board(id, "", false); err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	s.serve(w, r, id, "", false)
+
+	glog.V(4).Infof("stopped leaderboard watch for scheduled event %s", id)
+}
+
+// CreateTokenFunc replaces the leaderboard token of a scheduled event, links with the previous token stop working
+func (s *LeaderboardServer) CreateTokenFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(scheduledEventPlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scheduledevents")
+		return
+	}
+
+	id := mux.Vars(r)["se_id"]
+	token, err := util.RandSecretRunes(tokenLength)
+	if err != nil {
+		glog.Errorf("error generating leaderboard token %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+	if err = s.setToken(id, token); err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	encodedToken, err := json.Marshal(PreparedToken{Token: token})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedToken)
+
+	glog.V(2).Infof("created leaderboard token for scheduled event %s", id)
+}
+
+func (s *LeaderboardServer) RevokeTokenFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(scheduledEventPlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scheduledevents")
+		return
+	}
+
+	id := mux.Vars(r)["se_id"]
+	if err = s.setToken(id, ""); err != nil {
+		s.returnError(w, r, id, err)
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "revoked leaderboard token")
+
+	glog.V(2).Infof("revoked leaderboard token for scheduled event %s", id)
+}
+
+func (s *LeaderboardServer) setToken(id string, token string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		se, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if se.Spec.Leaderboard == nil {
+			return errNoLeaderboard
+		}
+
+		se.Spec.Leaderboard.Token = token
+		_, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Update(s.ctx, se, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// board computes the leaderboard of a scheduled event. The token is only checked if requireToken is set.
+func (s *LeaderboardServer) board(id string, token string, requireToken bool) (leaderboard.Board, error) {
+	se, err := s.scheduledEventLister.ScheduledEvents(util.GetReleaseNamespace()).Get(id)
+	if err != nil {
+		return leaderboard.Board{}, err
+	}
+	if se.Spec.Leaderboard == nil {
+		return leaderboard.Board{}, errNoLeaderboard
+	}
+	if requireToken && (se.Spec.Leaderboard.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(se.Spec.Leaderboard.Token)) != 1) {
+		return leaderboard.Board{}, errInvalidToken
+	}
+
+	scoring, err := leaderboard.NewScoring(*se.Spec.Leaderboard)
+	if err != nil {
+		return leaderboard.Board{}, err
+	}
+
+	progress, err := s.progressLister.Progresses(util.GetReleaseNamespace()).List(labels.SelectorFromSet(labels.Set{util.ScheduledEventLabel: id}))
+	if err != nil {
+		return leaderboard.Board{}, err
+	}
+
+	names := map[string]string{}
+	for _, p := range progress {
+		if _, ok := names[p.Spec.UserId]; ok {
+			continue
+		}
+		names[p.Spec.UserId] = ""
+		if user, err := s.userLister.Users(util.GetReleaseNamespace()).Get(p.Spec.UserId); err == nil {
+			names[p.Spec.UserId] = leaderboard.DisplayName(user.Spec.Email)
+		}
+	}
+
+	return leaderboard.Compute(se, scoring, progress, names), nil
+}
+
+func (s *LeaderboardServer) returnError(w http.ResponseWriter, r *http.Request, id string, err error) {
+	switch {
+	case err == errInvalidToken:
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", err.Error())
+	case err == errNoLeaderboard, apierrors.IsNotFound(err):
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no leaderboard found")
+	default:
+		glog.Errorf("error computing leaderboard of scheduled event %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error computing leaderboard")
+	}
+}
+
+// serve pushes the leaderboard whenever it changed. The token is checked again on every push,
+// so revoking it ends the streams that were opened with it.
+func (s *LeaderboardServer) serve(w http.ResponseWriter, r *http.Request, id string, token string, requireToken bool) {
+	var upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+
+	// same as the status watch, origins are checked by the CORS handler in front of us
+	upgrader.CheckOrigin = func(r *http.Request) bool {
+		return true
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied to the client
+		glog.Errorf("error upgrading leaderboard watch: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	wt := &watcher{scheduledEvent: id, changed: make(chan struct{}, 1)}
+	wt.changed <- struct{}{} // sends the current board right away
+
+	s.mu.Lock()
+	s.watchers[wt] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, wt)
+		s.mu.Unlock()
+	}()
+
+	// we never expect anything from the client, but need to read to notice when it goes away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		select {
+		case <-gone:
+			return
+		case <-wt.changed:
+			board, err := s.board(id, token, requireToken)
+			if err != nil {
+				glog.V(4).Infof("closing leaderboard watch for scheduled event %s: %v", id, err)
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()), time.Now().Add(writeTimeout))
+				return
+			}
+			encoded, err := json.Marshal(board)
+			if err != nil {
+				glog.Error(err)
+				return
+			}
+			if bytes.Equal(encoded, last) {
+				continue
+			}
+			last = encoded
+
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err = conn.WriteMessage(websocket.TextMessage, encoded); err != nil {
+				glog.V(4).Infof("error writing leaderboard: %v", err)
+				return
+			}
+
+			// changes in the meantime pile up in the channel and are sent with the next push
+			select {
+			case <-gone:
+				return
+			case <-time.After(minPushPeriod):
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// notify wakes the watchers of the scheduled event a progress or scheduled event belongs to
+func (s *LeaderboardServer) notify(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	var id string
+	switch o := obj.(type) {
+	case *hfv2.Progress:
+		id = o.Labels[util.ScheduledEventLabel]
+	case *hfv2.ScheduledEvent:
+		id = o.Name
+	}
+	if id == "" {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for wt := range s.watchers {
+		if wt.scheduledEvent != id {
+			continue
+		}
+		select {
+		case wt.changed <- struct{}{}:
+		default:
+			// a push is already pending
+		}
+	}
+}
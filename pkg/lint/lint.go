@@ -0,0 +1,278 @@
+package lint
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/locale"
+	"github.com/hobbyfarm/gargantua/v3/pkg/quiz"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
+	"github.com/hobbyfarm/gargantua/v3/pkg/stepcheck"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // the content breaks sessions, the validation webhook rejects it
+	SeverityWarning Severity = "warning" // the content works but likely not as intended
+)
+
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Field    string   `json:"field"`
+	Message  string   `json:"message"`
+}
+
+type linter struct {
+	ctx         context.Context
+	hfClientSet hfClientset.Interface
+	issues      []Issue
+	templates   map[string]bool
+}
+
+// Scenario lints a scenario, it checks that its texts decode, durations parse and referenced vm templates exist
+func Scenario(ctx context.Context, hfClientSet hfClientset.Interface, spec hfv1.ScenarioSpec) []Issue {
+	l := newLinter(ctx, hfClientSet)
+
+	l.named("name", spec.Name)
+	l.named("description", spec.Description)
+	if spec.Description == "" {
+		l.warn("description", "scenario has no description")
+	}
+	l.duration("keepalive_duration", spec.KeepAliveDuration)
+	l.duration("pause_duration", spec.PauseDuration)
+	if spec.Pauseable && spec.PauseDuration == "" {
+		l.warn("pause_duration", "scenario is pauseable but has no pause duration")
+	}
+	l.virtualMachines(spec.VirtualMachines)
+	l.categories(spec.Categories)
+
+	if err := sessionpolicy.Validate(spec.SessionPolicy); err != nil {
+		l.fail("session_policy", err.Error())
+	}
+
+	if len(spec.Steps) == 0 {
+		l.warn("steps", "scenario has no steps")
+	}
+	for i, step := range spec.Steps {
+		l.decoded(fmt.Sprintf("steps[%d].title", i), step.Title)
+		l.decoded(fmt.Sprintf("steps[%d].content", i), step.Content)
+		if step.Content == "" {
+			l.warn(fmt.Sprintf("steps[%d].content", i), "step has no content")
+		}
+		for j, check := range step.Checks {
+			field := fmt.Sprintf("steps[%d].checks[%d]", i, j)
+			if err := stepcheck.Validate(check); err != nil {
+				l.fail(field, err.Error())
+			}
+			if !hasVirtualMachine(spec.VirtualMachines, check.VirtualMachine) {
+				l.fail(field, fmt.Sprintf("vm %s is not part of the scenario", check.VirtualMachine))
+			}
+		}
+		for j, question := range step.Questions {
+			if err := quiz.Validate(question); err != nil {
+				l.fail(fmt.Sprintf("steps[%d].questions[%d]", i, j), err.Error())
+			}
+		}
+		if step.Gated && len(step.Checks) == 0 && len(step.Questions) == 0 {
+			l.warn(fmt.Sprintf("steps[%d].gated", i), "step is gated but has neither checks nor questions")
+		}
+	}
+
+	if err := locale.ValidateScenario(spec); err != nil {
+		l.fail("translations", err.Error())
+	}
+	for loc, translation := range spec.Translations {
+		l.decoded(fmt.Sprintf("translations[%s].name", loc), translation.Name)
+		l.decoded(fmt.Sprintf("translations[%s].description", loc), translation.Description)
+		for i, step := range translation.Steps {
+			l.decoded(fmt.Sprintf("translations[%s].steps[%d].title", loc, i), step.Title)
+			l.decoded(fmt.Sprintf("translations[%s].steps[%d].content", loc, i), step.Content)
+		}
+	}
+	for _, report := range locale.ScenarioCompleteness(spec) {
+		if !report.Complete {
+			l.warn(fmt.Sprintf("translations[%s]", report.Locale), fmt.Sprintf("%d of %d texts are not translated", len(report.Missing), report.Total))
+		}
+	}
+
+	return l.issues
+}
+
+// Course lints a course, it checks that referenced scenarios and vm templates exist, durations parse
+// and the category queries of dynamic scenarios are valid selectors
+func Course(ctx context.Context, hfClientSet hfClientset.Interface, spec hfv1.CourseSpec) []Issue {
+	l := newLinter(ctx, hfClientSet)
+
+	l.named("name", spec.Name)
+	l.named("description", spec.Description)
+	l.duration("keepalive_duration", spec.KeepAliveDuration)
+	l.duration("pause_duration", spec.PauseDuration)
+	l.virtualMachines(spec.VirtualMachines)
+
+	if err := sessionpolicy.Validate(spec.SessionPolicy); err != nil {
+		l.fail("session_policy", err.Error())
+	}
+
+	for i, id := range spec.Scenarios {
+		_, err := hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(ctx, id, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			l.fail(fmt.Sprintf("scenarios[%d]", i), fmt.Sprintf("scenario %s does not exist", id))
+		} else if err != nil {
+			l.fail(fmt.Sprintf("scenarios[%d]", i), fmt.Sprintf("error retrieving scenario %s: %v", id, err))
+		}
+	}
+
+	for i, categoryQuery := range spec.Categories {
+		field := fmt.Sprintf("categories[%d]", i)
+		selector, err := util.CategorySelector(categoryQuery)
+		if err != nil {
+			l.fail(field, fmt.Sprintf("invalid category query %s: %v", categoryQuery, err))
+			continue
+		}
+		scenarios, err := hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err == nil && len(scenarios.Items) == 0 {
+			l.warn(field, fmt.Sprintf("category query %s matches no scenario", categoryQuery))
+		}
+	}
+
+	if len(spec.Scenarios) == 0 && len(spec.Categories) == 0 {
+		l.warn("scenarios", "course has no scenarios")
+	}
+
+	if err := completion.Validate(spec); err != nil {
+		l.fail("prerequisites", err.Error())
+	}
+
+	if err := locale.ValidateCourse(spec); err != nil {
+		l.fail("translations", err.Error())
+	}
+	for loc, translation := range spec.Translations {
+		l.decoded(fmt.Sprintf("translations[%s].name", loc), translation.Name)
+		l.decoded(fmt.Sprintf("translations[%s].description", loc), translation.Description)
+	}
+
+	return l.issues
+}
+
+// Errors returns the issues that make content invalid
+func Errors(issues []Issue) []Issue {
+	errs := []Issue{}
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// Message joins issues into a single message
+func Message(issues []Issue) string {
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func newLinter(ctx context.Context, hfClientSet hfClientset.Interface) *linter {
+	return &linter{
+		ctx:         ctx,
+		hfClientSet: hfClientSet,
+		issues:      []Issue{},
+		templates:   map[string]bool{},
+	}
+}
+
+func (l *linter) fail(field string, message string) {
+	l.issues = append(l.issues, Issue{SeverityError, field, message})
+}
+
+func (l *linter) warn(field string, message string) {
+	l.issues = append(l.issues, Issue{SeverityWarning, field, message})
+}
+
+// decoded checks that a text is base64 encoded like the ui stores it
+func (l *linter) decoded(field string, text string) {
+	if _, err := base64.StdEncoding.DecodeString(text); err != nil {
+		l.fail(field, "text is not base64 encoded")
+	}
+}
+
+// named checks the encoding of a name or description. Older content stored them as plain text,
+// which is still shown, so it is only warned about.
+func (l *linter) named(field string, text string) {
+	if _, err := base64.StdEncoding.DecodeString(text); err != nil {
+		l.warn(field, "text is not base64 encoded")
+	}
+}
+
+func (l *linter) duration(field string, duration string) {
+	if duration == "" {
+		return
+	}
+	if _, err := time.ParseDuration(duration); err != nil {
+		l.fail(field, fmt.Sprintf("invalid duration %s", duration))
+	}
+}
+
+// virtualMachines checks that every vm set maps vm names to existing vm templates
+func (l *linter) virtualMachines(vmSets []map[string]string) {
+	for i, vmSet := range vmSets {
+		for vm, template := range vmSet {
+			field := fmt.Sprintf("virtualmachines[%d].%s", i, vm)
+			if template == "" {
+				l.fail(field, "no vm template set")
+				continue
+			}
+			if err := l.templateExists(template); err != nil {
+				l.fail(field, err.Error())
+			}
+		}
+	}
+}
+
+func (l *linter) templateExists(template string) error {
+	if l.templates[template] {
+		return nil
+	}
+
+	_, err := l.hfClientSet.HobbyfarmV1().VirtualMachineTemplates(util.GetReleaseNamespace()).Get(l.ctx, template, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("vm template %s does not exist", template)
+	}
+	if err != nil {
+		return fmt.Errorf("error retrieving vm template %s: %v", template, err)
+	}
+
+	l.templates[template] = true
+	return nil
+}
+
+// categories checks that the categories of a scenario can be stored as labels
+func (l *linter) categories(categories []string) {
+	for i, category := range categories {
+		if errs := validation.IsQualifiedName("category-" + category); len(errs) > 0 {
+			l.fail(fmt.Sprintf("categories[%d]", i), fmt.Sprintf("invalid category %s: %s", category, strings.Join(errs, ", ")))
+		}
+	}
+}
+
+func hasVirtualMachine(vmSets []map[string]string, vm string) bool {
+	for _, vmSet := range vmSets {
+		if _, ok := vmSet[vm]; ok {
+			return true
+		}
+	}
+	return false
+}
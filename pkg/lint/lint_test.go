@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func b64(text string) string {
+	return base64.StdEncoding.EncodeToString([]byte(text))
+}
+
+func Test_Scenario(t *testing.T) {
+	client := fake.NewSimpleClientset(&hfv1.VirtualMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: util.GetReleaseNamespace()},
+	})
+
+	tests := []struct {
+		name   string
+		change func(spec *hfv1.ScenarioSpec)
+		want   []Issue // the unchanged scenario has no issues
+	}{
+		{"unchanged", func(*hfv1.ScenarioSpec) {}, nil},
+		{
+			"plain text name",
+			func(spec *hfv1.ScenarioSpec) { spec.Name = "Getting started!" },
+			[]Issue{{SeverityWarning, "name", "text is not base64 encoded"}},
+		},
+		{
+			"plain text step",
+			func(spec *hfv1.ScenarioSpec) { spec.Steps[0].Content = "Log in!" },
+			[]Issue{{SeverityError, "steps[0].content", "text is not base64 encoded"}},
+		},
+		{
+			"invalid duration",
+			func(spec *hfv1.ScenarioSpec) { spec.KeepAliveDuration = "10 minutes" },
+			[]Issue{{SeverityError, "keepalive_duration", "invalid duration 10 minutes"}},
+		},
+		{
+			"pauseable without a pause duration",
+			func(spec *hfv1.ScenarioSpec) { spec.Pauseable = true },
+			[]Issue{{SeverityWarning, "pause_duration", "scenario is pauseable but has no pause duration"}},
+		},
+		{
+			"unknown vm template",
+			func(spec *hfv1.ScenarioSpec) { spec.VirtualMachines[0]["db"] = "postgres" },
+			[]Issue{{SeverityError, "virtualmachines[0].db", "vm template postgres does not exist"}},
+		},
+		{
+			"vm without a template",
+			func(spec *hfv1.ScenarioSpec) { spec.VirtualMachines[0]["db"] = "" },
+			[]Issue{{SeverityError, "virtualmachines[0].db", "no vm template set"}},
+		},
+		{
+			"check on a vm of another scenario",
+			func(spec *hfv1.ScenarioSpec) { spec.Steps[0].Checks[0].VirtualMachine = "worker" },
+			[]Issue{{SeverityError, "steps[0].checks[0]", "vm worker is not part of the scenario"}},
+		},
+		{
+			"gated step without checks",
+			func(spec *hfv1.ScenarioSpec) { spec.Steps[1].Gated = true },
+			[]Issue{{SeverityWarning, "steps[1].gated", "step is gated but has neither checks nor questions"}},
+		},
+		{
+			"step without content",
+			func(spec *hfv1.ScenarioSpec) { spec.Steps[1].Content = "" },
+			[]Issue{{SeverityWarning, "steps[1].content", "step has no content"}},
+		},
+		{
+			"incomplete translation",
+			func(spec *hfv1.ScenarioSpec) {
+				spec.Translations = map[string]hfv1.ScenarioTranslation{"de": {Name: b64("Erste Schritte")}}
+			},
+			[]Issue{{SeverityWarning, "translations[de]", "5 of 6 texts are not translated"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := hfv1.ScenarioSpec{
+				Name:            b64("Getting started"),
+				Description:     b64("First steps"),
+				VirtualMachines: []map[string]string{{"cp": "ubuntu"}},
+				Steps: []hfv1.ScenarioStep{
+					{Title: b64("Login"), Content: b64("Log in"), Checks: []hfv1.StepCheck{{VirtualMachine: "cp", Command: "true"}}},
+					{Title: b64("Explore"), Content: b64("Look around")},
+				},
+			}
+			tt.change(&spec)
+
+			got := Scenario(context.Background(), client, spec)
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("Scenario() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ScenarioErrors(t *testing.T) {
+	spec := hfv1.ScenarioSpec{
+		Name:          "plain",
+		Categories:    []string{"networking", "not a label"},
+		SessionPolicy: hfv1.SessionPolicy{MaxSessionDuration: "forever"},
+		Steps: []hfv1.ScenarioStep{{
+			Title:     b64("Quiz"),
+			Content:   b64("Answer"),
+			Checks:    []hfv1.StepCheck{{VirtualMachine: "cp"}},
+			Questions: []hfv1.StepQuestion{{Type: hfv1.QuestionTypeSingleChoice, Text: "Pick", Choices: []string{"a", "b"}}},
+		}},
+	}
+
+	issues := Scenario(context.Background(), fake.NewSimpleClientset(), spec)
+	errs := Errors(issues)
+
+	fields := []string{}
+	for _, issue := range errs {
+		fields = append(fields, issue.Field)
+	}
+	want := []string{"categories[1]", "session_policy", "steps[0].checks[0]", "steps[0].checks[0]", "steps[0].questions[0]"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("errors of %s, want errors of %v", Message(errs), want)
+	}
+	if len(issues) == len(errs) {
+		t.Error("Errors() did not leave out the warnings")
+	}
+	if message := Message(errs); !strings.HasPrefix(message, "categories[1]: invalid category not a label") || strings.Count(message, "; ") != len(errs)-1 {
+		t.Errorf("Message() = %q", message)
+	}
+}
+
+func Test_Course(t *testing.T) {
+	ns := util.GetReleaseNamespace()
+	client := fake.NewSimpleClientset(
+		&hfv1.Scenario{ObjectMeta: metav1.ObjectMeta{Name: "intro", Namespace: ns, Labels: map[string]string{"category-basics": "true"}}},
+		&hfv1.Scenario{ObjectMeta: metav1.ObjectMeta{Name: "install", Namespace: ns}},
+	)
+
+	course := hfv1.CourseSpec{
+		Name:          b64("Kubernetes"),
+		Description:   b64("All about it"),
+		Scenarios:     []string{"intro", "install", "operate"},
+		Categories:    []string{"basics", "advanced"},
+		Sequence:      hfv1.CourseSequencePrerequisites,
+		Prerequisites: map[string][]string{"install": {"install"}},
+	}
+
+	got := Course(context.Background(), client, course)
+	want := []Issue{
+		{SeverityError, "scenarios[2]", "scenario operate does not exist"},
+		{SeverityWarning, "categories[1]", "category query advanced matches no scenario"},
+		{SeverityError, "prerequisites", "scenario install requires itself"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Course() = %+v, want %+v", got, want)
+	}
+
+	if got := Course(context.Background(), client, hfv1.CourseSpec{Name: b64("Empty"), Description: b64("Nothing")}); len(got) != 1 || got[0].Field != "scenarios" {
+		t.Errorf("Course() of a course without scenarios = %+v", got)
+	}
+}
@@ -0,0 +1,272 @@
+package locale
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"golang.org/x/text/language"
+)
+
+const (
+	// SettingKey is the user setting holding the preferred locale
+	SettingKey = "locale"
+)
+
+// Report tells how much of a content object is translated into a locale
+type Report struct {
+	Locale     string   `json:"locale"`
+	Translated int      `json:"translated"`
+	Total      int      `json:"total"`
+	Complete   bool     `json:"complete"`
+	Missing    []string `json:"missing"` // fields that fall back to the default locale
+}
+
+// Preferred returns the locales a user asked for, most preferred first.
+// The locale in the user's settings wins over the Accept-Language header.
+func Preferred(r *http.Request, settings map[string]string) []language.Tag {
+	preferred := []language.Tag{}
+
+	if tag, err := language.Parse(settings[SettingKey]); err == nil {
+		preferred = append(preferred, tag)
+	}
+
+	if tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language")); err == nil {
+		preferred = append(preferred, tags...)
+	}
+
+	return preferred
+}
+
+// Match picks the locale out of the translations that fits the preferred ones best.
+// An empty result means the default locale.
+func Match(preferred []language.Tag, defaultLocale string, translations []string) string {
+	if len(preferred) == 0 || len(translations) == 0 {
+		return ""
+	}
+
+	// the default locale comes first so that it wins whenever nothing fits
+	defaultTag, err := language.Parse(defaultLocale)
+	if err != nil {
+		defaultTag = language.Und
+	}
+	supported := []language.Tag{defaultTag}
+	locales := []string{""}
+	for _, t := range translations {
+		tag, err := language.Parse(t)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		locales = append(locales, t)
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No {
+		return ""
+	}
+	return locales[index]
+}
+
+// Scenario returns the scenario translated into the preferred locale along with that locale.
+// Translations are dropped from the result.
+func Scenario(spec hfv1.ScenarioSpec, preferred []language.Tag) (hfv1.ScenarioSpec, string) {
+	locale := Match(preferred, spec.Locale, scenarioLocales(spec))
+	translation, ok := spec.Translations[locale]
+
+	localized := *spec.DeepCopy()
+	localized.Translations = nil
+	if !ok {
+		return localized, spec.Locale
+	}
+
+	localized.Locale = locale
+	localized.Name = fallback(translation.Name, spec.Name)
+	localized.Description = fallback(translation.Description, spec.Description)
+	for i, st := range translation.Steps {
+		if i >= len(localized.Steps) {
+			break
+		}
+		step := &localized.Steps[i]
+		step.Title = fallback(st.Title, step.Title)
+		step.Content = fallback(st.Content, step.Content)
+		for j, qt := range st.Questions {
+			if j >= len(step.Questions) {
+				break
+			}
+			question := &step.Questions[j]
+			question.Text = fallback(qt.Text, question.Text)
+			if len(qt.Choices) == len(question.Choices) {
+				question.Choices = qt.Choices
+			}
+		}
+	}
+
+	return localized, locale
+}
+
+// Course returns the course translated into the preferred locale along with that locale.
+// Translations are dropped from the result.
+func Course(spec hfv1.CourseSpec, preferred []language.Tag) (hfv1.CourseSpec, string) {
+	locales := []string{}
+	for l := range spec.Translations {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	locale := Match(preferred, spec.Locale, locales)
+	translation, ok := spec.Translations[locale]
+
+	localized := *spec.DeepCopy()
+	localized.Translations = nil
+	if !ok {
+		return localized, spec.Locale
+	}
+
+	localized.Locale = locale
+	localized.Name = fallback(translation.Name, spec.Name)
+	localized.Description = fallback(translation.Description, spec.Description)
+
+	return localized, locale
+}
+
+// ValidateScenario checks that the translations of a scenario fit its steps
+func ValidateScenario(spec hfv1.ScenarioSpec) error {
+	if err := validateLocales(spec.Locale, scenarioLocales(spec)); err != nil {
+		return err
+	}
+
+	for locale, translation := range spec.Translations {
+		if len(translation.Steps) > len(spec.Steps) {
+			return fmt.Errorf("translation %s has %d steps, the scenario only %d", locale, len(translation.Steps), len(spec.Steps))
+		}
+		for i, st := range translation.Steps {
+			if len(st.Questions) > len(spec.Steps[i].Questions) {
+				return fmt.Errorf("translation %s step %d has more questions than the step", locale, i)
+			}
+			for j, qt := range st.Questions {
+				if len(qt.Choices) > 0 && len(qt.Choices) != len(spec.Steps[i].Questions[j].Choices) {
+					return fmt.Errorf("translation %s step %d question %d has to translate all %d choices", locale, i, j, len(spec.Steps[i].Questions[j].Choices))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCourse checks the locales of a course
+func ValidateCourse(spec hfv1.CourseSpec) error {
+	locales := []string{}
+	for l := range spec.Translations {
+		locales = append(locales, l)
+	}
+	return validateLocales(spec.Locale, locales)
+}
+
+// ScenarioCompleteness reports every translation of a scenario
+func ScenarioCompleteness(spec hfv1.ScenarioSpec) []Report {
+	reports := []Report{}
+	for _, locale := range scenarioLocales(spec) {
+		translation := spec.Translations[locale]
+		report := Report{Locale: locale, Missing: []string{}}
+
+		report.check("name", spec.Name, translation.Name)
+		report.check("description", spec.Description, translation.Description)
+		for i, step := range spec.Steps {
+			st := hfv1.StepTranslation{}
+			if i < len(translation.Steps) {
+				st = translation.Steps[i]
+			}
+			report.check(fmt.Sprintf("steps[%d].title", i), step.Title, st.Title)
+			report.check(fmt.Sprintf("steps[%d].content", i), step.Content, st.Content)
+			for j, question := range step.Questions {
+				qt := hfv1.QuestionTranslation{}
+				if j < len(st.Questions) {
+					qt = st.Questions[j]
+				}
+				report.check(fmt.Sprintf("steps[%d].questions[%d].text", i, j), question.Text, qt.Text)
+				choices := ""
+				if len(qt.Choices) > 0 {
+					choices = "translated"
+				}
+				report.check(fmt.Sprintf("steps[%d].questions[%d].choices", i, j), fmt.Sprint(len(question.Choices) > 0), choices)
+			}
+		}
+
+		report.Complete = len(report.Missing) == 0
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// CourseCompleteness reports every translation of a course
+func CourseCompleteness(spec hfv1.CourseSpec) []Report {
+	locales := []string{}
+	for l := range spec.Translations {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	reports := []Report{}
+	for _, locale := range locales {
+		translation := spec.Translations[locale]
+		report := Report{Locale: locale, Missing: []string{}}
+
+		report.check("name", spec.Name, translation.Name)
+		report.check("description", spec.Description, translation.Description)
+
+		report.Complete = len(report.Missing) == 0
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// check counts a field that has a text in the default locale
+func (r *Report) check(field string, original string, translated string) {
+	if original == "" || original == "false" {
+		return
+	}
+	r.Total++
+	if translated == "" {
+		r.Missing = append(r.Missing, field)
+		return
+	}
+	r.Translated++
+}
+
+func scenarioLocales(spec hfv1.ScenarioSpec) []string {
+	locales := []string{}
+	for l := range spec.Translations {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func validateLocales(defaultLocale string, translations []string) error {
+	if defaultLocale != "" {
+		if _, err := language.Parse(defaultLocale); err != nil {
+			return fmt.Errorf("invalid locale %s", defaultLocale)
+		}
+	}
+
+	for _, l := range translations {
+		if _, err := language.Parse(l); err != nil {
+			return fmt.Errorf("invalid locale %s", l)
+		}
+		if l == defaultLocale {
+			return fmt.Errorf("the default locale %s can not be a translation", l)
+		}
+	}
+
+	return nil
+}
+
+func fallback(translated string, original string) string {
+	if translated == "" {
+		return original
+	}
+	return translated
+}
@@ -0,0 +1,184 @@
+package locale
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"golang.org/x/text/language"
+)
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		name         string
+		preferred    string // Accept-Language
+		translations []string
+		want         string
+	}{
+		{"nothing preferred", "", []string{"de"}, ""},
+		{"no translations", "de", nil, ""},
+		{"exact match", "de", []string{"de", "fr"}, "de"},
+		{"regional variant", "de-AT", []string{"de", "fr"}, "de"},
+		{"default locale preferred", "en-GB, de;q=0.5", []string{"de"}, ""},
+		{"second choice", "ja, fr;q=0.8", []string{"de", "fr"}, "fr"},
+		{"region picked over the base language", "pt-BR", []string{"pt-PT", "pt-BR"}, "pt-BR"},
+		{"nothing fits", "ja", []string{"de", "fr"}, ""},
+		{"invalid translation is skipped", "de", []string{"not a locale", "de"}, "de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferred, _, err := language.ParseAcceptLanguage(tt.preferred)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Match(preferred, "en", tt.translations); got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.preferred, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Preferred(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr-CH, fr;q=0.9")
+
+	got := Preferred(r, map[string]string{SettingKey: "de"})
+	want := []language.Tag{language.German, language.MustParse("fr-CH"), language.French}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Preferred() = %v, want %v", got, want)
+	}
+
+	if got := Preferred(r, map[string]string{SettingKey: "!!"}); len(got) != 2 {
+		t.Errorf("Preferred() with an invalid setting = %v, want the header only", got)
+	}
+}
+
+func Test_Scenario(t *testing.T) {
+	spec := hfv1.ScenarioSpec{
+		Name:        "Getting started",
+		Description: "First steps",
+		Locale:      "en",
+		Steps: []hfv1.ScenarioStep{
+			{Title: "Login", Content: "Log in"},
+			{Title: "Quiz", Content: "Answer", Questions: []hfv1.StepQuestion{
+				{Text: "Pick one", Choices: []string{"yes", "no"}, CorrectChoices: []int{0}},
+				{Text: "Pick again", Choices: []string{"a", "b", "c"}},
+			}},
+		},
+		Translations: map[string]hfv1.ScenarioTranslation{
+			"de": {
+				Name: "Erste Schritte",
+				Steps: []hfv1.StepTranslation{
+					{Title: "Anmelden"},
+					{Content: "Antworten", Questions: []hfv1.QuestionTranslation{
+						{Text: "Wähle eins", Choices: []string{"ja", "nein"}},
+						{Choices: []string{"a", "b"}}, // not all choices, they are kept
+					}},
+				},
+			},
+		},
+	}
+
+	localized, locale := Scenario(spec, []language.Tag{language.German})
+	if locale != "de" || localized.Locale != "de" || localized.Translations != nil {
+		t.Fatalf("Scenario() = %s, %+v", locale, localized)
+	}
+	if localized.Name != "Erste Schritte" || localized.Description != "First steps" {
+		t.Errorf("name and description = %q, %q", localized.Name, localized.Description)
+	}
+	if s := localized.Steps[0]; s.Title != "Anmelden" || s.Content != "Log in" {
+		t.Errorf("step 0 = %+v", s)
+	}
+	if s := localized.Steps[1]; s.Title != "Quiz" || s.Content != "Antworten" {
+		t.Errorf("step 1 = %+v", s)
+	}
+	q := localized.Steps[1].Questions
+	if q[0].Text != "Wähle eins" || !reflect.DeepEqual(q[0].Choices, []string{"ja", "nein"}) || !reflect.DeepEqual(q[0].CorrectChoices, []int{0}) {
+		t.Errorf("question 0 = %+v", q[0])
+	}
+	if q[1].Text != "Pick again" || len(q[1].Choices) != 3 {
+		t.Errorf("question 1 = %+v", q[1])
+	}
+	if spec.Steps[1].Questions[0].Choices[0] != "yes" || spec.Translations == nil {
+		t.Error("Scenario() changed the original spec")
+	}
+
+	original, locale := Scenario(spec, []language.Tag{language.Japanese})
+	if locale != "en" || original.Name != "Getting started" || original.Translations != nil {
+		t.Errorf("Scenario() without a fitting translation = %s, %+v", locale, original)
+	}
+}
+
+func Test_ValidateScenario(t *testing.T) {
+	base := func() hfv1.ScenarioSpec {
+		return hfv1.ScenarioSpec{
+			Locale: "en",
+			Steps: []hfv1.ScenarioStep{{Title: "Quiz", Questions: []hfv1.StepQuestion{
+				{Text: "Pick", Choices: []string{"a", "b"}},
+			}}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		translation hfv1.ScenarioTranslation
+		locale      string
+		wantErr     string
+	}{
+		{"valid", hfv1.ScenarioTranslation{Steps: []hfv1.StepTranslation{{Questions: []hfv1.QuestionTranslation{{Choices: []string{"x", "y"}}}}}}, "de", ""},
+		{"invalid locale", hfv1.ScenarioTranslation{}, "deutsch!", "invalid locale"},
+		{"default locale", hfv1.ScenarioTranslation{}, "en", "can not be a translation"},
+		{"too many steps", hfv1.ScenarioTranslation{Steps: make([]hfv1.StepTranslation, 2)}, "de", "has 2 steps"},
+		{"too many questions", hfv1.ScenarioTranslation{Steps: []hfv1.StepTranslation{{Questions: make([]hfv1.QuestionTranslation, 2)}}}, "de", "more questions"},
+		{"some of the choices", hfv1.ScenarioTranslation{Steps: []hfv1.StepTranslation{{Questions: []hfv1.QuestionTranslation{{Choices: []string{"x"}}}}}}, "de", "all 2 choices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := base()
+			spec.Translations = map[string]hfv1.ScenarioTranslation{tt.locale: tt.translation}
+			err := ValidateScenario(spec)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateScenario() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateScenario() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ScenarioCompleteness(t *testing.T) {
+	spec := hfv1.ScenarioSpec{
+		Name: "Getting started",
+		Steps: []hfv1.ScenarioStep{
+			{Title: "Login", Content: "Log in"},
+			{Title: "Quiz", Questions: []hfv1.StepQuestion{{Text: "Pick", Choices: []string{"a", "b"}}}},
+		},
+		Translations: map[string]hfv1.ScenarioTranslation{
+			"fr": {Name: "Premiers pas", Steps: []hfv1.StepTranslation{
+				{Title: "Connexion", Content: "Connectez-vous"},
+				{Title: "Quiz", Questions: []hfv1.QuestionTranslation{{Text: "Choisir", Choices: []string{"a", "b"}}}},
+			}},
+			"de": {Name: "Erste Schritte", Steps: []hfv1.StepTranslation{{Title: "Anmelden"}}},
+		},
+	}
+
+	reports := ScenarioCompleteness(spec)
+	if len(reports) != 2 || reports[0].Locale != "de" || reports[1].Locale != "fr" {
+		t.Fatalf("ScenarioCompleteness() = %+v, want de and fr", reports)
+	}
+
+	// the description and the content of the second step are empty and not counted
+	de := reports[0]
+	wantMissing := []string{"steps[0].content", "steps[1].title", "steps[1].questions[0].text", "steps[1].questions[0].choices"}
+	if de.Complete || de.Total != 6 || de.Translated != 2 || !reflect.DeepEqual(de.Missing, wantMissing) {
+		t.Errorf("de = %+v, want missing %v", de, wantMissing)
+	}
+	if fr := reports[1]; !fr.Complete || fr.Translated != fr.Total || len(fr.Missing) != 0 {
+		t.Errorf("fr = %+v, want complete", fr)
+	}
+}
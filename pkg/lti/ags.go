@@ -0,0 +1,177 @@
+package lti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	ActivityProgressStarted    = "Started"
+	ActivityProgressCompleted  = "Completed"
+	GradingProgressFullyGraded = "FullyGraded"
+
+	scoreContentType = "application/vnd.ims.lis.v1.score+json"
+	assertionType    = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// Score is published to the line item of a resource link
+type Score struct {
+	UserId           string `json:"userId"`
+	ScoreGiven       int    `json:"scoreGiven"`
+	ScoreMaximum     int    `json:"scoreMaximum"`
+	ActivityProgress string `json:"activityProgress"`
+	GradingProgress  string `json:"gradingProgress"`
+	Timestamp        string `json:"timestamp"`
+}
+
+type accessToken struct {
+	token   string
+	expires time.Time
+}
+
+// GradeService publishes scores through the assignment and grade services of platforms. Access tokens are
+// requested with a client assertion signed by the tool key and reused until they expire.
+type GradeService struct {
+	keys       *KeyStore
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]accessToken
+}
+
+func NewGradeService(keys *KeyStore) *GradeService {
+	return &GradeService{
+		keys:       keys,
+		httpClient: &http.Client{Timeout: httpTimeout},
+		tokens:     map[string]accessToken{},
+	}
+}
+
+func (g *GradeService) Publish(ctx context.Context, platform *hfv1.LTIPlatform, lineItem string, score Score) error {
+	token, err := g.accessToken(ctx, platform)
+	if err != nil {
+		return err
+	}
+
+	target, err := scoresURL(lineItem)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(score)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", scoreContentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		g.forgetToken(platform)
+	}
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("platform %s responded with %d: %s", platform.Name, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (g *GradeService) accessToken(ctx context.Context, platform *hfv1.LTIPlatform) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if cached, ok := g.tokens[platform.Name]; ok && time.Now().Before(cached.expires) {
+		return cached.token, nil
+	}
+
+	jti, err := util.RandSecretRunes(32)
+	if err != nil {
+		return "", err
+	}
+	assertion, err := g.keys.Sign(jwt.MapClaims{
+		"iss": platform.Spec.ClientId,
+		"sub": platform.Spec.ClientId,
+		"aud": platform.Spec.AuthTokenURL,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(responseTTL).Unix(),
+		"jti": jti,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {assertionType},
+		"client_assertion":      {assertion},
+		"scope":                 {ScopeScore},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, platform.Spec.AuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting access token from %s: %v", platform.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("error requesting access token from %s: status %d: %s", platform.Name, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var granted struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&granted); err != nil {
+		return "", fmt.Errorf("error decoding access token from %s: %v", platform.Name, err)
+	}
+
+	// renew a bit early so that a token does not expire while it is used
+	expiresIn := max(time.Duration(granted.ExpiresIn)*time.Second-time.Minute, 0)
+	g.tokens[platform.Name] = accessToken{token: granted.AccessToken, expires: time.Now().Add(expiresIn)}
+
+	return granted.AccessToken, nil
+}
+
+func (g *GradeService) forgetToken(platform *hfv1.LTIPlatform) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.tokens, platform.Name)
+}
+
+// scoresURL returns the scores endpoint of a line item, the line item url may carry a query
+func scoresURL(lineItem string) (string, error) {
+	u, err := url.Parse(lineItem)
+	if err != nil {
+		return "", fmt.Errorf("invalid line item %s: %v", lineItem, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/scores"
+	return u.String(), nil
+}
@@ -0,0 +1,131 @@
+package lti
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	stateAudience   = "hobbyfarm-lti-state"
+	pickerAudience  = "hobbyfarm-lti-deep-linking"
+	stateTTL        = 10 * time.Minute
+	responseTTL     = 5 * time.Minute
+	maxScoreDefault = 100
+)
+
+// ContentItem is a resource link offered to the platform in a deep linking response
+type ContentItem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Custom   map[string]string `json:"custom,omitempty"`
+	LineItem *LineItem         `json:"lineItem,omitempty"`
+}
+
+// LineItem asks the platform to create a gradebook column for a content item
+type LineItem struct {
+	ScoreMaximum int    `json:"scoreMaximum"`
+	Label        string `json:"label"`
+}
+
+// NewState returns the state the login is started with, it is signed so that a launch can only complete a login
+// this tool started and holds the nonce the id token has to carry
+func NewState(keys *KeyStore, platform string) (string, string, error) {
+	nonce, err := util.RandSecretRunes(32)
+	if err != nil {
+		return "", "", err
+	}
+	state, err := keys.Sign(jwt.MapClaims{
+		"aud":      stateAudience,
+		"platform": platform,
+		"nonce":    nonce,
+		"iat":      time.Now().Unix(),
+		"exp":      time.Now().Add(stateTTL).Unix(),
+	})
+	return state, nonce, err
+}
+
+// ParseState returns the platform and nonce of a state created by NewState
+func ParseState(keys *KeyStore, state string) (string, string, error) {
+	claims, err := keys.Verify(state)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid state: %v", err)
+	}
+	if !claims.VerifyAudience(stateAudience, true) {
+		return "", "", fmt.Errorf("invalid state")
+	}
+	platform, _ := claims["platform"].(string)
+	nonce, _ := claims["nonce"].(string)
+	return platform, nonce, nil
+}
+
+// NewPickerToken carries a deep linking request from the launch to the submission of the chosen content
+func NewPickerToken(keys *KeyStore, platform string, launch Launch) (string, error) {
+	return keys.Sign(jwt.MapClaims{
+		"aud":           pickerAudience,
+		"platform":      platform,
+		"deployment_id": launch.DeploymentId,
+		"context":       launch.Context,
+		"return_url":    launch.DeepLinkReturnURL,
+		"data":          launch.DeepLinkData,
+		"iat":           time.Now().Unix(),
+		"exp":           time.Now().Add(stateTTL).Unix(),
+	})
+}
+
+// ParsePickerToken returns the platform and the deep linking request of a token created by NewPickerToken
+func ParsePickerToken(keys *KeyStore, token string) (string, Launch, error) {
+	claims, err := keys.Verify(token)
+	if err != nil {
+		return "", Launch{}, fmt.Errorf("invalid deep linking request: %v", err)
+	}
+	if !claims.VerifyAudience(pickerAudience, true) {
+		return "", Launch{}, fmt.Errorf("invalid deep linking request")
+	}
+
+	platform, _ := claims["platform"].(string)
+	launch := Launch{MessageType: MessageTypeDeepLinking}
+	launch.DeploymentId, _ = claims["deployment_id"].(string)
+	launch.Context, _ = claims["context"].(string)
+	launch.DeepLinkReturnURL, _ = claims["return_url"].(string)
+	launch.DeepLinkData, _ = claims["data"].(string)
+	return platform, launch, nil
+}
+
+// NewResourceLink returns the content item that launches a scenario or course, kind is CustomScenario or CustomCourse.
+// It has no url, platforms launch it through the launch url the tool was registered with.
+func NewResourceLink(kind string, id string, title string) ContentItem {
+	return ContentItem{
+		Type:     "ltiResourceLink",
+		Title:    title,
+		Custom:   map[string]string{kind: id},
+		LineItem: &LineItem{ScoreMaximum: maxScoreDefault, Label: title},
+	}
+}
+
+// DeepLinkingResponse returns the signed response that hands the chosen content items back to the platform
+func DeepLinkingResponse(keys *KeyStore, platform *hfv1.LTIPlatform, launch Launch, items []ContentItem) (string, error) {
+	nonce, err := util.RandSecretRunes(32)
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.MapClaims{
+		"iss":                 platform.Spec.ClientId,
+		"aud":                 platform.Spec.Issuer,
+		"iat":                 time.Now().Unix(),
+		"exp":                 time.Now().Add(responseTTL).Unix(),
+		"nonce":               nonce,
+		ClaimMessageType:      MessageTypeDeepLinkingResponse,
+		ClaimVersion:          Version,
+		ClaimDeploymentId:     launch.DeploymentId,
+		ClaimDeepLinkingItems: items,
+	}
+	if launch.DeepLinkData != "" {
+		claims[ClaimDeepLinkingData] = launch.DeepLinkData
+	}
+	return keys.Sign(claims)
+}
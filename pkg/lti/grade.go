@@ -0,0 +1,64 @@
+package lti
+
+import (
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+)
+
+// ScenarioScore grades a scenario by its best quiz score, or by the steps reached if it has no quiz.
+// Returns false if the user did not start the scenario yet.
+func ScenarioScore(subject string, scenario string, progress []hfv2.Progress) (Score, bool) {
+	score := Score{UserId: subject, ActivityProgress: ActivityProgressStarted, GradingProgress: GradingProgressFullyGraded}
+	started := false
+	quiz := false
+	for _, p := range progress {
+		if p.Spec.Scenario != scenario {
+			continue
+		}
+		started = true
+		if completion.ScenarioCompleted(p.Spec) {
+			score.ActivityProgress = ActivityProgressCompleted
+		}
+
+		if p.Spec.MaxScore > 0 {
+			if !quiz || p.Spec.Score > score.ScoreGiven {
+				score.ScoreGiven = p.Spec.Score
+				score.ScoreMaximum = p.Spec.MaxScore
+			}
+			quiz = true
+		} else if reached := min(p.Spec.MaxStep+1, p.Spec.TotalStep); !quiz && reached >= score.ScoreGiven {
+			score.ScoreGiven = reached
+			score.ScoreMaximum = p.Spec.TotalStep
+		}
+	}
+	score.Timestamp = time.Now().Format(time.RFC3339)
+	return score, started
+}
+
+// CourseScore grades a course by the number of its scenarios completed within it.
+// Returns false if the user did not start the course yet.
+func CourseScore(subject string, course *hfv1.Course, progress []hfv2.Progress) (Score, bool) {
+	score := Score{UserId: subject, ActivityProgress: ActivityProgressStarted, GradingProgress: GradingProgressFullyGraded}
+	started := false
+	for _, p := range progress {
+		if p.Spec.Course == course.Name {
+			started = true
+		}
+	}
+
+	completed := completion.CompletedScenarios(progress, course.Name)
+	for _, s := range course.Spec.Scenarios {
+		if completed[s] {
+			score.ScoreGiven++
+		}
+	}
+	score.ScoreMaximum = len(course.Spec.Scenarios)
+	if completion.CourseCompleted(course.Spec, completed) {
+		score.ActivityProgress = ActivityProgressCompleted
+	}
+	score.Timestamp = time.Now().Format(time.RFC3339)
+	return score, started
+}
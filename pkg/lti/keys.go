@@ -0,0 +1,145 @@
+package lti
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	keySecretName = "hobbyfarm-lti-key"
+	keySecretData = "key.pem"
+	keySize       = 2048
+)
+
+// JWK is a public rsa key as published in a json web key set
+type JWK struct {
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type KeySet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyStore holds the key of the tool. Platforms verify deep linking responses and client assertions with it,
+// it is created on first use and kept in a secret so that every replica signs with the same key.
+type KeyStore struct {
+	kubeClient kubernetes.Interface
+	ctx        context.Context
+
+	mu    sync.Mutex
+	key   *rsa.PrivateKey
+	keyId string
+}
+
+func NewKeyStore(kubeClient kubernetes.Interface, ctx context.Context) *KeyStore {
+	return &KeyStore{
+		kubeClient: kubeClient,
+		ctx:        ctx,
+	}
+}
+
+// Sign returns a RS256 signed token whose header names the key it was signed with
+func (k *KeyStore) Sign(claims jwt.MapClaims) (string, error) {
+	key, keyId, err := k.loadKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyId
+	return token.SignedString(key)
+}
+
+// Verify parses a token signed by the tool itself
+func (k *KeyStore) Verify(tokenString string) (jwt.MapClaims, error) {
+	key, _, err := k.loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
+// KeySet returns the public key of the tool as a json web key set
+func (k *KeyStore) KeySet() (KeySet, error) {
+	key, keyId, err := k.loadKey()
+	if err != nil {
+		return KeySet{}, err
+	}
+
+	return KeySet{Keys: []JWK{{
+		Kty: "RSA",
+		Alg: "RS256",
+		Use: "sig",
+		Kid: keyId,
+		N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+	}}}, nil
+}
+
+func (k *KeyStore) loadKey() (*rsa.PrivateKey, string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != nil {
+		return k.key, k.keyId, nil
+	}
+
+	encoded, err := util.SigningKey(k.kubeClient, keySecretName, keySecretData, func() ([]byte, error) {
+		key, err := rsa.GenerateKey(rand.Reader, keySize)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	}, k.ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("error retrieving lti signing key: %v", err)
+	}
+
+	block, _ := pem.Decode(encoded)
+	if block == nil {
+		return nil, "", fmt.Errorf("secret %s holds no valid signing key", keySecretName)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("secret %s holds no valid signing key: %v", keySecretName, err)
+	}
+
+	k.key = key
+	hash := sha256.Sum256(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	k.keyId = hex.EncodeToString(hash[:8])
+
+	return k.key, k.keyId, nil
+}
@@ -0,0 +1,114 @@
+package lti
+
+import (
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	Version = "1.3.0"
+
+	MessageTypeResourceLink        = "LtiResourceLinkRequest"
+	MessageTypeDeepLinking         = "LtiDeepLinkingRequest"
+	MessageTypeDeepLinkingResponse = "LtiDeepLinkingResponse"
+
+	ClaimMessageType         = "https://purl.imsglobal.org/spec/lti/claim/message_type"
+	ClaimVersion             = "https://purl.imsglobal.org/spec/lti/claim/version"
+	ClaimDeploymentId        = "https://purl.imsglobal.org/spec/lti/claim/deployment_id"
+	ClaimContext             = "https://purl.imsglobal.org/spec/lti/claim/context"
+	ClaimResourceLink        = "https://purl.imsglobal.org/spec/lti/claim/resource_link"
+	ClaimCustom              = "https://purl.imsglobal.org/spec/lti/claim/custom"
+	ClaimDeepLinkingSettings = "https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"
+	ClaimDeepLinkingItems    = "https://purl.imsglobal.org/spec/lti-dl/claim/content_items"
+	ClaimDeepLinkingData     = "https://purl.imsglobal.org/spec/lti-dl/claim/data"
+	ClaimGradeService        = "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"
+
+	ScopeScore = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+	// CustomScenario and CustomCourse are the custom parameters deep linking stores the chosen content in
+	CustomScenario = "scenario"
+	CustomCourse   = "course"
+)
+
+// Launch holds the claims of a validated id token the tool acts on
+type Launch struct {
+	MessageType  string
+	DeploymentId string
+	Subject      string
+	Email        string
+	Name         string
+	Context      string
+	ResourceLink string
+	Custom       map[string]string
+
+	// assignment and grade services, the line item is only set if the platform accepts scores for the resource link
+	LineItem    string
+	ScoreScoped bool
+
+	// deep linking
+	DeepLinkReturnURL string
+	DeepLinkData      string
+}
+
+// ParseLaunch reads the claims of an id token that passed ValidateIDToken
+func ParseLaunch(claims jwt.MapClaims) (Launch, error) {
+	launch := Launch{
+		Custom: map[string]string{},
+	}
+
+	if version, _ := claims[ClaimVersion].(string); version != Version {
+		return Launch{}, fmt.Errorf("unsupported lti version %q", version)
+	}
+
+	launch.MessageType, _ = claims[ClaimMessageType].(string)
+	launch.DeploymentId, _ = claims[ClaimDeploymentId].(string)
+	launch.Subject, _ = claims["sub"].(string)
+	launch.Email, _ = claims["email"].(string)
+	launch.Name, _ = claims["name"].(string)
+	if launch.Subject == "" {
+		return Launch{}, fmt.Errorf("launch has no subject")
+	}
+
+	launch.Context = claimField(claims, ClaimContext, "id")
+	launch.ResourceLink = claimField(claims, ClaimResourceLink, "id")
+	if custom, ok := claims[ClaimCustom].(map[string]interface{}); ok {
+		for key, value := range custom {
+			if s, ok := value.(string); ok {
+				launch.Custom[key] = s
+			}
+		}
+	}
+
+	launch.LineItem = claimField(claims, ClaimGradeService, "lineitem")
+	if service, ok := claims[ClaimGradeService].(map[string]interface{}); ok {
+		launch.ScoreScoped = util.StringInSlice(ScopeScore, stringSlice(service["scope"]))
+	}
+
+	switch launch.MessageType {
+	case MessageTypeResourceLink:
+		if launch.ResourceLink == "" {
+			return Launch{}, fmt.Errorf("resource link launch has no resource link")
+		}
+	case MessageTypeDeepLinking:
+		launch.DeepLinkReturnURL = claimField(claims, ClaimDeepLinkingSettings, "deep_link_return_url")
+		launch.DeepLinkData = claimField(claims, ClaimDeepLinkingSettings, "data")
+		if launch.DeepLinkReturnURL == "" {
+			return Launch{}, fmt.Errorf("deep linking launch has no return url")
+		}
+	default:
+		return Launch{}, fmt.Errorf("unsupported message type %q", launch.MessageType)
+	}
+
+	return launch, nil
+}
+
+func claimField(claims jwt.MapClaims, claim string, field string) string {
+	object, ok := claims[claim].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := object[field].(string)
+	return value
+}
@@ -0,0 +1,178 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	keySetTTL     = time.Hour
+	keySetRefresh = time.Minute // an unknown key id refetches the key set at most this often
+	clockSkew     = time.Minute
+	httpTimeout   = 10 * time.Second
+)
+
+type cachedKeySet struct {
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// KeySetCache fetches and caches the key sets platforms sign their id tokens with
+type KeySetCache struct {
+	httpClient *http.Client
+
+	mu   sync.Mutex
+	sets map[string]cachedKeySet
+}
+
+func NewKeySetCache() *KeySetCache {
+	return &KeySetCache{
+		httpClient: &http.Client{Timeout: httpTimeout},
+		sets:       map[string]cachedKeySet{},
+	}
+}
+
+// Key returns the key of a key set. The key set is fetched again once it expired, or if the key is unknown
+// as platforms rotate their keys.
+func (c *KeySetCache) Key(url string, keyId string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, ok := c.sets[url]
+	age := time.Since(set.fetched)
+	if !ok || age > keySetTTL || (set.keys[keyId] == nil && age > keySetRefresh) {
+		keys, err := c.fetch(url)
+		if err != nil {
+			return nil, err
+		}
+		set = cachedKeySet{keys: keys, fetched: time.Now()}
+		c.sets[url] = set
+	}
+
+	key, ok := set.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in key set %s", keyId, url)
+	}
+	return key, nil
+}
+
+func (c *KeySetCache) fetch(url string) (map[string]*rsa.PublicKey, error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching key set %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching key set %s: status %d", url, resp.StatusCode)
+	}
+
+	set := KeySet{}
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error decoding key set %s: %v", url, err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := publicKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in key set %s: %v", jwk.Kid, url, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func publicKey(jwk JWK) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent too large")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// ValidateIDToken verifies the id token of a launch. It has to be signed with a key of the platform's key set,
+// be issued by the platform for the tool's client id and carry the nonce the login was started with.
+func ValidateIDToken(platform *hfv1.LTIPlatform, idToken string, nonce string, keys *KeySetCache) (jwt.MapClaims, error) {
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg()}, SkipClaimsValidation: true}
+	token, err := parser.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		keyId, _ := token.Header["kid"].(string)
+		return keys.Key(platform.Spec.KeySetURL, keyId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid id token")
+	}
+
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("id token is expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("id token used before issued")
+	}
+	if !claims.VerifyIssuer(platform.Spec.Issuer, true) {
+		return nil, fmt.Errorf("id token was not issued by %s", platform.Spec.Issuer)
+	}
+
+	audience := stringSlice(claims["aud"])
+	if !util.StringInSlice(platform.Spec.ClientId, audience) {
+		return nil, fmt.Errorf("id token was not issued for client %s", platform.Spec.ClientId)
+	}
+	if azp, ok := claims["azp"].(string); (ok || len(audience) > 1) && azp != platform.Spec.ClientId {
+		return nil, fmt.Errorf("id token was not issued for client %s", platform.Spec.ClientId)
+	}
+
+	if claimed, _ := claims["nonce"].(string); nonce == "" || claimed != nonce {
+		return nil, fmt.Errorf("id token nonce does not match")
+	}
+
+	deploymentId, _ := claims[ClaimDeploymentId].(string)
+	if len(platform.Spec.DeploymentIds) > 0 && !util.StringInSlice(deploymentId, platform.Spec.DeploymentIds) {
+		return nil, fmt.Errorf("deployment %s is not registered", deploymentId)
+	}
+
+	return claims, nil
+}
+
+// stringSlice reads a claim that is either a single string or a list of strings
+func stringSlice(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := []string{}
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
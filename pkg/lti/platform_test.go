@@ -0,0 +1,194 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testPlatform signs id tokens with a key of its own and publishes it like a platform does
+type testPlatform struct {
+	signer   *KeyStore
+	keySet   *httptest.Server
+	platform *hfv1.LTIPlatform
+}
+
+func newTestPlatform(t *testing.T) *testPlatform {
+	p := &testPlatform{signer: NewKeyStore(fake.NewSimpleClientset(), context.Background())}
+	p.keySet = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := p.signer.KeySet()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(p.keySet.Close)
+
+	p.platform = &hfv1.LTIPlatform{Spec: hfv1.LTIPlatformSpec{
+		Issuer:        "https://lms.example.com",
+		ClientId:      "hobbyfarm",
+		DeploymentIds: []string{"deployment-1"},
+		KeySetURL:     p.keySet.URL,
+	}}
+	return p
+}
+
+func (p *testPlatform) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":             "https://lms.example.com",
+		"aud":             "hobbyfarm",
+		"sub":             "learner-1",
+		"exp":             now.Add(5 * time.Minute).Unix(),
+		"iat":             now.Unix(),
+		"nonce":           "nonce-1",
+		ClaimDeploymentId: "deployment-1",
+		ClaimVersion:      Version,
+		ClaimMessageType:  MessageTypeResourceLink,
+		ClaimResourceLink: map[string]interface{}{"id": "link-1"},
+	}
+}
+
+func Test_ValidateIDToken(t *testing.T) {
+	p := newTestPlatform(t)
+	keys := NewKeySetCache()
+
+	tests := []struct {
+		name    string
+		change  func(claims jwt.MapClaims)
+		nonce   string
+		wantErr string
+	}{
+		{"valid", func(jwt.MapClaims) {}, "nonce-1", ""},
+		{"audience list with azp", func(c jwt.MapClaims) { c["aud"] = []string{"other", "hobbyfarm"}; c["azp"] = "hobbyfarm" }, "nonce-1", ""},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }, "nonce-1", "expired"},
+		{"without expiry", func(c jwt.MapClaims) { delete(c, "exp") }, "nonce-1", "expired"},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(2 * time.Minute).Unix() }, "nonce-1", "before issued"},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, "nonce-1", "not issued by"},
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "other" }, "nonce-1", "not issued for client"},
+		{"audience list without azp", func(c jwt.MapClaims) { c["aud"] = []string{"other", "hobbyfarm"} }, "nonce-1", "not issued for client"},
+		{"azp of another client", func(c jwt.MapClaims) { c["azp"] = "other" }, "nonce-1", "not issued for client"},
+		{"other nonce", func(jwt.MapClaims) {}, "nonce-2", "nonce does not match"},
+		{"login without a nonce", func(c jwt.MapClaims) { c["nonce"] = "" }, "", "nonce does not match"},
+		{"unregistered deployment", func(c jwt.MapClaims) { c[ClaimDeploymentId] = "deployment-2" }, "nonce-1", "not registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := p.claims()
+			tt.change(claims)
+			idToken, err := p.signer.Sign(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ValidateIDToken(p.platform, idToken, tt.nonce, keys)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateIDToken() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateIDToken() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ValidateIDTokenSignature(t *testing.T) {
+	p := newTestPlatform(t)
+	keys := NewKeySetCache()
+
+	// a key the platform does not publish
+	other := NewKeyStore(fake.NewSimpleClientset(), context.Background())
+	foreign, err := other.Sign(p.claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a token signed with the client id as shared secret instead of the platform's key
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims()).SignedString([]byte("hobbyfarm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a token whose header names the platform's key but that was signed by another one
+	_, keyId, err := p.signer.loadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _, err := other.loadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+	token.Header["kid"] = keyId
+	forged, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := strings.Join(strings.Split(forged, ".")[:2], ".") + "."
+
+	for name, idToken := range map[string]string{
+		"unknown key":       foreign,
+		"hmac":              hmac,
+		"forged signature":  forged,
+		"missing signature": unsigned,
+		"not a token":       "not-a-token",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ValidateIDToken(p.platform, idToken, "nonce-1", keys); err == nil {
+				t.Error("ValidateIDToken() succeeded")
+			}
+		})
+	}
+}
+
+func Test_ParseLaunch(t *testing.T) {
+	claims := (&testPlatform{}).claims()
+	claims["email"] = "learner@example.com"
+	claims[ClaimCustom] = map[string]interface{}{CustomScenario: "sc-1", "count": 3}
+	claims[ClaimGradeService] = map[string]interface{}{
+		"lineitem": "https://lms.example.com/lineitems/1",
+		"scope":    []interface{}{ScopeScore},
+	}
+
+	launch, err := ParseLaunch(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if launch.Subject != "learner-1" || launch.Email != "learner@example.com" || launch.ResourceLink != "link-1" || launch.DeploymentId != "deployment-1" {
+		t.Errorf("ParseLaunch() = %+v", launch)
+	}
+	if len(launch.Custom) != 1 || launch.Custom[CustomScenario] != "sc-1" {
+		t.Errorf("Custom = %v, want only the string parameters", launch.Custom)
+	}
+	if launch.LineItem != "https://lms.example.com/lineitems/1" || !launch.ScoreScoped {
+		t.Errorf("grade service = %s %v", launch.LineItem, launch.ScoreScoped)
+	}
+
+	failures := map[string]func(c jwt.MapClaims){
+		"unsupported version":     func(c jwt.MapClaims) { c[ClaimVersion] = "1.1" },
+		"no subject":              func(c jwt.MapClaims) { delete(c, "sub") },
+		"no resource link":        func(c jwt.MapClaims) { delete(c, ClaimResourceLink) },
+		"unsupported message":     func(c jwt.MapClaims) { c[ClaimMessageType] = "LtiSubmissionReviewRequest" },
+		"deep link without a url": func(c jwt.MapClaims) { c[ClaimMessageType] = MessageTypeDeepLinking },
+	}
+	for name, change := range failures {
+		t.Run(name, func(t *testing.T) {
+			claims := (&testPlatform{}).claims()
+			change(claims)
+			if _, err := ParseLaunch(claims); err == nil {
+				t.Error("ParseLaunch() succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,690 @@
+package ltiserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authserver"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/lti"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/settingclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	nonceTTL = 10 * time.Minute
+
+	// the state of a login is kept in a cookie named after its nonce, so that concurrent logins in one browser work
+	stateCookiePrefix = "lti_state_"
+
+	identityIndex = "lti.hobbyfarm.io/user-identity-index"
+	emailIndex    = "lti.hobbyfarm.io/user-email-index"
+
+	// the identity label holds a hash, the annotations keep the issuer and subject it was derived from
+	issuerAnnotation  = "lti.hobbyfarm.io/issuer"
+	subjectAnnotation = "lti.hobbyfarm.io/subject"
+)
+
+// LTIServer is the LTI 1.3 tool. Platforms start a login, the launch provisions the user and hands a token to the ui,
+// deep linking lets instructors pick the course or scenario a resource link launches.
+type LTIServer struct {
+	hfClientSet      hfClientset.Interface
+	authServer       authserver.AuthServer
+	accessCodeClient *accesscode.AccessCodeClient
+	keys             *lti.KeyStore
+	keySets          *lti.KeySetCache
+	rbac             *rbacclient.Client
+	userIndexer      cache.Indexer
+	ctx              context.Context
+}
+
+type PreparedPickerItem struct {
+	Kind  string
+	ID    string
+	Title string
+}
+
+var pickerTemplate = template.Must(template.New("picker").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>HobbyFarm</title></head>
+<body>
+<h1>Choose the content to link</h1>
+{{ if not .Items }}<p>No courses or scenarios are available for this context.</p>{{ end }}
+{{ range .Items }}
+<form method="post" action="{{ $.Action }}">
+<input type="hidden" name="token" value="{{ $.Token }}">
+<input type="hidden" name="kind" value="{{ .Kind }}">
+<input type="hidden" name="id" value="{{ .ID }}">
+<button type="submit">{{ .Kind }}: {{ .Title }}</button>
+</form>
+{{ end }}
+</body></html>`))
+
+var autoPostTemplate = template.Must(template.New("autopost").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>HobbyFarm</title></head>
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{ .Action }}">
+<input type="hidden" name="JWT" value="{{ .JWT }}">
+<noscript><button type="submit">Continue</button></noscript>
+</form>
+</body></html>`))
+
+func NewLTIServer(hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, kubeClient kubernetes.Interface, authServer authserver.AuthServer, acClient *accesscode.AccessCodeClient, rbac *rbacclient.Client, ctx context.Context) (*LTIServer, error) {
+	l := LTIServer{}
+
+	l.hfClientSet = hfClientSet
+	l.authServer = authServer
+	l.accessCodeClient = acClient
+	l.rbac = rbac
+
+	inf := hfInformerFactory.Hobbyfarm().V2().Users().Informer()
+	indexers := map[string]cache.IndexFunc{identityIndex: userIdentityIndexer, emailIndex: userEmailIndexer}
+	inf.AddIndexers(indexers)
+	l.userIndexer = inf.GetIndexer()
+	l.keys = lti.NewKeyStore(kubeClient, ctx)
+	l.keySets = lti.NewKeySetCache()
+	l.ctx = ctx
+
+	return &l, nil
+}
+
+func (l *LTIServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/lti/login", l.LoginFunc).Methods("GET", "POST")
+	r.HandleFunc("/lti/launch", l.LaunchFunc).Methods("POST")
+	r.HandleFunc("/lti/deeplink", l.DeepLinkFunc).Methods("POST")
+	r.HandleFunc("/lti/jwks", l.KeySetFunc).Methods("GET")
+	glog.V(2).Infof("set up routes for lti server")
+}
+
+// LoginFunc handles the third party login initiation of a platform and redirects to its authorization endpoint
+func (l *LTIServer) LoginFunc(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid login request")
+		return
+	}
+
+	issuer := r.FormValue("iss")
+	loginHint := r.FormValue("login_hint")
+	targetLinkURI := r.FormValue("target_link_uri")
+	if issuer == "" || loginHint == "" || targetLinkURI == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "iss, login_hint and target_link_uri are required")
+		return
+	}
+
+	platform, err := l.findPlatform(issuer, r.FormValue("client_id"))
+	if err != nil {
+		glog.Errorf("error finding lti platform for %s: %v", issuer, err)
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "platform is not registered")
+		return
+	}
+
+	state, nonce, err := lti.NewState(l.keys, platform.Name)
+	if err != nil {
+		glog.Errorf("error creating lti state: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error starting login")
+		return
+	}
+
+	// the launch is only accepted from the browser that started the login. The platform posts the launch
+	// cross-site, so the cookie has to allow that.
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookiePrefix + nonce,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   int(nonceTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	query := url.Values{
+		"scope":         {"openid"},
+		"response_type": {"id_token"},
+		"response_mode": {"form_post"},
+		"prompt":        {"none"},
+		"client_id":     {platform.Spec.ClientId},
+		"redirect_uri":  {targetLinkURI},
+		"login_hint":    {loginHint},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	if messageHint := r.FormValue("lti_message_hint"); messageHint != "" {
+		query.Set("lti_message_hint", messageHint)
+	}
+
+	target, err := url.Parse(platform.Spec.AuthLoginURL)
+	if err != nil {
+		glog.Errorf("invalid auth login url of lti platform %s: %v", platform.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "platform is misconfigured")
+		return
+	}
+	target.RawQuery = query.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+// LaunchFunc validates the id token of a launch. Resource link launches sign the user in and redirect to the ui,
+// deep linking launches show the content picker.
+func (l *LTIServer) LaunchFunc(w http.ResponseWriter, r *http.Request) {
+	state := r.PostFormValue("state")
+	platformName, nonce, err := lti.ParseState(l.keys, state)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 401, "unauthorized", "invalid state")
+		return
+	}
+
+	cookie, err := r.Cookie(stateCookiePrefix + nonce)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		util.ReturnHTTPMessage(w, r, 401, "unauthorized", "login was not started in this browser")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookiePrefix + nonce,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	platform, err := l.hfClientSet.HobbyfarmV1().LTIPlatforms(util.GetReleaseNamespace()).Get(l.ctx, platformName, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error retrieving lti platform %s: %v", platformName, err)
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "platform is not registered")
+		return
+	}
+
+	claims, err := lti.ValidateIDToken(platform, r.PostFormValue("id_token"), nonce, l.keySets)
+	if err != nil {
+		glog.Errorf("invalid id token from lti platform %s: %v", platform.Name, err)
+		util.ReturnHTTPMessage(w, r, 401, "unauthorized", "invalid id token")
+		return
+	}
+
+	launch, err := lti.ParseLaunch(claims)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	fresh, err := l.useNonce(platform, launch, nonce)
+	if err != nil {
+		glog.Errorf("error recording nonce of lti launch from %s: %v", platform.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error recording launch")
+		return
+	}
+	if !fresh {
+		util.ReturnHTTPMessage(w, r, 401, "unauthorized", "id token was already used")
+		return
+	}
+
+	if launch.MessageType == lti.MessageTypeDeepLinking {
+		l.showPicker(w, r, platform, launch)
+		return
+	}
+
+	l.launchResource(w, r, platform, launch)
+}
+
+// DeepLinkFunc returns the content an instructor picked to the platform
+func (l *LTIServer) DeepLinkFunc(w http.ResponseWriter, r *http.Request) {
+	platformName, launch, err := lti.ParsePickerToken(l.keys, r.PostFormValue("token"))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 401, "unauthorized", "invalid deep linking request")
+		return
+	}
+
+	platform, err := l.hfClientSet.HobbyfarmV1().LTIPlatforms(util.GetReleaseNamespace()).Get(l.ctx, platformName, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error retrieving lti platform %s: %v", platformName, err)
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "platform is not registered")
+		return
+	}
+
+	kind := r.PostFormValue("kind")
+	id := r.PostFormValue("id")
+	if !util.StringInSlice(id, l.contentIds(platform, launch.Context, kind)) {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "content is not available for this context")
+		return
+	}
+
+	var title string
+	switch kind {
+	case lti.CustomScenario:
+		scenario, err := l.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(l.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 404, "notfound", "scenario not found")
+			return
+		}
+		title = decode(scenario.Spec.Name)
+	case lti.CustomCourse:
+		course, err := l.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(l.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 404, "notfound", "course not found")
+			return
+		}
+		title = decode(course.Spec.Name)
+	default:
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "kind has to be scenario or course")
+		return
+	}
+
+	response, err := lti.DeepLinkingResponse(l.keys, platform, launch, []lti.ContentItem{lti.NewResourceLink(kind, id, title)})
+	if err != nil {
+		glog.Errorf("error signing deep linking response: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error creating deep linking response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err = autoPostTemplate.Execute(w, map[string]string{"Action": launch.DeepLinkReturnURL, "JWT": response}); err != nil {
+		glog.Errorf("error rendering deep linking response: %v", err)
+	}
+}
+
+// KeySetFunc publishes the key platforms verify the tool's messages with
+func (l *LTIServer) KeySetFunc(w http.ResponseWriter, r *http.Request) {
+	keySet, err := l.keys.KeySet()
+	if err != nil {
+		glog.Errorf("error loading lti key: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error loading key")
+		return
+	}
+
+	encoded, err := json.Marshal(keySet)
+	if err != nil {
+		glog.Error(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	util.ReturnHTTPRaw(w, r, string(encoded))
+}
+
+func (l *LTIServer) showPicker(w http.ResponseWriter, r *http.Request, platform *hfv1.LTIPlatform, launch lti.Launch) {
+	token, err := lti.NewPickerToken(l.keys, platform.Name, launch)
+	if err != nil {
+		glog.Errorf("error creating deep linking token: %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error starting deep linking")
+		return
+	}
+
+	items := []PreparedPickerItem{}
+	for _, id := range l.contentIds(platform, launch.Context, lti.CustomCourse) {
+		if course, err := l.hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(l.ctx, id, metav1.GetOptions{}); err == nil {
+			items = append(items, PreparedPickerItem{Kind: lti.CustomCourse, ID: id, Title: decode(course.Spec.Name)})
+		}
+	}
+	for _, id := range l.contentIds(platform, launch.Context, lti.CustomScenario) {
+		if scenario, err := l.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(l.ctx, id, metav1.GetOptions{}); err == nil {
+			items = append(items, PreparedPickerItem{Kind: lti.CustomScenario, ID: id, Title: decode(scenario.Spec.Name)})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = pickerTemplate.Execute(w, map[string]interface{}{
+		"Action": "/lti/deeplink",
+		"Token":  token,
+		"Items":  items,
+	})
+	if err != nil {
+		glog.Errorf("error rendering deep linking picker: %v", err)
+	}
+}
+
+func (l *LTIServer) launchResource(w http.ResponseWriter, r *http.Request, platform *hfv1.LTIPlatform, launch lti.Launch) {
+	uiURL := ""
+	if set := settingclient.GetSetting(settingclient.LTIUIURL); set != nil {
+		uiURL = set.(string)
+	}
+	if uiURL == "" {
+		util.ReturnHTTPMessage(w, r, 500, "error", "lti is not configured")
+		return
+	}
+
+	user, err := l.provisionUser(platform, launch)
+	if err != nil {
+		glog.Errorf("error provisioning user for lti launch from %s: %v", platform.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error provisioning user")
+		return
+	}
+
+	code := accessCodeFor(platform, launch.Context)
+	if code != "" && !util.StringInSlice(code, user.Spec.AccessCodes) {
+		if err = l.authServer.AddAccessCode(user.Name, code); err != nil {
+			glog.Errorf("error adding access code %s to lti user %s: %v", code, user.Name, err)
+		}
+	}
+
+	if err = l.recordLaunch(platform, launch, user.Name, code); err != nil {
+		// grades can not be sent back, the learner may still work on the content
+		glog.Errorf("error recording lti launch of user %s: %v", user.Name, err)
+	}
+
+	token, err := authserver.GenerateJWT(*user)
+	if err != nil {
+		glog.Errorf("error generating token for lti user %s: %v", user.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error signing in")
+		return
+	}
+
+	// the token is passed in the fragment so that it does not end up in access logs
+	fragment := url.Values{"token": {token}}
+	if code != "" {
+		fragment.Set("accesscode", code)
+	}
+	if scenario := launch.Custom[lti.CustomScenario]; scenario != "" {
+		fragment.Set("scenario", scenario)
+	}
+	if course := launch.Custom[lti.CustomCourse]; course != "" {
+		fragment.Set("course", course)
+	}
+
+	http.Redirect(w, r, strings.TrimSuffix(uiURL, "/")+"/lti#"+fragment.Encode(), http.StatusFound)
+}
+
+// provisionUser returns the user of a launch and creates it on the first launch. Users are tied to the issuer and
+// subject of the launch. Existing accounts are only signed in to by email if the platform is trusted to assert the
+// email of its users, accounts with role bindings are never signed in to through lti.
+func (l *LTIServer) provisionUser(platform *hfv1.LTIPlatform, launch lti.Launch) (*hfv2.User, error) {
+	identity := ltiIdentity(platform, launch)
+	if user, err := l.userByIdentity(identity, platform, launch); err == nil {
+		return user, nil
+	}
+
+	email := launch.Email
+	if email != "" {
+		if user, err := l.userByEmail(email); err == nil {
+			if !platform.Spec.LinkUsersByEmail {
+				// the account is not ours to sign in to, the learner gets an account of their own
+				email = ""
+			} else {
+				bound, err := l.rbac.HasRoleBindings(user.Name)
+				if err != nil {
+					return nil, err
+				}
+				if bound {
+					return nil, fmt.Errorf("user %s has role bindings and can not be linked to platform %s", user.Name, platform.Name)
+				}
+
+				glog.V(2).Infof("linking user %s to lti platform %s", user.Name, platform.Name)
+				return l.linkUser(user.Name, identity, platform, launch)
+			}
+		}
+	}
+	if email == "" {
+		// platforms may withhold the email, the subject is stable per platform
+		email = identity + "@" + platform.Name
+	}
+
+	// the password is never handed out, lti users sign in through their platform
+	password, err := util.RandSecretRunes(32)
+	if err != nil {
+		return nil, err
+	}
+	id, err := l.authServer.NewUser(email, password)
+	if err != nil {
+		return nil, err
+	}
+	glog.V(2).Infof("provisioned lti user %s from platform %s", email, platform.Name)
+
+	return l.linkUser(id, identity, platform, launch)
+}
+
+// linkUser labels a user with the lti identity of a launch so that later launches sign in to it
+func (l *LTIServer) linkUser(id string, identity string, platform *hfv1.LTIPlatform, launch lti.Launch) (*hfv2.User, error) {
+	var user *hfv2.User
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, err := l.hfClientSet.HobbyfarmV2().Users(util.GetReleaseNamespace()).Get(l.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+		if result.Annotations == nil {
+			result.Annotations = map[string]string{}
+		}
+		result.Labels[util.LTIIdentityLabel] = identity
+		result.Annotations[issuerAnnotation] = platform.Spec.Issuer
+		result.Annotations[subjectAnnotation] = launch.Subject
+
+		user, err = l.hfClientSet.HobbyfarmV2().Users(util.GetReleaseNamespace()).Update(l.ctx, result, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error linking user %s to lti platform %s: %v", id, platform.Name, err)
+	}
+
+	return user, nil
+}
+
+func (l *LTIServer) userByIdentity(identity string, platform *hfv1.LTIPlatform, launch lti.Launch) (*hfv2.User, error) {
+	obj, err := l.userIndexer.ByIndex(identityIndex, identity)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving user by lti identity: %v", err)
+	}
+
+	for _, o := range obj {
+		user, ok := o.(*hfv2.User)
+		if !ok {
+			continue
+		}
+		if user.Annotations[issuerAnnotation] == platform.Spec.Issuer && user.Annotations[subjectAnnotation] == launch.Subject {
+			return user.DeepCopy(), nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (l *LTIServer) userByEmail(email string) (*hfv2.User, error) {
+	obj, err := l.userIndexer.ByIndex(emailIndex, email)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving user by email: %v", err)
+	}
+
+	for _, o := range obj {
+		if user, ok := o.(*hfv2.User); ok {
+			return user.DeepCopy(), nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// recordLaunch keeps the line item of a resource link so that the lti controller can send grades to it
+func (l *LTIServer) recordLaunch(platform *hfv1.LTIPlatform, launch lti.Launch, userId string, code string) error {
+	spec := hfv1.LTILaunchSpec{
+		Platform:     platform.Name,
+		UserId:       userId,
+		Subject:      launch.Subject,
+		Context:      launch.Context,
+		ResourceLink: launch.ResourceLink,
+		AccessCode:   code,
+		Scenario:     launch.Custom[lti.CustomScenario],
+		Course:       launch.Custom[lti.CustomCourse],
+		LastLaunch:   time.Now().Format(time.UnixDate),
+	}
+	if launch.ScoreScoped {
+		spec.LineItem = launch.LineItem
+	}
+
+	launches := l.hfClientSet.HobbyfarmV1().LTILaunches(util.GetReleaseNamespace())
+	name := launchName(platform, launch)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := launches.Get(l.ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			record := &hfv1.LTILaunch{}
+			record.Name = name
+			record.Labels = map[string]string{
+				util.UserLabel: userId,
+			}
+			record.Spec = spec
+			_, err = launches.Create(l.ctx, record, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		spec.Nonces = existing.Spec.Nonces
+		existing.Spec = spec
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		existing.Labels[util.UserLabel] = userId
+		_, err = launches.Update(l.ctx, existing, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (l *LTIServer) findPlatform(issuer string, clientId string) (*hfv1.LTIPlatform, error) {
+	platforms, err := l.hfClientSet.HobbyfarmV1().LTIPlatforms(util.GetReleaseNamespace()).List(l.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, platform := range platforms.Items {
+		if platform.Spec.Issuer == issuer && (clientId == "" || platform.Spec.ClientId == clientId) {
+			return &platform, nil
+		}
+	}
+	return nil, fmt.Errorf("no platform registered for issuer %s and client %s", issuer, clientId)
+}
+
+// useNonce records the nonce of an id token on the launch record and returns false if it was used before. The record
+// is shared by all replicas, a concurrent launch with the same id token conflicts on the update and then finds the nonce.
+func (l *LTIServer) useNonce(platform *hfv1.LTIPlatform, launch lti.Launch, nonce string) (bool, error) {
+	launches := l.hfClientSet.HobbyfarmV1().LTILaunches(util.GetReleaseNamespace())
+	name := launchName(platform, launch)
+	now := time.Now()
+	used := false
+
+	retryable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err := retry.OnError(retry.DefaultRetry, retryable, func() error {
+		existing, err := launches.Get(l.ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// the launch is recorded in full once the user is provisioned
+			record := &hfv1.LTILaunch{}
+			record.Name = name
+			record.Spec = hfv1.LTILaunchSpec{
+				Platform:     platform.Name,
+				Subject:      launch.Subject,
+				Context:      launch.Context,
+				ResourceLink: launch.ResourceLink,
+				Nonces:       map[string]string{nonce: now.Format(time.UnixDate)},
+			}
+			_, err = launches.Create(l.ctx, record, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := existing.Spec.Nonces[nonce]; ok {
+			used = true
+			return nil
+		}
+
+		nonces := map[string]string{nonce: now.Format(time.UnixDate)}
+		for n, usedAt := range existing.Spec.Nonces {
+			// id tokens expire, their nonces do not have to be kept for longer than a login takes
+			if t, err := time.Parse(time.UnixDate, usedAt); err == nil && now.Sub(t) <= nonceTTL {
+				nonces[n] = usedAt
+			}
+		}
+		existing.Spec.Nonces = nonces
+
+		_, err = launches.Update(l.ctx, existing, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return !used, nil
+}
+
+// contentIds returns the courses or scenarios the access code of a context grants, only these can be deep linked
+func (l *LTIServer) contentIds(platform *hfv1.LTIPlatform, context string, kind string) []string {
+	code := accessCodeFor(platform, context)
+	if code == "" {
+		return nil
+	}
+
+	var ids []string
+	var err error
+	if kind == lti.CustomCourse {
+		ids, err = l.accessCodeClient.GetCourseIds(code)
+	} else {
+		ids, err = l.accessCodeClient.GetScenarioIds(code)
+	}
+	if err != nil {
+		glog.Errorf("error retrieving content of access code %s: %v", code, err)
+	}
+	return ids
+}
+
+// launchName returns the name of the record of the launches of a user from a resource link
+func launchName(platform *hfv1.LTIPlatform, launch lti.Launch) string {
+	return util.GenerateResourceName("lti", platform.Name+"/"+launch.ResourceLink+"/"+launch.Subject, 16)
+}
+
+// ltiIdentity returns the value of the identity label of the user a platform asserts with the subject of a launch
+func ltiIdentity(platform *hfv1.LTIPlatform, launch lti.Launch) string {
+	return util.GenerateResourceName("lti", platform.Spec.Issuer+"/"+launch.Subject, 16)
+}
+
+func userIdentityIndexer(obj interface{}) ([]string, error) {
+	user, ok := obj.(*hfv2.User)
+	if !ok {
+		return []string{}, nil
+	}
+	if identity, ok := user.Labels[util.LTIIdentityLabel]; ok {
+		return []string{identity}, nil
+	}
+	return []string{}, nil
+}
+
+func userEmailIndexer(obj interface{}) ([]string, error) {
+	user, ok := obj.(*hfv2.User)
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{user.Spec.Email}, nil
+}
+
+func accessCodeFor(platform *hfv1.LTIPlatform, context string) string {
+	if code, ok := platform.Spec.AccessCodes[context]; ok {
+		return strings.ToLower(code)
+	}
+	return strings.ToLower(platform.Spec.DefaultAccessCode)
+}
+
+func decode(name string) string {
+	decoded, err := base64.StdEncoding.DecodeString(name)
+	if err != nil {
+		return name
+	}
+	return string(decoded)
+}
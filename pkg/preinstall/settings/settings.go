@@ -144,5 +144,65 @@ func settings() []*v1.Setting {
 				DisplayName: "ScheduledEvent retention time (h)",
 			},
 		},
+		{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      string(settingclient.VMClaimQueueTimeout),
+				Namespace: util.GetReleaseNamespace(),
+				Labels: map[string]string{
+					labels.SettingScope: "gargantua",
+				},
+			},
+			Value: "30",
+			Property: property.Property{
+				DataType:    property.DataTypeInteger,
+				ValueType:   property.ValueTypeScalar,
+				DisplayName: "Time a session may wait for free capacity (m)",
+			},
+		},
+		{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      string(settingclient.XAPIEndpoint),
+				Namespace: util.GetReleaseNamespace(),
+				Labels: map[string]string{
+					labels.SettingScope: "gargantua",
+				},
+			},
+			Value: "",
+			Property: property.Property{
+				DataType:    property.DataTypeString,
+				ValueType:   property.ValueTypeScalar,
+				DisplayName: "xAPI learning record store endpoint (empty disables xAPI)",
+			},
+		},
+		{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      string(settingclient.XAPIActivityBase),
+				Namespace: util.GetReleaseNamespace(),
+				Labels: map[string]string{
+					labels.SettingScope: "gargantua",
+				},
+			},
+			Value: "https://hobbyfarm.io/xapi",
+			Property: property.Property{
+				DataType:    property.DataTypeString,
+				ValueType:   property.ValueTypeScalar,
+				DisplayName: "xAPI activity id prefix",
+			},
+		},
+		{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      string(settingclient.LTIUIURL),
+				Namespace: util.GetReleaseNamespace(),
+				Labels: map[string]string{
+					labels.SettingScope: "gargantua",
+				},
+			},
+			Value: "",
+			Property: property.Property{
+				DataType:    property.DataTypeString,
+				ValueType:   property.ValueTypeScalar,
+				DisplayName: "URL of the ui LTI launches are redirected to",
+			},
+		},
 	}
 }
@@ -0,0 +1,100 @@
+package prewarm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+const DefaultBatchInterval = time.Minute
+
+// Schedule is the effective pre-warming of a scheduled event in one environment
+type Schedule struct {
+	LeadTime      time.Duration
+	BatchSize     int // 0 requests all virtual machines at once
+	BatchInterval time.Duration
+}
+
+// Validate checks a policy before it is stored on a scheduled event or environment
+func Validate(policy hfv1.PrewarmPolicy) error {
+	_, err := NewSchedule(policy)
+	return err
+}
+
+func NewSchedule(policy hfv1.PrewarmPolicy) (Schedule, error) {
+	s := Schedule{
+		BatchSize:     policy.BatchSize,
+		BatchInterval: DefaultBatchInterval,
+	}
+	if s.BatchSize < 0 {
+		return Schedule{}, fmt.Errorf("batch_size may not be negative")
+	}
+
+	if policy.LeadTime != "" {
+		lead, err := time.ParseDuration(policy.LeadTime)
+		if err != nil || lead < 0 {
+			return Schedule{}, fmt.Errorf("invalid lead_time %s", policy.LeadTime)
+		}
+		s.LeadTime = lead
+	}
+	if policy.BatchInterval != "" {
+		interval, err := time.ParseDuration(policy.BatchInterval)
+		if err != nil || interval <= 0 {
+			return Schedule{}, fmt.Errorf("invalid batch_interval %s", policy.BatchInterval)
+		}
+		s.BatchInterval = interval
+	}
+
+	return s, nil
+}
+
+// Resolve returns the schedule of a scheduled event in an environment. The policy of the event wins over the one of
+// the environment, without either the virtual machines are requested all at once when the event starts.
+func Resolve(event *hfv1.PrewarmPolicy, environment *hfv1.PrewarmPolicy) (Schedule, error) {
+	switch {
+	case event != nil:
+		return NewSchedule(*event)
+	case environment != nil:
+		return NewSchedule(*environment)
+	default:
+		return NewSchedule(hfv1.PrewarmPolicy{})
+	}
+}
+
+// ProvisionTime returns when provisioning of an event starting at start begins, the longest lead time of all
+// environments counts since the event is provisioned as a whole
+func ProvisionTime(start time.Time, schedules map[string]Schedule) time.Time {
+	var lead time.Duration
+	for _, s := range schedules {
+		lead = max(lead, s.LeadTime)
+	}
+	return start.Add(-lead)
+}
+
+// Batch returns the counts the virtual machine sets of an environment grow to with the next batch. The batch size
+// is shared by all templates of the environment, counts above their target shrink right away.
+func (s Schedule) Batch(current map[string]int, target map[string]int) map[string]int {
+	templates := make([]string, 0, len(target))
+	for template := range target {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	budget := s.BatchSize
+	next := map[string]int{}
+	for _, template := range templates {
+		want := target[template]
+		have := min(current[template], want)
+		if s.BatchSize == 0 {
+			next[template] = want
+			continue
+		}
+		added := min(want-have, budget)
+		budget -= added
+		next[template] = have + added
+	}
+
+	return next
+}
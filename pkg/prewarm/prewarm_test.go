@@ -0,0 +1,155 @@
+package prewarm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+func Test_NewSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  hfv1.PrewarmPolicy
+		want    Schedule
+		wantErr bool
+	}{
+		{"defaults", hfv1.PrewarmPolicy{}, Schedule{BatchInterval: DefaultBatchInterval}, false},
+		{"all set", hfv1.PrewarmPolicy{LeadTime: "1h", BatchSize: 5, BatchInterval: "2m"}, Schedule{LeadTime: time.Hour, BatchSize: 5, BatchInterval: 2 * time.Minute}, false},
+		{"negative batch size", hfv1.PrewarmPolicy{BatchSize: -1}, Schedule{}, true},
+		{"invalid lead time", hfv1.PrewarmPolicy{LeadTime: "soon"}, Schedule{}, true},
+		{"negative lead time", hfv1.PrewarmPolicy{LeadTime: "-1h"}, Schedule{}, true},
+		{"zero batch interval", hfv1.PrewarmPolicy{BatchInterval: "0s"}, Schedule{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSchedule(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewSchedule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	event := &hfv1.PrewarmPolicy{LeadTime: "30m"}
+	environment := &hfv1.PrewarmPolicy{LeadTime: "2h", BatchSize: 10}
+
+	tests := []struct {
+		name        string
+		event       *hfv1.PrewarmPolicy
+		environment *hfv1.PrewarmPolicy
+		want        Schedule
+	}{
+		{"event wins", event, environment, Schedule{LeadTime: 30 * time.Minute, BatchInterval: DefaultBatchInterval}},
+		{"environment default", nil, environment, Schedule{LeadTime: 2 * time.Hour, BatchSize: 10, BatchInterval: DefaultBatchInterval}},
+		{"neither", nil, nil, Schedule{BatchInterval: DefaultBatchInterval}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.event, tt.environment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ProvisionTime(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		schedules map[string]Schedule
+		want      time.Time
+	}{
+		{"no environments", nil, start},
+		{"no lead time", map[string]Schedule{"env-a": {}}, start},
+		{"longest lead time counts", map[string]Schedule{"env-a": {LeadTime: 30 * time.Minute}, "env-b": {LeadTime: 2 * time.Hour}, "env-c": {}}, start.Add(-2 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProvisionTime(start, tt.schedules); !got.Equal(tt.want) {
+				t.Errorf("ProvisionTime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Batch(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		current  map[string]int
+		target   map[string]int
+		want     map[string]int
+	}{
+		{
+			name:     "batch size 0 requests everything at once",
+			schedule: Schedule{},
+			current:  map[string]int{},
+			target:   map[string]int{"ubuntu": 10, "centos": 3},
+			want:     map[string]int{"ubuntu": 10, "centos": 3},
+		},
+		{
+			name:     "first batch",
+			schedule: Schedule{BatchSize: 4},
+			current:  map[string]int{},
+			target:   map[string]int{"ubuntu": 10},
+			want:     map[string]int{"ubuntu": 4},
+		},
+		{
+			name:     "later batch grows from the current count",
+			schedule: Schedule{BatchSize: 4},
+			current:  map[string]int{"ubuntu": 8},
+			target:   map[string]int{"ubuntu": 10},
+			want:     map[string]int{"ubuntu": 10},
+		},
+		{
+			name:     "budget is shared by templates in sorted order",
+			schedule: Schedule{BatchSize: 5},
+			current:  map[string]int{},
+			target:   map[string]int{"ubuntu": 4, "centos": 3},
+			want:     map[string]int{"centos": 3, "ubuntu": 2},
+		},
+		{
+			name:     "finished templates leave the budget to others",
+			schedule: Schedule{BatchSize: 3},
+			current:  map[string]int{"centos": 3, "ubuntu": 1},
+			target:   map[string]int{"ubuntu": 4, "centos": 3},
+			want:     map[string]int{"centos": 3, "ubuntu": 4},
+		},
+		{
+			name:     "counts above the target shrink right away",
+			schedule: Schedule{BatchSize: 2},
+			current:  map[string]int{"ubuntu": 8, "centos": 0},
+			target:   map[string]int{"ubuntu": 5, "centos": 4},
+			want:     map[string]int{"centos": 2, "ubuntu": 5},
+		},
+		{
+			name:     "templates without a target are dropped",
+			schedule: Schedule{BatchSize: 2},
+			current:  map[string]int{"removed": 3},
+			target:   map[string]int{"ubuntu": 1},
+			want:     map[string]int{"ubuntu": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Batch(tt.current, tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Batch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
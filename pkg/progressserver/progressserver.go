@@ -1,21 +1,37 @@
 package progressserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
+	"github.com/hobbyfarm/gargantua/v3/pkg/analytics"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	hfListersV2 "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/quiz"
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/report"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/stepcheck"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"github.com/hobbyfarm/gargantua/v3/pkg/xapi"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
 )
 
@@ -24,10 +40,15 @@ const (
 	resourcePlural = "progresses"
 )
 
+var errNoActiveProgress = fmt.Errorf("no active progress for this session found")
+
 type ProgressServer struct {
-	auth        *authclient.AuthClient
-	hfClientSet hfClientset.Interface
-	ctx         context.Context
+	auth           *authclient.AuthClient
+	hfClientSet    hfClientset.Interface
+	ctx            context.Context
+	checkRunner    *stepcheck.Runner
+	progressLister hfListersV2.ProgressLister
+	courseLister   hfListers.CourseLister
 }
 
 type AdminPreparedProgress struct {
@@ -43,25 +64,84 @@ type AdminPreparedProgressWithScheduledEvent struct {
 	ScheduledEvent string `json:"scheduled_event"`
 }
 
+type PreparedCheckResults struct {
+	Step    int                    `json:"step"`
+	Passed  bool                   `json:"passed"`
+	Results []hfv1.StepCheckResult `json:"results"`
+}
+
+type PreparedAnswerResult struct {
+	Step         int  `json:"step"`
+	Question     int  `json:"question"`
+	Correct      bool `json:"correct"`
+	Points       int  `json:"points"`
+	Attempts     int  `json:"attempts"`
+	AttemptsLeft bool `json:"attempts_left"`
+	Score        int  `json:"score"`
+	MaxScore     int  `json:"max_score"`
+}
+
+type PreparedScore struct {
+	ID       string `json:"id"`
+	Session  string `json:"session"`
+	User     string `json:"user"`
+	Scenario string `json:"scenario"`
+	Course   string `json:"course"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+	Answered int    `json:"answered"`
+	Correct  int    `json:"correct"`
+	Finished bool   `json:"finished"`
+}
+
+type PreparedUserScore struct {
+	User     string `json:"user"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+}
+
+type ScheduledEventScores struct {
+	ScheduledEvent string              `json:"scheduled_event"`
+	Scores         []PreparedScore     `json:"scores"`
+	Users          []PreparedUserScore `json:"users"`
+}
+
+type PreparedAnalytics struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	analytics.Report
+	CourseCompleted int `json:"course_completed,omitempty"` // learners that completed every scenario of the course
+}
+
 type ScheduledEventProgressCount struct {
 	CountMap map[string]int `json:"count_map"`
 }
 
-func NewProgressServer(authClient *authclient.AuthClient, hfClientset hfClientset.Interface, ctx context.Context) (*ProgressServer, error) {
+func NewProgressServer(authClient *authclient.AuthClient, hfClientset hfClientset.Interface, kubeClient kubernetes.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*ProgressServer, error) {
 	progress := ProgressServer{}
 
 	progress.hfClientSet = hfClientset
 	progress.auth = authClient
 	progress.ctx = ctx
+	progress.checkRunner = stepcheck.NewRunner(kubeClient, hfClientset, ctx)
+	progress.progressLister = hfInformerFactory.Hobbyfarm().V2().Progresses().Lister()
+	progress.courseLister = hfInformerFactory.Hobbyfarm().V1().Courses().Lister()
 	return &progress, nil
 }
 
 func (s ProgressServer) SetupRoutes(r *mux.Router) {
 	r.HandleFunc("/a/progress/scheduledevent/{id}", s.ListByScheduledEventFunc).Methods("GET")
+	r.HandleFunc("/a/progress/scheduledevent/{id}/scores", s.ScoresByScheduledEventFunc).Methods("GET")
+	r.HandleFunc("/a/progress/scheduledevent/{id}/export", s.ExportByScheduledEventFunc).Methods("GET")
+	r.HandleFunc("/a/progress/analytics/scheduledevent/{id}", s.AnalyticsByScheduledEventFunc).Methods("GET")
+	r.HandleFunc("/a/progress/analytics/course/{id}", s.AnalyticsByCourseFunc).Methods("GET")
+	r.HandleFunc("/a/progress/analytics/scenario/{id}", s.AnalyticsByScenarioFunc).Methods("GET")
 	r.HandleFunc("/a/progress/user/{id}", s.ListByUserFunc).Methods("GET")
 	r.HandleFunc("/a/progress/count", s.CountByScheduledEvent).Methods("GET")
 	r.HandleFunc("/a/progress/range", s.ListByRangeFunc).Methods("GET")
 	r.HandleFunc("/progress/update/{id}", s.Update).Methods("POST")
+	r.HandleFunc("/progress/check/{id}/{step:[0-9]+}", s.CheckStepFunc).Methods("POST")
+	r.HandleFunc("/progress/answer/{id}/{step:[0-9]+}/{question:[0-9]+}", s.AnswerFunc).Methods("POST")
 	r.HandleFunc("/progress/list", s.ListForUserFunc).Methods("GET")
 	glog.V(2).Infof("set up routes for ProgressServer")
 }
@@ -99,6 +179,85 @@ func (s ProgressServer) ListByScheduledEventFunc(w http.ResponseWriter, r *http.
 	glog.V(2).Infof("listed progress for scheduledevent %s", id)
 }
 
+/*
+Scores of all progress of a Scheduled Event, finished progress included
+
+	Vars:
+	- id : The scheduled event id
+*/
+func (s ProgressServer) ScoresByScheduledEventFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list progress")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	id := vars["id"]
+
+	if len(id) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no id passed in")
+		return
+	}
+
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, id)})
+
+	if err != nil {
+		glog.Errorf("error while retrieving progress %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "no progress found")
+		return
+	}
+
+	scores := ScheduledEventScores{
+		ScheduledEvent: id,
+		Scores:         []PreparedScore{},
+		Users:          []PreparedUserScore{},
+	}
+	users := map[string]*PreparedUserScore{}
+	for _, p := range progress.Items {
+		score := PreparedScore{
+			ID:       p.Name,
+			Session:  p.Labels[util.SessionLabel],
+			User:     p.Spec.UserId,
+			Scenario: p.Spec.Scenario,
+			Course:   p.Spec.Course,
+			Score:    p.Spec.Score,
+			MaxScore: p.Spec.MaxScore,
+			Answered: len(p.Spec.QuestionResults),
+			Finished: p.Spec.Finished,
+		}
+		for _, result := range p.Spec.QuestionResults {
+			if result.Correct {
+				score.Correct++
+			}
+		}
+		scores.Scores = append(scores.Scores, score)
+
+		if _, ok := users[p.Spec.UserId]; !ok {
+			users[p.Spec.UserId] = &PreparedUserScore{User: p.Spec.UserId}
+		}
+		users[p.Spec.UserId].Score += p.Spec.Score
+		users[p.Spec.UserId].MaxScore += p.Spec.MaxScore
+	}
+
+	for _, u := range users {
+		scores.Users = append(scores.Users, *u)
+	}
+	sort.Slice(scores.Users, func(i, j int) bool {
+		return scores.Users[i].User < scores.Users[j].User
+	})
+
+	encodedScores, err := json.Marshal(scores)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedScores)
+
+	glog.V(2).Infof("listed scores for scheduledevent %s", id)
+}
+
 func (s ProgressServer) ListByRangeFunc(w http.ResponseWriter, r *http.Request) {
 	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
 	if err != nil {
@@ -137,6 +296,199 @@ func (s ProgressServer) ListByRangeFunc(w http.ResponseWriter, r *http.Request)
 	glog.V(2).Info("listed progress for time range")
 }
 
+/*
+Export the attendance of a Scheduled Event, one row per user and scenario
+
+	Vars:
+	- id : The scheduled event id
+	Query:
+	- format : csv (default) or xlsx
+	- columns : Comma separated columns, optional
+	- date_format : unixdate (default), rfc3339, datetime, date or a go time layout
+*/
+func (s ProgressServer) ExportByScheduledEventFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list progress")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.FormatCSV
+	}
+	if format != report.FormatCSV && format != report.FormatXLSX {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", fmt.Sprintf("unknown format %s", format))
+		return
+	}
+
+	columns := []string{}
+	if rawColumns := r.URL.Query().Get("columns"); rawColumns != "" {
+		columns = strings.Split(rawColumns, ",")
+	}
+
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, id)})
+	if err != nil {
+		glog.Errorf("error while retrieving progress %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "no progress found")
+		return
+	}
+
+	emails := map[string]string{}
+	scenarioNames := map[string]string{}
+	for _, p := range progress.Items {
+		if _, ok := emails[p.Spec.UserId]; !ok {
+			emails[p.Spec.UserId] = ""
+			user, err := s.hfClientSet.HobbyfarmV2().Users(util.GetReleaseNamespace()).Get(s.ctx, p.Spec.UserId, metav1.GetOptions{})
+			if err != nil {
+				glog.Errorf("error retrieving user %s for export: %v", p.Spec.UserId, err)
+			} else {
+				emails[p.Spec.UserId] = user.Spec.Email
+			}
+		}
+		if _, ok := scenarioNames[p.Spec.Scenario]; !ok {
+			scenarioNames[p.Spec.Scenario] = p.Spec.Scenario
+			scenario, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(s.ctx, p.Spec.Scenario, metav1.GetOptions{})
+			if err == nil {
+				scenarioNames[p.Spec.Scenario] = scenario.Spec.Name
+			}
+		}
+	}
+
+	table, err := report.NewTable(report.Rows(progress.Items, emails, scenarioNames), columns, r.URL.Query().Get("date_format"))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv"
+	if format == report.FormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = table.WriteXLSX(&buf)
+	} else {
+		err = table.WriteCSV(&buf)
+	}
+	if err != nil {
+		glog.Errorf("error writing export of scheduledevent %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error exporting progress")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"attendance-%s.%s\"", id, format))
+	util.ReturnHTTPRaw(w, r, buf.String())
+
+	glog.V(2).Infof("exported progress for scheduledevent %s", id)
+}
+
+/*
+Analytics of the progress of a Scheduled Event, Course or Scenario
+
+	Vars:
+	- id : The scheduled event, course or scenario id
+	Query:
+	- from, to : Only progress started within this range, optional
+	- idle : Duration after which learners without progress count as idle, optional
+*/
+func (s ProgressServer) AnalyticsByScheduledEventFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	s.analytics(w, r, "scheduledevent", id, labels.SelectorFromSet(labels.Set{util.ScheduledEventLabel: id}), nil)
+}
+
+func (s ProgressServer) AnalyticsByCourseFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	s.analytics(w, r, "course", id, labels.Everything(), func(p *hfv2.Progress) bool {
+		return p.Spec.Course == id
+	})
+}
+
+func (s ProgressServer) AnalyticsByScenarioFunc(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	s.analytics(w, r, "scenario", id, labels.Everything(), func(p *hfv2.Progress) bool {
+		return p.Spec.Scenario == id
+	})
+}
+
+// analytics aggregates the progress from the informer cache, so that dashboards polling it do not list from the api server
+func (s ProgressServer) analytics(w http.ResponseWriter, r *http.Request, kind string, id string, selector labels.Selector, match func(p *hfv2.Progress) bool) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list progress")
+		return
+	}
+
+	filter := analytics.Filter{}
+	if from := r.URL.Query().Get("from"); from != "" {
+		filter.From, err = time.Parse(time.UnixDate, from)
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "error parsing start time")
+			return
+		}
+	}
+	if to := r.URL.Query().Get("to"); to != "" {
+		filter.To, err = time.Parse(time.UnixDate, to)
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "error parsing end time")
+			return
+		}
+	}
+
+	idleAfter := analytics.DefaultIdleAfter
+	if idle := r.URL.Query().Get("idle"); idle != "" {
+		idleAfter, err = time.ParseDuration(idle)
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "error parsing idle duration")
+			return
+		}
+	}
+
+	cached, err := s.progressLister.Progresses(util.GetReleaseNamespace()).List(selector)
+	if err != nil {
+		glog.Errorf("error while retrieving progress %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "no progress found")
+		return
+	}
+
+	progress := []*hfv2.Progress{}
+	for _, p := range cached {
+		if (match == nil || match(p)) && filter.Matches(p) {
+			progress = append(progress, p)
+		}
+	}
+
+	prepared := PreparedAnalytics{
+		Kind:   kind,
+		ID:     id,
+		Report: analytics.Compute(progress, time.Now(), idleAfter),
+	}
+
+	if kind == "course" {
+		if course, err := s.courseLister.Courses(util.GetReleaseNamespace()).Get(id); err == nil {
+			byUser := map[string][]hfv2.Progress{}
+			for _, p := range progress {
+				byUser[p.Spec.UserId] = append(byUser[p.Spec.UserId], *p)
+			}
+			for _, userProgress := range byUser {
+				if completion.CourseCompleted(course.Spec, completion.CompletedScenarios(userProgress, id)) {
+					prepared.CourseCompleted++
+				}
+			}
+		}
+	}
+
+	encodedAnalytics, err := json.Marshal(prepared)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedAnalytics)
+
+	glog.V(4).Infof("computed progress analytics for %s %s", kind, id)
+}
+
 /*
 List Progress for the authenticated user
 */
@@ -184,7 +536,7 @@ func (s ProgressServer) CountByScheduledEvent(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	progress, err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", "finished", "false")})
 
 	if err != nil {
@@ -214,7 +566,7 @@ func (s ProgressServer) ListByRange(w http.ResponseWriter, r *http.Request, star
 	if includeFinished {
 		includeFinishedFilter = ""
 	}
-	progress, err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s", includeFinishedFilter)})
 
 	if err != nil {
@@ -232,7 +584,7 @@ func (s ProgressServer) ListByRange(w http.ResponseWriter, r *http.Request, star
 		if p.CreationTimestamp.Before(&v1TimeStart) || v1TimeEnd.Before(&p.CreationTimestamp) {
 			continue
 		}
-		pProgressWithScenarioName := AdminPreparedProgressWithScheduledEvent{p.Name, p.Labels[util.SessionLabel], p.Spec, p.Labels[util.ScheduledEventLabel]}
+		pProgressWithScenarioName := AdminPreparedProgressWithScheduledEvent{p.Name, p.Labels[util.SessionLabel], hfv2.ConvertProgressSpecToV1(p.Spec), p.Labels[util.ScheduledEventLabel]}
 		preparedProgress = append(preparedProgress, pProgressWithScenarioName)
 	}
 
@@ -248,7 +600,7 @@ func (s ProgressServer) ListByLabel(w http.ResponseWriter, r *http.Request, labe
 	if includeFinished {
 		includeFinishedFilter = ""
 	}
-	progress, err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s%s", label, value, includeFinishedFilter)})
 
 	if err != nil {
@@ -259,7 +611,7 @@ func (s ProgressServer) ListByLabel(w http.ResponseWriter, r *http.Request, labe
 
 	preparedProgress := []AdminPreparedProgress{}
 	for _, p := range progress.Items {
-		pProgress := AdminPreparedProgress{p.Name, p.Labels[util.SessionLabel], p.Spec}
+		pProgress := AdminPreparedProgress{p.Name, p.Labels[util.SessionLabel], hfv2.ConvertProgressSpecToV1(p.Spec)}
 		preparedProgress = append(preparedProgress, pProgress)
 	}
 
@@ -307,7 +659,7 @@ func (s ProgressServer) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	progress, err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+	progress, err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s,%s=%s,finished=false", util.SessionLabel, id, util.UserLabel, user.Name)})
 
 	if err != nil {
@@ -321,20 +673,26 @@ func (s ProgressServer) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// gated steps have to be verified before moving past them
+	if blocked, ok := s.blockingStep(progress.Items[0], step); ok {
+		util.ReturnHTTPMessage(w, r, 409, "notverified", fmt.Sprintf("step %d has to be checked before moving on", blocked))
+		return
+	}
+
 	for _, p := range progress.Items {
+		wasCompleted := completion.ScenarioCompleted(p.Spec)
+		var updated *hfv2.Progress
 		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			if step > p.Spec.MaxStep {
-				p.Spec.MaxStep = step
+			latest, getErr := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Get(s.ctx, p.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
 			}
-			p.Spec.CurrentStep = step
-			p.Spec.LastUpdate = now.Format(time.UnixDate)
 
-			steps := p.Spec.Steps
-			newStep := hfv1.ProgressStep{Step: step, Timestamp: now.Format(time.UnixDate)}
-			steps = append(steps, newStep)
-			p.Spec.Steps = steps
+			// the step history is compacted, revisiting a step adds to its visits and time spent
+			latest.Spec.Visit(step, now)
 
-			_, updateErr := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).Update(s.ctx, &p, metav1.UpdateOptions{})
+			var updateErr error
+			updated, updateErr = s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(s.ctx, latest, metav1.UpdateOptions{})
 			glog.V(4).Infof("updated result for environment")
 
 			return updateErr
@@ -345,7 +703,295 @@ func (s ProgressServer) Update(w http.ResponseWriter, r *http.Request) {
 			util.ReturnHTTPMessage(w, r, 500, "error", "progress could not be updated")
 			return
 		}
+
+		xapi.Record(s.ctx, s.hfClientSet, xapi.VerbProgressed, updated)
+		if !wasCompleted && completion.ScenarioCompleted(updated.Spec) {
+			xapi.Record(s.ctx, s.hfClientSet, xapi.VerbCompleted, updated)
+		}
 	}
 
 	util.ReturnHTTPMessage(w, r, 200, "success", "Progress was updated")
 }
+
+// blockingStep returns the first gated step before the requested one whose checks have not passed yet.
+// Going back to steps that were already visited is always allowed.
+func (s ProgressServer) blockingStep(p hfv2.Progress, step int) (int, bool) {
+	if step <= p.Spec.MaxStep || p.Spec.Scenario == "" {
+		return 0, false
+	}
+
+	scenario, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(s.ctx, p.Spec.Scenario, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error retrieving scenario %s for progress %s: %v", p.Spec.Scenario, p.Name, err)
+		return 0, false
+	}
+
+	steps := scenario.Spec.Steps
+	session, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, p.Labels[util.SessionLabel], metav1.GetOptions{})
+	if err == nil {
+		spec, err := revision.ScenarioSpec(s.ctx, s.hfClientSet, *scenario, session.Spec.ScenarioRevision)
+		if err != nil {
+			glog.Errorf("error retrieving scenario revision for progress %s: %v", p.Name, err)
+		} else {
+			steps = spec.Steps
+		}
+	}
+
+	for i := 0; i < step && i < len(steps); i++ {
+		scenarioStep := steps[i]
+		if scenarioStep.Gated && len(scenarioStep.Checks) > 0 && !stepcheck.Passed(p.Spec.CheckResults, i, len(scenarioStep.Checks)) {
+			return i, true
+		}
+		if scenarioStep.Gated && !quiz.Answered(p.Spec.QuestionResults, i, scenarioStep.Questions) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+/*
+Check a step
+
+	Vars:
+	- id : Session linked to the progress resource
+	- step : Scenario step to run the checks of
+*/
+func (s ProgressServer) CheckStepFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to check steps")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	id := vars["id"]
+	if len(id) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no id passed in")
+		return
+	}
+
+	step, err := strconv.Atoi(vars["step"])
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "provided step was invalid")
+		return
+	}
+
+	session, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	if err != nil || !util.IsSessionMember(session, user.Name) {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no session found")
+		return
+	}
+
+	if session.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "session was finished")
+		return
+	}
+
+	scenario, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(s.ctx, session.Spec.ScenarioId, metav1.GetOptions{})
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no scenario found")
+		return
+	}
+
+	// sessions keep the revision they were started with
+	spec, err := revision.ScenarioSpec(s.ctx, s.hfClientSet, *scenario, session.Spec.ScenarioRevision)
+	if err != nil {
+		glog.Errorf("error retrieving scenario revision for session %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving scenario")
+		return
+	}
+
+	if step < 0 || step >= len(spec.Steps) || len(spec.Steps[step].Checks) == 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "step has no checks")
+		return
+	}
+
+	checks := spec.Steps[step].Checks
+	results := s.checkRunner.RunStep(session, step, checks)
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		progress, listErr := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s=%s,finished=false", util.SessionLabel, id, util.UserLabel, user.Name)})
+		if listErr != nil {
+			return listErr
+		}
+
+		for _, p := range progress.Items {
+			p.Spec.CheckResults = stepcheck.Merge(p.Spec.CheckResults, step, results)
+			p.Spec.LastUpdate = metav1.Now()
+
+			_, updateErr := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(s.ctx, &p, metav1.UpdateOptions{})
+			if updateErr != nil {
+				return updateErr
+			}
+		}
+
+		return nil
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error recording check results for session %s: %v", id, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "check results could not be recorded")
+		return
+	}
+
+	prepared := PreparedCheckResults{
+		Step:    step,
+		Passed:  stepcheck.Passed(results, step, len(checks)),
+		Results: results,
+	}
+
+	encodedResults, err := json.Marshal(prepared)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedResults)
+}
+
+/*
+Answer a question, the answer is graded by the server
+
+	Vars:
+	- id : Session linked to the progress resource
+	- step : Scenario step the question belongs to
+	- question : Index of the question in the step
+
+	Form values:
+	- answer : JSON array, the indices of the chosen choices or a single free text answer
+*/
+func (s ProgressServer) AnswerFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to answer questions")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	id := vars["id"]
+	if len(id) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no id passed in")
+		return
+	}
+
+	step, err := strconv.Atoi(vars["step"])
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "provided step was invalid")
+		return
+	}
+
+	index, err := strconv.Atoi(vars["question"])
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "provided question was invalid")
+		return
+	}
+
+	answer := []string{}
+	if err = json.Unmarshal([]byte(r.PostFormValue("answer")), &answer); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "answer has to be a list")
+		return
+	}
+
+	session, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	if err != nil || !util.IsSessionMember(session, user.Name) {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no session found")
+		return
+	}
+
+	if session.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "session was finished")
+		return
+	}
+
+	scenario, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(s.ctx, session.Spec.ScenarioId, metav1.GetOptions{})
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no scenario found")
+		return
+	}
+
+	// sessions keep the revision they were started with
+	spec, err := revision.ScenarioSpec(s.ctx, s.hfClientSet, *scenario, session.Spec.ScenarioRevision)
+	if err != nil {
+		glog.Errorf("error retrieving scenario revision for session %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving scenario")
+		return
+	}
+
+	if step < 0 || step >= len(spec.Steps) || index < 0 || index >= len(spec.Steps[step].Questions) {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "question not found")
+		return
+	}
+
+	question := spec.Steps[step].Questions[index]
+	prepared := PreparedAnswerResult{Step: step, Question: index}
+	var gradeErr error
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		progress, listErr := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s=%s,finished=false", util.SessionLabel, id, util.UserLabel, user.Name)})
+		if listErr != nil {
+			return listErr
+		}
+
+		if len(progress.Items) < 1 {
+			gradeErr = errNoActiveProgress
+			return nil
+		}
+
+		p := progress.Items[0]
+		results, result, err := quiz.Answer(p.Spec.QuestionResults, step, index, question, answer)
+		gradeErr = err
+		if err != nil {
+			return nil
+		}
+
+		p.Spec.QuestionResults = results
+		p.Spec.Score = quiz.Score(results)
+		p.Spec.MaxScore = quiz.MaxScore(spec.Steps)
+		p.Spec.LastUpdate = metav1.Now()
+
+		_, updateErr := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(s.ctx, &p, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		prepared.Correct = result.Correct
+		prepared.Points = result.Points
+		prepared.Attempts = result.Attempts
+		prepared.AttemptsLeft = quiz.AttemptsLeft(question, result)
+		prepared.Score = p.Spec.Score
+		prepared.MaxScore = p.Spec.MaxScore
+		return nil
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error recording answer for session %s: %v", id, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "answer could not be recorded")
+		return
+	}
+
+	switch gradeErr {
+	case nil:
+	case quiz.ErrInvalidAnswer:
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", gradeErr.Error())
+		return
+	case quiz.ErrAlreadyCorrect, quiz.ErrNoAttemptsLeft:
+		util.ReturnHTTPMessage(w, r, 409, "answered", gradeErr.Error())
+		return
+	case errNoActiveProgress:
+		util.ReturnHTTPMessage(w, r, 404, "notfound", gradeErr.Error())
+		return
+	default:
+		glog.Errorf("error grading step %d question %d for session %s: %v", step, index, id, gradeErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "answer could not be graded")
+		return
+	}
+
+	encodedResult, err := json.Marshal(prepared)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedResult)
+}
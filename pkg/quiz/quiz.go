@@ -0,0 +1,240 @@
+package quiz
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+const (
+	maxAnswerLength = 1024
+)
+
+var (
+	ErrAlreadyCorrect = fmt.Errorf("question was already answered correctly")
+	ErrNoAttemptsLeft = fmt.Errorf("no attempts left for this question")
+	ErrInvalidAnswer  = fmt.Errorf("invalid answer")
+)
+
+// PreparedQuestion is what learners see of a question, it never contains the correct answers
+type PreparedQuestion struct {
+	Name        string            `json:"name,omitempty"`
+	Type        hfv1.QuestionType `json:"type"`
+	Text        string            `json:"text"`
+	Choices     []string          `json:"choices,omitempty"`
+	Points      int               `json:"points"`
+	MaxAttempts int               `json:"max_attempts"`
+}
+
+// Prepare strips the correct answers of the questions of a step
+func Prepare(questions []hfv1.StepQuestion) []PreparedQuestion {
+	prepared := []PreparedQuestion{}
+	for _, q := range questions {
+		prepared = append(prepared, PreparedQuestion{
+			Name:        q.Name,
+			Type:        q.Type,
+			Text:        q.Text,
+			Choices:     q.Choices,
+			Points:      q.Points,
+			MaxAttempts: q.MaxAttempts,
+		})
+	}
+	return prepared
+}
+
+// Validate checks that a question can be answered and graded
+func Validate(question hfv1.StepQuestion) error {
+	if strings.TrimSpace(question.Text) == "" {
+		return fmt.Errorf("question has no text")
+	}
+	if question.Points < 0 {
+		return fmt.Errorf("points must not be negative")
+	}
+	if question.MaxAttempts < 0 {
+		return fmt.Errorf("max_attempts must not be negative")
+	}
+
+	switch question.Type {
+	case hfv1.QuestionTypeSingleChoice, hfv1.QuestionTypeMultipleChoice:
+		if len(question.Choices) < 2 {
+			return fmt.Errorf("choice questions need at least two choices")
+		}
+		if len(question.CorrectAnswers) > 0 {
+			return fmt.Errorf("choice questions use correct_choices, not correct_answers")
+		}
+		if len(question.CorrectChoices) == 0 {
+			return fmt.Errorf("no correct choice given")
+		}
+		if question.Type == hfv1.QuestionTypeSingleChoice && len(question.CorrectChoices) != 1 {
+			return fmt.Errorf("single choice questions need exactly one correct choice")
+		}
+		seen := map[int]bool{}
+		for _, c := range question.CorrectChoices {
+			if c < 0 || c >= len(question.Choices) {
+				return fmt.Errorf("correct choice %d does not exist", c)
+			}
+			if seen[c] {
+				return fmt.Errorf("correct choice %d is given twice", c)
+			}
+			seen[c] = true
+		}
+	case hfv1.QuestionTypeFreeText:
+		if len(question.Choices) > 0 || len(question.CorrectChoices) > 0 {
+			return fmt.Errorf("free text questions have no choices")
+		}
+		if len(question.CorrectAnswers) == 0 {
+			return fmt.Errorf("no correct answer given")
+		}
+		for _, a := range question.CorrectAnswers {
+			if strings.TrimSpace(a) == "" {
+				return fmt.Errorf("correct answers must not be empty")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown question type %q", question.Type)
+	}
+
+	return nil
+}
+
+// Grade grades an answer to a question. Choice questions are answered with the indices of the chosen choices,
+// free text questions with a single answer. Multiple choice questions are only correct if exactly the correct choices were chosen.
+func Grade(question hfv1.StepQuestion, answer []string) (bool, error) {
+	switch question.Type {
+	case hfv1.QuestionTypeSingleChoice, hfv1.QuestionTypeMultipleChoice:
+		if len(answer) == 0 || (question.Type == hfv1.QuestionTypeSingleChoice && len(answer) != 1) {
+			return false, ErrInvalidAnswer
+		}
+		chosen := map[int]bool{}
+		for _, a := range answer {
+			c, err := strconv.Atoi(strings.TrimSpace(a))
+			if err != nil || c < 0 || c >= len(question.Choices) || chosen[c] {
+				return false, ErrInvalidAnswer
+			}
+			chosen[c] = true
+		}
+		if len(chosen) != len(question.CorrectChoices) {
+			return false, nil
+		}
+		for _, c := range question.CorrectChoices {
+			if !chosen[c] {
+				return false, nil
+			}
+		}
+		return true, nil
+	case hfv1.QuestionTypeFreeText:
+		if len(answer) != 1 || strings.TrimSpace(answer[0]) == "" || len(answer[0]) > maxAnswerLength {
+			return false, ErrInvalidAnswer
+		}
+		given := strings.TrimSpace(answer[0])
+		for _, correct := range question.CorrectAnswers {
+			if strings.EqualFold(given, strings.TrimSpace(correct)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unknown question type %q", question.Type)
+}
+
+// Answer grades an answer to a question of a step and returns the updated results.
+// Answers are rejected once a question was answered correctly or all attempts were used.
+func Answer(results []hfv1.QuestionResult, step int, index int, question hfv1.StepQuestion, answer []string) ([]hfv1.QuestionResult, hfv1.QuestionResult, error) {
+	result := hfv1.QuestionResult{
+		Step:     step,
+		Question: index,
+		Name:     question.Name,
+	}
+	if previous, ok := Find(results, step, index); ok {
+		if previous.Correct {
+			return results, previous, ErrAlreadyCorrect
+		}
+		result.Attempts = previous.Attempts
+	}
+	if !AttemptsLeft(question, result) {
+		return results, result, ErrNoAttemptsLeft
+	}
+
+	correct, err := Grade(question, answer)
+	if err != nil {
+		return results, result, err
+	}
+
+	result.Answer = answer
+	result.Correct = correct
+	result.Attempts++
+	if correct {
+		result.Points = question.Points
+	}
+	result.Timestamp = time.Now().Format(time.UnixDate)
+
+	merged := []hfv1.QuestionResult{}
+	for _, r := range results {
+		if r.Step != step || r.Question != index {
+			merged = append(merged, r)
+		}
+	}
+	merged = append(merged, result)
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Step != merged[j].Step {
+			return merged[i].Step < merged[j].Step
+		}
+		return merged[i].Question < merged[j].Question
+	})
+
+	return merged, result, nil
+}
+
+// Find returns the result of a question if it was answered before
+func Find(results []hfv1.QuestionResult, step int, index int) (hfv1.QuestionResult, bool) {
+	for _, r := range results {
+		if r.Step == step && r.Question == index {
+			return r, true
+		}
+	}
+	return hfv1.QuestionResult{}, false
+}
+
+// AttemptsLeft returns true if the question may be answered again
+func AttemptsLeft(question hfv1.StepQuestion, result hfv1.QuestionResult) bool {
+	if result.Correct {
+		return false
+	}
+	return question.MaxAttempts == 0 || result.Attempts < question.MaxAttempts
+}
+
+// Answered returns true once every question of a step was answered correctly or ran out of attempts
+func Answered(results []hfv1.QuestionResult, step int, questions []hfv1.StepQuestion) bool {
+	for i, q := range questions {
+		r, ok := Find(results, step, i)
+		if !ok || AttemptsLeft(q, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Score sums up the points of all results
+func Score(results []hfv1.QuestionResult) int {
+	score := 0
+	for _, r := range results {
+		score += r.Points
+	}
+	return score
+}
+
+// MaxScore sums up the points of all questions of a scenario
+func MaxScore(steps []hfv1.ScenarioStep) int {
+	score := 0
+	for _, s := range steps {
+		for _, q := range s.Questions {
+			score += q.Points
+		}
+	}
+	return score
+}
@@ -0,0 +1,201 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+var (
+	capital = hfv1.StepQuestion{
+		Name:           "capital",
+		Type:           hfv1.QuestionTypeSingleChoice,
+		Text:           "What is the capital of France?",
+		Choices:        []string{"Lyon", "Paris", "Nice"},
+		CorrectChoices: []int{1},
+		Points:         2,
+		MaxAttempts:    2,
+	}
+	primes = hfv1.StepQuestion{
+		Type:           hfv1.QuestionTypeMultipleChoice,
+		Text:           "Which numbers are prime?",
+		Choices:        []string{"2", "4", "7", "9"},
+		CorrectChoices: []int{0, 2},
+		Points:         3,
+	}
+	command = hfv1.StepQuestion{
+		Type:           hfv1.QuestionTypeFreeText,
+		Text:           "Which command lists pods?",
+		CorrectAnswers: []string{"kubectl get pods", " kubectl get po "},
+		Points:         5,
+	}
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		question func() hfv1.StepQuestion
+		wantErr  string
+	}{
+		{"single choice", func() hfv1.StepQuestion { return capital }, ""},
+		{"multiple choice", func() hfv1.StepQuestion { return primes }, ""},
+		{"free text", func() hfv1.StepQuestion { return command }, ""},
+		{"no text", func() hfv1.StepQuestion { q := capital; q.Text = "  "; return q }, "no text"},
+		{"negative points", func() hfv1.StepQuestion { q := capital; q.Points = -1; return q }, "points"},
+		{"negative attempts", func() hfv1.StepQuestion { q := capital; q.MaxAttempts = -1; return q }, "max_attempts"},
+		{"one choice", func() hfv1.StepQuestion {
+			q := capital
+			q.Choices = []string{"Paris"}
+			q.CorrectChoices = []int{0}
+			return q
+		}, "at least two"},
+		{"answers on a choice question", func() hfv1.StepQuestion { q := capital; q.CorrectAnswers = []string{"Paris"}; return q }, "use correct_choices"},
+		{"no correct choice", func() hfv1.StepQuestion { q := primes; q.CorrectChoices = nil; return q }, "no correct choice"},
+		{"two correct single choices", func() hfv1.StepQuestion { q := capital; q.CorrectChoices = []int{0, 1}; return q }, "exactly one"},
+		{"choice out of range", func() hfv1.StepQuestion { q := primes; q.CorrectChoices = []int{0, 4}; return q }, "choice 4 does not exist"},
+		{"choice given twice", func() hfv1.StepQuestion { q := primes; q.CorrectChoices = []int{2, 2}; return q }, "given twice"},
+		{"free text with choices", func() hfv1.StepQuestion { q := command; q.Choices = []string{"a", "b"}; return q }, "no choices"},
+		{"free text without answers", func() hfv1.StepQuestion { q := command; q.CorrectAnswers = nil; return q }, "no correct answer"},
+		{"empty correct answer", func() hfv1.StepQuestion { q := command; q.CorrectAnswers = []string{"kubectl get pods", " "}; return q }, "must not be empty"},
+		{"unknown type", func() hfv1.StepQuestion { q := command; q.Type = "essay"; return q }, "unknown question type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.question())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Validate() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Grade(t *testing.T) {
+	tests := []struct {
+		question hfv1.StepQuestion
+		answer   []string
+		want     bool
+		wantErr  error
+	}{
+		{capital, []string{"1"}, true, nil},
+		{capital, []string{" 1 "}, true, nil},
+		{capital, []string{"0"}, false, nil},
+		{capital, []string{"0", "1"}, false, ErrInvalidAnswer},
+		{capital, []string{"Paris"}, false, ErrInvalidAnswer},
+		{capital, []string{"3"}, false, ErrInvalidAnswer},
+		{capital, nil, false, ErrInvalidAnswer},
+		{primes, []string{"2", "0"}, true, nil},
+		{primes, []string{"0"}, false, nil},           // some of the correct choices are not enough
+		{primes, []string{"0", "2", "3"}, false, nil}, // neither is choosing everything
+		{primes, []string{"0", "0", "2"}, false, ErrInvalidAnswer},
+		{command, []string{"KUBECTL GET PODS"}, true, nil},
+		{command, []string{"kubectl get po"}, true, nil},
+		{command, []string{"kubectl get nodes"}, false, nil},
+		{command, []string{"  "}, false, ErrInvalidAnswer},
+		{command, []string{"kubectl", "get pods"}, false, ErrInvalidAnswer},
+		{command, []string{strings.Repeat("x", maxAnswerLength+1)}, false, ErrInvalidAnswer},
+	}
+
+	for _, tt := range tests {
+		got, err := Grade(tt.question, tt.answer)
+		if got != tt.want || err != tt.wantErr {
+			t.Errorf("Grade(%q, %q) = %v, %v, want %v, %v", tt.question.Text, tt.answer, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func Test_Answer(t *testing.T) {
+	// an earlier answer to a question of a later step stays in place
+	results := []hfv1.QuestionResult{{Step: 3, Question: 0, Correct: true, Points: 5, Attempts: 1}}
+
+	results, result, err := Answer(results, 1, 0, capital, []string{"0"})
+	if err != nil || result.Correct || result.Attempts != 1 || result.Points != 0 || result.Name != "capital" {
+		t.Fatalf("wrong answer = %+v, %v", result, err)
+	}
+	if _, _, err := Answer(results, 1, 0, capital, []string{"Lyon"}); !errors.Is(err, ErrInvalidAnswer) {
+		t.Errorf("invalid answer = %v, want %v", err, ErrInvalidAnswer)
+	}
+	if r, _ := Find(results, 1, 0); r.Attempts != 1 {
+		t.Errorf("invalid answer used an attempt, attempts = %d", r.Attempts)
+	}
+	if Answered(results, 1, []hfv1.StepQuestion{capital}) {
+		t.Error("Answered() with an attempt left")
+	}
+
+	results, result, err = Answer(results, 1, 0, capital, []string{"1"})
+	if err != nil || !result.Correct || result.Attempts != 2 || result.Points != 2 || result.Timestamp == "" {
+		t.Fatalf("correct answer = %+v, %v", result, err)
+	}
+	if _, _, err := Answer(results, 1, 0, capital, []string{"1"}); err != ErrAlreadyCorrect {
+		t.Errorf("answer after a correct one = %v, want %v", err, ErrAlreadyCorrect)
+	}
+
+	if len(results) != 2 || results[0].Step != 1 || results[1].Step != 3 {
+		t.Errorf("results = %+v, want one result per question ordered by step", results)
+	}
+	if !Answered(results, 1, []hfv1.StepQuestion{capital}) || Answered(results, 1, []hfv1.StepQuestion{capital, primes}) {
+		t.Error("Answered() does not require every question of the step")
+	}
+	if got := Score(results); got != 7 {
+		t.Errorf("Score() = %d, want 7", got)
+	}
+}
+
+func Test_AnswerAttempts(t *testing.T) {
+	oneTry := capital
+	oneTry.MaxAttempts = 1
+
+	results, _, err := Answer(nil, 0, 0, oneTry, []string{"2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, result, err := Answer(results, 0, 0, oneTry, []string{"1"}); err != ErrNoAttemptsLeft || result.Correct {
+		t.Errorf("answer without attempts left = %+v, %v", result, err)
+	}
+	// running out of attempts answers the question, so gated steps can go on
+	if !Answered(results, 0, []hfv1.StepQuestion{oneTry}) {
+		t.Error("Answered() after using all attempts = false")
+	}
+
+	// without a limit learners may keep trying
+	unlimited := capital
+	unlimited.MaxAttempts = 0
+	results = nil
+	for i := 0; i < 5; i++ {
+		if results, _, err = Answer(results, 0, 0, unlimited, []string{"0"}); err != nil {
+			t.Fatalf("attempt %d = %v", i+1, err)
+		}
+	}
+	if r, _ := Find(results, 0, 0); r.Attempts != 5 || len(results) != 1 {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func Test_Prepare(t *testing.T) {
+	prepared := Prepare([]hfv1.StepQuestion{capital, command})
+
+	encoded, err := json.Marshal(prepared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"correct", "kubectl get"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Errorf("prepared questions %s contain %q", encoded, secret)
+		}
+	}
+	if !reflect.DeepEqual(prepared[0].Choices, capital.Choices) || prepared[0].MaxAttempts != 2 || prepared[1].Points != 5 {
+		t.Errorf("Prepare() = %+v", prepared)
+	}
+
+	steps := []hfv1.ScenarioStep{{Questions: []hfv1.StepQuestion{capital, primes}}, {}, {Questions: []hfv1.StepQuestion{command}}}
+	if got := MaxScore(steps); got != 10 {
+		t.Errorf("MaxScore() = %d, want 10", got)
+	}
+}
@@ -41,7 +41,8 @@ func List() []Role {
 		// ScheduledEvent Creator can create and edit scheduled events + view dashboards
 		newRole("scheduledevent-creator", func(r Role) Role {
 			return r.
-				addRule([]string{"hobbyfarm.io"}, []string{"*"}, []string{"scheduledevents", "accesscodes"}).
+				addRule([]string{"hobbyfarm.io"}, []string{"*"}, []string{"scheduledevents", "scheduledeventseries", "accesscodes"}).
+				addRule([]string{"hobbyfarm.io"}, []string{"list", "get", "delete"}, []string{"certificates"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"list"}, []string{"environments"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"list", "get"}, []string{"scenarios", "courses", "virtualmachinetemplates", "virtualmachinesets", "users"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"list", "get", "watch"}, []string{"progresses", "virtualmachines", "virtualmachineclaims"}).
@@ -50,7 +51,7 @@ func List() []Role {
 		// ScheduledEvent Proctor is allowed to view scheduled events + dashboards
 		newRole("scheduledevent-proctor", func(r Role) Role {
 			return r.
-				addRule([]string{"hobbyfarm.io"}, []string{"list", "get"}, []string{"scheduledevents", "accesscodes", "scenarios", "courses", "environments", "virtualmachinetemplates", "virtualmachinesets", "users"}).
+				addRule([]string{"hobbyfarm.io"}, []string{"list", "get"}, []string{"scheduledevents", "scheduledeventseries", "accesscodes", "scenarios", "courses", "environments", "virtualmachinetemplates", "virtualmachinesets", "users", "certificates"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"list"}, []string{"environments"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"list", "get", "watch"}, []string{"progresses", "virtualmachines", "virtualmachineclaims"}).
 				addRule([]string{"hobbyfarm.io"}, []string{"update", "delete", "list", "get"}, []string{"sessions"})
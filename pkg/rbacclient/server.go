@@ -57,3 +57,18 @@ func (rs *Client) GetAccessSet(user string) (*AccessSet, error) {
 func (rs *Client) GetHobbyfarmRoleBindings(user string) ([]*rbacv1.RoleBinding, error) {
 	return rs.userIndex.getRoleBindings(user)
 }
+
+// HasRoleBindings returns true if the user is the subject of any role binding or cluster role binding
+func (rs *Client) HasRoleBindings(user string) (bool, error) {
+	rb, err := rs.userIndex.getRoleBindings(user)
+	if err != nil {
+		return false, err
+	}
+
+	crb, err := rs.userIndex.getClusterRoleBindings(user)
+	if err != nil {
+		return false, err
+	}
+
+	return len(rb) > 0 || len(crb) > 0, nil
+}
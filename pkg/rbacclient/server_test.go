@@ -44,6 +44,26 @@ func Test_RbacClient(t *testing.T) {
 		}
 	})
 
+	t.Run("test has rolebindings", func(t *testing.T) {
+		bound, err := rbacclient.HasRoleBindings(FakeEmail)
+		if err != nil {
+			t.Errorf("error checking rolebindings: %s", err.Error())
+		}
+
+		if !bound {
+			t.Error("no rolebindings found, should be")
+		}
+
+		bound, err = rbacclient.HasRoleBindings("nobody@fake.com")
+		if err != nil {
+			t.Errorf("error checking rolebindings: %s", err.Error())
+		}
+
+		if bound {
+			t.Error("rolebindings found for unbound user, should NOT be")
+		}
+	})
+
 	t.Run("test role permissions allowed", func(t *testing.T) {
 		perms := RbacRequest().HobbyfarmPermission(RoleResource, RoleVerb).GetPermissions()
 
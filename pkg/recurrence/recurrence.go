@@ -0,0 +1,186 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+
+	// DateFormat identifies an occurrence, there is at most one per day
+	DateFormat = "2006-01-02"
+
+	maxIterations = 10000
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is the subset of an RFC 5545 RRULE that scheduled events use: FREQ, INTERVAL, BYDAY (weekly only), COUNT and UNTIL
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int       // 0 if unlimited
+	Until    time.Time // zero if unlimited, inclusive
+}
+
+// Occurrence is a single start of a recurring event. Index counts from 0 at the first start, exceptions included.
+type Occurrence struct {
+	Index int
+	Start time.Time
+}
+
+func (o Occurrence) Date() string {
+	return o.Start.Format(DateFormat)
+}
+
+// Parse reads a recurrence rule. A date-only UNTIL includes the whole day in loc, the location of the series,
+// an UNTIL with a time of day is in UTC.
+func Parse(rule string, loc *time.Location) (Rule, error) {
+	r := Rule{Interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid recurrence part %s", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly {
+				return Rule{}, fmt.Errorf("unsupported recurrence frequency %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return Rule{}, fmt.Errorf("invalid recurrence interval %s", value)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err != nil || r.Count < 1 {
+				return Rule{}, fmt.Errorf("invalid recurrence count %s", value)
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(value, loc)
+			if err != nil {
+				return Rule{}, err
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return Rule{}, fmt.Errorf("unsupported recurrence day %s", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "WKST":
+			// weeks always start on monday
+		default:
+			return Rule{}, fmt.Errorf("unsupported recurrence part %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return Rule{}, fmt.Errorf("recurrence has no frequency")
+	}
+	if len(r.ByDay) > 0 && r.Freq != FreqWeekly {
+		return Rule{}, fmt.Errorf("BYDAY is only supported with a weekly frequency")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return Rule{}, fmt.Errorf("recurrence may not have both COUNT and UNTIL")
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		// a date includes the whole day
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence end %s", value)
+}
+
+// Between returns the occurrences that start within [from, to]. The first occurrence always is the start
+// of the series, later ones keep its time of day in its location.
+func (r Rule) Between(first time.Time, from time.Time, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+
+	index := 0
+	for i := 0; i < maxIterations; i++ {
+		for _, start := range r.period(first, i) {
+			if start.Before(first) {
+				continue
+			}
+			if (r.Count > 0 && index >= r.Count) || (!r.Until.IsZero() && start.After(r.Until)) || start.After(to) {
+				return occurrences
+			}
+			if !start.Before(from) {
+				occurrences = append(occurrences, Occurrence{Index: index, Start: start})
+			}
+			index++
+		}
+	}
+
+	return occurrences
+}
+
+// period returns the starts within the n-th period of the rule, in order
+func (r Rule) period(first time.Time, n int) []time.Time {
+	step := n * r.Interval
+	switch r.Freq {
+	case FreqDaily:
+		return []time.Time{first.AddDate(0, 0, step)}
+	case FreqMonthly:
+		start := first.AddDate(0, step, 0)
+		if start.Day() != first.Day() {
+			// the month is too short, like the 31st in april
+			return nil
+		}
+		return []time.Time{start}
+	}
+
+	weekStart := first.AddDate(0, 0, 7*step)
+	if len(r.ByDay) == 0 {
+		return []time.Time{weekStart}
+	}
+
+	// monday of the week, at the time of day of the first occurrence
+	monday := weekStart.AddDate(0, 0, -((int(weekStart.Weekday()) + 6) % 7))
+	var starts []time.Time
+	if n == 0 {
+		// the first occurrence counts even if it is not on one of the days
+		starts = append(starts, first)
+	}
+	for _, day := range r.ByDay {
+		start := monday.AddDate(0, 0, (int(day)+6)%7)
+		if n != 0 || !start.Equal(first) {
+			starts = append(starts, start)
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts
+}
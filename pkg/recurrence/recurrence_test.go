@@ -0,0 +1,244 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("error loading location %s: %v", name, err)
+	}
+	return loc
+}
+
+func Test_Parse(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name    string
+		rule    string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			name: "daily with defaults",
+			rule: "FREQ=DAILY",
+			want: Rule{Freq: FreqDaily, Interval: 1},
+		},
+		{
+			name: "rrule prefix and lower case",
+			rule: "RRULE:freq=weekly;interval=2;byday=mo,fr;wkst=MO",
+			want: Rule{Freq: FreqWeekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Friday}},
+		},
+		{
+			name: "count",
+			rule: "FREQ=MONTHLY;COUNT=3",
+			want: Rule{Freq: FreqMonthly, Interval: 1, Count: 3},
+		},
+		{
+			name: "until with a time is utc",
+			rule: "FREQ=DAILY;UNTIL=20240310T120000Z",
+			want: Rule{Freq: FreqDaily, Interval: 1, Until: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "date-only until is the end of the day in the location",
+			rule: "FREQ=DAILY;UNTIL=20240310",
+			want: Rule{Freq: FreqDaily, Interval: 1, Until: time.Date(2024, 3, 10, 23, 59, 59, 0, berlin)},
+		},
+		{name: "no frequency", rule: "INTERVAL=2", wantErr: true},
+		{name: "unsupported frequency", rule: "FREQ=YEARLY", wantErr: true},
+		{name: "invalid interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "invalid count", rule: "FREQ=DAILY;COUNT=none", wantErr: true},
+		{name: "invalid until", rule: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20240310", wantErr: true},
+		{name: "byday without weekly", rule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "unsupported day", rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "unsupported part", rule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "malformed part", rule: "FREQ=DAILY;COUNT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.rule, berlin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Freq != tt.want.Freq || got.Interval != tt.want.Interval || got.Count != tt.want.Count || !got.Until.Equal(tt.want.Until) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.ByDay) != len(tt.want.ByDay) {
+				t.Fatalf("Parse() days = %v, want %v", got.ByDay, tt.want.ByDay)
+			}
+			for i := range got.ByDay {
+				if got.ByDay[i] != tt.want.ByDay[i] {
+					t.Errorf("Parse() days = %v, want %v", got.ByDay, tt.want.ByDay)
+				}
+			}
+		})
+	}
+}
+
+func Test_Between(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	losAngeles := mustLoad(t, "America/Los_Angeles")
+
+	tests := []struct {
+		name  string
+		rule  string
+		loc   *time.Location
+		first time.Time
+		from  time.Time
+		to    time.Time
+		want  []string // starts formatted as time.RFC3339
+	}{
+		{
+			name:  "daily keeps the local time across the start of daylight saving time",
+			rule:  "FREQ=DAILY",
+			loc:   newYork,
+			first: time.Date(2024, 3, 9, 9, 0, 0, 0, newYork),
+			from:  time.Date(2024, 3, 9, 0, 0, 0, 0, newYork),
+			to:    time.Date(2024, 3, 11, 23, 0, 0, 0, newYork),
+			want:  []string{"2024-03-09T09:00:00-05:00", "2024-03-10T09:00:00-04:00", "2024-03-11T09:00:00-04:00"},
+		},
+		{
+			name:  "weekly keeps the local time across the end of daylight saving time",
+			rule:  "FREQ=WEEKLY",
+			loc:   newYork,
+			first: time.Date(2024, 10, 28, 18, 30, 0, 0, newYork),
+			from:  time.Date(2024, 10, 1, 0, 0, 0, 0, newYork),
+			to:    time.Date(2024, 11, 5, 0, 0, 0, 0, newYork),
+			want:  []string{"2024-10-28T18:30:00-04:00", "2024-11-04T18:30:00-05:00"},
+		},
+		{
+			name:  "byday week where the first occurrence is not on a listed day",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE",
+			loc:   time.UTC,
+			first: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), // tuesday
+			from:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2024-03-05T10:00:00Z", "2024-03-06T10:00:00Z", "2024-03-11T10:00:00Z", "2024-03-13T10:00:00Z"},
+		},
+		{
+			name:  "byday every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR",
+			loc:   time.UTC,
+			first: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), // friday
+			from:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2024-03-01T10:00:00Z", "2024-03-15T10:00:00Z", "2024-03-29T10:00:00Z"},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			loc:   time.UTC,
+			first: time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+			from:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2024-01-31T10:00:00Z", "2024-03-31T10:00:00Z", "2024-05-31T10:00:00Z", "2024-07-31T10:00:00Z"},
+		},
+		{
+			name:  "count limits the occurrences",
+			rule:  "FREQ=DAILY;COUNT=3",
+			loc:   time.UTC,
+			first: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			from:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2024-03-01T10:00:00Z", "2024-03-02T10:00:00Z", "2024-03-03T10:00:00Z"},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=DAILY;UNTIL=20240303T100000Z",
+			loc:   time.UTC,
+			first: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			from:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:  []string{"2024-03-01T10:00:00Z", "2024-03-02T10:00:00Z", "2024-03-03T10:00:00Z"},
+		},
+		{
+			name:  "date-only until includes the last day in the location of the series",
+			rule:  "FREQ=DAILY;UNTIL=20240303",
+			loc:   losAngeles,
+			first: time.Date(2024, 3, 1, 20, 0, 0, 0, losAngeles), // already the next day in utc
+			from:  time.Date(2024, 3, 1, 0, 0, 0, 0, losAngeles),
+			to:    time.Date(2024, 4, 1, 0, 0, 0, 0, losAngeles),
+			want:  []string{"2024-03-01T20:00:00-08:00", "2024-03-02T20:00:00-08:00", "2024-03-03T20:00:00-08:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule, tt.loc)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := rule.Between(tt.first, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+			for i, o := range got {
+				if o.Index != i {
+					t.Errorf("occurrence %d has index %d", i, o.Index)
+				}
+				if start := o.Start.Format(time.RFC3339); start != tt.want[i] {
+					t.Errorf("occurrence %d starts %s, want %s", i, start, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_BetweenWindow(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=5", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	got := rule.Between(first, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	// occurrences before the window still count towards the index and the count
+	if len(got) != 3 {
+		t.Fatalf("Between() returned %d occurrences, want 3", len(got))
+	}
+	if got[0].Index != 2 || got[0].Date() != "2024-03-03" {
+		t.Errorf("first occurrence in window = %d %s, want 2 2024-03-03", got[0].Index, got[0].Date())
+	}
+	if got[2].Index != 4 || got[2].Date() != "2024-03-05" {
+		t.Errorf("last occurrence in window = %d %s, want 4 2024-03-05", got[2].Index, got[2].Date())
+	}
+}
+
+func Test_Period(t *testing.T) {
+	rule := Rule{Freq: FreqWeekly, Interval: 1, ByDay: []time.Weekday{time.Friday, time.Monday}}
+	first := time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC) // wednesday
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		// the first week contains the first occurrence and the listed days, sorted, even those before the first
+		{0, []string{"2024-03-04", "2024-03-06", "2024-03-08"}},
+		{1, []string{"2024-03-11", "2024-03-15"}},
+	}
+
+	for _, tt := range tests {
+		starts := rule.period(first, tt.n)
+		if len(starts) != len(tt.want) {
+			t.Fatalf("period(%d) = %v, want %v", tt.n, starts, tt.want)
+		}
+		for i, start := range starts {
+			if start.Format(DateFormat) != tt.want[i] || start.Hour() != 10 {
+				t.Errorf("period(%d)[%d] = %s, want %s at 10:00", tt.n, i, start, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package report
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// DefaultColumns are exported if the caller does not choose any
+var DefaultColumns = []string{"email", "scenario_name", "course", "started", "finished", "max_step", "total_steps", "session_time", "score", "max_score"}
+
+// DateFormats are the named date formats, any other format is used as a go time layout
+var DateFormats = map[string]string{
+	"":         time.UnixDate,
+	"unixdate": time.UnixDate,
+	"rfc3339":  time.RFC3339,
+	"datetime": "2006-01-02 15:04:05",
+	"date":     "2006-01-02",
+}
+
+// Row is the attendance of a user in a scenario, aggregated over all sessions the user started for it
+type Row struct {
+	User         string
+	Email        string
+	Scenario     string
+	ScenarioName string
+	Course       string
+	Sessions     int
+	Started      time.Time
+	Finished     time.Time // zero while a session is still running
+	MaxStep      int       // highest step reached, counted from 1
+	TotalSteps   int
+	Completed    bool
+	SessionTime  time.Duration
+	Score        int
+	MaxScore     int
+	HasQuiz      bool
+}
+
+type column struct {
+	header string
+	value  func(row Row, dateFormat string) string
+}
+
+var columns = map[string]column{
+	"user":          {"User", func(row Row, _ string) string { return row.User }},
+	"email":         {"Email", func(row Row, _ string) string { return row.Email }},
+	"scenario":      {"Scenario", func(row Row, _ string) string { return row.Scenario }},
+	"scenario_name": {"Scenario Name", func(row Row, _ string) string { return row.ScenarioName }},
+	"course":        {"Course", func(row Row, _ string) string { return row.Course }},
+	"sessions":      {"Sessions", func(row Row, _ string) string { return strconv.Itoa(row.Sessions) }},
+	"started":       {"Started", func(row Row, f string) string { return formatTime(row.Started, f) }},
+	"finished":      {"Finished", func(row Row, f string) string { return formatTime(row.Finished, f) }},
+	"max_step":      {"Max Step", func(row Row, _ string) string { return strconv.Itoa(row.MaxStep) }},
+	"total_steps":   {"Total Steps", func(row Row, _ string) string { return strconv.Itoa(row.TotalSteps) }},
+	"completed":     {"Completed", func(row Row, _ string) string { return strconv.FormatBool(row.Completed) }},
+	"session_time":  {"Session Time", func(row Row, _ string) string { return row.SessionTime.Round(time.Second).String() }},
+	"score":         {"Score", func(row Row, _ string) string { return quizValue(row, row.Score) }},
+	"max_score":     {"Max Score", func(row Row, _ string) string { return quizValue(row, row.MaxScore) }},
+}
+
+// Table is an export ready to be written
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Rows aggregates progress into one row per user and scenario. Emails are looked up by user id,
+// scenario names are the base64 encoded names of the scenarios.
+func Rows(progress []hfv2.Progress, emails map[string]string, scenarioNames map[string]string) []Row {
+	rows := map[string]*Row{}
+	running := map[string]bool{}
+	for _, p := range progress {
+		key := p.Spec.UserId + "/" + p.Spec.Scenario
+		row, ok := rows[key]
+		if !ok {
+			row = &Row{
+				User:         p.Spec.UserId,
+				Email:        emails[p.Spec.UserId],
+				Scenario:     p.Spec.Scenario,
+				ScenarioName: decode(scenarioNames[p.Spec.Scenario]),
+				Course:       p.Spec.Course,
+			}
+			rows[key] = row
+		}
+
+		row.Sessions++
+		started := p.Spec.Started.Time
+		lastUpdate := p.Spec.LastUpdate.Time
+		if row.Started.IsZero() || started.Before(row.Started) {
+			row.Started = started
+		}
+		if !p.Spec.Finished {
+			running[key] = true
+		} else if lastUpdate.After(row.Finished) {
+			row.Finished = lastUpdate
+		}
+		if !started.IsZero() && lastUpdate.After(started) {
+			row.SessionTime += lastUpdate.Sub(started)
+		}
+
+		row.MaxStep = max(row.MaxStep, p.Spec.MaxStep+1)
+		row.TotalSteps = max(row.TotalSteps, p.Spec.TotalStep)
+		row.Completed = row.Completed || completion.ScenarioCompleted(p.Spec)
+		if p.Spec.MaxScore > 0 {
+			row.HasQuiz = true
+			// the best attempt counts
+			if p.Spec.Score >= row.Score {
+				row.Score = p.Spec.Score
+				row.MaxScore = p.Spec.MaxScore
+			}
+		}
+	}
+
+	sorted := []Row{}
+	for key, row := range rows {
+		// the finish time is only known once every session is finished
+		if running[key] {
+			row.Finished = time.Time{}
+		}
+		sorted = append(sorted, *row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Email != sorted[j].Email {
+			return sorted[i].Email < sorted[j].Email
+		}
+		if sorted[i].User != sorted[j].User {
+			return sorted[i].User < sorted[j].User
+		}
+		return sorted[i].Started.Before(sorted[j].Started)
+	})
+	return sorted
+}
+
+// NewTable returns the chosen columns of the rows. The date format is either one of DateFormats or a go time layout.
+func NewTable(rows []Row, columnNames []string, dateFormat string) (Table, error) {
+	if len(columnNames) == 0 {
+		columnNames = DefaultColumns
+	}
+
+	layout, ok := DateFormats[strings.ToLower(dateFormat)]
+	if !ok {
+		layout = dateFormat
+	}
+
+	table := Table{Header: []string{}, Rows: [][]string{}}
+	selected := []column{}
+	for _, name := range columnNames {
+		c, ok := columns[name]
+		if !ok {
+			return Table{}, fmt.Errorf("unknown column %s", name)
+		}
+		selected = append(selected, c)
+		table.Header = append(table.Header, c.header)
+	}
+
+	for _, row := range rows {
+		values := make([]string, 0, len(selected))
+		for _, c := range selected {
+			values = append(values, c.value(row, layout))
+		}
+		table.Rows = append(table.Rows, values)
+	}
+
+	return table, nil
+}
+
+// ColumnNames returns the names of all columns that can be exported
+func ColumnNames() []string {
+	names := []string{}
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteCSV writes the table as csv including a header line. Cells that spreadsheet applications would read as a
+// formula are prefixed with a quote, names and emails are chosen by users.
+func (t Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.Header); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		escaped := make([]string, len(row))
+		for i, value := range row {
+			escaped[i] = escapeFormula(value)
+		}
+		if err := writer.Write(escaped); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func quizValue(row Row, value int) string {
+	if !row.HasQuiz {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func decode(name string) string {
+	decoded, err := base64.StdEncoding.DecodeString(name)
+	if err != nil {
+		return name
+	}
+	return string(decoded)
+}
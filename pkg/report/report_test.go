@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Rows(t *testing.T) {
+	day := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC)
+	attempt := func(user string, scenario string, started int, updated int, maxStep int, finished bool, score int) hfv2.Progress {
+		return hfv2.Progress{Spec: hfv2.ProgressSpec{
+			UserId:     user,
+			Scenario:   scenario,
+			TotalStep:  5,
+			MaxStep:    maxStep,
+			Started:    metav1.NewTime(day.Add(time.Duration(started) * time.Minute)),
+			LastUpdate: metav1.NewTime(day.Add(time.Duration(updated) * time.Minute)),
+			Finished:   finished,
+			Score:      score,
+			MaxScore:   10,
+		}}
+	}
+	progress := []hfv2.Progress{
+		attempt("u-2", "sc-1", 30, 50, 4, true, 6),
+		attempt("u-2", "sc-1", 0, 20, 2, true, 9),
+		attempt("u-1", "sc-1", 0, 10, 1, true, 3),
+		attempt("u-1", "sc-2", 5, 15, 0, false, 0),
+	}
+	emails := map[string]string{"u-1": "b@example.com", "u-2": "a@example.com"}
+	names := map[string]string{"sc-1": base64.StdEncoding.EncodeToString([]byte("Intro")), "sc-2": "not base64!"}
+
+	rows := Rows(progress, emails, names)
+	if len(rows) != 3 {
+		t.Fatalf("Rows() = %+v, want 3 rows", rows)
+	}
+
+	// two sessions of a@example.com in sc-1 are aggregated, the best score counts
+	a := rows[0]
+	if a.Email != "a@example.com" || a.ScenarioName != "Intro" || a.Sessions != 2 {
+		t.Errorf("rows[0] = %+v", a)
+	}
+	if !a.Started.Equal(day) || !a.Finished.Equal(day.Add(50*time.Minute)) || a.SessionTime != 40*time.Minute {
+		t.Errorf("rows[0] times = %v - %v, %v", a.Started, a.Finished, a.SessionTime)
+	}
+	if a.MaxStep != 5 || !a.Completed || a.Score != 9 || a.MaxScore != 10 {
+		t.Errorf("rows[0] = %+v, want completed with a score of 9", a)
+	}
+
+	// b@example.com is ordered by start, the running session has no finish time
+	if rows[1].Scenario != "sc-1" || rows[1].Completed || rows[1].MaxStep != 2 {
+		t.Errorf("rows[1] = %+v", rows[1])
+	}
+	if rows[2].Scenario != "sc-2" || !rows[2].Finished.IsZero() || rows[2].ScenarioName != "not base64!" {
+		t.Errorf("rows[2] = %+v", rows[2])
+	}
+}
+
+func Test_NewTable(t *testing.T) {
+	row := Row{
+		Email:       "learner@example.com",
+		Sessions:    2,
+		Started:     time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC),
+		SessionTime: 90*time.Minute + 400*time.Millisecond,
+	}
+
+	tests := []struct {
+		name       string
+		columns    []string
+		dateFormat string
+		wantHeader []string
+		wantRow    []string
+		wantErr    bool
+	}{
+		{
+			name:       "named date format",
+			columns:    []string{"email", "started", "finished", "session_time"},
+			dateFormat: "DATE",
+			wantHeader: []string{"Email", "Started", "Finished", "Session Time"},
+			wantRow:    []string{"learner@example.com", "2026-06-01", "", "1h30m0s"},
+		},
+		{
+			name:       "go time layout",
+			columns:    []string{"started", "sessions"},
+			dateFormat: "02.01.2006 15:04",
+			wantHeader: []string{"Started", "Sessions"},
+			wantRow:    []string{"01.06.2026 09:00", "2"},
+		},
+		{
+			name:       "scores of scenarios without a quiz are empty",
+			columns:    []string{"score", "max_score", "completed"},
+			wantHeader: []string{"Score", "Max Score", "Completed"},
+			wantRow:    []string{"", "", "false"},
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"email", "password"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := NewTable([]Row{row}, tt.columns, tt.dateFormat)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(table.Header, tt.wantHeader) {
+				t.Errorf("Header = %v, want %v", table.Header, tt.wantHeader)
+			}
+			if len(table.Rows) != 1 || !reflect.DeepEqual(table.Rows[0], tt.wantRow) {
+				t.Errorf("Rows = %v, want [%v]", table.Rows, tt.wantRow)
+			}
+		})
+	}
+
+	table, err := NewTable(nil, nil, "")
+	if err != nil || len(table.Header) != len(DefaultColumns) || len(table.Rows) != 0 {
+		t.Errorf("NewTable() without columns = %+v, %v, want the default columns", table, err)
+	}
+}
+
+func Test_WriteCSV(t *testing.T) {
+	table := Table{
+		Header: []string{"Email", "Scenario Name", "Score"},
+		Rows: [][]string{
+			{"learner@example.com", "Intro, part 1", "7"},
+			{"=HYPERLINK(\"http://example.com\")", "+1 extra", "-3"},
+			{"@SUM(A1:A2)", "", "a=b"},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := table.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Email,Scenario Name,Score
+learner@example.com,"Intro, part 1",7
+"'=HYPERLINK(""http://example.com"")",'+1 extra,'-3
+'@SUM(A1:A2),,a=b
+`
+	if b.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
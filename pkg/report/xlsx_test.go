@@ -0,0 +1,85 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func Test_WriteXLSX(t *testing.T) {
+	table := Table{
+		Header: []string{"Email", "Score"},
+		Rows:   [][]string{{"<b>&co@example.com", "7"}, {"=1+1", "07x"}},
+	}
+	for i := 0; i < 27; i++ {
+		table.Header = append(table.Header, "")
+	}
+	table.Header[28] = "AC"
+
+	var b bytes.Buffer
+	if err := table.WriteXLSX(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string][]byte{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("part %s is missing", name)
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref   string `xml:"r,attr"`
+				Type  string `xml:"t,attr"`
+				Value string `xml:"v"`
+				Text  string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("sheet is not valid xml: %v", err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("sheet has %d rows, want 3", len(sheet.Rows))
+	}
+
+	cells := map[string]string{}
+	for _, row := range sheet.Rows {
+		for _, c := range row.Cells {
+			if c.Type == "inlineStr" {
+				cells[c.Ref] = "text:" + c.Text
+			} else {
+				cells[c.Ref] = "number:" + c.Value
+			}
+		}
+	}
+	want := map[string]string{
+		"A1":  "text:Email",
+		"AC1": "text:AC",
+		"A2":  "text:<b>&co@example.com",
+		"B2":  "number:7",
+		"A3":  "text:=1+1",
+		"B3":  "text:07x",
+	}
+	for ref, value := range want {
+		if cells[ref] != value {
+			t.Errorf("cell %s = %q, want %q", ref, cells[ref], value)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RevisionLabel = "hobbyfarm.io/revision"
+)
+
+// Change is a single field that differs between two revisions
+type Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+func ScenarioRevisionName(scenario string, revision int) string {
+	return fmt.Sprintf("%s-r%d", scenario, revision)
+}
+
+func CourseRevisionName(course string, revision int) string {
+	return fmt.Sprintf("%s-r%d", course, revision)
+}
+
+// Published returns true if content in the given state may be shown to learners
+func Published(state hfv1.ContentState) bool {
+	return state != hfv1.ContentStateDraft
+}
+
+// ParseState reads a content state from a form value, content without a state is published
+func ParseState(raw string) (hfv1.ContentState, error) {
+	switch hfv1.ContentState(raw) {
+	case "", hfv1.ContentStatePublished:
+		return hfv1.ContentStatePublished, nil
+	case hfv1.ContentStateDraft:
+		return hfv1.ContentStateDraft, nil
+	}
+	return "", fmt.Errorf("invalid state %s, has to be %s or %s", raw, hfv1.ContentStateDraft, hfv1.ContentStatePublished)
+}
+
+// ScenarioChanged returns true if the content of a scenario differs, ignoring its state and revision
+func ScenarioChanged(old hfv1.ScenarioSpec, new hfv1.ScenarioSpec) bool {
+	old.State, new.State = "", ""
+	old.Revision, new.Revision = 0, 0
+	return !reflect.DeepEqual(old, new)
+}
+
+// CourseChanged returns true if the content of a course differs, ignoring its state and revision
+func CourseChanged(old hfv1.CourseSpec, new hfv1.CourseSpec) bool {
+	old.State, new.State = "", ""
+	old.Revision, new.Revision = 0, 0
+	return !reflect.DeepEqual(old, new)
+}
+
+// CreateScenarioRevision stores the current spec of a scenario as revision scenario.Spec.Revision.
+// Revisions are owned by their scenario and never updated.
+func CreateScenarioRevision(ctx context.Context, hfClientSet hfClientset.Interface, scenario *hfv1.Scenario, creator string) error {
+	rev := &hfv1.ScenarioRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ScenarioRevisionName(scenario.Name, scenario.Spec.Revision),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "hobbyfarm.io/v1",
+					Kind:       "Scenario",
+					Name:       scenario.Name,
+					UID:        scenario.UID,
+				},
+			},
+			Labels: map[string]string{
+				util.ScenarioLabel: scenario.Name,
+				RevisionLabel:      strconv.Itoa(scenario.Spec.Revision),
+			},
+		},
+		Spec: hfv1.ScenarioRevisionSpec{
+			Scenario: scenario.Name,
+			Revision: scenario.Spec.Revision,
+			Creator:  creator,
+			Created:  time.Now().Format(time.UnixDate),
+			Snapshot: *scenario.Spec.DeepCopy(),
+		},
+	}
+
+	_, err := hfClientSet.HobbyfarmV1().ScenarioRevisions(util.GetReleaseNamespace()).Create(ctx, rev, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// CreateCourseRevision stores the current spec of a course as revision course.Spec.Revision.
+// Revisions are owned by their course and never updated.
+func CreateCourseRevision(ctx context.Context, hfClientSet hfClientset.Interface, course *hfv1.Course, creator string) error {
+	rev := &hfv1.CourseRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CourseRevisionName(course.Name, course.Spec.Revision),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "hobbyfarm.io/v1",
+					Kind:       "Course",
+					Name:       course.Name,
+					UID:        course.UID,
+				},
+			},
+			Labels: map[string]string{
+				util.CourseLabel: course.Name,
+				RevisionLabel:    strconv.Itoa(course.Spec.Revision),
+			},
+		},
+		Spec: hfv1.CourseRevisionSpec{
+			Course:   course.Name,
+			Revision: course.Spec.Revision,
+			Creator:  creator,
+			Created:  time.Now().Format(time.UnixDate),
+			Snapshot: *course.Spec.DeepCopy(),
+		},
+	}
+
+	_, err := hfClientSet.HobbyfarmV1().CourseRevisions(util.GetReleaseNamespace()).Create(ctx, rev, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// ScenarioSpec returns the spec of a scenario as it was at the given revision.
+// Revision 0 is what sessions from before revisions carry, they started on the first revision.
+func ScenarioSpec(ctx context.Context, hfClientSet hfClientset.Interface, scenario hfv1.Scenario, revision int) (hfv1.ScenarioSpec, error) {
+	if revision == 0 {
+		revision = 1
+	}
+	if scenario.Spec.Revision == 0 || scenario.Spec.Revision == revision {
+		return scenario.Spec, nil
+	}
+
+	rev, err := hfClientSet.HobbyfarmV1().ScenarioRevisions(util.GetReleaseNamespace()).Get(ctx, ScenarioRevisionName(scenario.Name, revision), metav1.GetOptions{})
+	if err != nil {
+		return hfv1.ScenarioSpec{}, fmt.Errorf("error retrieving revision %d of scenario %s: %v", revision, scenario.Name, err)
+	}
+
+	return rev.Spec.Snapshot, nil
+}
+
+// CourseSpec returns the spec of a course as it was at the given revision.
+// Revision 0 is what sessions from before revisions carry, they started on the first revision.
+func CourseSpec(ctx context.Context, hfClientSet hfClientset.Interface, course hfv1.Course, revision int) (hfv1.CourseSpec, error) {
+	if revision == 0 {
+		revision = 1
+	}
+	if course.Spec.Revision == 0 || course.Spec.Revision == revision {
+		return course.Spec, nil
+	}
+
+	rev, err := hfClientSet.HobbyfarmV1().CourseRevisions(util.GetReleaseNamespace()).Get(ctx, CourseRevisionName(course.Name, revision), metav1.GetOptions{})
+	if err != nil {
+		return hfv1.CourseSpec{}, fmt.Errorf("error retrieving revision %d of course %s: %v", revision, course.Name, err)
+	}
+
+	return rev.Spec.Snapshot, nil
+}
+
+// ListScenarioRevisions returns all revisions of a scenario, oldest first
+func ListScenarioRevisions(ctx context.Context, hfClientSet hfClientset.Interface, scenario string) ([]hfv1.ScenarioRevision, error) {
+	list, err := hfClientSet.HobbyfarmV1().ScenarioRevisions(util.GetReleaseNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScenarioLabel, scenario),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := list.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+	})
+
+	return revisions, nil
+}
+
+// ListCourseRevisions returns all revisions of a course, oldest first
+func ListCourseRevisions(ctx context.Context, hfClientSet hfClientset.Interface, course string) ([]hfv1.CourseRevision, error) {
+	list, err := hfClientSet.HobbyfarmV1().CourseRevisions(util.GetReleaseNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.CourseLabel, course),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := list.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+	})
+
+	return revisions, nil
+}
+
+// Pin returns the current revision of every given scenario and course. Content that no longer exists is skipped.
+// Content from before revisions is pinned to revision 1, which is recorded as soon as it is first changed.
+func Pin(ctx context.Context, hfClientSet hfClientset.Interface, scenarios []string, courses []string) (map[string]int, map[string]int, error) {
+	scenarioRevisions := map[string]int{}
+	courseRevisions := map[string]int{}
+
+	for _, id := range courses {
+		course, err := hfClientSet.HobbyfarmV1().Courses(util.GetReleaseNamespace()).Get(ctx, id, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error retrieving course %s: %v", id, err)
+		}
+		courseRevisions[id] = max(course.Spec.Revision, 1)
+		scenarios = append(scenarios, course.Spec.Scenarios...)
+	}
+
+	for _, id := range scenarios {
+		scenario, err := hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(ctx, id, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error retrieving scenario %s: %v", id, err)
+		}
+		scenarioRevisions[id] = max(scenario.Spec.Revision, 1)
+	}
+
+	return scenarioRevisions, courseRevisions, nil
+}
+
+// Pinned returns the revision pinned for id, or current if nothing was pinned
+func Pinned(pinned map[string]int, id string, current int) int {
+	if revision, ok := pinned[id]; ok && revision > 0 {
+		return revision
+	}
+	return current
+}
+
+// Diff compares two specs field by field. Nested fields are named like "steps[1].content".
+func Diff(from interface{}, to interface{}) ([]Change, error) {
+	fromValue, err := toGeneric(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := toGeneric(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	diff("", fromValue, toValue, &changes)
+	return changes, nil
+}
+
+func toGeneric(obj interface{}) (interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(raw, &generic)
+	return generic, err
+}
+
+func diff(field string, from interface{}, to interface{}, changes *[]Change) {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := []string{}
+		for k := range fromMap {
+			keys = append(keys, k)
+		}
+		for k := range toMap {
+			if _, ok := fromMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			name := k
+			if field != "" {
+				name = field + "." + k
+			}
+			diff(name, fromMap[k], toMap[k], changes)
+		}
+		return
+	}
+
+	fromSlice, fromIsSlice := from.([]interface{})
+	toSlice, toIsSlice := to.([]interface{})
+	if fromIsSlice && toIsSlice {
+		length := len(fromSlice)
+		if len(toSlice) > length {
+			length = len(toSlice)
+		}
+
+		for i := 0; i < length; i++ {
+			var f, t interface{}
+			if i < len(fromSlice) {
+				f = fromSlice[i]
+			}
+			if i < len(toSlice) {
+				t = toSlice[i]
+			}
+			diff(fmt.Sprintf("%s[%d]", field, i), f, t, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, Change{Field: field, From: from, To: to})
+	}
+}
@@ -0,0 +1,130 @@
+package revision
+
+import (
+	"reflect"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+func Test_Pinned(t *testing.T) {
+	pinned := map[string]int{"sc-pinned": 2, "sc-zero": 0}
+
+	tests := []struct {
+		name    string
+		pinned  map[string]int
+		id      string
+		current int
+		want    int
+	}{
+		{"pinned revision wins", pinned, "sc-pinned", 5, 2},
+		{"unpinned falls back to current", pinned, "sc-other", 5, 5},
+		{"revision 0 is not pinned", pinned, "sc-zero", 5, 5},
+		{"nil map falls back to current", nil, "sc-pinned", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pinned(tt.pinned, tt.id, tt.current); got != tt.want {
+				t.Errorf("Pinned() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	base := hfv1.ScenarioSpec{
+		Name:  "intro",
+		Steps: []hfv1.ScenarioStep{{Title: "one", Content: "first"}, {Title: "two", Content: "second"}},
+		Tags:  []string{"a"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(spec *hfv1.ScenarioSpec)
+		want   []Change
+	}{
+		{
+			name:   "no changes",
+			modify: func(spec *hfv1.ScenarioSpec) {},
+			want:   []Change{},
+		},
+		{
+			name:   "top level field",
+			modify: func(spec *hfv1.ScenarioSpec) { spec.Name = "intro v2" },
+			want:   []Change{{Field: "name", From: "intro", To: "intro v2"}},
+		},
+		{
+			name: "nested step content",
+			modify: func(spec *hfv1.ScenarioSpec) {
+				spec.Steps = []hfv1.ScenarioStep{spec.Steps[0], {Title: "two", Content: "changed"}}
+			},
+			want: []Change{{Field: "steps[1].content", From: "second", To: "changed"}},
+		},
+		{
+			name: "added step",
+			modify: func(spec *hfv1.ScenarioSpec) {
+				spec.Steps = append(append([]hfv1.ScenarioStep{}, spec.Steps...), hfv1.ScenarioStep{Title: "three"})
+			},
+			want: []Change{{Field: "steps[2]", To: map[string]interface{}{"title": "three", "content": ""}}},
+		},
+		{
+			name: "removed list item",
+			modify: func(spec *hfv1.ScenarioSpec) {
+				spec.Tags = []string{}
+			},
+			want: []Change{{Field: "tags[0]", From: "a"}},
+		},
+		{
+			name: "fields are sorted",
+			modify: func(spec *hfv1.ScenarioSpec) {
+				spec.Pauseable = true
+				spec.Description = "changed"
+			},
+			want: []Change{
+				{Field: "description", From: "", To: "changed"},
+				{Field: "pauseable", From: false, To: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := base
+			tt.modify(&to)
+
+			got, err := Diff(base, to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Published(t *testing.T) {
+	tests := []struct {
+		raw       string
+		published bool
+		wantErr   bool
+	}{
+		{"", true, false},
+		{string(hfv1.ContentStatePublished), true, false},
+		{string(hfv1.ContentStateDraft), false, false},
+		{"archived", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			state, err := ParseState(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseState(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if err == nil && Published(state) != tt.published {
+				t.Errorf("Published(%q) = %v, want %v", state, Published(state), tt.published)
+			}
+		})
+	}
+}
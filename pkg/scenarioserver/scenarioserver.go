@@ -17,11 +17,20 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/contenttemplate"
 	"github.com/hobbyfarm/gargantua/v3/pkg/courseclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/lint"
+	"github.com/hobbyfarm/gargantua/v3/pkg/locale"
+	"github.com/hobbyfarm/gargantua/v3/pkg/quiz"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
+	"github.com/hobbyfarm/gargantua/v3/pkg/stepcheck"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"golang.org/x/text/language"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
@@ -39,11 +48,27 @@ type ScenarioServer struct {
 	scenarioIndexer cache.Indexer
 	ctx             context.Context
 	courseClient    *courseclient.CourseClient
+	contentBuilder  *contenttemplate.Builder
 }
 
 type PreparedScenarioStep struct {
-	Title   string `json:"title"`
+	Title      string                  `json:"title"`
+	Content    string                  `json:"content"`
+	CheckCount int                     `json:"check_count"`
+	Gated      bool                    `json:"gated"`
+	Questions  []quiz.PreparedQuestion `json:"questions,omitempty"`
+}
+
+type PreparedRevision struct {
+	Revision int               `json:"revision"`
+	Creator  string            `json:"creator"`
+	Created  string            `json:"created"`
+	State    hfv1.ContentState `json:"state"`
+}
+
+type PreparedContentPreview struct {
 	Content string `json:"content"`
+	Error   string `json:"error,omitempty"`
 }
 
 type PreparedScenario struct {
@@ -54,6 +79,13 @@ type PreparedScenario struct {
 	VirtualMachines []map[string]string `json:"virtualmachines"`
 	Pauseable       bool                `json:"pauseable"`
 	Printable       bool                `json:"printable"`
+	Locale          string              `json:"locale,omitempty"`
+}
+
+type PreparedTranslations struct {
+	ID           string          `json:"id"`
+	Locale       string          `json:"locale"`
+	Translations []locale.Report `json:"translations"`
 }
 
 type AdminPreparedScenario struct {
@@ -76,6 +108,7 @@ func NewScenarioServer(authClient *authclient.AuthClient, acClient *accesscode.A
 	}
 	scenario.scenarioIndexer = inf.GetIndexer()
 	scenario.ctx = ctx
+	scenario.contentBuilder = contenttemplate.NewBuilder(hfClientset, ctx)
 	return &scenario, nil
 }
 
@@ -84,15 +117,23 @@ func (s ScenarioServer) SetupRoutes(r *mux.Router) {
 	r.HandleFunc("/a/scenario/categories", s.ListCategories).Methods("GET")
 	r.HandleFunc("/a/scenario/list/{category}", s.ListByCategoryFunc).Methods("GET")
 	r.HandleFunc("/a/scenario/list", s.ListAllFunc).Methods("GET")
+	r.HandleFunc("/a/scenario/translations", s.ListTranslationsFunc).Methods("GET")
 	r.HandleFunc("/a/scenario/{id}", s.AdminGetFunc).Methods("GET")
 	r.HandleFunc("/a/scenario/{id}", s.AdminDeleteFunc).Methods("DELETE")
 	r.HandleFunc("/scenario/{scenario_id}", s.GetScenarioFunc).Methods("GET")
 	r.HandleFunc("/scenario/{id}/printable", s.PrintFunc).Methods("GET")
 	r.HandleFunc("/a/scenario/{id}/printable", s.AdminPrintFunc).Methods("GET")
 	r.HandleFunc("/a/scenario/new", s.CreateFunc).Methods("POST")
+	r.HandleFunc("/a/scenario/lint", s.LintFunc).Methods("POST")
 	r.HandleFunc("/a/scenario/copy/{id}", s.CopyFunc).Methods("POST")
 	r.HandleFunc("/a/scenario/{id}", s.UpdateFunc).Methods("PUT")
 	r.HandleFunc("/scenario/{scenario_id}/step/{step_id:[0-9]+}", s.GetScenarioStepFunc).Methods("GET")
+	r.HandleFunc("/a/scenario/{id}/step/{step_id:[0-9]+}/preview", s.PreviewStepFunc).Methods("POST")
+	r.HandleFunc("/a/scenario/{id}/translations", s.GetTranslationsFunc).Methods("GET")
+	r.HandleFunc("/a/scenario/{id}/revisions", s.ListRevisionsFunc).Methods("GET")
+	r.HandleFunc("/a/scenario/{id}/revision/{revision:[0-9]+}", s.GetRevisionFunc).Methods("GET")
+	r.HandleFunc("/a/scenario/{id}/revision/{revision:[0-9]+}/rollback", s.RollbackFunc).Methods("POST")
+	r.HandleFunc("/a/scenario/{id}/diff/{from:[0-9]+}/{to:[0-9]+}", s.DiffFunc).Methods("GET")
 	glog.V(2).Infof("set up route")
 }
 
@@ -106,19 +147,21 @@ func (s ScenarioServer) prepareScenario(scenario hfv1.Scenario, printable bool)
 	ps.Pauseable = scenario.Spec.Pauseable
 	ps.Printable = printable
 	ps.StepCount = len(scenario.Spec.Steps)
+	ps.Locale = scenario.Spec.Locale
 
 	return ps, nil
 }
 
-func (s ScenarioServer) getPreparedScenarioStepById(id string, step int) (PreparedScenarioStep, error) {
-	scenario, err := s.GetScenarioById(id)
-	if err != nil {
-		return PreparedScenarioStep{}, fmt.Errorf("error while retrieving scenario step")
-	}
-
+func prepareScenarioStep(scenario hfv1.Scenario, step int) (PreparedScenarioStep, error) {
 	if step >= 0 && len(scenario.Spec.Steps) > step {
 		stepContent := scenario.Spec.Steps[step]
-		return PreparedScenarioStep{stepContent.Title, stepContent.Content}, nil
+		return PreparedScenarioStep{
+			Title:      stepContent.Title,
+			Content:    stepContent.Content,
+			CheckCount: len(stepContent.Checks),
+			Gated:      stepContent.Gated,
+			Questions:  quiz.Prepare(stepContent.Questions),
+		}, nil
 	}
 
 	return PreparedScenarioStep{}, fmt.Errorf("error while retrieving scenario step, most likely doesn't exist in index")
@@ -154,13 +197,25 @@ func (s ScenarioServer) getPrintableScenarioIds(accessCodes []string) []string {
 	return printableScenarioIds
 }
 
-func (s ScenarioServer) getPreparedScenarioById(id string, accessCodes []string) (PreparedScenario, error) {
+func (s ScenarioServer) getPreparedScenarioById(id string, accessCodes []string, session *hfv1.Session, preferred []language.Tag) (PreparedScenario, error) {
 	scenario, err := s.GetScenarioById(id)
 
 	if err != nil {
 		return PreparedScenario{}, fmt.Errorf("error while retrieving scenario %v", err)
 	}
 
+	// sessions keep the revision they were started with
+	if session != nil {
+		scenario.Spec, err = revision.ScenarioSpec(s.ctx, s.hfClientSet, scenario, session.Spec.ScenarioRevision)
+		if err != nil {
+			return PreparedScenario{}, err
+		}
+	} else if !revision.Published(scenario.Spec.State) {
+		// drafts are only shown to sessions that were started on them
+		return PreparedScenario{}, fmt.Errorf("scenario %s is a draft", id)
+	}
+	scenario.Spec, _ = locale.Scenario(scenario.Spec, preferred)
+
 	printableScenarioIds := s.getPrintableScenarioIds(accessCodes)
 	printable := util.StringInSlice(scenario.Name, printableScenarioIds)
 
@@ -189,7 +244,17 @@ func (s ScenarioServer) GetScenarioFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	scenario, err := s.getPreparedScenarioById(scenario_id, user.Spec.AccessCodes)
+	var session *hfv1.Session
+	sessionId := r.URL.Query().Get("session")
+	if sessionId != "" {
+		session, err = s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, sessionId, metav1.GetOptions{})
+		if err != nil || !util.IsSessionMember(session, user.Name) || session.Spec.ScenarioId != scenario_id {
+			util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("session %s not found", sessionId))
+			return
+		}
+	}
+
+	scenario, err := s.getPreparedScenarioById(scenario_id, user.Spec.AccessCodes, session, locale.Preferred(r, user.Spec.Settings))
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s not found", vars["scenario_id"]))
 		return
@@ -257,7 +322,7 @@ func (s ScenarioServer) AdminDeleteFunc(w http.ResponseWriter, r *http.Request)
 	// 2. when there are no sessions using the scenario
 	// 3. when there is no course using the scenario
 
-	seList, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
+	seList, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
 	if err != nil {
 		glog.Errorf("error retrieving scheduledevent list: %v", err)
 		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error while deleting scenario")
@@ -329,7 +394,7 @@ func (s ScenarioServer) AdminDeleteFunc(w http.ResponseWriter, r *http.Request)
 }
 
 func (s ScenarioServer) GetScenarioStepFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := s.auth.AuthN(w, r)
+	user, err := s.auth.AuthN(w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scenario steps")
 		return
@@ -342,11 +407,53 @@ func (s ScenarioServer) GetScenarioStepFunc(w http.ResponseWriter, r *http.Reque
 		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s step %s not found", vars["scenario_id"], vars["step_id"]))
 		return
 	}
-	step, err := s.getPreparedScenarioStepById(vars["scenario_id"], stepId)
+	scenario, err := s.GetScenarioById(vars["scenario_id"])
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s not found", vars["scenario_id"]))
 		return
 	}
+
+	var session *hfv1.Session
+	sessionId := r.URL.Query().Get("session")
+	if sessionId != "" {
+		session, err = s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, sessionId, metav1.GetOptions{})
+		if err != nil || !util.IsSessionMember(session, user.Name) || session.Spec.ScenarioId != vars["scenario_id"] {
+			util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("session %s not found", sessionId))
+			return
+		}
+
+		// sessions keep the revision they were started with
+		scenario.Spec, err = revision.ScenarioSpec(s.ctx, s.hfClientSet, scenario, session.Spec.ScenarioRevision)
+		if err != nil {
+			glog.Errorf("error retrieving scenario for session %s: %v", sessionId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving scenario revision")
+			return
+		}
+	} else if !revision.Published(scenario.Spec.State) {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s not found", vars["scenario_id"]))
+		return
+	}
+	scenario.Spec, _ = locale.Scenario(scenario.Spec, locale.Preferred(r, user.Spec.Settings))
+
+	step, err := prepareScenarioStep(scenario, stepId)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s step %s not found", vars["scenario_id"], vars["step_id"]))
+		return
+	}
+
+	// steps of templated scenarios fetched in the context of a session get the variables of that session filled in
+	if session != nil && scenario.Spec.Templated {
+		templateVars, err := s.contentBuilder.ForSession(session, user)
+		if err != nil {
+			glog.Errorf("error building content variables for session %s: %v", sessionId, err)
+		} else if content, err := renderContent(step.Content, templateVars); err != nil {
+			// templates are checked when the scenario is saved, content that still does not render is shown as written
+			glog.Errorf("error rendering step %d of scenario %s for session %s: %v", stepId, session.Spec.ScenarioId, sessionId, err)
+		} else {
+			step.Content = content
+		}
+	}
+
 	encodedStep, err := json.Marshal(step)
 	if err != nil {
 		glog.Error(err)
@@ -384,6 +491,19 @@ func (s ScenarioServer) ListScenariosForAccessCode(w http.ResponseWriter, r *htt
 	}
 	scenarioIds = append(scenarioIds, ac.Spec.Scenarios...)
 
+	// learners see the revisions pinned by the scheduled event
+	pinned := map[string]int{}
+	if seId := ac.Labels[util.ScheduledEventLabel]; seId != "" {
+		se, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, seId, metav1.GetOptions{})
+		if err != nil {
+			glog.Errorf("error retrieving scheduled event %s: %v", seId, err)
+		} else {
+			pinned = se.Status.ScenarioRevisions
+		}
+	}
+
+	preferred := locale.Preferred(r, user.Spec.Settings)
+
 	var scenarios []PreparedScenario
 	for _, scenarioId := range scenarioIds {
 		scenario, err := s.GetScenarioById(scenarioId)
@@ -391,6 +511,15 @@ func (s ScenarioServer) ListScenariosForAccessCode(w http.ResponseWriter, r *htt
 			glog.Errorf("error retrieving scenario %v", err)
 			continue
 		}
+		scenario.Spec, err = revision.ScenarioSpec(s.ctx, s.hfClientSet, scenario, revision.Pinned(pinned, scenario.Name, scenario.Spec.Revision))
+		if err != nil {
+			glog.Errorf("error retrieving scenario revision %v", err)
+			continue
+		}
+		if !revision.Published(scenario.Spec.State) {
+			continue
+		}
+		scenario.Spec, _ = locale.Scenario(scenario.Spec, preferred)
 		pScenario, err := s.prepareScenario(scenario, ac.Spec.Printable)
 		if err != nil {
 			glog.Errorf("error preparing scenario %v", err)
@@ -585,6 +714,7 @@ func (s ScenarioServer) PrintFunc(w http.ResponseWriter, r *http.Request) {
 		util.ReturnHTTPMessage(w, r, 500, "error", "no scenario found")
 		return
 	}
+	scenario.Spec, _ = locale.Scenario(scenario.Spec, locale.Preferred(r, user.Spec.Settings))
 
 	var content string
 
@@ -622,7 +752,7 @@ func (s ScenarioServer) PrintFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s ScenarioServer) CopyFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := s.auth.AuthGrant(
+	user, err := s.auth.AuthGrant(
 		rbacclient.RbacRequest().
 			HobbyfarmPermission(resourcePlural, rbacclient.VerbCreate).
 			HobbyfarmPermission(resourcePlural, rbacclient.VerbGet),
@@ -660,10 +790,15 @@ func (s ScenarioServer) CopyFunc(w http.ResponseWriter, r *http.Request) {
 		sha := base32.StdEncoding.WithPadding(-1).EncodeToString(hasher.Sum(nil))[:10]
 
 		scenario.Spec.Name = copyName
+		scenario.Spec.Revision = 1
 		scenario.Name = "s-" + strings.ToLower(sha)
 
-		_, updateErr := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Create(s.ctx, scenario, metav1.CreateOptions{})
-		return updateErr
+		created, updateErr := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Create(s.ctx, scenario, metav1.CreateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return revision.CreateScenarioRevision(s.ctx, s.hfClientSet, created, user.Name)
 	})
 
 	if retryErr != nil {
@@ -676,7 +811,7 @@ func (s ScenarioServer) CopyFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s ScenarioServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbCreate), w, r)
+	user, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbCreate), w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to create scenarios")
 		return
@@ -743,6 +878,33 @@ func (s ScenarioServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 
 	pauseable := r.PostFormValue("pauseable")
 	pauseDuration := r.PostFormValue("pause_duration")
+	templated := r.PostFormValue("templated")
+
+	sessionPolicy := hfv1.SessionPolicy{}
+	rawSessionPolicy := r.PostFormValue("session_policy")
+	if rawSessionPolicy != "" {
+		err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshaling session policy %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return
+		}
+	}
+
+	translations := map[string]hfv1.ScenarioTranslation{}
+	rawTranslations := r.PostFormValue("translations")
+	if rawTranslations != "" {
+		err = json.Unmarshal([]byte(rawTranslations), &translations)
+		if err != nil {
+			glog.Errorf("error while unmarshaling translations %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+	}
 
 	scenario := &hfv1.Scenario{}
 
@@ -770,6 +932,38 @@ func (s ScenarioServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		scenario.Spec.PauseDuration = pauseDuration
 	}
 
+	scenario.Spec.Templated = strings.ToLower(templated) == "true"
+	scenario.Spec.SessionPolicy = sessionPolicy
+	scenario.Spec.Locale = r.PostFormValue("locale")
+	scenario.Spec.Translations = translations
+
+	scenario.Spec.State, err = revision.ParseState(r.PostFormValue("state"))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+	scenario.Spec.Revision = 1
+
+	if err = validateStepChecks(steps, virtualmachines); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	if err = validateStepQuestions(steps); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	if err = locale.ValidateScenario(scenario.Spec); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	if err = s.validateStepTemplates(scenario); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
 	scenario, err = s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Create(s.ctx, scenario, metav1.CreateOptions{})
 	if err != nil {
 		glog.Errorf("error creating scenario %v", err)
@@ -777,12 +971,17 @@ func (s ScenarioServer) CreateFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = revision.CreateScenarioRevision(s.ctx, s.hfClientSet, scenario, user.Name)
+	if err != nil {
+		glog.Errorf("error creating revision of scenario %s: %v", scenario.Name, err)
+	}
+
 	util.ReturnHTTPMessage(w, r, 201, "created", scenario.Name)
 	return
 }
 
 func (s ScenarioServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
+	user, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scenarios")
 		return
@@ -804,15 +1003,29 @@ func (s ScenarioServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			return fmt.Errorf("bad")
 		}
 
+		// make sure the revision about to be replaced is recorded, scenarios from before revisions start at 1
+		if scenario.Spec.Revision == 0 {
+			scenario.Spec.Revision = 1
+		}
+		if err = revision.CreateScenarioRevision(s.ctx, s.hfClientSet, scenario, ""); err != nil {
+			return err
+		}
+		previous := *scenario.Spec.DeepCopy()
+
 		name := r.PostFormValue("name")
 		description := r.PostFormValue("description")
 		rawSteps := r.PostFormValue("steps")
 		pauseable := r.PostFormValue("pauseable")
 		pauseDuration := r.PostFormValue("pause_duration")
+		templated := r.PostFormValue("templated")
 		keepaliveDuration := r.PostFormValue("keepalive_duration")
 		rawVirtualMachines := r.PostFormValue("virtualmachines")
 		rawCategories := r.PostFormValue("categories")
 		rawTags := r.PostFormValue("tags")
+		rawSessionPolicy := r.PostFormValue("session_policy")
+		rawState := r.PostFormValue("state")
+		rawLocale := r.PostFormValue("locale")
+		rawTranslations := r.PostFormValue("translations")
 
 		if name != "" {
 			scenario.Spec.Name = name
@@ -836,6 +1049,10 @@ func (s ScenarioServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			scenario.Spec.PauseDuration = pauseDuration
 		}
 
+		if templated != "" {
+			scenario.Spec.Templated = strings.ToLower(templated) == "true"
+		}
+
 		if rawSteps != "" {
 			steps := []hfv1.ScenarioStep{}
 
@@ -894,8 +1111,74 @@ func (s ScenarioServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 			scenario.Spec.Tags = tagsSlice
 		}
 
-		_, updateErr := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Update(s.ctx, scenario, metav1.UpdateOptions{})
-		return updateErr
+		if rawSessionPolicy != "" {
+			sessionPolicy := hfv1.SessionPolicy{}
+			err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshaling session policy %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return fmt.Errorf("bad")
+			}
+			scenario.Spec.SessionPolicy = sessionPolicy
+		}
+
+		if rawState != "" {
+			scenario.Spec.State, err = revision.ParseState(rawState)
+			if err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return fmt.Errorf("bad")
+			}
+		}
+
+		if rawLocale != "" {
+			scenario.Spec.Locale = rawLocale
+		}
+
+		if rawTranslations != "" {
+			translations := map[string]hfv1.ScenarioTranslation{}
+			err = json.Unmarshal([]byte(rawTranslations), &translations)
+			if err != nil {
+				glog.Errorf("error while unmarshaling translations %v", err)
+				util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+				return fmt.Errorf("bad")
+			}
+			scenario.Spec.Translations = translations
+		}
+
+		if err = validateStepChecks(scenario.Spec.Steps, scenario.Spec.VirtualMachines); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if err = validateStepQuestions(scenario.Spec.Steps); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if err = locale.ValidateScenario(scenario.Spec); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if err = s.validateStepTemplates(scenario); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return fmt.Errorf("bad")
+		}
+
+		if revision.ScenarioChanged(previous, scenario.Spec) {
+			scenario.Spec.Revision = previous.Revision + 1
+		}
+
+		updated, updateErr := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Update(s.ctx, scenario, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return revision.CreateScenarioRevision(s.ctx, s.hfClientSet, updated, user.Name)
 	})
 
 	if retryErr != nil {
@@ -907,6 +1190,401 @@ func (s ScenarioServer) UpdateFunc(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+func (s ScenarioServer) ListRevisionsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scenarios")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	revisions, err := revision.ListScenarioRevisions(s.ctx, s.hfClientSet, id)
+	if err != nil {
+		glog.Errorf("error listing revisions of scenario %s: %v", id, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error listing revisions")
+		return
+	}
+
+	preparedRevisions := []PreparedRevision{}
+	for _, rev := range revisions {
+		preparedRevisions = append(preparedRevisions, PreparedRevision{
+			Revision: rev.Spec.Revision,
+			Creator:  rev.Spec.Creator,
+			Created:  rev.Spec.Created,
+			State:    rev.Spec.Snapshot.State,
+		})
+	}
+
+	encodedRevisions, err := json.Marshal(preparedRevisions)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedRevisions)
+}
+
+func (s ScenarioServer) GetRevisionFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scenarios")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	spec, ok := s.getRevisionSpec(w, r, vars["id"], vars["revision"])
+	if !ok {
+		return
+	}
+
+	encodedSpec, err := json.Marshal(spec)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedSpec)
+}
+
+func (s ScenarioServer) DiffFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scenarios")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	from, ok := s.getRevisionSpec(w, r, vars["id"], vars["from"])
+	if !ok {
+		return
+	}
+	to, ok := s.getRevisionSpec(w, r, vars["id"], vars["to"])
+	if !ok {
+		return
+	}
+
+	changes, err := revision.Diff(from, to)
+	if err != nil {
+		glog.Errorf("error comparing revisions of scenario %s: %v", vars["id"], err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error comparing revisions")
+		return
+	}
+
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedChanges)
+}
+
+// RollbackFunc restores the content of an older revision. The restored content becomes a new revision, history is never rewritten.
+func (s ScenarioServer) RollbackFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scenarios")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	target, ok := s.getRevisionSpec(w, r, id, vars["revision"])
+	if !ok {
+		return
+	}
+
+	var restored int
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scenario, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if scenario.Spec.Revision == 0 {
+			scenario.Spec.Revision = 1
+		}
+		if err = revision.CreateScenarioRevision(s.ctx, s.hfClientSet, scenario, ""); err != nil {
+			return err
+		}
+
+		spec := *target.DeepCopy()
+		spec.State = scenario.Spec.State
+		spec.Revision = scenario.Spec.Revision + 1
+		scenario.Spec = spec
+
+		updated, updateErr := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).Update(s.ctx, scenario, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		restored = updated.Spec.Revision
+		return revision.CreateScenarioRevision(s.ctx, s.hfClientSet, updated, user.Name)
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error rolling back scenario %s: %v", id, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error attempting to roll back")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", strconv.Itoa(restored))
+}
+
+func (s ScenarioServer) getRevisionSpec(w http.ResponseWriter, r *http.Request, id string, rawRevision string) (hfv1.ScenarioSpec, bool) {
+	rev, err := strconv.Atoi(rawRevision)
+	if err != nil || rev < 1 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid revision")
+		return hfv1.ScenarioSpec{}, false
+	}
+
+	scenario, err := s.GetScenarioById(id)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s not found", id))
+		return hfv1.ScenarioSpec{}, false
+	}
+
+	if rev > scenario.Spec.Revision && rev > 1 {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("revision %d of scenario %s not found", rev, id))
+		return hfv1.ScenarioSpec{}, false
+	}
+
+	spec, err := revision.ScenarioSpec(s.ctx, s.hfClientSet, scenario, rev)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("revision %d of scenario %s not found", rev, id))
+		return hfv1.ScenarioSpec{}, false
+	}
+
+	return spec, true
+}
+
+/*
+Preview the rendered content of a step
+
+	Vars:
+	- id : Scenario the step belongs to
+	- step_id : Step to preview
+
+	Form:
+	- content : base64 encoded content to render instead of the stored one (optional)
+	- session : Session to take the variables from, placeholders are used if empty (optional)
+*/
+// LintFunc checks the scenario passed as json in the form value scenario without saving it.
+// It returns errors, which the validation webhook would reject, as well as warnings.
+func (s ScenarioServer) LintFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to lint scenarios")
+		return
+	}
+
+	spec := hfv1.ScenarioSpec{}
+	err = json.Unmarshal([]byte(r.PostFormValue("scenario")), &spec)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid scenario")
+		return
+	}
+
+	encodedIssues, err := json.Marshal(lint.Scenario(s.ctx, s.hfClientSet, spec))
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedIssues)
+}
+
+// ListTranslationsFunc reports how complete the translations of every scenario are
+func (s ScenarioServer) ListTranslationsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list scenarios")
+		return
+	}
+
+	scenarios, err := s.hfClientSet.HobbyfarmV1().Scenarios(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
+	if err != nil {
+		glog.Errorf("error while retrieving scenarios %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "no scenarios found")
+		return
+	}
+
+	preparedTranslations := []PreparedTranslations{}
+	for _, scenario := range scenarios.Items {
+		preparedTranslations = append(preparedTranslations, PreparedTranslations{scenario.Name, scenario.Spec.Locale, locale.ScenarioCompleteness(scenario.Spec)})
+	}
+
+	encodedTranslations, err := json.Marshal(preparedTranslations)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedTranslations)
+}
+
+// GetTranslationsFunc reports how complete the translations of a scenario are, listing the fields that are missing
+func (s ScenarioServer) GetTranslationsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get Scenario")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	scenario, err := s.GetScenarioById(id)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", fmt.Sprintf("scenario %s not found", id))
+		return
+	}
+
+	encodedTranslations, err := json.Marshal(PreparedTranslations{scenario.Name, scenario.Spec.Locale, locale.ScenarioCompleteness(scenario.Spec)})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedTranslations)
+}
+
+func (s ScenarioServer) PreviewStepFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to preview scenario steps")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	scenario, err := s.GetScenarioById(vars["id"])
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s not found", vars["id"]))
+		return
+	}
+
+	stepId, err := strconv.Atoi(vars["step_id"])
+	if err != nil || stepId < 0 || stepId >= len(scenario.Spec.Steps) {
+		util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("scenario %s step %s not found", vars["id"], vars["step_id"]))
+		return
+	}
+
+	content := r.PostFormValue("content")
+	if content == "" {
+		content = scenario.Spec.Steps[stepId].Content
+	}
+
+	templateVars := s.contentBuilder.Sample(&scenario)
+	sessionId := r.PostFormValue("session")
+	if sessionId != "" {
+		session, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(s.ctx, sessionId, metav1.GetOptions{})
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 404, "not found", fmt.Sprintf("session %s not found", sessionId))
+			return
+		}
+
+		// the preview shows the step the way the learner of the session sees it
+		owner, err := s.hfClientSet.HobbyfarmV2().Users(util.GetReleaseNamespace()).Get(s.ctx, session.Spec.UserId, metav1.GetOptions{})
+		if err != nil {
+			glog.Errorf("error retrieving user %s of session %s: %v", session.Spec.UserId, sessionId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving session variables")
+			return
+		}
+
+		templateVars, err = s.contentBuilder.ForSession(session, *owner)
+		if err != nil {
+			glog.Errorf("error building content variables for session %s: %v", sessionId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving session variables")
+			return
+		}
+	}
+
+	preview := PreparedContentPreview{}
+	if !scenario.Spec.Templated {
+		// learners see the content as written
+		preview.Content = content
+	} else if rendered, err := renderContent(content, templateVars); err != nil {
+		preview.Error = err.Error()
+	} else {
+		preview.Content = rendered
+	}
+
+	encodedPreview, err := json.Marshal(preview)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedPreview)
+}
+
+// renderContent renders base64 encoded step content and returns it encoded again
+func renderContent(content string, vars map[string]interface{}) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", fmt.Errorf("content is not base64 encoded: %v", err)
+	}
+
+	rendered, err := contenttemplate.Render(string(decoded), vars)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(rendered)), nil
+}
+
+// validateStepTemplates renders the steps of a templated scenario and their translations with placeholder variables,
+// so that authors learn about broken templates when saving instead of learners seeing them unrendered
+func (s ScenarioServer) validateStepTemplates(scenario *hfv1.Scenario) error {
+	if !scenario.Spec.Templated {
+		return nil
+	}
+
+	vars := s.contentBuilder.Sample(scenario)
+	for i, step := range scenario.Spec.Steps {
+		if _, err := renderContent(step.Content, vars); err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+	}
+	for lang, translation := range scenario.Spec.Translations {
+		for i, step := range translation.Steps {
+			if step.Content == "" {
+				continue
+			}
+			if _, err := renderContent(step.Content, vars); err != nil {
+				return fmt.Errorf("step %d translation %s: %v", i, lang, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateStepChecks makes sure every check of every step can be run and targets a vm of the scenario
+func validateStepChecks(steps []hfv1.ScenarioStep, virtualMachines []map[string]string) error {
+	for i, step := range steps {
+		for j, check := range step.Checks {
+			if err := stepcheck.Validate(check); err != nil {
+				return fmt.Errorf("step %d check %d: %v", i, j, err)
+			}
+
+			found := false
+			for _, vmSet := range virtualMachines {
+				if _, ok := vmSet[check.VirtualMachine]; ok {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("step %d check %d: vm %s is not part of the scenario", i, j, check.VirtualMachine)
+			}
+		}
+	}
+	return nil
+}
+
+// validateStepQuestions makes sure every question of every step can be graded
+func validateStepQuestions(steps []hfv1.ScenarioStep) error {
+	for i, step := range steps {
+		for j, question := range step.Questions {
+			if err := quiz.Validate(question); err != nil {
+				return fmt.Errorf("step %d question %d: %v", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s ScenarioServer) GetScenarioById(id string) (hfv1.Scenario, error) {
 	if len(id) == 0 {
 		return hfv1.Scenario{}, fmt.Errorf("scenario id passed in was blank")
@@ -932,10 +1610,10 @@ func (s ScenarioServer) GetScenarioById(id string) (hfv1.Scenario, error) {
 }
 
 // Filter a ScheduledEventList to find SEs that are a) active and b) using the course specified
-func filterScheduledEvents(scenario string, seList *hfv1.ScheduledEventList) *[]hfv1.ScheduledEvent {
-	outList := make([]hfv1.ScheduledEvent, 0)
+func filterScheduledEvents(scenario string, seList *hfv2.ScheduledEventList) *[]hfv2.ScheduledEvent {
+	outList := make([]hfv2.ScheduledEvent, 0)
 	for _, se := range seList.Items {
-		if se.Status.Finished == true {
+		if se.Status.IsFinished() {
 			continue
 		}
 
@@ -13,8 +13,12 @@ import (
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/leaderboard"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -40,15 +44,15 @@ func NewScheduledEventServer(authClient *authclient.AuthClient, hfClientset hfCl
 	return &es, nil
 }
 
-func (s ScheduledEventServer) getScheduledEvent(id string) (hfv1.ScheduledEvent, error) {
+func (s ScheduledEventServer) getScheduledEvent(id string) (hfv2.ScheduledEvent, error) {
 
-	empty := hfv1.ScheduledEvent{}
+	empty := hfv2.ScheduledEvent{}
 
 	if len(id) == 0 {
 		return empty, fmt.Errorf("scheduledevent passed in was empty")
 	}
 
-	obj, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	obj, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
 	if err != nil {
 		return empty, fmt.Errorf("error while retrieving ScheduledEvent by id: %s with error: %v", id, err)
 	}
@@ -66,13 +70,27 @@ func (s ScheduledEventServer) SetupRoutes(r *mux.Router) {
 	r.HandleFunc("/a/scheduledevent/{id}/otacs/delete/{otac}", s.DeleteOTACFunc).Methods("GET")
 	r.HandleFunc("/a/scheduledevent/{id}/otacs/list", s.GetOTACsFunc).Methods("GET")
 	r.HandleFunc("/a/scheduledevent/delete/{id}", s.DeleteFunc).Methods("DELETE")
+	s.setupSeriesRoutes(r)
 	glog.V(2).Infof("set up routes for admin scheduledevent server")
 }
 
+// PreparedScheduledEvent keeps the v1 representation, times are formatted in the time zone of the event
 type PreparedScheduledEvent struct {
 	ID string `json:"id"`
 	hfv1.ScheduledEventSpec
 	hfv1.ScheduledEventStatus
+	TimeZone   string             `json:"time_zone,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func prepareScheduledEvent(se hfv2.ScheduledEvent) PreparedScheduledEvent {
+	return PreparedScheduledEvent{
+		ID:                   se.Name,
+		ScheduledEventSpec:   hfv2.ConvertScheduledEventSpecToV1(se.Spec),
+		ScheduledEventStatus: hfv2.ConvertScheduledEventStatusToV1(se.Status),
+		TimeZone:             se.Spec.TimeZone,
+		Conditions:           se.Status.Conditions,
+	}
 }
 
 type PreparedOTAC struct {
@@ -105,7 +123,7 @@ func (s ScheduledEventServer) GetFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	preparedScheduledEvent := PreparedScheduledEvent{scheduledEvent.Name, scheduledEvent.Spec, scheduledEvent.Status}
+	preparedScheduledEvent := prepareScheduledEvent(scheduledEvent)
 
 	encodedScheduledEvent, err := json.Marshal(preparedScheduledEvent)
 	if err != nil {
@@ -123,7 +141,7 @@ func (s ScheduledEventServer) ListFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scheduledEvents, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
+	scheduledEvents, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
 
 	if err != nil {
 		glog.Errorf("error while retrieving scheduledevents %v", err)
@@ -133,7 +151,7 @@ func (s ScheduledEventServer) ListFunc(w http.ResponseWriter, r *http.Request) {
 
 	preparedScheduledEvents := []PreparedScheduledEvent{} // must be declared this way so as to JSON marshal into [] instead of null
 	for _, s := range scheduledEvents.Items {
-		preparedScheduledEvents = append(preparedScheduledEvents, PreparedScheduledEvent{s.Name, s.Spec, s.Status})
+		preparedScheduledEvents = append(preparedScheduledEvents, prepareScheduledEvent(s))
 	}
 
 	encodedScheduledEvents, err := json.Marshal(preparedScheduledEvents)
@@ -163,16 +181,35 @@ func (s ScheduledEventServer) CreateFunc(w http.ResponseWriter, r *http.Request)
 		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no description passed in")
 		return
 	}
-	startTime := r.PostFormValue("start_time")
-	if startTime == "" {
+	timeZone := r.PostFormValue("time_zone")
+	if _, err = time.LoadLocation(timeZone); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid time zone")
+		return
+	}
+	startTimeRaw := r.PostFormValue("start_time")
+	if startTimeRaw == "" {
 		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no start time passed in")
 		return
 	}
-	endTime := r.PostFormValue("end_time")
-	if endTime == "" {
+	startTime, err := hfv2.ParseScheduledEventTime(startTimeRaw, hfv2.LoadLocation(timeZone))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid start time")
+		return
+	}
+	endTimeRaw := r.PostFormValue("end_time")
+	if endTimeRaw == "" {
 		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no end time passed in")
 		return
 	}
+	endTime, err := hfv2.ParseScheduledEventTime(endTimeRaw, hfv2.LoadLocation(timeZone))
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid end time")
+		return
+	}
+	if !endTime.After(startTime.Time) {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "end time must be after start time")
+		return
+	}
 	requiredVM := r.PostFormValue("required_vms")
 	if requiredVM == "" {
 		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no required vm map passed in")
@@ -257,7 +294,58 @@ func (s ScheduledEventServer) CreateFunc(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	scheduledEvent := &hfv1.ScheduledEvent{}
+	sessionPolicy := hfv1.SessionPolicy{}
+	rawSessionPolicy := r.PostFormValue("session_policy")
+	if rawSessionPolicy != "" {
+		err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshalling session policy %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+			return
+		}
+	}
+
+	var leaderboardPolicy *hfv1.LeaderboardPolicy
+	rawLeaderboard := r.PostFormValue("leaderboard")
+	if rawLeaderboard != "" {
+		err = json.Unmarshal([]byte(rawLeaderboard), &leaderboardPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshalling leaderboard %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if leaderboardPolicy != nil {
+			if err = leaderboard.Validate(*leaderboardPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return
+			}
+			// tokens are only handed out through the leaderboard server
+			leaderboardPolicy.Token = ""
+		}
+	}
+
+	var prewarmPolicy *hfv1.PrewarmPolicy
+	rawPrewarm := r.PostFormValue("prewarm")
+	if rawPrewarm != "" {
+		err = json.Unmarshal([]byte(rawPrewarm), &prewarmPolicy)
+		if err != nil {
+			glog.Errorf("error while unmarshalling prewarm policy %v", err)
+			util.ReturnHTTPMessage(w, r, 500, "internalerror", "error parsing")
+			return
+		}
+		if prewarmPolicy != nil {
+			if err = prewarm.Validate(*prewarmPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return
+			}
+		}
+	}
+
+	scheduledEvent := &hfv2.ScheduledEvent{}
 	random := util.RandStringRunes(16)
 	scheduledEvent.Name = "se-" + util.GenerateResourceName("se", random, 10)
 
@@ -266,10 +354,14 @@ func (s ScheduledEventServer) CreateFunc(w http.ResponseWriter, r *http.Request)
 	scheduledEvent.Spec.Creator = user.Name
 	scheduledEvent.Spec.StartTime = startTime
 	scheduledEvent.Spec.EndTime = endTime
+	scheduledEvent.Spec.TimeZone = timeZone
 	scheduledEvent.Spec.OnDemand = onDemand
 	scheduledEvent.Spec.Printable = printable
 	scheduledEvent.Spec.RequiredVirtualMachines = requiredVMUnmarshaled
 	scheduledEvent.Spec.AccessCode = accessCode
+	scheduledEvent.Spec.SessionPolicy = sessionPolicy
+	scheduledEvent.Spec.Leaderboard = leaderboardPolicy
+	scheduledEvent.Spec.Prewarm = prewarmPolicy
 
 	if scenariosRaw != "" {
 		scheduledEvent.Spec.Scenarios = scenarios
@@ -286,20 +378,32 @@ func (s ScheduledEventServer) CreateFunc(w http.ResponseWriter, r *http.Request)
 		scheduledEvent.Spec.RestrictedBindValue = scheduledEvent.Name
 	}
 
-	scheduledEvent, err = s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Create(s.ctx, scheduledEvent, metav1.CreateOptions{})
+	shortfall, err := util.CapacityShortfall(s.hfClientSet, scheduledEvent, s.ctx)
+	if err != nil {
+		glog.Errorf("error checking capacity for scheduled event %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error checking capacity")
+		return
+	}
+	if len(shortfall) > 0 {
+		if !overcommitAllowed(r) {
+			util.ReturnHTTPMessage(w, r, 409, "overcommitted", overcommitMessage(shortfall))
+			return
+		}
+		glog.Warningf("scheduled event %s overcommits its environments, missing %s", scheduledEvent.Name, util.DescribeShortfall(shortfall))
+	}
+
+	scheduledEvent, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Create(s.ctx, scheduledEvent, metav1.CreateOptions{})
 	if err != nil {
 		glog.Errorf("error creating scheduled event %v", err)
 		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error creating scheduled event")
 		return
 	}
 
-	scheduledEvent.Status.Active = true
-	scheduledEvent.Status.Finished = false
-	scheduledEvent.Status.Ready = false
-	scheduledEvent.Status.Provisioned = false
+	scheduledEvent.Status.SetActive(true)
+	scheduledEvent.Status.MarkScheduled()
 	scheduledEvent.Status.VirtualMachineSets = []string{}
 
-	_, err = s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, scheduledEvent, metav1.UpdateOptions{})
+	_, err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, scheduledEvent, metav1.UpdateOptions{})
 
 	if err != nil {
 		glog.Errorf("error updating status subresource for scheduled event %v", err)
@@ -326,8 +430,9 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	var shortfall map[string]map[string]int
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		scheduledEvent, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+		scheduledEvent, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
 		if err != nil {
 			glog.Error(err)
 			util.ReturnHTTPMessage(w, r, 404, "badrequest", "no scheduledEvent found with given ID")
@@ -338,6 +443,7 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 		description := r.PostFormValue("description")
 		startTime := r.PostFormValue("start_time")
 		endTime := r.PostFormValue("end_time")
+		timeZone := r.PostFormValue("time_zone")
 		requiredVM := r.PostFormValue("required_vms")
 		accessCode := r.PostFormValue("access_code")
 		scenariosRaw := r.PostFormValue("scenarios")
@@ -345,6 +451,9 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 		onDemandRaw := r.PostFormValue("on_demand")
 		restrictionDisabledRaw := r.PostFormValue("disable_restriction")
 		printableRaw := r.PostFormValue("printable")
+		rawSessionPolicy := r.PostFormValue("session_policy")
+		rawLeaderboard := r.PostFormValue("leaderboard")
+		rawPrewarm := r.PostFormValue("prewarm")
 
 		if name != "" {
 			scheduledEvent.Spec.Name = name
@@ -352,11 +461,30 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 		if description != "" {
 			scheduledEvent.Spec.Description = description
 		}
+		if timeZone != "" {
+			if _, err = time.LoadLocation(timeZone); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid time zone")
+				return err
+			}
+			scheduledEvent.Spec.TimeZone = timeZone
+		}
 		if startTime != "" {
-			scheduledEvent.Spec.StartTime = startTime
+			scheduledEvent.Spec.StartTime, err = hfv2.ParseScheduledEventTime(startTime, scheduledEvent.Spec.Location())
+			if err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid start time")
+				return err
+			}
 		}
 		if endTime != "" {
-			scheduledEvent.Spec.EndTime = endTime
+			scheduledEvent.Spec.EndTime, err = hfv2.ParseScheduledEventTime(endTime, scheduledEvent.Spec.Location())
+			if err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid end time")
+				return err
+			}
+		}
+		if (startTime != "" || endTime != "") && !scheduledEvent.Spec.EndTime.After(scheduledEvent.Spec.StartTime.Time) {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "end time must be after start time")
+			return fmt.Errorf("end time must be after start time")
 		}
 
 		if accessCode != "" {
@@ -394,6 +522,57 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 			scheduledEvent.Spec.Scenarios = scenarios
 		}
 
+		if rawSessionPolicy != "" {
+			sessionPolicy := hfv1.SessionPolicy{}
+			err = json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshaling session policy %v", err)
+				return fmt.Errorf("bad")
+			}
+			if err = sessionpolicy.Validate(sessionPolicy); err != nil {
+				util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+				return err
+			}
+			scheduledEvent.Spec.SessionPolicy = sessionPolicy
+		}
+
+		if rawLeaderboard != "" {
+			var leaderboardPolicy *hfv1.LeaderboardPolicy
+			err = json.Unmarshal([]byte(rawLeaderboard), &leaderboardPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshaling leaderboard %v", err)
+				return fmt.Errorf("bad")
+			}
+			if leaderboardPolicy != nil {
+				if err = leaderboard.Validate(*leaderboardPolicy); err != nil {
+					util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+					return err
+				}
+				// keep the token, screens showing the leaderboard stay connected
+				leaderboardPolicy.Token = ""
+				if scheduledEvent.Spec.Leaderboard != nil {
+					leaderboardPolicy.Token = scheduledEvent.Spec.Leaderboard.Token
+				}
+			}
+			scheduledEvent.Spec.Leaderboard = leaderboardPolicy
+		}
+
+		if rawPrewarm != "" {
+			var prewarmPolicy *hfv1.PrewarmPolicy
+			err = json.Unmarshal([]byte(rawPrewarm), &prewarmPolicy)
+			if err != nil {
+				glog.Errorf("error while unmarshaling prewarm policy %v", err)
+				return fmt.Errorf("bad")
+			}
+			if prewarmPolicy != nil {
+				if err = prewarm.Validate(*prewarmPolicy); err != nil {
+					util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+					return err
+				}
+			}
+			scheduledEvent.Spec.Prewarm = prewarmPolicy
+		}
+
 		restrictionDisabled := scheduledEvent.Spec.RestrictedBind
 
 		if restrictionDisabledRaw != "" {
@@ -441,19 +620,35 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 			}
 		}
 
+		// only changes of the time window or the virtual machines can overcommit an environment, pre-warming moves
+		// the start of the window
+		if startTime != "" || endTime != "" || requiredVM != "" || rawPrewarm != "" {
+			shortfall, err = util.CapacityShortfall(s.hfClientSet, scheduledEvent, s.ctx)
+			if err != nil {
+				return err
+			}
+			if len(shortfall) > 0 {
+				if !overcommitAllowed(r) {
+					return nil
+				}
+				glog.Warningf("scheduled event %s overcommits its environments, missing %s", scheduledEvent.Name, util.DescribeShortfall(shortfall))
+				shortfall = nil
+			}
+		}
+
 		// if our event is already provisioned, we need to undo that and delete the corresponding access code(s) and DBC(s)
 		// our scheduledeventcontroller will then provision our scheduledevent with the updated values
-		if scheduledEvent.Status.Provisioned {
+		if scheduledEvent.Status.IsProvisioned() {
 			now := time.Now()
-
-			beginTime, err := time.Parse(time.UnixDate, scheduledEvent.Spec.StartTime)
+			schedules, err := util.PrewarmSchedules(s.hfClientSet, scheduledEvent, s.ctx)
 			if err != nil {
 				return err
 			}
+			beginTime := prewarm.ProvisionTime(scheduledEvent.Spec.StartTime.Time, schedules)
 
-			// the SE's begin time has been rescheduled to the future but was already provisioned
+			// the SE's (pre-warmed) begin time has been rescheduled to the future but was already provisioned
 			// OR the on demand setting has been removed completely.
-			if (now.Before(beginTime) && scheduledEvent.Status.Active) || (!onDemandBeforeUpdate && onDemand) {
+			if (now.Before(beginTime) && scheduledEvent.Status.IsActive()) || (!onDemandBeforeUpdate && onDemand) {
 				err = s.deleteVMSetsFromScheduledEvent(scheduledEvent)
 				if err != nil {
 					return err
@@ -466,16 +661,14 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 			}
 		}
 
-		updateSE, updateErr := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Update(s.ctx, scheduledEvent, metav1.UpdateOptions{})
+		updateSE, updateErr := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Update(s.ctx, scheduledEvent, metav1.UpdateOptions{})
 		if updateErr != nil {
 			return updateErr
 		}
 
-		updateSE.Status.Provisioned = false
-		updateSE.Status.Ready = false
-		updateSE.Status.Finished = false
+		updateSE.Status.MarkScheduled()
 
-		_, updateErr = s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, updateSE, metav1.UpdateOptions{})
+		_, updateErr = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).UpdateStatus(s.ctx, updateSE, metav1.UpdateOptions{})
 		return updateErr
 	})
 
@@ -484,6 +677,10 @@ func (s ScheduledEventServer) UpdateFunc(w http.ResponseWriter, r *http.Request)
 		util.ReturnHTTPMessage(w, r, 500, "error", "error attempting to update")
 		return
 	}
+	if len(shortfall) > 0 {
+		util.ReturnHTTPMessage(w, r, 409, "overcommitted", overcommitMessage(shortfall))
+		return
+	}
 
 	util.ReturnHTTPMessage(w, r, 200, "updated", "")
 	return
@@ -504,7 +701,7 @@ func (s ScheduledEventServer) DeleteFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	scheduledEvent, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	scheduledEvent, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
 	if err != nil {
 		glog.Error(err)
 		util.ReturnHTTPMessage(w, r, 404, "badrequest", "no scheduledEvent found with given ID")
@@ -536,7 +733,7 @@ func (s ScheduledEventServer) DeleteFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Delete(s.ctx, scheduledEvent.Name, metav1.DeleteOptions{})
+	err = s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Delete(s.ctx, scheduledEvent.Name, metav1.DeleteOptions{})
 
 	if err != nil {
 		glog.Errorf("error deleting scheduled event %v", err)
@@ -642,7 +839,7 @@ func (s ScheduledEventServer) GenerateOTACsFunc(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	scheduledEvent, err := s.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	scheduledEvent, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
 	if err != nil {
 		glog.Error(err)
 		util.ReturnHTTPMessage(w, r, 404, "badrequest", "no scheduledEvent found with given ID")
@@ -700,7 +897,7 @@ func (s ScheduledEventServer) GenerateOTACsFunc(w http.ResponseWriter, r *http.R
 	glog.V(4).Infof("generated %d new OTACs for SE %s", count, id)
 }
 
-func (s ScheduledEventServer) deleteScheduledEventConfig(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventServer) deleteScheduledEventConfig(se *hfv2.ScheduledEvent) error {
 	glog.V(6).Infof("ScheduledEvent %s is updated or deleted, deleting corresponding access code(s) and DBC(s)", se.Name)
 
 	// delete all DBCs corresponding to this scheduled event
@@ -722,9 +919,9 @@ func (s ScheduledEventServer) deleteScheduledEventConfig(se *hfv1.ScheduledEvent
 	return nil // break (return) here because we're done with this SE.
 }
 
-func (s ScheduledEventServer) deleteProgressFromScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventServer) deleteProgressFromScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// for each vmset that belongs to this to-be-stopped scheduled event, delete that vmset
-	err := s.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+	err := s.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
 	})
 	if err != nil {
@@ -734,7 +931,7 @@ func (s ScheduledEventServer) deleteProgressFromScheduledEvent(se *hfv1.Schedule
 	return nil
 }
 
-func (s ScheduledEventServer) deleteVMSetsFromScheduledEvent(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventServer) deleteVMSetsFromScheduledEvent(se *hfv2.ScheduledEvent) error {
 	// delete all vmsets corresponding to this scheduled event
 	err := s.hfClientSet.HobbyfarmV1().VirtualMachineSets(util.GetReleaseNamespace()).DeleteCollection(s.ctx, metav1.DeleteOptions{}, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventLabel, se.Name),
@@ -746,7 +943,7 @@ func (s ScheduledEventServer) deleteVMSetsFromScheduledEvent(se *hfv1.ScheduledE
 	return nil
 }
 
-func (s ScheduledEventServer) finishSessions(se *hfv1.ScheduledEvent) error {
+func (s ScheduledEventServer) finishSessions(se *hfv2.ScheduledEvent) error {
 	// get a list of sessions for the user
 	sessionList, err := s.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.AccessCodeLabel, se.Spec.AccessCode),
@@ -778,3 +975,14 @@ func (s ScheduledEventServer) finishSessions(se *hfv1.ScheduledEvent) error {
 	}
 	return nil
 }
+
+// overcommitAllowed returns true if the client confirmed that the scheduled event may overcommit its environments.
+// The controller then provisions as many virtual machines as fit and reports the rest in the status.
+func overcommitAllowed(r *http.Request) bool {
+	overcommit, _ := strconv.ParseBool(r.PostFormValue("overcommit"))
+	return overcommit
+}
+
+func overcommitMessage(shortfall map[string]map[string]int) string {
+	return "not enough capacity, missing " + util.DescribeShortfall(shortfall) + ". pass overcommit=true to save anyway"
+}
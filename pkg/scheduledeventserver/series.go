@@ -0,0 +1,417 @@
+package scheduledeventserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/eventseries"
+	"github.com/hobbyfarm/gargantua/v3/pkg/leaderboard"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/recurrence"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	seriesResourcePlural = "scheduledeventseries"
+
+	// maxPreview limits how many upcoming occurrences are listed for a series
+	maxPreview = 50
+)
+
+type PreparedScheduledEventSeries struct {
+	ID string `json:"id"`
+	hfv1.ScheduledEventSeriesSpec
+	hfv1.ScheduledEventSeriesStatus
+}
+
+type PreparedOccurrence struct {
+	Date           string `json:"date"`
+	Index          int    `json:"index"`
+	StartTime      string `json:"start_time"`
+	AccessCode     string `json:"access_code"`
+	ScheduledEvent string `json:"scheduled_event,omitempty"` // empty if the occurrence was not materialized yet
+	Edited         bool   `json:"edited"`                    // edited on its own, changes of the series are not applied
+}
+
+func (s ScheduledEventServer) setupSeriesRoutes(r *mux.Router) {
+	r.HandleFunc("/a/scheduledeventseries/list", s.ListSeriesFunc).Methods("GET")
+	r.HandleFunc("/a/scheduledeventseries/new", s.CreateSeriesFunc).Methods("POST")
+	r.HandleFunc("/a/scheduledeventseries/{id}", s.GetSeriesFunc).Methods("GET")
+	r.HandleFunc("/a/scheduledeventseries/{id}", s.UpdateSeriesFunc).Methods("PUT")
+	r.HandleFunc("/a/scheduledeventseries/{id}/occurrences", s.ListOccurrencesFunc).Methods("GET")
+	r.HandleFunc("/a/scheduledeventseries/delete/{id}", s.DeleteSeriesFunc).Methods("DELETE")
+}
+
+func (s ScheduledEventServer) GetSeriesFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scheduledeventseries")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	series, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error while retrieving scheduledeventseries %v", err)
+		util.ReturnHTTPMessage(w, r, http.StatusNotFound, "error", "no scheduledeventseries with given ID found")
+		return
+	}
+
+	encodedSeries, err := json.Marshal(PreparedScheduledEventSeries{series.Name, series.Spec, series.Status})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedSeries)
+}
+
+func (s ScheduledEventServer) ListSeriesFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list scheduledeventseries")
+		return
+	}
+
+	seriesList, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{})
+	if err != nil {
+		glog.Errorf("error while retrieving scheduledeventseries %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "no scheduledeventseries found")
+		return
+	}
+
+	preparedSeries := []PreparedScheduledEventSeries{} // must be declared this way so as to JSON marshal into [] instead of null
+	for _, series := range seriesList.Items {
+		preparedSeries = append(preparedSeries, PreparedScheduledEventSeries{series.Name, series.Spec, series.Status})
+	}
+
+	encodedSeries, err := json.Marshal(preparedSeries)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedSeries)
+}
+
+func (s ScheduledEventServer) CreateSeriesFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbCreate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to create scheduledeventseries")
+		return
+	}
+
+	for _, required := range []string{"name", "description", "start_time", "end_time", "required_vms", "recurrence", "access_code_pattern"} {
+		if r.PostFormValue(required) == "" {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "no "+required+" passed in")
+			return
+		}
+	}
+	if r.PostFormValue("scenarios") == "" && r.PostFormValue("courses") == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no scenarios or courses passed in")
+		return
+	}
+
+	series := &hfv1.ScheduledEventSeries{}
+	series.Name = util.GenerateResourceName("ses", util.RandStringRunes(16), 10)
+	series.Spec.Template.Creator = user.Name
+	series.Spec.Template.RestrictedBind = true
+	series.Spec.Template.Scenarios = []string{}
+	series.Spec.Template.Courses = []string{}
+
+	if err = applySeriesForm(r, &series.Spec); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+	if err = eventseries.Validate(series); err != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", err.Error())
+		return
+	}
+
+	series, err = s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Create(s.ctx, series, metav1.CreateOptions{})
+	if err != nil {
+		glog.Errorf("error creating scheduledeventseries %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error creating scheduledeventseries")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 201, "created", series.Name)
+	glog.V(4).Infof("Created scheduledeventseries %s", series.Name)
+}
+
+// UpdateSeriesFunc changes the series and with it all occurrences that neither started nor were edited on their own.
+// A single occurrence is edited through its scheduled event.
+func (s ScheduledEventServer) UpdateSeriesFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to update scheduledeventseries")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no ID passed in")
+		return
+	}
+
+	var validationErr error
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		series, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if validationErr = applySeriesForm(r, &series.Spec); validationErr != nil {
+			return nil
+		}
+		if validationErr = eventseries.Validate(series); validationErr != nil {
+			return nil
+		}
+
+		_, err = s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Update(s.ctx, series, metav1.UpdateOptions{})
+		return err
+	})
+
+	if apierrors.IsNotFound(retryErr) {
+		util.ReturnHTTPMessage(w, r, 404, "badrequest", "no scheduledeventseries found with given ID")
+		return
+	}
+	if retryErr != nil {
+		glog.Error(retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error attempting to update")
+		return
+	}
+	if validationErr != nil {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", validationErr.Error())
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "")
+}
+
+// DeleteSeriesFunc deletes the series, the controller removes occurrences that have not started yet
+func (s ScheduledEventServer) DeleteSeriesFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbDelete), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to delete scheduledeventseries")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	err = s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Delete(s.ctx, id, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		util.ReturnHTTPMessage(w, r, 404, "badrequest", "no scheduledeventseries found with given ID")
+		return
+	}
+	if err != nil {
+		glog.Errorf("error deleting scheduledeventseries %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "internalerror", "error deleting scheduledeventseries")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "deleted", "Deleted: "+id)
+}
+
+// ListOccurrencesFunc lists the materialized occurrences of a series followed by the upcoming ones
+func (s ScheduledEventServer) ListOccurrencesFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(seriesResourcePlural, rbacclient.VerbGet), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get scheduledeventseries")
+		return
+	}
+	_, err = s.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list scheduledevents")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	series, err := s.hfClientSet.HobbyfarmV1().ScheduledEventSerieses(util.GetReleaseNamespace()).Get(s.ctx, id, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error while retrieving scheduledeventseries %v", err)
+		util.ReturnHTTPMessage(w, r, http.StatusNotFound, "error", "no scheduledeventseries with given ID found")
+		return
+	}
+
+	seList, err := s.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(s.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.ScheduledEventSeriesLabel, series.Name),
+	})
+	if err != nil {
+		glog.Errorf("error while retrieving scheduledevents %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "error listing occurrences")
+		return
+	}
+
+	occurrences := []PreparedOccurrence{} // must be declared this way so as to JSON marshal into [] instead of null
+	materialized := map[string]bool{}
+	for _, se := range seList.Items {
+		date := se.Annotations[eventseries.OccurrenceAnnotation]
+		materialized[date] = true
+		occurrences = append(occurrences, PreparedOccurrence{
+			Date:           date,
+			Index:          -1,
+			StartTime:      se.Spec.StartTime.In(se.Spec.Location()).Format(time.UnixDate),
+			AccessCode:     se.Spec.AccessCode,
+			ScheduledEvent: se.Name,
+			Edited:         eventseries.Edited(&se),
+		})
+	}
+
+	// occurrences that were materialized before are not created again, even if their scheduled event was deleted
+	for _, date := range series.Status.Materialized {
+		materialized[date] = true
+	}
+
+	now := time.Now()
+	upcoming, err := eventseries.Occurrences(series, now, now.AddDate(1, 0, 0))
+	if err == nil {
+		for _, o := range upcoming {
+			if len(occurrences) >= len(seList.Items)+maxPreview {
+				break
+			}
+			if materialized[o.Date()] {
+				continue
+			}
+			occurrences = append(occurrences, PreparedOccurrence{
+				Date:       o.Date(),
+				Index:      o.Index,
+				StartTime:  o.Start.Format(time.UnixDate),
+				AccessCode: eventseries.AccessCode(series, o),
+			})
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Date < occurrences[j].Date })
+
+	encodedOccurrences, err := json.Marshal(occurrences)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedOccurrences)
+}
+
+// applySeriesForm applies the form values that were passed in to a series, the template takes the same values
+// as a scheduled event
+func applySeriesForm(r *http.Request, spec *hfv1.ScheduledEventSeriesSpec) error {
+	template := &spec.Template
+
+	if name := r.PostFormValue("name"); name != "" {
+		template.Name = name
+	}
+	if description := r.PostFormValue("description"); description != "" {
+		template.Description = description
+	}
+	if startTime := r.PostFormValue("start_time"); startTime != "" {
+		template.StartTime = startTime
+	}
+	if endTime := r.PostFormValue("end_time"); endTime != "" {
+		template.EndTime = endTime
+	}
+	if timeZone := r.PostFormValue("time_zone"); timeZone != "" {
+		spec.TimeZone = timeZone
+	}
+
+	if requiredVM := r.PostFormValue("required_vms"); requiredVM != "" {
+		requiredVMUnmarshaled := map[string]map[string]int{}
+		if err := json.Unmarshal([]byte(requiredVM), &requiredVMUnmarshaled); err != nil {
+			return fmt.Errorf("invalid value for required_vms")
+		}
+		template.RequiredVirtualMachines = requiredVMUnmarshaled
+	}
+	if scenariosRaw := r.PostFormValue("scenarios"); scenariosRaw != "" {
+		scenarios := []string{}
+		if err := json.Unmarshal([]byte(scenariosRaw), &scenarios); err != nil {
+			return fmt.Errorf("invalid value for scenarios")
+		}
+		template.Scenarios = scenarios
+	}
+	if coursesRaw := r.PostFormValue("courses"); coursesRaw != "" {
+		courses := []string{}
+		if err := json.Unmarshal([]byte(coursesRaw), &courses); err != nil {
+			return fmt.Errorf("invalid value for courses")
+		}
+		template.Courses = courses
+	}
+
+	if onDemandRaw := r.PostFormValue("on_demand"); onDemandRaw != "" {
+		onDemand, err := strconv.ParseBool(onDemandRaw)
+		if err != nil {
+			return fmt.Errorf("invalid value for on_demand")
+		}
+		template.OnDemand = onDemand
+	}
+	if printableRaw := r.PostFormValue("printable"); printableRaw != "" {
+		printable, err := strconv.ParseBool(printableRaw)
+		if err != nil {
+			return fmt.Errorf("invalid value for printable")
+		}
+		template.Printable = printable
+	}
+	if restrictionDisabledRaw := r.PostFormValue("disable_restriction"); restrictionDisabledRaw != "" {
+		template.RestrictedBind = strings.ToLower(restrictionDisabledRaw) == "false"
+	}
+
+	if rawSessionPolicy := r.PostFormValue("session_policy"); rawSessionPolicy != "" {
+		sessionPolicy := hfv1.SessionPolicy{}
+		if err := json.Unmarshal([]byte(rawSessionPolicy), &sessionPolicy); err != nil {
+			return fmt.Errorf("invalid value for session_policy")
+		}
+		if err := sessionpolicy.Validate(sessionPolicy); err != nil {
+			return err
+		}
+		template.SessionPolicy = sessionPolicy
+	}
+	if rawLeaderboard := r.PostFormValue("leaderboard"); rawLeaderboard != "" {
+		var leaderboardPolicy *hfv1.LeaderboardPolicy
+		if err := json.Unmarshal([]byte(rawLeaderboard), &leaderboardPolicy); err != nil {
+			return fmt.Errorf("invalid value for leaderboard")
+		}
+		if leaderboardPolicy != nil {
+			if err := leaderboard.Validate(*leaderboardPolicy); err != nil {
+				return err
+			}
+			// every occurrence gets its own token through the leaderboard server
+			leaderboardPolicy.Token = ""
+		}
+		template.Leaderboard = leaderboardPolicy
+	}
+	if rawPrewarm := r.PostFormValue("prewarm"); rawPrewarm != "" {
+		var prewarmPolicy *hfv1.PrewarmPolicy
+		if err := json.Unmarshal([]byte(rawPrewarm), &prewarmPolicy); err != nil {
+			return fmt.Errorf("invalid value for prewarm")
+		}
+		if prewarmPolicy != nil {
+			if err := prewarm.Validate(*prewarmPolicy); err != nil {
+				return err
+			}
+		}
+		template.Prewarm = prewarmPolicy
+	}
+
+	if recurrenceRaw := r.PostFormValue("recurrence"); recurrenceRaw != "" {
+		spec.Recurrence = recurrenceRaw
+	}
+	if exceptionsRaw := r.PostFormValue("exceptions"); exceptionsRaw != "" {
+		exceptions := []string{}
+		if err := json.Unmarshal([]byte(exceptionsRaw), &exceptions); err != nil {
+			return fmt.Errorf("invalid value for exceptions, expected a list of dates like %s", recurrence.DateFormat)
+		}
+		spec.Exceptions = exceptions
+	}
+	if pattern := r.PostFormValue("access_code_pattern"); pattern != "" {
+		spec.AccessCodePattern = pattern
+	}
+	if materializeAhead := r.PostFormValue("materialize_ahead"); materializeAhead != "" {
+		spec.MaterializeAhead = materializeAhead
+	}
+
+	return nil
+}
@@ -0,0 +1,506 @@
+package search
+
+import (
+	"encoding/base64"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	KindScenario = "scenario"
+	KindCourse   = "course"
+
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+	FieldCategories  = "categories"
+	FieldStepTitle   = "step_title"
+	FieldStepContent = "step_content"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	maxQueryTerms    = 10
+	minPrefixLength  = 2
+	matchesPerResult = 3
+	snippetRadius    = 60
+	ellipsis         = "…"
+)
+
+// matches in names count more than matches deep inside step content
+var fieldWeights = map[string]float64{
+	FieldName:        5,
+	FieldTags:        3,
+	FieldCategories:  3,
+	FieldDescription: 2,
+	FieldStepTitle:   2,
+	FieldStepContent: 1,
+}
+
+// Result is a scenario or course matching a query, best matches first
+type Result struct {
+	Kind    string            `json:"kind"`
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	State   hfv1.ContentState `json:"state,omitempty"`
+	Score   float64           `json:"score"`
+	Matches []Match           `json:"matches"`
+}
+
+// Match is a snippet of a field that matched. Highlights are [start, end) byte offsets of the matched words in Snippet.
+type Match struct {
+	Field      string   `json:"field"`
+	Step       *int     `json:"step,omitempty"`
+	Snippet    string   `json:"snippet"`
+	Highlights [][2]int `json:"highlights"`
+}
+
+// Query is a search. Every word of Text has to match, the last one also matches as a prefix.
+// Allowed, if set, limits the results to the scenarios and courses it returns true for.
+type Query struct {
+	Text          string
+	Kind          string
+	IncludeDrafts bool
+	Allowed       func(kind string, id string) bool
+	Limit         int
+}
+
+type token struct {
+	term       string
+	start, end int
+}
+
+type field struct {
+	name   string
+	step   int
+	text   string
+	tokens []token
+}
+
+type document struct {
+	kind      string
+	id        string
+	name      string
+	state     hfv1.ContentState
+	scenarios []string
+	fields    []field
+}
+
+// Index is an in memory full text index over scenarios and courses, kept up to date by their informers
+type Index struct {
+	mu    sync.RWMutex
+	docs  map[string]*document
+	terms map[string]map[string]struct{} // term to the keys of the documents containing it
+}
+
+func NewIndex(hfInformerFactory hfInformers.SharedInformerFactory) *Index {
+	i := &Index{
+		docs:  map[string]*document{},
+		terms: map[string]map[string]struct{}{},
+	}
+
+	hfInformerFactory.Hobbyfarm().V1().Scenarios().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: i.putObject,
+		UpdateFunc: func(old, new interface{}) {
+			i.putObject(new)
+		},
+		DeleteFunc: i.deleteObject,
+	})
+	hfInformerFactory.Hobbyfarm().V1().Courses().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: i.putObject,
+		UpdateFunc: func(old, new interface{}) {
+			i.putObject(new)
+		},
+		DeleteFunc: i.deleteObject,
+	})
+
+	return i
+}
+
+// CourseScenarios returns the scenarios of an indexed course
+func (i *Index) CourseScenarios(id string) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	doc, ok := i.docs[key(KindCourse, id)]
+	if !ok {
+		return nil
+	}
+	return doc.scenarios
+}
+
+// Search returns the best matching scenarios and courses for a query
+func (i *Index) Search(q Query) []Result {
+	words := tokenize(q.Text)
+	if len(words) == 0 {
+		return []Result{}
+	}
+	if len(words) > maxQueryTerms {
+		words = words[:maxQueryTerms]
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	// every word of the query expands to the indexed terms it matches
+	expanded := make([]map[string]bool, len(words))
+	for n, w := range words {
+		expanded[n] = map[string]bool{}
+		if _, ok := i.terms[w.term]; ok {
+			expanded[n][w.term] = true
+		}
+		if n == len(words)-1 && len(w.term) >= minPrefixLength {
+			for term := range i.terms {
+				if strings.HasPrefix(term, w.term) {
+					expanded[n][term] = true
+				}
+			}
+		}
+		if len(expanded[n]) == 0 {
+			return []Result{}
+		}
+	}
+
+	// documents have to contain all words
+	var candidates map[string]struct{}
+	idf := make([]float64, len(words))
+	for n, terms := range expanded {
+		docs := map[string]struct{}{}
+		for term := range terms {
+			for k := range i.terms[term] {
+				docs[k] = struct{}{}
+			}
+		}
+		idf[n] = math.Log(1 + float64(len(i.docs))/float64(len(docs)))
+
+		if candidates == nil {
+			candidates = docs
+			continue
+		}
+		for k := range candidates {
+			if _, ok := docs[k]; !ok {
+				delete(candidates, k)
+			}
+		}
+	}
+
+	results := []Result{}
+	for k := range candidates {
+		doc := i.docs[k]
+		if q.Kind != "" && doc.kind != q.Kind {
+			continue
+		}
+		if !q.IncludeDrafts && doc.state == hfv1.ContentStateDraft {
+			continue
+		}
+		if q.Allowed != nil && !q.Allowed(doc.kind, doc.id) {
+			continue
+		}
+		results = append(results, score(doc, expanded, idf))
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].ID < results[b].ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+type fieldScore struct {
+	field *field
+	score float64
+}
+
+func score(doc *document, expanded []map[string]bool, idf []float64) Result {
+	result := Result{
+		Kind:    doc.kind,
+		ID:      doc.id,
+		Name:    doc.name,
+		State:   doc.state,
+		Matches: []Match{},
+	}
+
+	scored := []fieldScore{}
+	for n := range doc.fields {
+		f := &doc.fields[n]
+		s := 0.0
+		for w, terms := range expanded {
+			tf := 0
+			for _, t := range f.tokens {
+				if terms[t.term] {
+					tf++
+				}
+			}
+			if tf > 0 {
+				s += idf[w] * fieldWeights[f.name] * (1 + math.Log(float64(tf)))
+			}
+		}
+		if s > 0 {
+			scored = append(scored, fieldScore{field: f, score: s})
+			result.Score += s
+		}
+	}
+
+	sort.SliceStable(scored, func(a, b int) bool {
+		return scored[a].score > scored[b].score
+	})
+	for n, fs := range scored {
+		if n == matchesPerResult {
+			break
+		}
+		result.Matches = append(result.Matches, snippet(fs.field, expanded))
+	}
+
+	result.Score = math.Round(result.Score*1000) / 1000
+	return result
+}
+
+// snippet cuts the text around the matches out of a field and marks every match in it
+func snippet(f *field, expanded []map[string]bool) Match {
+	match := Match{Field: f.name, Highlights: [][2]int{}}
+	if f.step >= 0 {
+		step := f.step
+		match.Step = &step
+	}
+
+	matched := func(t token) bool {
+		for _, terms := range expanded {
+			if terms[t.term] {
+				return true
+			}
+		}
+		return false
+	}
+
+	hits := []token{}
+	for _, t := range f.tokens {
+		if matched(t) {
+			hits = append(hits, t)
+		}
+	}
+	if len(hits) == 0 {
+		return match
+	}
+
+	// center the snippet on the match with the most other matches around it, hits are ordered by position
+	best, bestCount := 0, 0
+	for n, t := range hits {
+		from := sort.Search(len(hits), func(m int) bool { return hits[m].start >= t.start-snippetRadius })
+		to := sort.Search(len(hits), func(m int) bool { return hits[m].end > t.end+snippetRadius })
+		if to-from > bestCount {
+			best, bestCount = n, to-from
+		}
+	}
+
+	start := hits[best].start - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	for start > 0 && !utf8.RuneStart(f.text[start]) {
+		start--
+	}
+	end := hits[best].end + snippetRadius
+	if end > len(f.text) {
+		end = len(f.text)
+	}
+	for end < len(f.text) && !utf8.RuneStart(f.text[end]) {
+		end++
+	}
+
+	prefix := ""
+	if start > 0 {
+		prefix = ellipsis
+	}
+	suffix := ""
+	if end < len(f.text) {
+		suffix = ellipsis
+	}
+
+	// whitespace is replaced byte for byte so the offsets stay valid
+	text := strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, f.text[start:end])
+	match.Snippet = prefix + text + suffix
+
+	for _, t := range hits {
+		if t.start >= start && t.end <= end {
+			match.Highlights = append(match.Highlights, [2]int{t.start - start + len(prefix), t.end - start + len(prefix)})
+		}
+	}
+
+	return match
+}
+
+func (i *Index) putObject(obj interface{}) {
+	var doc *document
+	switch o := obj.(type) {
+	case *hfv1.Scenario:
+		doc = scenarioDocument(o)
+	case *hfv1.Course:
+		doc = courseDocument(o)
+	default:
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	k := key(doc.kind, doc.id)
+	i.remove(k)
+	i.docs[k] = doc
+	for _, f := range doc.fields {
+		for _, t := range f.tokens {
+			if i.terms[t.term] == nil {
+				i.terms[t.term] = map[string]struct{}{}
+			}
+			i.terms[t.term][k] = struct{}{}
+		}
+	}
+	glog.V(8).Infof("indexed %s", k)
+}
+
+func (i *Index) deleteObject(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	var k string
+	switch o := obj.(type) {
+	case *hfv1.Scenario:
+		k = key(KindScenario, o.Name)
+	case *hfv1.Course:
+		k = key(KindCourse, o.Name)
+	default:
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(k)
+}
+
+// remove drops a document from the index, the caller holds the lock
+func (i *Index) remove(k string) {
+	doc, ok := i.docs[k]
+	if !ok {
+		return
+	}
+
+	for _, f := range doc.fields {
+		for _, t := range f.tokens {
+			delete(i.terms[t.term], k)
+			if len(i.terms[t.term]) == 0 {
+				delete(i.terms, t.term)
+			}
+		}
+	}
+	delete(i.docs, k)
+}
+
+func scenarioDocument(s *hfv1.Scenario) *document {
+	name := decode(s.Spec.Name)
+	doc := &document{
+		kind:  KindScenario,
+		id:    s.Name,
+		name:  name,
+		state: s.Spec.State,
+	}
+
+	doc.add(FieldName, -1, name)
+	doc.add(FieldDescription, -1, decode(s.Spec.Description))
+	doc.add(FieldTags, -1, strings.Join(s.Spec.Tags, ", "))
+	doc.add(FieldCategories, -1, strings.Join(s.Spec.Categories, ", "))
+	for n, step := range s.Spec.Steps {
+		doc.add(FieldStepTitle, n, decode(step.Title))
+		doc.add(FieldStepContent, n, decode(step.Content))
+	}
+
+	return doc
+}
+
+func courseDocument(c *hfv1.Course) *document {
+	name := decode(c.Spec.Name)
+	doc := &document{
+		kind:      KindCourse,
+		id:        c.Name,
+		name:      name,
+		state:     c.Spec.State,
+		scenarios: c.Spec.Scenarios,
+	}
+
+	doc.add(FieldName, -1, name)
+	doc.add(FieldDescription, -1, decode(c.Spec.Description))
+	doc.add(FieldCategories, -1, strings.Join(c.Spec.Categories, ", "))
+
+	return doc
+}
+
+func (d *document) add(name string, step int, text string) {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return
+	}
+	d.fields = append(d.fields, field{name: name, step: step, text: text, tokens: tokens})
+}
+
+// tokenize splits text into lower case words of letters and digits
+func tokenize(text string) []token {
+	tokens := []token{}
+	start := -1
+	for n, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = n
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{term: strings.ToLower(text[start:n]), start: start, end: n})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// decode returns the text of a base64 encoded field, content that is not encoded is indexed as it is
+func decode(s string) string {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || !utf8.Valid(decoded) {
+		return s
+	}
+	return string(decoded)
+}
+
+func key(kind string, id string) string {
+	return kind + "/" + id
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	kubernetesBasics = &hfv1.Scenario{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-basics"},
+		Spec: hfv1.ScenarioSpec{
+			Name:        base64.StdEncoding.EncodeToString([]byte("Kubernetes Basics")),
+			Description: base64.StdEncoding.EncodeToString([]byte("Pods, deployments and services")),
+			Tags:        []string{"containers"},
+			Steps: []hfv1.ScenarioStep{
+				{Title: base64.StdEncoding.EncodeToString([]byte("Install")), Content: base64.StdEncoding.EncodeToString([]byte("Run the installer on the control plane"))},
+				{Title: base64.StdEncoding.EncodeToString([]byte("Deploy")), Content: base64.StdEncoding.EncodeToString([]byte("kubectl apply -f nginx.yaml"))},
+			},
+		},
+	}
+	// content that is not base64 encoded is indexed as it is
+	dockerIntro = &hfv1.Scenario{
+		ObjectMeta: metav1.ObjectMeta{Name: "docker-intro"},
+		Spec: hfv1.ScenarioSpec{
+			Name:       "Docker Introduction",
+			Categories: []string{"containers"},
+			Steps:      []hfv1.ScenarioStep{{Title: "Images", Content: "Build an image and push it to a registry, then deploy it to kubernetes"}},
+		},
+	}
+	helmDraft = &hfv1.Scenario{
+		ObjectMeta: metav1.ObjectMeta{Name: "helm-draft"},
+		Spec:       hfv1.ScenarioSpec{Name: "Helm charts for Kubernetes", State: hfv1.ContentStateDraft},
+	}
+	platformCourse = &hfv1.Course{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: hfv1.CourseSpec{
+			Name:        "Platform engineering",
+			Description: "From containers to kubernetes clusters",
+			Scenarios:   []string{"docker-intro", "k8s-basics"},
+		},
+	}
+)
+
+func newTestIndex(objects ...interface{}) *Index {
+	i := NewIndex(hfInformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0))
+	for _, obj := range objects {
+		i.putObject(obj)
+	}
+	return i
+}
+
+func ids(results []Result) []string {
+	found := []string{}
+	for _, r := range results {
+		found = append(found, r.Kind+"/"+r.ID)
+	}
+	return found
+}
+
+func Test_Search(t *testing.T) {
+	i := newTestIndex(kubernetesBasics, dockerIntro, helmDraft, platformCourse)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"empty query", Query{Text: " ,. "}, []string{}},
+		{"unknown word", Query{Text: "terraform"}, []string{}},
+		{"name counts more than step content", Query{Text: "kubernetes"}, []string{"scenario/k8s-basics", "course/platform", "scenario/docker-intro"}},
+		{"every word has to match", Query{Text: "kubernetes registry"}, []string{"scenario/docker-intro"}},
+		{"case and punctuation", Query{Text: "KUBECTL, Nginx!"}, []string{"scenario/k8s-basics"}},
+		{"last word as prefix, tags over description", Query{Text: "contain"}, []string{"scenario/docker-intro", "scenario/k8s-basics", "course/platform"}},
+		{"only the last word as prefix", Query{Text: "contain kubernetes"}, []string{}},
+		{"prefix too short", Query{Text: "k"}, []string{}},
+		{"kind", Query{Text: "kubernetes", Kind: KindCourse}, []string{"course/platform"}},
+		{"drafts", Query{Text: "helm", IncludeDrafts: true}, []string{"scenario/helm-draft"}},
+		{"drafts hidden", Query{Text: "helm"}, []string{}},
+		{"allowed", Query{Text: "kubernetes", Allowed: func(kind string, id string) bool { return id != "k8s-basics" }}, []string{"course/platform", "scenario/docker-intro"}},
+		{"limit", Query{Text: "kubernetes", Limit: 1}, []string{"scenario/k8s-basics"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(i.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query.Text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SearchMatches(t *testing.T) {
+	i := newTestIndex(kubernetesBasics, dockerIntro)
+
+	results := i.Search(Query{Text: "deploy"})
+	if len(results) != 2 || results[0].ID != "k8s-basics" || results[0].Name != "Kubernetes Basics" {
+		t.Fatalf("Search() = %+v", results)
+	}
+
+	// the prefix also matches deployments in the description, both fields weigh the same
+	matches := results[0].Matches
+	if len(matches) != 2 || matches[0].Field != FieldDescription || matches[1].Field != FieldStepTitle || matches[1].Step == nil || *matches[1].Step != 1 {
+		t.Fatalf("Matches = %+v", matches)
+	}
+	if h := matches[0].Highlights; len(h) != 1 || matches[0].Snippet[h[0][0]:h[0][1]] != "deployments" {
+		t.Errorf("description match = %+v", matches[0])
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("scores = %v, %v", results[0].Score, results[1].Score)
+	}
+
+	m := results[1].Matches[0]
+	if m.Field != FieldStepContent || len(m.Highlights) != 1 {
+		t.Fatalf("match = %+v", m)
+	}
+	if h := m.Highlights[0]; m.Snippet[h[0]:h[1]] != "deploy" {
+		t.Errorf("highlight %v of %q = %q", h, m.Snippet, m.Snippet[h[0]:h[1]])
+	}
+}
+
+func Test_Snippet(t *testing.T) {
+	text := strings.Repeat("überall ", 20) + "the\tcluster\nis ready, check the cluster " + strings.Repeat("später ", 20)
+	f := &field{name: FieldStepContent, step: 3, text: text, tokens: tokenize(text)}
+
+	m := snippet(f, []map[string]bool{{"cluster": true}})
+
+	if !strings.HasPrefix(m.Snippet, ellipsis) || !strings.HasSuffix(m.Snippet, ellipsis) {
+		t.Errorf("snippet %q is not cut on both sides", m.Snippet)
+	}
+	if !strings.Contains(m.Snippet, "the cluster is ready") {
+		t.Errorf("snippet %q still contains tabs or newlines", m.Snippet)
+	}
+	if strings.ContainsRune(m.Snippet, '�') || !strings.Contains(m.Snippet, "überall") {
+		t.Errorf("snippet %q is not cut at a character boundary", m.Snippet)
+	}
+	if len(m.Highlights) != 2 {
+		t.Fatalf("Highlights = %v, want both matches", m.Highlights)
+	}
+	for _, h := range m.Highlights {
+		if m.Snippet[h[0]:h[1]] != "cluster" {
+			t.Errorf("highlight %v = %q", h, m.Snippet[h[0]:h[1]])
+		}
+	}
+	if m.Step == nil || *m.Step != 3 {
+		t.Errorf("Step = %v, want 3", m.Step)
+	}
+}
+
+func Test_IndexUpdates(t *testing.T) {
+	i := newTestIndex(kubernetesBasics, platformCourse)
+
+	if got := i.CourseScenarios("platform"); !reflect.DeepEqual(got, []string{"docker-intro", "k8s-basics"}) {
+		t.Errorf("CourseScenarios() = %v", got)
+	}
+
+	renamed := kubernetesBasics.DeepCopy()
+	renamed.Spec.Name = "Cluster Basics"
+	i.putObject(renamed)
+
+	if got := ids(i.Search(Query{Text: "cluster basics"})); !reflect.DeepEqual(got, []string{"scenario/k8s-basics"}) {
+		t.Errorf("Search() for the new name = %v", got)
+	}
+	// the course still mentions kubernetes, the old name of the scenario is gone
+	if got := ids(i.Search(Query{Text: "kubernetes"})); !reflect.DeepEqual(got, []string{"course/platform"}) {
+		t.Errorf("Search() for the old name = %v", got)
+	}
+
+	i.deleteObject(cache.DeletedFinalStateUnknown{Key: "platform", Obj: platformCourse})
+	i.deleteObject(renamed)
+
+	if len(i.docs) != 0 || len(i.terms) != 0 {
+		t.Errorf("index keeps %d documents and %d terms after deleting everything", len(i.docs), len(i.terms))
+	}
+	if got := i.CourseScenarios("platform"); got != nil {
+		t.Errorf("CourseScenarios() of a deleted course = %v", got)
+	}
+}
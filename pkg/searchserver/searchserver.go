@@ -0,0 +1,156 @@
+package searchserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/search"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+)
+
+const (
+	scenarioPlural = "scenarios"
+	coursePlural   = "courses"
+)
+
+type SearchServer struct {
+	auth     *authclient.AuthClient
+	acClient *accesscode.AccessCodeClient
+	index    *search.Index
+}
+
+func NewSearchServer(authClient *authclient.AuthClient, acClient *accesscode.AccessCodeClient, hfInformerFactory hfInformers.SharedInformerFactory) (*SearchServer, error) {
+	s := SearchServer{}
+
+	s.auth = authClient
+	s.acClient = acClient
+	s.index = search.NewIndex(hfInformerFactory)
+
+	return &s, nil
+}
+
+func (s SearchServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/search", s.SearchFunc).Methods("GET")
+	r.HandleFunc("/a/search", s.AdminSearchFunc).Methods("GET")
+	glog.V(2).Infof("set up routes for search server")
+}
+
+// AdminSearchFunc searches all scenarios and courses, drafts included, the caller may list.
+// Query parameters are q, kind (scenario or course) and limit.
+func (s SearchServer) AdminSearchFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to search")
+		return
+	}
+
+	_, scenarioErr := s.auth.VerifyRBAC(rbacclient.RbacRequest().HobbyfarmPermission(scenarioPlural, rbacclient.VerbList), user)
+	_, courseErr := s.auth.VerifyRBAC(rbacclient.RbacRequest().HobbyfarmPermission(coursePlural, rbacclient.VerbList), user)
+	if scenarioErr != nil && courseErr != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to search")
+		return
+	}
+
+	query, ok := parseQuery(w, r)
+	if !ok {
+		return
+	}
+	query.IncludeDrafts = true
+	query.Allowed = func(kind string, id string) bool {
+		if kind == search.KindScenario {
+			return scenarioErr == nil
+		}
+		return courseErr == nil
+	}
+
+	s.returnResults(w, r, query)
+}
+
+// SearchFunc searches the published scenarios and courses reachable through the access codes of the caller
+func (s SearchServer) SearchFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to search")
+		return
+	}
+
+	query, ok := parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	scenarios := map[string]bool{}
+	courses := map[string]bool{}
+	if len(user.Spec.AccessCodes) > 0 {
+		accessCodes, err := s.acClient.GetAccessCodesWithOTACs(user.Spec.AccessCodes)
+		if err != nil {
+			glog.Errorf("error retrieving access codes of user %s: %v", user.Name, err)
+		}
+		for _, ac := range accessCodes {
+			for _, id := range ac.Spec.Scenarios {
+				scenarios[id] = true
+			}
+			for _, id := range ac.Spec.Courses {
+				courses[id] = true
+				// the scenarios of a course are reachable through it
+				for _, scenarioId := range s.index.CourseScenarios(id) {
+					scenarios[scenarioId] = true
+				}
+			}
+		}
+	}
+
+	query.Allowed = func(kind string, id string) bool {
+		if kind == search.KindScenario {
+			return scenarios[id]
+		}
+		return courses[id]
+	}
+
+	s.returnResults(w, r, query)
+}
+
+func (s SearchServer) returnResults(w http.ResponseWriter, r *http.Request, query search.Query) {
+	results := s.index.Search(query)
+
+	encodedResults, err := json.Marshal(results)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedResults)
+}
+
+func parseQuery(w http.ResponseWriter, r *http.Request) (search.Query, bool) {
+	query := search.Query{
+		Text: r.URL.Query().Get("q"),
+		Kind: r.URL.Query().Get("kind"),
+	}
+
+	if query.Text == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no query passed in")
+		return query, false
+	}
+
+	if query.Kind != "" && query.Kind != search.KindScenario && query.Kind != search.KindCourse {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "kind has to be scenario or course")
+		return query, false
+	}
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid limit")
+			return query, false
+		}
+		query.Limit = limit
+	}
+
+	return query, true
+}
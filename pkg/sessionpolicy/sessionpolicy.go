@@ -0,0 +1,270 @@
+package sessionpolicy
+
+import (
+	"fmt"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+const (
+	ReasonMaxSessionDuration = "max_session_duration"
+	ReasonMaxKeepAliveCount  = "max_keepalive_count"
+	ReasonMaxPauseCount      = "max_pause_count"
+	ReasonMaxPauseDuration   = "max_pause_duration"
+
+	// Unlimited is reported for counters that are not limited by any policy
+	Unlimited = -1
+)
+
+// Limits is the effective policy for a session, after merging every SessionPolicy that applies to it.
+// Zero values mean no limit.
+type Limits struct {
+	MaxSessionDuration time.Duration
+	MaxKeepAliveCount  int
+	MaxPauseCount      int
+	MaxPauseDuration   time.Duration
+}
+
+// RefusedError is returned when a session may not be extended any further
+type RefusedError struct {
+	Reason  string
+	Message string
+}
+
+func (r RefusedError) Error() string {
+	return r.Message
+}
+
+func IsRefused(err error) (RefusedError, bool) {
+	re, ok := err.(RefusedError)
+	return re, ok
+}
+
+// Remaining tells the UI how much longer a session can be used, and why it was refused if it was
+type Remaining struct {
+	ExpirationTime       string `json:"end_time"`
+	TimeRemaining        string `json:"time_remaining"`
+	SessionTimeRemaining string `json:"session_time_remaining,omitempty"` // until max_session_duration is reached, empty if unlimited
+	KeepAlivesRemaining  int    `json:"keepalives_remaining"`             // -1 if unlimited
+	PausesRemaining      int    `json:"pauses_remaining"`                 // -1 if unlimited
+	PauseTimeRemaining   string `json:"pause_time_remaining,omitempty"`   // empty if unlimited
+	Reason               string `json:"reason,omitempty"`
+	Message              string `json:"message,omitempty"`
+}
+
+// Merge combines policies into a single set of limits. The strictest non-zero value wins.
+func Merge(policies ...hfv1.SessionPolicy) (Limits, error) {
+	l := Limits{}
+
+	for _, p := range policies {
+		maxSession, err := parseDuration(p.MaxSessionDuration)
+		if err != nil {
+			return Limits{}, fmt.Errorf("invalid max_session_duration %s: %v", p.MaxSessionDuration, err)
+		}
+		maxPause, err := parseDuration(p.MaxPauseDuration)
+		if err != nil {
+			return Limits{}, fmt.Errorf("invalid max_pause_duration %s: %v", p.MaxPauseDuration, err)
+		}
+		if p.MaxKeepAliveCount < 0 || p.MaxPauseCount < 0 {
+			return Limits{}, fmt.Errorf("max_keepalive_count and max_pause_count may not be negative")
+		}
+
+		l.MaxSessionDuration = minDuration(l.MaxSessionDuration, maxSession)
+		l.MaxPauseDuration = minDuration(l.MaxPauseDuration, maxPause)
+		l.MaxKeepAliveCount = minInt(l.MaxKeepAliveCount, p.MaxKeepAliveCount)
+		l.MaxPauseCount = minInt(l.MaxPauseCount, p.MaxPauseCount)
+	}
+
+	return l, nil
+}
+
+// Validate checks that the durations in a policy can be parsed
+func Validate(policy hfv1.SessionPolicy) error {
+	_, err := Merge(policy)
+	return err
+}
+
+// Deadline returns the point in time after which the session may not be used anymore.
+// Time an admin granted to the session is added on top of the maximum duration.
+// The second return value is false if the session lifetime is not limited.
+func (l Limits) Deadline(status hfv1.SessionStatus) (time.Time, bool) {
+	if l.MaxSessionDuration == 0 {
+		return time.Time{}, false
+	}
+
+	start, err := time.Parse(time.UnixDate, status.StartTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	extended, err := parseDuration(status.ExtendedDuration)
+	if err != nil {
+		extended = 0
+	}
+
+	return start.Add(l.MaxSessionDuration + extended), true
+}
+
+// Exceeded returns true if the session has outlived its maximum lifetime
+func (l Limits) Exceeded(status hfv1.SessionStatus, now time.Time) bool {
+	deadline, bounded := l.Deadline(status)
+	return bounded && !deadline.After(now)
+}
+
+// CapExpiration makes sure an expiration does not reach past the session deadline
+func (l Limits) CapExpiration(status hfv1.SessionStatus, expiration time.Time) time.Time {
+	deadline, bounded := l.Deadline(status)
+	if bounded && expiration.After(deadline) {
+		return deadline
+	}
+
+	return expiration
+}
+
+// KeepAlive returns the new expiration time for a session that is kept alive for timeout,
+// or a RefusedError if the session may not be extended anymore.
+func (l Limits) KeepAlive(status hfv1.SessionStatus, timeout time.Duration, now time.Time) (time.Time, error) {
+	if l.MaxKeepAliveCount > 0 && status.KeepAliveCount >= l.MaxKeepAliveCount {
+		return time.Time{}, RefusedError{
+			Reason:  ReasonMaxKeepAliveCount,
+			Message: fmt.Sprintf("session was already kept alive %d times", status.KeepAliveCount),
+		}
+	}
+
+	if l.Exceeded(status, now) {
+		return time.Time{}, RefusedError{
+			Reason:  ReasonMaxSessionDuration,
+			Message: fmt.Sprintf("session reached its maximum duration of %s", l.MaxSessionDuration),
+		}
+	}
+
+	return l.CapExpiration(status, now.Add(timeout)), nil
+}
+
+// Pause returns the time a pause starting now expires at, or a RefusedError if the session may not be paused anymore.
+func (l Limits) Pause(status hfv1.SessionStatus, timeout time.Duration, now time.Time) (time.Time, error) {
+	if l.MaxPauseCount > 0 && status.PauseCount >= l.MaxPauseCount {
+		return time.Time{}, RefusedError{
+			Reason:  ReasonMaxPauseCount,
+			Message: fmt.Sprintf("session was already paused %d times", status.PauseCount),
+		}
+	}
+
+	if l.MaxPauseDuration > 0 {
+		remaining := l.MaxPauseDuration - PausedDuration(status, now)
+		if remaining <= 0 {
+			return time.Time{}, RefusedError{
+				Reason:  ReasonMaxPauseDuration,
+				Message: fmt.Sprintf("session was already paused for %s", l.MaxPauseDuration),
+			}
+		}
+		if remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	if l.Exceeded(status, now) {
+		return time.Time{}, RefusedError{
+			Reason:  ReasonMaxSessionDuration,
+			Message: fmt.Sprintf("session reached its maximum duration of %s", l.MaxSessionDuration),
+		}
+	}
+
+	return l.CapExpiration(status, now.Add(timeout)), nil
+}
+
+// PausedDuration returns the total time a session spent paused, including a pause that is still running
+func PausedDuration(status hfv1.SessionStatus, now time.Time) time.Duration {
+	total, err := parseDuration(status.TotalPauseDuration)
+	if err != nil {
+		total = 0
+	}
+
+	if status.Paused && status.PauseStartTime != "" {
+		pauseStart, err := time.Parse(time.UnixDate, status.PauseStartTime)
+		if err == nil && now.After(pauseStart) {
+			total += now.Sub(pauseStart)
+		}
+	}
+
+	return total
+}
+
+// Remaining summarizes how much of the policy is left for a session
+func (l Limits) Remaining(status hfv1.SessionStatus, now time.Time) Remaining {
+	r := Remaining{
+		ExpirationTime:      status.ExpirationTime,
+		KeepAlivesRemaining: Unlimited,
+		PausesRemaining:     Unlimited,
+	}
+
+	expiration, err := time.Parse(time.UnixDate, status.ExpirationTime)
+	if err == nil {
+		r.TimeRemaining = nonNegative(expiration.Sub(now)).String()
+	}
+
+	if deadline, bounded := l.Deadline(status); bounded {
+		r.SessionTimeRemaining = nonNegative(deadline.Sub(now)).String()
+	}
+
+	if l.MaxKeepAliveCount > 0 {
+		r.KeepAlivesRemaining = maxInt(l.MaxKeepAliveCount-status.KeepAliveCount, 0)
+	}
+
+	if l.MaxPauseCount > 0 {
+		r.PausesRemaining = maxInt(l.MaxPauseCount-status.PauseCount, 0)
+	}
+
+	if l.MaxPauseDuration > 0 {
+		r.PauseTimeRemaining = nonNegative(l.MaxPauseDuration - PausedDuration(status, now)).String()
+	}
+
+	return r
+}
+
+func parseDuration(d string) (time.Duration, error) {
+	if d == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration may not be negative")
+	}
+
+	return duration, nil
+}
+
+// minDuration returns the smaller of two durations, ignoring zero (unlimited) values
+func minDuration(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// minInt returns the smaller of two limits, ignoring zero (unlimited) values
+func minInt(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func maxInt(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d.Round(time.Second)
+}
@@ -0,0 +1,343 @@
+package sessionpolicy
+
+import (
+	"testing"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+var testNow = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func ago(d time.Duration) string {
+	return testNow.Add(-d).Format(time.UnixDate)
+}
+
+func Test_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []hfv1.SessionPolicy
+		want     Limits
+		wantErr  bool
+	}{
+		{
+			name: "no policies are unlimited",
+			want: Limits{},
+		},
+		{
+			name: "stricter course limits override the scenario",
+			policies: []hfv1.SessionPolicy{
+				{MaxSessionDuration: "1h", MaxKeepAliveCount: 2},                   // course
+				{MaxSessionDuration: "2h", MaxKeepAliveCount: 5, MaxPauseCount: 3}, // scenario
+			},
+			want: Limits{MaxSessionDuration: time.Hour, MaxKeepAliveCount: 2, MaxPauseCount: 3},
+		},
+		{
+			name: "looser course limits do not lift the scenario limits",
+			policies: []hfv1.SessionPolicy{
+				{MaxSessionDuration: "4h", MaxPauseDuration: "1h"},  // course
+				{MaxSessionDuration: "2h", MaxPauseDuration: "30m"}, // scenario
+			},
+			want: Limits{MaxSessionDuration: 2 * time.Hour, MaxPauseDuration: 30 * time.Minute},
+		},
+		{
+			name: "unset values do not count as limits",
+			policies: []hfv1.SessionPolicy{
+				{},
+				{MaxPauseCount: 1},
+				{MaxPauseDuration: "10m"},
+			},
+			want: Limits{MaxPauseCount: 1, MaxPauseDuration: 10 * time.Minute},
+		},
+		{
+			name:     "invalid duration",
+			policies: []hfv1.SessionPolicy{{MaxSessionDuration: "forever"}},
+			wantErr:  true,
+		},
+		{
+			name:     "negative duration",
+			policies: []hfv1.SessionPolicy{{MaxPauseDuration: "-1h"}},
+			wantErr:  true,
+		},
+		{
+			name:     "negative count",
+			policies: []hfv1.SessionPolicy{{MaxKeepAliveCount: -1}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(tt.policies...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Deadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      Limits
+		status      hfv1.SessionStatus
+		want        time.Time
+		wantBounded bool
+	}{
+		{
+			name:   "unlimited",
+			limits: Limits{},
+			status: hfv1.SessionStatus{StartTime: ago(time.Hour)},
+		},
+		{
+			name:        "counted from the start",
+			limits:      Limits{MaxSessionDuration: 2 * time.Hour},
+			status:      hfv1.SessionStatus{StartTime: ago(time.Hour)},
+			want:        testNow.Add(time.Hour),
+			wantBounded: true,
+		},
+		{
+			name:        "extended by an admin",
+			limits:      Limits{MaxSessionDuration: 2 * time.Hour},
+			status:      hfv1.SessionStatus{StartTime: ago(time.Hour), ExtendedDuration: "30m"},
+			want:        testNow.Add(90 * time.Minute),
+			wantBounded: true,
+		},
+		{
+			name:        "unparsable extension is ignored",
+			limits:      Limits{MaxSessionDuration: 2 * time.Hour},
+			status:      hfv1.SessionStatus{StartTime: ago(time.Hour), ExtendedDuration: "a while"},
+			want:        testNow.Add(time.Hour),
+			wantBounded: true,
+		},
+		{
+			name:   "unparsable start is unbounded",
+			limits: Limits{MaxSessionDuration: 2 * time.Hour},
+			status: hfv1.SessionStatus{StartTime: "yesterday"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, bounded := tt.limits.Deadline(tt.status)
+			if bounded != tt.wantBounded {
+				t.Fatalf("Deadline() bounded = %v, want %v", bounded, tt.wantBounded)
+			}
+			if bounded && !got.Equal(tt.want) {
+				t.Errorf("Deadline() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CapExpiration(t *testing.T) {
+	limits := Limits{MaxSessionDuration: 2 * time.Hour}
+	status := hfv1.SessionStatus{StartTime: ago(time.Hour)}
+
+	tests := []struct {
+		name       string
+		limits     Limits
+		expiration time.Time
+		want       time.Time
+	}{
+		{"before the deadline", limits, testNow.Add(30 * time.Minute), testNow.Add(30 * time.Minute)},
+		{"past the deadline is capped", limits, testNow.Add(3 * time.Hour), testNow.Add(time.Hour)},
+		{"unlimited is never capped", Limits{}, testNow.Add(3 * time.Hour), testNow.Add(3 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.CapExpiration(status, tt.expiration); !got.Equal(tt.want) {
+				t.Errorf("CapExpiration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_KeepAlive(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     Limits
+		status     hfv1.SessionStatus
+		want       time.Time
+		wantReason string
+	}{
+		{
+			name:   "unlimited",
+			limits: Limits{},
+			status: hfv1.SessionStatus{StartTime: ago(10 * time.Hour), KeepAliveCount: 100},
+			want:   testNow.Add(time.Hour),
+		},
+		{
+			name:   "capped by the max lifetime",
+			limits: Limits{MaxSessionDuration: 2 * time.Hour},
+			status: hfv1.SessionStatus{StartTime: ago(90 * time.Minute)},
+			want:   testNow.Add(30 * time.Minute),
+		},
+		{
+			name:       "max lifetime reached",
+			limits:     Limits{MaxSessionDuration: 2 * time.Hour},
+			status:     hfv1.SessionStatus{StartTime: ago(2 * time.Hour)},
+			wantReason: ReasonMaxSessionDuration,
+		},
+		{
+			name:   "extension lifts the max lifetime",
+			limits: Limits{MaxSessionDuration: 2 * time.Hour},
+			status: hfv1.SessionStatus{StartTime: ago(2 * time.Hour), ExtendedDuration: "2h"},
+			want:   testNow.Add(time.Hour),
+		},
+		{
+			name:   "keepalives left",
+			limits: Limits{MaxKeepAliveCount: 3},
+			status: hfv1.SessionStatus{StartTime: ago(time.Hour), KeepAliveCount: 2},
+			want:   testNow.Add(time.Hour),
+		},
+		{
+			name:       "keepalives used up",
+			limits:     Limits{MaxKeepAliveCount: 3},
+			status:     hfv1.SessionStatus{StartTime: ago(time.Hour), KeepAliveCount: 3},
+			wantReason: ReasonMaxKeepAliveCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.limits.KeepAlive(tt.status, time.Hour, testNow)
+			checkRefused(t, err, tt.wantReason)
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("KeepAlive() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Pause(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     Limits
+		status     hfv1.SessionStatus
+		want       time.Time
+		wantReason string
+	}{
+		{
+			name:   "unlimited",
+			limits: Limits{},
+			status: hfv1.SessionStatus{StartTime: ago(time.Hour), PauseCount: 10},
+			want:   testNow.Add(time.Hour),
+		},
+		{
+			name:       "pauses used up",
+			limits:     Limits{MaxPauseCount: 2},
+			status:     hfv1.SessionStatus{StartTime: ago(time.Hour), PauseCount: 2},
+			wantReason: ReasonMaxPauseCount,
+		},
+		{
+			name:   "shortened to the remaining pause time",
+			limits: Limits{MaxPauseDuration: time.Hour},
+			status: hfv1.SessionStatus{StartTime: ago(time.Hour), TotalPauseDuration: "40m"},
+			want:   testNow.Add(20 * time.Minute),
+		},
+		{
+			name:       "accumulated pauses used up the pause time",
+			limits:     Limits{MaxPauseDuration: time.Hour},
+			status:     hfv1.SessionStatus{StartTime: ago(2 * time.Hour), TotalPauseDuration: "45m", Paused: true, PauseStartTime: ago(15 * time.Minute)},
+			wantReason: ReasonMaxPauseDuration,
+		},
+		{
+			name:   "capped by the max lifetime",
+			limits: Limits{MaxSessionDuration: 2 * time.Hour},
+			status: hfv1.SessionStatus{StartTime: ago(110 * time.Minute)},
+			want:   testNow.Add(10 * time.Minute),
+		},
+		{
+			name:       "max lifetime reached",
+			limits:     Limits{MaxSessionDuration: 2 * time.Hour},
+			status:     hfv1.SessionStatus{StartTime: ago(3 * time.Hour)},
+			wantReason: ReasonMaxSessionDuration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.limits.Pause(tt.status, time.Hour, testNow)
+			checkRefused(t, err, tt.wantReason)
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("Pause() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_PausedDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		status hfv1.SessionStatus
+		want   time.Duration
+	}{
+		{
+			name:   "never paused",
+			status: hfv1.SessionStatus{},
+			want:   0,
+		},
+		{
+			name:   "accumulated pauses",
+			status: hfv1.SessionStatus{TotalPauseDuration: "25m"},
+			want:   25 * time.Minute,
+		},
+		{
+			name:   "running pause is added",
+			status: hfv1.SessionStatus{TotalPauseDuration: "25m", Paused: true, PauseStartTime: ago(5 * time.Minute)},
+			want:   30 * time.Minute,
+		},
+		{
+			name:   "pause start of a resumed session is ignored",
+			status: hfv1.SessionStatus{TotalPauseDuration: "25m", Paused: false, PauseStartTime: ago(5 * time.Minute)},
+			want:   25 * time.Minute,
+		},
+		{
+			name:   "unparsable pause start is ignored",
+			status: hfv1.SessionStatus{TotalPauseDuration: "25m", Paused: true, PauseStartTime: "a minute ago"},
+			want:   25 * time.Minute,
+		},
+		{
+			name:   "pause start in the future is ignored",
+			status: hfv1.SessionStatus{Paused: true, PauseStartTime: ago(-5 * time.Minute)},
+			want:   0,
+		},
+		{
+			name:   "unparsable total is ignored",
+			status: hfv1.SessionStatus{TotalPauseDuration: "long", Paused: true, PauseStartTime: ago(5 * time.Minute)},
+			want:   5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PausedDuration(tt.status, testNow); got != tt.want {
+				t.Errorf("PausedDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func checkRefused(t *testing.T, err error, wantReason string) {
+	t.Helper()
+
+	if wantReason == "" {
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return
+	}
+
+	refused, ok := IsRefused(err)
+	if !ok {
+		t.Fatalf("error = %v, want refusal %s", err, wantReason)
+	}
+	if refused.Reason != wantReason {
+		t.Errorf("refused with %s, want %s", refused.Reason, wantReason)
+	}
+}
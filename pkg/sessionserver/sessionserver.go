@@ -7,18 +7,27 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/hobbyfarm/gargantua/v3/pkg/accesscode"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/completion"
 	"github.com/hobbyfarm/gargantua/v3/pkg/courseclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/quiz"
+	"github.com/hobbyfarm/gargantua/v3/pkg/revision"
 	"github.com/hobbyfarm/gargantua/v3/pkg/scenarioclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/sessionpolicy"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"github.com/hobbyfarm/gargantua/v3/pkg/xapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
@@ -26,6 +35,7 @@ import (
 
 const (
 	ssIndex            = "sss.hobbyfarm.io/session-id-index"
+	inviteIndex        = "sss.hobbyfarm.io/session-invite-index"
 	newSSTimeout       = "5m"
 	keepaliveSSTimeout = "5m"
 	pauseSSTimeout     = "2h"
@@ -39,12 +49,22 @@ type SessionServer struct {
 	accessCodeClient *accesscode.AccessCodeClient
 	auth             *authclient.AuthClient
 	ssIndexer        cache.Indexer
+	vmClaimLister    hfListers.VirtualMachineClaimLister
 	ctx              context.Context
 }
 
 type preparedSession struct {
 	ID string `json:"id"`
 	hfv1.SessionSpec
+	Queued        bool `json:"queued"`                   // at least one vmclaim is waiting for capacity
+	QueuePosition int  `json:"queue_position,omitempty"` // position of the vmclaim furthest back in the queue
+}
+
+type adminPreparedSession struct {
+	ID             string `json:"id"`
+	ScheduledEvent string `json:"scheduled_event"`
+	hfv1.SessionSpec
+	hfv1.SessionStatus
 }
 
 func NewSessionServer(authClient *authclient.AuthClient, accessCodeClient *accesscode.AccessCodeClient, scenarioClient *scenarioclient.ScenarioClient, courseClient *courseclient.CourseClient, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, ctx context.Context) (*SessionServer, error) {
@@ -55,9 +75,10 @@ func NewSessionServer(authClient *authclient.AuthClient, accessCodeClient *acces
 	a.auth = authClient
 	a.accessCodeClient = accessCodeClient
 	inf := hfInformerFactory.Hobbyfarm().V1().Sessions().Informer()
-	indexers := map[string]cache.IndexFunc{ssIndex: ssIdIndexer}
+	indexers := map[string]cache.IndexFunc{ssIndex: ssIdIndexer, inviteIndex: ssInviteIndexer}
 	inf.AddIndexers(indexers)
 	a.ssIndexer = inf.GetIndexer()
+	a.vmClaimLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
 	a.ctx = ctx
 
 	return &a, nil
@@ -65,11 +86,22 @@ func NewSessionServer(authClient *authclient.AuthClient, accessCodeClient *acces
 
 func (sss SessionServer) SetupRoutes(r *mux.Router) {
 	r.HandleFunc("/session/new", sss.NewSessionFunc).Methods("POST")
+	r.HandleFunc("/session/join", sss.JoinSessionFunc).Methods("POST")
 	r.HandleFunc("/session/{session_id}", sss.GetSessionFunc).Methods("GET")
 	r.HandleFunc("/session/{session_id}/finished", sss.FinishedSessionFunc).Methods("PUT")
 	r.HandleFunc("/session/{session_id}/keepalive", sss.KeepAliveSessionFunc).Methods("PUT")
 	r.HandleFunc("/session/{session_id}/pause", sss.PauseSessionFunc).Methods("PUT")
 	r.HandleFunc("/session/{session_id}/resume", sss.ResumeSessionFunc).Methods("PUT")
+	r.HandleFunc("/session/{session_id}/invite", sss.InviteSessionFunc).Methods("PUT")
+	r.HandleFunc("/session/{session_id}/invite", sss.RevokeInviteSessionFunc).Methods("DELETE")
+	r.HandleFunc("/session/{session_id}/leave", sss.LeaveSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/list", sss.ListSessionsFunc).Methods("GET")
+	r.HandleFunc("/a/session/{session_id}/finish", sss.AdminFinishSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/{session_id}/extend", sss.AdminExtendSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/{session_id}/pause", sss.AdminPauseSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/{session_id}/resume", sss.AdminResumeSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/{session_id}/reassign", sss.AdminReassignSessionFunc).Methods("PUT")
+	r.HandleFunc("/a/session/{session_id}/vm/{vm_id}/replace", sss.AdminReplaceVMFunc).Methods("PUT")
 	glog.V(2).Infof("set up routes for session server")
 }
 
@@ -142,6 +174,42 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// sessions run on the revisions pinned by the scheduled event, or on the current ones.
+	// drafts are not available to learners.
+	pinnedScenarios, pinnedCourses := sss.pinnedRevisions(accessCodeObj.Labels[util.ScheduledEventLabel])
+	if courseid != "" {
+		course.Spec, err = revision.CourseSpec(sss.ctx, sss.hfClientSet, course, revision.Pinned(pinnedCourses, course.Name, course.Spec.Revision))
+		if err != nil || !revision.Published(course.Spec.State) {
+			glog.Errorf("course %s not available %v", courseid, err)
+			util.ReturnHTTPMessage(w, r, 404, "error", "no course found")
+			return
+		}
+	}
+	if scenarioid != "" {
+		scenario.Spec, err = revision.ScenarioSpec(sss.ctx, sss.hfClientSet, scenario, revision.Pinned(pinnedScenarios, scenario.Name, scenario.Spec.Revision))
+		if err != nil || !revision.Published(scenario.Spec.State) {
+			glog.Errorf("scenario %s not available %v", scenarioid, err)
+			util.ReturnHTTPMessage(w, r, 404, "error", "no scenario found")
+			return
+		}
+	}
+
+	// courses may require scenarios to be completed in order
+	if courseid != "" && scenarioid != "" && course.Spec.Sequence != hfv1.CourseSequenceAny {
+		progress, err := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", util.UserLabel, user.Name)})
+		if err != nil {
+			glog.Errorf("error retrieving progress of user %s: %v", user.Name, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "error retrieving progress")
+			return
+		}
+		missing := completion.Missing(course.Spec, scenario.Name, completion.CompletedScenarios(progress.Items, course.Name))
+		if len(missing) > 0 {
+			util.ReturnHTTPMessage(w, r, 409, "prerequisites", fmt.Sprintf("scenarios %s have to be completed first", strings.Join(missing, ", ")))
+			return
+		}
+	}
+
 	// now we should check for existing sessions for the user
 	sessions, err := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", util.UserLabel, user.Name),
@@ -176,13 +244,14 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 					}
 
 					result.Spec.ScenarioId = scenarioid
+					result.Spec.ScenarioRevision = scenario.Spec.Revision
 
 					_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
 					glog.V(4).Infof("updated session for new scenario")
 
 					//finish old progress & create new progress for the new scenario
 					sss.FinishProgress(result.Name, user.Name)
-					sss.CreateProgress(result.Name, accessCodeObj.Labels[util.ScheduledEventLabel], scenario.Name, course.Name, user.Name, len(scenario.Spec.Steps))
+					sss.CreateProgress(result.Name, accessCodeObj.Labels[util.ScheduledEventLabel], scenario.Name, course.Name, user.Name, len(scenario.Spec.Steps), quiz.MaxScore(scenario.Spec.Steps))
 
 					return updateErr
 				})
@@ -195,7 +264,7 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 
 			}
 
-			preparedSession := preparedSession{v.Name, v.Spec}
+			preparedSession := sss.prepareSession(v)
 			encodedSS, err := json.Marshal(preparedSession)
 			if err != nil {
 				glog.Error(err)
@@ -212,6 +281,8 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 	session.Name = sessionName
 	session.Spec.CourseId = course.Name
 	session.Spec.ScenarioId = scenario.Name
+	session.Spec.ScenarioRevision = scenario.Spec.Revision
+	session.Spec.CourseRevision = course.Spec.Revision
 	session.Spec.UserId = user.Name
 	session.Spec.KeepCourseVM = course.Spec.KeepVM
 	labels := make(map[string]string)
@@ -232,11 +303,12 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	schedEvent, err := sss.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).Get(sss.ctx, owners[0].Name, metav1.GetOptions{})
+	schedEvent, err := sss.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(sss.ctx, owners[0].Name, metav1.GetOptions{})
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 500, "error", "unable to find scheduledEvent")
 		return
 	}
+	session.Labels[util.ScheduledEventLabel] = schedEvent.Name // map scheduledevent to session
 
 	var bindMode string
 	var baseName string
@@ -329,7 +401,12 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 	createdSession.Status.StartTime = now.Format(time.UnixDate)
 	duration, _ := time.ParseDuration(ssTimeout)
 
-	createdSession.Status.ExpirationTime = now.Add(duration).Format(time.UnixDate)
+	limits, err := sessionpolicy.Merge(course.Spec.SessionPolicy, scenario.Spec.SessionPolicy, schedEvent.Spec.SessionPolicy)
+	if err != nil {
+		glog.Errorf("error merging session policies for session %s: %v", createdSession.Name, err)
+	}
+
+	createdSession.Status.ExpirationTime = limits.CapExpiration(createdSession.Status, now.Add(duration)).Format(time.UnixDate)
 	createdSession.Status.Active = true
 	createdSession.Status.Finished = false
 
@@ -343,9 +420,9 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 
 	glog.V(2).Infof("created session ID %s", createdSession.Name)
 
-	sss.CreateProgress(createdSession.Name, accessCodeObj.Labels[util.ScheduledEventLabel], scenario.Name, course.Name, user.Name, len(scenario.Spec.Steps))
+	sss.CreateProgress(createdSession.Name, accessCodeObj.Labels[util.ScheduledEventLabel], scenario.Name, course.Name, user.Name, len(scenario.Spec.Steps), quiz.MaxScore(scenario.Spec.Steps))
 
-	preparedSession := preparedSession{createdSession.Name, createdSession.Spec}
+	preparedSession := sss.prepareSession(*createdSession)
 	encodedSS, err := json.Marshal(preparedSession)
 	if err != nil {
 		glog.Error(err)
@@ -354,28 +431,39 @@ func (sss SessionServer) NewSessionFunc(w http.ResponseWriter, r *http.Request)
 	return
 }
 
-func (sss SessionServer) CreateProgress(sessionId string, scheduledEventId string, scenarioId string, courseId string, userId string, totalSteps int) {
+// pinnedRevisions returns the scenario and course revisions a scheduled event pinned when it was provisioned
+func (sss SessionServer) pinnedRevisions(seId string) (map[string]int, map[string]int) {
+	if seId == "" {
+		return nil, nil
+	}
+
+	se, err := sss.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(sss.ctx, seId, metav1.GetOptions{})
+	if err != nil {
+		glog.Errorf("error retrieving scheduled event %s: %v", seId, err)
+		return nil, nil
+	}
+
+	return se.Status.ScenarioRevisions, se.Status.CourseRevisions
+}
+
+func (sss SessionServer) CreateProgress(sessionId string, scheduledEventId string, scenarioId string, courseId string, userId string, totalSteps int, maxScore int) {
 	random := util.RandStringRunes(16)
 	now := time.Now()
 
 	progressName := util.GenerateResourceName("progress", random, 16)
-	progress := hfv1.Progress{}
+	progress := hfv2.Progress{}
 
 	progress.Name = progressName
 	progress.Spec.Course = courseId
 	progress.Spec.Scenario = scenarioId
 	progress.Spec.UserId = userId
-	progress.Spec.Started = now.Format(time.UnixDate)
-	progress.Spec.LastUpdate = now.Format(time.UnixDate)
-	progress.Spec.Finished = "false"
+	progress.Spec.Started = metav1.NewTime(now)
+	progress.Spec.Finished = false
 	progress.Spec.TotalStep = totalSteps
-	progress.Spec.MaxStep = 0
-	progress.Spec.CurrentStep = 0
+	progress.Spec.MaxScore = maxScore
 
-	steps := []hfv1.ProgressStep{}
-	step := hfv1.ProgressStep{Step: 0, Timestamp: now.Format(time.UnixDate)}
-	steps = append(steps, step)
-	progress.Spec.Steps = steps
+	// opens the visit of the first step
+	progress.Spec.Visit(0, now)
 
 	labels := make(map[string]string)
 	labels[util.SessionLabel] = sessionId               // map to session
@@ -384,7 +472,7 @@ func (sss SessionServer) CreateProgress(sessionId string, scheduledEventId strin
 	labels["finished"] = "false"                        // default is in progress, finished = false
 	progress.Labels = labels
 
-	createdProgress, err := sss.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).Create(sss.ctx, &progress, metav1.CreateOptions{})
+	createdProgress, err := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Create(sss.ctx, &progress, metav1.CreateOptions{})
 
 	if err != nil {
 		glog.Errorf("error creating progress %v", err)
@@ -392,12 +480,14 @@ func (sss SessionServer) CreateProgress(sessionId string, scheduledEventId strin
 	}
 
 	glog.V(2).Infof("created progress with ID %s", createdProgress.Name)
+
+	xapi.Record(sss.ctx, sss.hfClientSet, xapi.VerbLaunched, createdProgress)
 }
 
 func (sss SessionServer) FinishProgress(sessionId string, userId string) {
 	now := time.Now()
 
-	progress, err := sss.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
+	progress, err := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s,%s=%s,finished=false", util.SessionLabel, sessionId, util.UserLabel, userId)})
 
 	if err != nil {
@@ -406,12 +496,14 @@ func (sss SessionServer) FinishProgress(sessionId string, userId string) {
 	}
 
 	for _, p := range progress.Items {
+		var updated *hfv2.Progress
 		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var updateErr error
 			p.Labels["finished"] = "true"
-			p.Spec.LastUpdate = now.Format(time.UnixDate)
-			p.Spec.Finished = "true"
+			p.Spec.LastUpdate = metav1.NewTime(now)
+			p.Spec.Finished = true
 
-			_, updateErr := sss.hfClientSet.HobbyfarmV1().Progresses(util.GetReleaseNamespace()).Update(sss.ctx, &p, metav1.UpdateOptions{})
+			updated, updateErr = sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(sss.ctx, &p, metav1.UpdateOptions{})
 			glog.V(4).Infof("updated progress with ID %s", p.Name)
 
 			return updateErr
@@ -420,6 +512,8 @@ func (sss SessionServer) FinishProgress(sessionId string, userId string) {
 			glog.Errorf("error finishing progress %v", err)
 			return
 		}
+
+		xapi.Record(sss.ctx, sss.hfClientSet, xapi.VerbTerminated, updated)
 	}
 }
 
@@ -447,9 +541,21 @@ func (sss SessionServer) FinishedSessionFunc(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	err = sss.finishSession(sessionId)
+	if err != nil {
+		glog.Errorf("error deleting session %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "updated session")
+	return
+}
+
+func (sss SessionServer) finishSession(sessionId string) error {
 	now := time.Now().Format(time.UnixDate)
 
-	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
 		if getErr != nil {
 			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
@@ -464,15 +570,6 @@ func (sss SessionServer) FinishedSessionFunc(w http.ResponseWriter, r *http.Requ
 
 		return updateErr
 	})
-
-	if retryErr != nil {
-		glog.Errorf("error deleting session %v", err)
-		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
-		return
-	}
-
-	util.ReturnHTTPMessage(w, r, 200, "updated", "updated session")
-	return
 }
 
 func (sss SessionServer) KeepAliveSessionFunc(w http.ResponseWriter, r *http.Request) {
@@ -490,7 +587,7 @@ func (sss SessionServer) KeepAliveSessionFunc(w http.ResponseWriter, r *http.Req
 	}
 
 	ss, err := sss.GetSessionById(sessionId)
-	if ss.Spec.UserId != user.Name {
+	if !util.IsSessionMember(&ss, user.Name) {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
 		return
 	}
@@ -551,7 +648,8 @@ func (sss SessionServer) KeepAliveSessionFunc(w http.ResponseWriter, r *http.Req
 	now := time.Now()
 	duration, _ := time.ParseDuration(ssTimeout)
 
-	expiration := now.Add(duration).Format(time.UnixDate)
+	limits := sss.getSessionLimits(ss, course, scenario)
+	var updated *hfv1.Session
 
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
@@ -559,21 +657,35 @@ func (sss SessionServer) KeepAliveSessionFunc(w http.ResponseWriter, r *http.Req
 			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
 		}
 
-		result.Status.ExpirationTime = expiration
+		expiration, policyErr := limits.KeepAlive(result.Status, duration, now)
+		if policyErr != nil {
+			updated = result
+			return policyErr
+		}
 
-		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		result.Status.ExpirationTime = expiration.Format(time.UnixDate)
+		result.Status.KeepAliveCount++
+
+		var updateErr error
+		updated, updateErr = sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
 		glog.V(4).Infof("updated expiration time for session")
 
 		return updateErr
 	})
 
+	if refused, ok := sessionpolicy.IsRefused(retryErr); ok {
+		glog.V(4).Infof("refused keepalive for session %s: %s", sessionId, refused.Message)
+		sss.returnRemaining(w, r, http.StatusConflict, "refused", limits, updated.Status, refused)
+		return
+	}
+
 	if retryErr != nil {
 		glog.Errorf("error creating session %v", err)
 		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
 		return
 	}
 
-	util.ReturnHTTPMessage(w, r, 202, "keepalived", "keepalive successful")
+	sss.returnRemaining(w, r, 202, "keepalived", limits, updated.Status, sessionpolicy.RefusedError{})
 	return
 }
 
@@ -592,7 +704,7 @@ func (sss SessionServer) PauseSessionFunc(w http.ResponseWriter, r *http.Request
 	}
 
 	ss, err := sss.GetSessionById(sessionId)
-	if ss.Spec.UserId != user.Name {
+	if !util.IsSessionMember(&ss, user.Name) {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
 		return
 	}
@@ -636,7 +748,8 @@ func (sss SessionServer) PauseSessionFunc(w http.ResponseWriter, r *http.Request
 	now := time.Now()
 	duration, _ := time.ParseDuration(ssTimeout)
 
-	pauseExpiration := now.Add(duration).Format(time.UnixDate)
+	limits := sss.getSessionLimits(ss, course, scenario)
+	var updated *hfv1.Session
 
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
@@ -644,22 +757,43 @@ func (sss SessionServer) PauseSessionFunc(w http.ResponseWriter, r *http.Request
 			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
 		}
 
-		result.Status.PausedTime = pauseExpiration
+		if result.Status.Paused {
+			// pausing twice would count against the policy twice
+			updated = result
+			return nil
+		}
+
+		pauseExpiration, policyErr := limits.Pause(result.Status, duration, now)
+		if policyErr != nil {
+			updated = result
+			return policyErr
+		}
+
+		result.Status.PausedTime = pauseExpiration.Format(time.UnixDate)
+		result.Status.PauseStartTime = now.Format(time.UnixDate)
 		result.Status.Paused = true
+		result.Status.PauseCount++
 
-		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		var updateErr error
+		updated, updateErr = sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
 		glog.V(4).Infof("updated result for course session")
 
 		return updateErr
 	})
 
+	if refused, ok := sessionpolicy.IsRefused(retryErr); ok {
+		glog.V(4).Infof("refused pause for session %s: %s", sessionId, refused.Message)
+		sss.returnRemaining(w, r, http.StatusConflict, "refused", limits, updated.Status, refused)
+		return
+	}
+
 	if retryErr != nil {
 		glog.Errorf("error creating session %v", err)
 		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
 		return
 	}
 
-	util.ReturnHTTPMessage(w, r, 204, "updated", "updated session")
+	sss.returnRemaining(w, r, 200, "updated", limits, updated.Status, sessionpolicy.RefusedError{})
 	return
 }
 
@@ -678,45 +812,59 @@ func (sss SessionServer) ResumeSessionFunc(w http.ResponseWriter, r *http.Reques
 	}
 
 	ss, err := sss.GetSessionById(sessionId)
-	if ss.Spec.UserId != user.Name {
+	if !util.IsSessionMember(&ss, user.Name) {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
 		return
 	}
 
-	var course hfv1.Course
-	var scenario hfv1.Scenario
+	updated, limits, err := sss.resumeSession(ss)
+	if err != nil {
+		glog.Errorf("error resuming session %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
 
-	if ss.Spec.CourseId != "" {
-		course, err = sss.courseClient.GetCourseById(ss.Spec.CourseId)
-		if err != nil {
-			glog.Errorf("error retrieving course %v", err)
-			util.ReturnHTTPMessage(w, r, 500, "error", "error getting course")
-			return
-		}
+	sss.returnRemaining(w, r, 200, "updated", limits, updated.Status, sessionpolicy.RefusedError{})
+	return
+}
+
+type preparedInvite struct {
+	InviteCode string `json:"invite_code"`
+}
+
+// InviteSessionFunc creates an invite code other users can join the session with. Only the owner may invite.
+func (sss SessionServer) InviteSessionFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := sss.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to invite to sessions")
+		return
 	}
-	if ss.Spec.ScenarioId != "" {
-		scenario, err = sss.scenarioClient.GetScenarioById(ss.Spec.ScenarioId)
-		if err != nil {
-			glog.Errorf("error retrieving scenario %v", err)
-			util.ReturnHTTPMessage(w, r, 500, "error", "error getting scenario")
-			return
-		}
+
+	sessionId := mux.Vars(r)["session_id"]
+	if len(sessionId) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no session id passed in")
+		return
 	}
 
-	var ssTimeout string
+	ss, err := sss.GetSessionById(sessionId)
+	if err != nil || ss.Spec.UserId != user.Name {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
+		return
+	}
 
-	if course.Spec.KeepAliveDuration != "" {
-		ssTimeout = course.Spec.KeepAliveDuration
-	} else if scenario.Spec.KeepAliveDuration != "" {
-		ssTimeout = scenario.Spec.KeepAliveDuration
-	} else {
-		ssTimeout = keepaliveSSTimeout
+	if ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "session was finished")
+		return
 	}
 
-	now := time.Now()
-	duration, _ := time.ParseDuration(ssTimeout)
+	newInviteCode, err := util.RandSecretRunes(12)
+	if err != nil {
+		glog.Errorf("error generating invite code for session %s %v", sessionId, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
 
-	newExpiration := now.Add(duration).Format(time.UnixDate)
+	var inviteCode string
 
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
@@ -724,95 +872,910 @@ func (sss SessionServer) ResumeSessionFunc(w http.ResponseWriter, r *http.Reques
 			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
 		}
 
-		result.Status.PausedTime = ""
-		result.Status.ExpirationTime = newExpiration
-		result.Status.Paused = false
+		// keep an existing code, so invites that were already handed out stay valid
+		if result.Spec.InviteCode != "" {
+			inviteCode = result.Spec.InviteCode
+			return nil
+		}
 
-		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
-		glog.V(4).Infof("updated result for session")
+		result.Spec.InviteCode = newInviteCode
+		inviteCode = result.Spec.InviteCode
 
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
 		return updateErr
 	})
 
 	if retryErr != nil {
-		glog.Errorf("error creating session %v", err)
+		glog.Errorf("error creating invite for session %s %v", sessionId, retryErr)
 		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
 		return
 	}
 
-	util.ReturnHTTPMessage(w, r, 204, "updated", "resumed session")
-	return
+	encodedInvite, err := json.Marshal(preparedInvite{inviteCode})
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedInvite)
 }
 
-func (sss SessionServer) GetSessionFunc(w http.ResponseWriter, r *http.Request) {
+// RevokeInviteSessionFunc removes the invite code of a session. Members that already joined stay in the team.
+func (sss SessionServer) RevokeInviteSessionFunc(w http.ResponseWriter, r *http.Request) {
 	user, err := sss.auth.AuthN(w, r)
 	if err != nil {
-		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get sessions")
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to invite to sessions")
 		return
 	}
 
-	vars := mux.Vars(r)
-
-	sessionId := vars["session_id"]
+	sessionId := mux.Vars(r)["session_id"]
 	if len(sessionId) == 0 {
 		util.ReturnHTTPMessage(w, r, 500, "error", "no session id passed in")
 		return
 	}
 
 	ss, err := sss.GetSessionById(sessionId)
+	if err != nil || ss.Spec.UserId != user.Name {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
+		return
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
+		}
 
+		result.Spec.InviteCode = ""
+
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error revoking invite for session %s %v", sessionId, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "revoked invite")
+}
+
+// JoinSessionFunc adds the calling user as a member to the session the invite code belongs to
+func (sss SessionServer) JoinSessionFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := sss.auth.AuthN(w, r)
 	if err != nil {
-		glog.Errorf("did not find a coressponding session with the given ID")
-		util.ReturnHTTPMessage(w, r, http.StatusNotFound, "error", "no session found")
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to join sessions")
 		return
 	}
 
-	if ss.Spec.UserId != user.Name {
-		_, err := sss.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission("sessions", rbacclient.VerbGet), w, r)
+	inviteCode := r.PostFormValue("invite_code")
+	if inviteCode == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no invite code passed in")
+		return
+	}
+
+	obj, err := sss.ssIndexer.ByIndex(inviteIndex, inviteCode)
+	if err != nil || len(obj) < 1 {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "invalid invite code")
+		return
+	}
+
+	ss, ok := obj[0].(*hfv1.Session)
+	if !ok || ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "invalid invite code")
+		return
+	}
+
+	if util.IsSessionMember(ss, user.Name) {
+		sss.returnJoinedSession(w, r, *ss)
+		return
+	}
+
+	var updated *hfv1.Session
+	joined := false
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, ss.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", ss.Name, getErr)
+		}
+
+		// a concurrent join of the same user may have added the member since the cache was read
+		if util.IsSessionMember(result, user.Name) {
+			updated = result
+			joined = true
+			return nil
+		}
+
+		result.Spec.Members = append(result.Spec.Members, user.Name)
+
+		var updateErr error
+		updated, updateErr = sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error joining session %s %v", ss.Name, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+	if joined {
+		sss.returnJoinedSession(w, r, *updated)
+		return
+	}
+
+	// every member tracks their own progress
+	totalSteps := 0
+	maxScore := 0
+	if updated.Spec.ScenarioId != "" {
+		scenario, err := sss.scenarioClient.GetScenarioById(updated.Spec.ScenarioId)
 		if err != nil {
-			util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches for this user")
-			return
+			glog.Errorf("error retrieving scenario %v", err)
+		}
+		spec, err := revision.ScenarioSpec(sss.ctx, sss.hfClientSet, scenario, updated.Spec.ScenarioRevision)
+		if err != nil {
+			glog.Errorf("error retrieving scenario revision %v", err)
 		}
+		totalSteps = len(spec.Steps)
+		maxScore = quiz.MaxScore(spec.Steps)
 	}
+	sss.CreateProgress(updated.Name, updated.Labels[util.ScheduledEventLabel], updated.Spec.ScenarioId, updated.Spec.CourseId, user.Name, totalSteps, maxScore)
 
-	preparedSession := preparedSession{ss.Name, ss.Spec}
+	preparedSession := sss.prepareSession(*updated)
 	encodedSS, err := json.Marshal(preparedSession)
 	if err != nil {
 		glog.Error(err)
 	}
-	util.ReturnHTTPContent(w, r, 200, "success", encodedSS)
+	util.ReturnHTTPContent(w, r, 201, "joined", encodedSS)
 
-	glog.V(2).Infof("retrieved session %s", ss.Name)
+	glog.V(2).Infof("user %s joined session %s", user.Name, updated.Name)
 }
 
-func ssIdIndexer(obj interface{}) ([]string, error) {
-	ss, ok := obj.(*hfv1.Session)
-	if !ok {
-		return []string{}, nil
+// returnJoinedSession answers a join of a user that is already a member of the session
+func (sss SessionServer) returnJoinedSession(w http.ResponseWriter, r *http.Request, ss hfv1.Session) {
+	preparedSession := sss.prepareSession(ss)
+	encodedSS, err := json.Marshal(preparedSession)
+	if err != nil {
+		glog.Error(err)
 	}
-	return []string{ss.Name}, nil
+	util.ReturnHTTPContent(w, r, 200, "exists", encodedSS)
 }
 
-func (sss SessionServer) GetSessionById(id string) (hfv1.Session, error) {
-	if len(id) == 0 {
-		return hfv1.Session{}, fmt.Errorf("id passed in was empty")
+// LeaveSessionFunc removes the calling member from a team session. The owner finishes the session instead.
+func (sss SessionServer) LeaveSessionFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := sss.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to leave sessions")
+		return
 	}
 
-	obj, err := sss.ssIndexer.ByIndex(ssIndex, id)
-	if err != nil {
-		return hfv1.Session{}, fmt.Errorf("error while retrieving session by id: %s with error: %v", id, err)
+	sessionId := mux.Vars(r)["session_id"]
+	if len(sessionId) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no session id passed in")
+		return
 	}
 
-	if len(obj) < 1 {
-		return hfv1.Session{}, fmt.Errorf("session server not found by id: %s", id)
+	ss, err := sss.GetSessionById(sessionId)
+	if err != nil || !util.IsSessionMember(&ss, user.Name) {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches this user")
+		return
 	}
 
-	Session, ok := obj[0].(*hfv1.Session)
+	if ss.Spec.UserId == user.Name {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "the owner can not leave a session")
+		return
+	}
 
-	if !ok {
-		return hfv1.Session{}, fmt.Errorf("error while converting session found by id to object: %s", id)
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, sessionId, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", sessionId, getErr)
+		}
+
+		members := []string{}
+		for _, member := range result.Spec.Members {
+			if member != user.Name {
+				members = append(members, member)
+			}
+		}
+		result.Spec.Members = members
+
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error leaving session %s %v", sessionId, retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
 	}
 
-	return *Session, nil
+	sss.FinishProgress(sessionId, user.Name)
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "left session")
+}
+
+// resumeSession ends a pause and gives the session a fresh keepalive period
+func (sss SessionServer) resumeSession(ss hfv1.Session) (*hfv1.Session, sessionpolicy.Limits, error) {
+	var course hfv1.Course
+	var scenario hfv1.Scenario
+	var err error
+
+	if ss.Spec.CourseId != "" {
+		course, err = sss.courseClient.GetCourseById(ss.Spec.CourseId)
+		if err != nil {
+			return nil, sessionpolicy.Limits{}, fmt.Errorf("error retrieving course %v", err)
+		}
+	}
+	if ss.Spec.ScenarioId != "" {
+		scenario, err = sss.scenarioClient.GetScenarioById(ss.Spec.ScenarioId)
+		if err != nil {
+			return nil, sessionpolicy.Limits{}, fmt.Errorf("error retrieving scenario %v", err)
+		}
+	}
+
+	var ssTimeout string
+
+	if course.Spec.KeepAliveDuration != "" {
+		ssTimeout = course.Spec.KeepAliveDuration
+	} else if scenario.Spec.KeepAliveDuration != "" {
+		ssTimeout = scenario.Spec.KeepAliveDuration
+	} else {
+		ssTimeout = keepaliveSSTimeout
+	}
+
+	now := time.Now()
+	duration, _ := time.ParseDuration(ssTimeout)
+
+	limits := sss.getSessionLimits(ss, course, scenario)
+	var updated *hfv1.Session
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, ss.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", ss.Name, getErr)
+		}
+
+		if result.Status.Paused {
+			result.Status.TotalPauseDuration = sessionpolicy.PausedDuration(result.Status, now).String()
+		}
+
+		result.Status.PausedTime = ""
+		result.Status.PauseStartTime = ""
+		result.Status.ExpirationTime = limits.CapExpiration(result.Status, now.Add(duration)).Format(time.UnixDate)
+		result.Status.Paused = false
+
+		var updateErr error
+		updated, updateErr = sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		glog.V(4).Infof("updated result for session")
+
+		return updateErr
+	})
+
+	if retryErr != nil {
+		return nil, limits, retryErr
+	}
+
+	return updated, limits, nil
+}
+
+// getSessionLimits merges the session policies of the course, scenario and scheduled event of a session.
+// Invalid policies are logged and ignored, so a typo does not lock learners out of their session.
+func (sss SessionServer) getSessionLimits(ss hfv1.Session, course hfv1.Course, scenario hfv1.Scenario) sessionpolicy.Limits {
+	policies := []hfv1.SessionPolicy{course.Spec.SessionPolicy, scenario.Spec.SessionPolicy}
+
+	if seName, ok := ss.Labels[util.ScheduledEventLabel]; ok {
+		se, err := sss.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).Get(sss.ctx, seName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			glog.Errorf("error retrieving scheduled event %s for session %s: %v", seName, ss.Name, err)
+		} else if err == nil {
+			policies = append(policies, se.Spec.SessionPolicy)
+		}
+	}
+
+	limits, err := sessionpolicy.Merge(policies...)
+	if err != nil {
+		glog.Errorf("error merging session policies for session %s: %v", ss.Name, err)
+		return sessionpolicy.Limits{}
+	}
+
+	return limits
+}
+
+func (sss SessionServer) returnRemaining(w http.ResponseWriter, r *http.Request, httpStatus int, messageType string, limits sessionpolicy.Limits, status hfv1.SessionStatus, refused sessionpolicy.RefusedError) {
+	remaining := limits.Remaining(status, time.Now())
+	remaining.Reason = refused.Reason
+	remaining.Message = refused.Message
+
+	encodedRemaining, err := json.Marshal(remaining)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, httpStatus, messageType, encodedRemaining)
+}
+
+func (sss SessionServer) GetSessionFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := sss.auth.AuthN(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to get sessions")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	sessionId := vars["session_id"]
+	if len(sessionId) == 0 {
+		util.ReturnHTTPMessage(w, r, 500, "error", "no session id passed in")
+		return
+	}
+
+	ss, err := sss.GetSessionById(sessionId)
+
+	if err != nil {
+		glog.Errorf("did not find a coressponding session with the given ID")
+		util.ReturnHTTPMessage(w, r, http.StatusNotFound, "error", "no session found")
+		return
+	}
+
+	if !util.IsSessionMember(&ss, user.Name) {
+		_, err := sss.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission("sessions", rbacclient.VerbGet), w, r)
+		if err != nil {
+			util.ReturnHTTPMessage(w, r, 403, "forbidden", "no session found that matches for this user")
+			return
+		}
+	}
+
+	preparedSession := sss.prepareSession(ss)
+	encodedSS, err := json.Marshal(preparedSession)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedSS)
+
+	glog.V(2).Infof("retrieved session %s", ss.Name)
+}
+
+// prepareSession adds the queue state of the vmclaims to a session
+func (sss SessionServer) prepareSession(ss hfv1.Session) preparedSession {
+	prepared := preparedSession{ID: ss.Name, SessionSpec: ss.Spec}
+
+	for _, vmcId := range ss.Spec.VmClaimSet {
+		vmc, err := sss.vmClaimLister.VirtualMachineClaims(util.GetReleaseNamespace()).Get(vmcId)
+		if err != nil {
+			continue
+		}
+
+		if vmc.Status.Queued {
+			prepared.Queued = true
+			if vmc.Status.QueuePosition > prepared.QueuePosition {
+				prepared.QueuePosition = vmc.Status.QueuePosition
+			}
+		}
+	}
+
+	return prepared
+}
+
+func ssInviteIndexer(obj interface{}) ([]string, error) {
+	ss, ok := obj.(*hfv1.Session)
+	if !ok || ss.Spec.InviteCode == "" {
+		return []string{}, nil
+	}
+	return []string{ss.Spec.InviteCode}, nil
+}
+
+func ssIdIndexer(obj interface{}) ([]string, error) {
+	ss, ok := obj.(*hfv1.Session)
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{ss.Name}, nil
+}
+
+func (sss SessionServer) GetSessionById(id string) (hfv1.Session, error) {
+	if len(id) == 0 {
+		return hfv1.Session{}, fmt.Errorf("id passed in was empty")
+	}
+
+	obj, err := sss.ssIndexer.ByIndex(ssIndex, id)
+	if err != nil {
+		return hfv1.Session{}, fmt.Errorf("error while retrieving session by id: %s with error: %v", id, err)
+	}
+
+	if len(obj) < 1 {
+		return hfv1.Session{}, fmt.Errorf("session server not found by id: %s", id)
+	}
+
+	Session, ok := obj[0].(*hfv1.Session)
+
+	if !ok {
+		return hfv1.Session{}, fmt.Errorf("error while converting session found by id to object: %s", id)
+	}
+
+	return *Session, nil
+
+}
+
+// ListSessionsFunc lists sessions for admins. Results can be filtered with the
+// scheduledevent, user, course and scenario query parameters, active=true hides finished sessions.
+func (sss SessionServer) ListSessionsFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := sss.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbList), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to list sessions")
+		return
+	}
+
+	query := r.URL.Query()
+	scheduledEvent := query.Get("scheduledevent")
+	userId := query.Get("user")
+	courseId := query.Get("course")
+	scenarioId := query.Get("scenario")
+	activeOnly := query.Get("active") == "true"
+
+	preparedSessions := []adminPreparedSession{}
+	for _, obj := range sss.ssIndexer.List() {
+		ss, ok := obj.(*hfv1.Session)
+		if !ok {
+			continue
+		}
+
+		if scheduledEvent != "" && ss.Labels[util.ScheduledEventLabel] != scheduledEvent {
+			continue
+		}
+		if userId != "" && ss.Spec.UserId != userId {
+			continue
+		}
+		if courseId != "" && ss.Spec.CourseId != courseId {
+			continue
+		}
+		if scenarioId != "" && ss.Spec.ScenarioId != scenarioId {
+			continue
+		}
+		if activeOnly && ss.Status.Finished {
+			continue
+		}
+
+		preparedSessions = append(preparedSessions, adminPreparedSession{ss.Name, ss.Labels[util.ScheduledEventLabel], ss.Spec, ss.Status})
+	}
+
+	encodedSessions, err := json.Marshal(preparedSessions)
+	if err != nil {
+		glog.Error(err)
+	}
+	util.ReturnHTTPContent(w, r, 200, "success", encodedSessions)
+
+	glog.V(4).Infof("listed sessions")
+}
+
+func (sss SessionServer) AdminFinishSessionFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	err := sss.finishSession(ss.Name)
+	if err != nil {
+		glog.Errorf("error finishing session %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "finished session")
+}
+
+// AdminExtendSessionFunc gives a session more time. The extension is not subject to the session policy,
+// and also pushes back the max_session_duration deadline by the same amount.
+func (sss SessionServer) AdminExtendSessionFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 409, "conflict", "session was finished")
+		return
+	}
+
+	duration, err := time.ParseDuration(r.PostFormValue("duration"))
+	if err != nil || duration <= 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid duration")
+		return
+	}
+
+	now := time.Now()
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, ss.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", ss.Name, getErr)
+		}
+
+		result.Status.ExpirationTime = extendTime(result.Status.ExpirationTime, duration, now)
+		if result.Status.Paused {
+			result.Status.PausedTime = extendTime(result.Status.PausedTime, duration, now)
+		}
+
+		extended, parseErr := time.ParseDuration(result.Status.ExtendedDuration)
+		if parseErr != nil {
+			extended = 0
+		}
+		result.Status.ExtendedDuration = (extended + duration).String()
+
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		glog.V(4).Infof("extended session %s by %s", ss.Name, duration)
+
+		return updateErr
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error extending session %v", retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "extended session")
+}
+
+// AdminPauseSessionFunc pauses a session on behalf of its user. Admin pauses do not count against the session policy.
+func (sss SessionServer) AdminPauseSessionFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 409, "conflict", "session was finished")
+		return
+	}
+
+	rawDuration := r.PostFormValue("duration")
+	if rawDuration == "" {
+		rawDuration = pauseSSTimeout
+	}
+
+	duration, err := time.ParseDuration(rawDuration)
+	if err != nil || duration <= 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "invalid duration")
+		return
+	}
+
+	now := time.Now()
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, ss.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("error retrieving latest version of session %s: %v", ss.Name, getErr)
+		}
+
+		// a pause started by the user keeps its start time, so the time until now is still accounted for
+		if !result.Status.Paused {
+			result.Status.PauseStartTime = now.Format(time.UnixDate)
+		}
+		result.Status.PausedTime = now.Add(duration).Format(time.UnixDate)
+		result.Status.Paused = true
+
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		glog.V(4).Infof("paused session %s", ss.Name)
+
+		return updateErr
+	})
+
+	if retryErr != nil {
+		glog.Errorf("error pausing session %v", retryErr)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "paused session")
+}
+
+func (sss SessionServer) AdminResumeSessionFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 409, "conflict", "session was finished")
+		return
+	}
+
+	_, _, err := sss.resumeSession(ss)
+	if err != nil {
+		glog.Errorf("error resuming session %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "resumed session")
+}
+
+// AdminReassignSessionFunc hands a session, including its vms and progress, over to another user
+func (sss SessionServer) AdminReassignSessionFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	userId := r.PostFormValue("user")
+	if userId == "" {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no user passed in")
+		return
+	}
+
+	_, err := sss.hfClientSet.HobbyfarmV2().Users(util.GetReleaseNamespace()).Get(sss.ctx, userId, metav1.GetOptions{})
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "user not found")
+		return
+	}
+
+	// a user may only have one active session at a time
+	sessions, err := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.UserLabel, userId),
+	})
+	if err != nil {
+		glog.Errorf("error listing sessions of user %s %v", userId, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+	for _, existing := range sessions.Items {
+		if !existing.Status.Finished && existing.Name != ss.Name {
+			util.ReturnHTTPMessage(w, r, 409, "conflict", "user already has an active session")
+			return
+		}
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(sss.ctx, ss.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+
+		result.Spec.UserId = userId
+		result.Labels[util.UserLabel] = userId
+
+		_, updateErr := sss.hfClientSet.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+	if err != nil {
+		glog.Errorf("error reassigning session %s %v", ss.Name, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	for _, vmcId := range ss.Spec.VmClaimSet {
+		err = sss.reassignVMClaim(vmcId, userId)
+		if err != nil {
+			glog.Errorf("error reassigning vmclaim %s %v", vmcId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+			return
+		}
+	}
+
+	progress, err := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(sss.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", util.SessionLabel, ss.Name)})
+	if err != nil {
+		glog.Errorf("error while retrieving progress %v", err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	for _, p := range progress.Items {
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Get(sss.ctx, p.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			result.Spec.UserId = userId
+			result.Labels[util.UserLabel] = userId
+
+			_, updateErr := sss.hfClientSet.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+			return updateErr
+		})
+		if err != nil {
+			glog.Errorf("error reassigning progress %s %v", p.Name, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+			return
+		}
+	}
+
+	util.ReturnHTTPMessage(w, r, 200, "updated", "reassigned session")
+}
+
+func (sss SessionServer) reassignVMClaim(vmcId string, userId string) error {
+	var vmc *hfv1.VirtualMachineClaim
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(sss.ctx, vmcId, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+
+		result.Spec.UserId = userId
+		result.Labels[util.UserLabel] = userId
+
+		var updateErr error
+		vmc, updateErr = sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, vm := range vmc.Spec.VirtualMachines {
+		if vm.VirtualMachineId == "" {
+			continue
+		}
+
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := sss.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(sss.ctx, vm.VirtualMachineId, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			result.Spec.UserId = userId
+
+			_, updateErr := sss.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+			return updateErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AdminReplaceVMFunc replaces a broken vm of a live session. The vm is tainted and removed from its claim,
+// the vmclaim controller then binds a fresh vm to the same claim.
+func (sss SessionServer) AdminReplaceVMFunc(w http.ResponseWriter, r *http.Request) {
+	ss, ok := sss.getSessionForAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	vmId := mux.Vars(r)["vm_id"]
+	if len(vmId) == 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no vm id passed in")
+		return
+	}
+
+	if ss.Status.Finished {
+		util.ReturnHTTPMessage(w, r, 409, "conflict", "session was finished")
+		return
+	}
+
+	found := false
+	for _, vmcId := range ss.Spec.VmClaimSet {
+		slot := ""
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(sss.ctx, vmcId, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			name := ""
+			for vmName, vm := range result.Spec.VirtualMachines {
+				if vm.VirtualMachineId == vmId {
+					name = vmName
+				}
+			}
+			if name == "" {
+				return nil
+			}
+
+			vm := result.Spec.VirtualMachines[name]
+			vm.VirtualMachineId = ""
+			result.Spec.VirtualMachines[name] = vm
+
+			_, updateErr := sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+			if updateErr == nil {
+				slot = name
+			}
+			return updateErr
+		})
+		if err != nil {
+			glog.Errorf("error removing vm %s from vmclaim %s %v", vmId, vmcId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+			return
+		}
+		if slot == "" {
+			continue
+		}
+		found = true
+
+		// the status is updated separately, a conflict with the vmclaim controller must not lose the emptied slot
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			result, getErr := sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(sss.ctx, vmcId, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			if result.Spec.VirtualMachines[slot].VirtualMachineId != "" {
+				// the slot has been filled again in the meantime
+				return nil
+			}
+
+			// unbinding the claim makes the vmclaim controller fill the empty slot
+			result.Status.Bound = false
+			result.Status.Ready = false
+
+			_, updateErr := sss.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+			return updateErr
+		})
+		if err != nil {
+			glog.Errorf("error unbinding vmclaim %s %v", vmcId, err)
+			util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+			return
+		}
+		break
+	}
+
+	if !found {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "vm does not belong to session")
+		return
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		result, getErr := sss.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(sss.ctx, vmId, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+
+		// the replacement takes over the slot in the claim, the tainted vm no longer belongs to the session
+		result.Labels[util.BoundLabel] = "false"
+		result.Spec.VirtualMachineClaimId = ""
+		result.Spec.UserId = ""
+
+		result, updateErr := sss.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Update(sss.ctx, result, metav1.UpdateOptions{})
+		if updateErr != nil {
+			return updateErr
+		}
+
+		result.Status.Tainted = true
+
+		_, updateErr = sss.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).UpdateStatus(sss.ctx, result, metav1.UpdateOptions{})
+		return updateErr
+	})
+	if err != nil {
+		glog.Errorf("error tainting vm %s %v", vmId, err)
+		util.ReturnHTTPMessage(w, r, 500, "error", "something happened")
+		return
+	}
+
+	glog.V(2).Infof("replacing vm %s of session %s", vmId, ss.Name)
+	util.ReturnHTTPMessage(w, r, 200, "updated", "replacing vm")
+}
+
+// getSessionForAdmin checks that the caller may update sessions and looks up the session from the url
+func (sss SessionServer) getSessionForAdmin(w http.ResponseWriter, r *http.Request) (hfv1.Session, bool) {
+	_, err := sss.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbUpdate), w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "access denied to update session")
+		return hfv1.Session{}, false
+	}
+
+	sessionId := mux.Vars(r)["session_id"]
+	if len(sessionId) == 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no session id passed in")
+		return hfv1.Session{}, false
+	}
+
+	ss, err := sss.GetSessionById(sessionId)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 404, "notfound", "no session found")
+		return hfv1.Session{}, false
+	}
+
+	return ss, true
+}
+
+// extendTime adds duration to a UnixDate timestamp. Timestamps in the past are extended from now.
+func extendTime(timestamp string, duration time.Duration, now time.Time) string {
+	t, err := time.Parse(time.UnixDate, timestamp)
+	if err != nil || t.Before(now) {
+		t = now
+	}
 
+	return t.Add(duration).Format(time.UnixDate)
 }
@@ -0,0 +1,537 @@
+package sessionserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	admin   = user("u-admin", "admin@example.com")
+	learner = user("u-learner", "learner@example.com")
+	other   = user("u-other", "other@example.com")
+)
+
+func user(name string, email string) *hfv2.User {
+	return &hfv2.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: util.GetReleaseNamespace()},
+		Spec:       hfv2.UserSpec{Email: email, Password: "secret-" + name},
+	}
+}
+
+// adminServer serves the session routes with admin granted the permission to update sessions
+type adminServer struct {
+	router *mux.Router
+	client *fake.Clientset
+}
+
+func newAdminServer(t *testing.T, objects ...runtime.Object) adminServer {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	kubeClient := k8sfake.NewSimpleClientset(
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "session-admin"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{rbacclient.APIGroup},
+				Resources: []string{resourcePlural},
+				Verbs:     []string{rbacclient.VerbUpdate},
+			}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "session-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacclient.KindUser, APIGroup: rbacclient.RbacGroup, Name: admin.Name}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacclient.RbacGroup, Kind: "ClusterRole", Name: "session-admin"},
+		},
+	)
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	rbac, err := rbacclient.NewRbacClient(util.GetReleaseNamespace(), kubeInformerFactory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := fake.NewSimpleClientset(append([]runtime.Object{admin, learner, other}, objects...)...)
+	hfInformerFactory := hfInformers.NewSharedInformerFactory(client, 0)
+	auth, err := authclient.NewAuthClient(client, hfInformerFactory, rbac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sss, err := NewSessionServer(auth, nil, nil, nil, client, hfInformerFactory, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeInformerFactory.Start(ctx.Done())
+	kubeInformerFactory.WaitForCacheSync(ctx.Done())
+	hfInformerFactory.Start(ctx.Done())
+	hfInformerFactory.WaitForCacheSync(ctx.Done())
+
+	router := mux.NewRouter()
+	sss.SetupRoutes(router)
+
+	return adminServer{router: router, client: client}
+}
+
+func (s adminServer) put(t *testing.T, as *hfv2.User, path string, form url.Values) int {
+	t.Helper()
+	return s.request(t, as, http.MethodPut, path, form).Code
+}
+
+func (s adminServer) request(t *testing.T, as *hfv2.User, method string, path string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": as.Spec.Email}).SignedString([]byte(as.Spec.Password))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+func (s adminServer) session(t *testing.T, name string) *hfv1.Session {
+	t.Helper()
+
+	ss, err := s.client.HobbyfarmV1().Sessions(util.GetReleaseNamespace()).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+func liveSession(name string, status hfv1.SessionStatus) *hfv1.Session {
+	return &hfv1.Session{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: util.GetReleaseNamespace(),
+			Labels:    map[string]string{util.UserLabel: learner.Name},
+		},
+		Spec:   hfv1.SessionSpec{UserId: learner.Name, VmClaimSet: []string{"vmc-1"}},
+		Status: status,
+	}
+}
+
+func boundClaim() *hfv1.VirtualMachineClaim {
+	return &hfv1.VirtualMachineClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmc-1",
+			Namespace: util.GetReleaseNamespace(),
+			Labels:    map[string]string{util.UserLabel: learner.Name},
+		},
+		Spec: hfv1.VirtualMachineClaimSpec{
+			UserId: learner.Name,
+			VirtualMachines: map[string]hfv1.VirtualMachineClaimVM{
+				"cp":     {Template: "ubuntu", VirtualMachineId: "vm-1"},
+				"worker": {Template: "ubuntu", VirtualMachineId: "vm-2"},
+			},
+		},
+		Status: hfv1.VirtualMachineClaimStatus{Bound: true, Ready: true},
+	}
+}
+
+func boundVM(name string) *hfv1.VirtualMachine {
+	return &hfv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: util.GetReleaseNamespace(),
+			Labels:    map[string]string{util.BoundLabel: "true"},
+		},
+		Spec: hfv1.VirtualMachineSpec{VirtualMachineClaimId: "vmc-1", UserId: learner.Name},
+	}
+}
+
+func Test_AdminForbidden(t *testing.T) {
+	s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true}))
+
+	for _, path := range []string{"/a/session/ss-1/finish", "/a/session/ss-1/pause", "/a/session/ss-1/vm/vm-1/replace"} {
+		if code := s.put(t, learner, path, nil); code != http.StatusForbidden {
+			t.Errorf("%s as learner returned %d, want %d", path, code, http.StatusForbidden)
+		}
+	}
+	if code := s.put(t, admin, "/a/session/ss-missing/finish", nil); code != http.StatusNotFound {
+		t.Errorf("finishing a missing session returned %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func Test_AdminReplaceVM(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   hfv1.SessionStatus
+		vmId     string
+		conflict bool // the vmclaim controller writes the claim status while the slot is emptied
+		wantCode int
+	}{
+		{"replace", hfv1.SessionStatus{Active: true}, "vm-1", false, http.StatusOK},
+		{"claim status conflict", hfv1.SessionStatus{Active: true}, "vm-1", true, http.StatusOK},
+		{"vm of another session", hfv1.SessionStatus{Active: true}, "vm-9", false, http.StatusNotFound},
+		{"finished session", hfv1.SessionStatus{Finished: true}, "vm-1", false, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAdminServer(t, liveSession("ss-1", tt.status), boundClaim(), boundVM("vm-1"), boundVM("vm-2"))
+
+			if tt.conflict {
+				conflicted := false
+				s.client.PrependReactor("update", "virtualmachineclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if action.GetSubresource() != "status" || conflicted {
+						return false, nil, nil
+					}
+					conflicted = true
+					return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "virtualmachineclaims"}, "vmc-1", nil)
+				})
+			}
+
+			if code := s.put(t, admin, "/a/session/ss-1/vm/"+tt.vmId+"/replace", nil); code != tt.wantCode {
+				t.Fatalf("replace returned %d, want %d", code, tt.wantCode)
+			}
+
+			vmc, err := s.client.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(context.Background(), "vmc-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			vm, err := s.client.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(context.Background(), "vm-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			replaced := tt.wantCode == http.StatusOK
+			if got := vmc.Spec.VirtualMachines["cp"].VirtualMachineId == ""; got != replaced {
+				t.Errorf("cp slot is %q, emptied = %v, want %v", vmc.Spec.VirtualMachines["cp"].VirtualMachineId, got, replaced)
+			}
+			if vmc.Spec.VirtualMachines["worker"].VirtualMachineId != "vm-2" {
+				t.Errorf("worker slot is %q, want it untouched", vmc.Spec.VirtualMachines["worker"].VirtualMachineId)
+			}
+			if vmc.Status.Bound == replaced || vmc.Status.Ready == replaced {
+				t.Errorf("claim status is bound %v, ready %v, want both %v", vmc.Status.Bound, vmc.Status.Ready, !replaced)
+			}
+			if vm.Status.Tainted != replaced || (vm.Labels[util.BoundLabel] == "false") != replaced || (vm.Spec.VirtualMachineClaimId == "") != replaced {
+				t.Errorf("vm is tainted %v with label %s and claim %q, replaced = %v", vm.Status.Tainted, vm.Labels[util.BoundLabel], vm.Spec.VirtualMachineClaimId, replaced)
+			}
+		})
+	}
+}
+
+func Test_AdminExtendSession(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+	s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true, ExpirationTime: expiration.Format(time.UnixDate), ExtendedDuration: "30m0s"}))
+
+	if code := s.put(t, admin, "/a/session/ss-1/extend", url.Values{"duration": {"-1h"}}); code != http.StatusBadRequest {
+		t.Errorf("negative extension returned %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := s.put(t, admin, "/a/session/ss-1/extend", url.Values{"duration": {"45m"}}); code != http.StatusOK {
+		t.Fatalf("extend returned %d, want %d", code, http.StatusOK)
+	}
+
+	status := s.session(t, "ss-1").Status
+	if want := expiration.Add(45 * time.Minute).Format(time.UnixDate); status.ExpirationTime != want {
+		t.Errorf("expiration = %s, want %s", status.ExpirationTime, want)
+	}
+	if status.ExtendedDuration != "1h15m0s" {
+		t.Errorf("extended duration = %s, want 1h15m0s", status.ExtendedDuration)
+	}
+}
+
+func Test_AdminPauseSession(t *testing.T) {
+	userPauseStart := time.Now().Add(-10 * time.Minute).Format(time.UnixDate)
+
+	tests := []struct {
+		name          string
+		status        hfv1.SessionStatus
+		duration      string
+		wantCode      int
+		wantStartedAt string // empty if the pause starts now
+	}{
+		{"default duration", hfv1.SessionStatus{Active: true}, "", http.StatusOK, ""},
+		{"keeps the start of a user pause", hfv1.SessionStatus{Active: true, Paused: true, PauseStartTime: userPauseStart}, "10m", http.StatusOK, userPauseStart},
+		{"invalid duration", hfv1.SessionStatus{Active: true}, "soon", http.StatusBadRequest, ""},
+		{"finished session", hfv1.SessionStatus{Finished: true}, "", http.StatusConflict, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAdminServer(t, liveSession("ss-1", tt.status))
+
+			before := time.Now().Truncate(time.Second)
+			if code := s.put(t, admin, "/a/session/ss-1/pause", url.Values{"duration": {tt.duration}}); code != tt.wantCode {
+				t.Fatalf("pause returned %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			status := s.session(t, "ss-1").Status
+			if !status.Paused {
+				t.Fatal("session was not paused")
+			}
+			if tt.wantStartedAt != "" && status.PauseStartTime != tt.wantStartedAt {
+				t.Errorf("pause start = %s, want %s", status.PauseStartTime, tt.wantStartedAt)
+			}
+			started, err := time.Parse(time.UnixDate, status.PauseStartTime)
+			if err != nil || (tt.wantStartedAt == "" && started.Before(before)) {
+				t.Errorf("pause start = %s, want a time not before %s", status.PauseStartTime, before)
+			}
+			if _, err := time.Parse(time.UnixDate, status.PausedTime); err != nil {
+				t.Errorf("paused time = %s, want a timestamp", status.PausedTime)
+			}
+		})
+	}
+}
+
+func Test_AdminReassignSession(t *testing.T) {
+	progress := &hfv2.Progress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "progress-1",
+			Namespace: util.GetReleaseNamespace(),
+			Labels:    map[string]string{util.SessionLabel: "ss-1", util.UserLabel: learner.Name},
+		},
+		Spec: hfv2.ProgressSpec{UserId: learner.Name},
+	}
+
+	t.Run("reassign", func(t *testing.T) {
+		s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true}), boundClaim(), boundVM("vm-1"), boundVM("vm-2"), progress)
+
+		if code := s.put(t, admin, "/a/session/ss-1/reassign", url.Values{"user": {other.Name}}); code != http.StatusOK {
+			t.Fatalf("reassign returned %d, want %d", code, http.StatusOK)
+		}
+
+		ctx := context.Background()
+		if ss := s.session(t, "ss-1"); ss.Spec.UserId != other.Name || ss.Labels[util.UserLabel] != other.Name {
+			t.Errorf("session belongs to %s with label %s", ss.Spec.UserId, ss.Labels[util.UserLabel])
+		}
+		vmc, err := s.client.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(ctx, "vmc-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vmc.Spec.UserId != other.Name || vmc.Labels[util.UserLabel] != other.Name {
+			t.Errorf("vmclaim belongs to %s with label %s", vmc.Spec.UserId, vmc.Labels[util.UserLabel])
+		}
+		for _, name := range []string{"vm-1", "vm-2"} {
+			vm, err := s.client.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if vm.Spec.UserId != other.Name {
+				t.Errorf("%s belongs to %s", name, vm.Spec.UserId)
+			}
+		}
+		p, err := s.client.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Get(ctx, "progress-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Spec.UserId != other.Name || p.Labels[util.UserLabel] != other.Name {
+			t.Errorf("progress belongs to %s with label %s", p.Spec.UserId, p.Labels[util.UserLabel])
+		}
+	})
+
+	t.Run("user with an active session", func(t *testing.T) {
+		active := liveSession("ss-2", hfv1.SessionStatus{Active: true})
+		active.Spec.UserId = other.Name
+		active.Labels[util.UserLabel] = other.Name
+		s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true}), active)
+
+		if code := s.put(t, admin, "/a/session/ss-1/reassign", url.Values{"user": {other.Name}}); code != http.StatusConflict {
+			t.Errorf("reassign returned %d, want %d", code, http.StatusConflict)
+		}
+		if ss := s.session(t, "ss-1"); ss.Spec.UserId != learner.Name {
+			t.Errorf("session was reassigned to %s", ss.Spec.UserId)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true}))
+
+		if code := s.put(t, admin, "/a/session/ss-1/reassign", url.Values{"user": {"u-nobody"}}); code != http.StatusNotFound {
+			t.Errorf("reassign returned %d, want %d", code, http.StatusNotFound)
+		}
+	})
+}
+
+// teamSession is a session of learner that other can join with the invite code team-code
+func teamSession(members ...string) *hfv1.Session {
+	ss := liveSession("ss-team", hfv1.SessionStatus{Active: true})
+	ss.Labels[util.ScheduledEventLabel] = "se-1"
+	ss.Spec.InviteCode = "team-code"
+	ss.Spec.Members = members
+	return ss
+}
+
+func (s adminServer) progress(t *testing.T, userId string) []hfv2.Progress {
+	t.Helper()
+
+	progress, err := s.client.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).List(context.Background(), metav1.ListOptions{
+		LabelSelector: util.UserLabel + "=" + userId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return progress.Items
+}
+
+func Test_InviteSession(t *testing.T) {
+	s := newAdminServer(t, liveSession("ss-1", hfv1.SessionStatus{Active: true}))
+
+	invite := func(as *hfv2.User) (int, string) {
+		w := s.request(t, as, http.MethodPut, "/session/ss-1/invite", nil)
+		var content util.HTTPContent
+		var prepared preparedInvite
+		if err := json.NewDecoder(w.Body).Decode(&content); err == nil {
+			json.Unmarshal(content.Content, &prepared)
+		}
+		return w.Code, prepared.InviteCode
+	}
+
+	if code, _ := invite(other); code != http.StatusForbidden {
+		t.Errorf("invite by another user returned %d, want %d", code, http.StatusForbidden)
+	}
+
+	code, first := invite(learner)
+	if code != http.StatusOK || len(first) != 12 || s.session(t, "ss-1").Spec.InviteCode != first {
+		t.Fatalf("invite returned %d with code %q, stored %q", code, first, s.session(t, "ss-1").Spec.InviteCode)
+	}
+	// codes that were handed out stay valid
+	if _, second := invite(learner); second != first {
+		t.Errorf("second invite returned %q, want %q", second, first)
+	}
+
+	if w := s.request(t, other, http.MethodDelete, "/session/ss-1/invite", nil); w.Code != http.StatusForbidden {
+		t.Errorf("revoke by another user returned %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := s.request(t, learner, http.MethodDelete, "/session/ss-1/invite", nil); w.Code != http.StatusOK || s.session(t, "ss-1").Spec.InviteCode != "" {
+		t.Errorf("revoke returned %d and left code %q", w.Code, s.session(t, "ss-1").Spec.InviteCode)
+	}
+}
+
+func Test_JoinSession(t *testing.T) {
+	join := func(s adminServer, code string) int {
+		return s.request(t, other, http.MethodPost, "/session/join", url.Values{"invite_code": {code}}).Code
+	}
+
+	t.Run("join", func(t *testing.T) {
+		s := newAdminServer(t, teamSession())
+
+		if code := join(s, "wrong-code"); code != http.StatusNotFound {
+			t.Errorf("join with a wrong code returned %d, want %d", code, http.StatusNotFound)
+		}
+		if code := join(s, "team-code"); code != http.StatusCreated {
+			t.Fatalf("join returned %d, want %d", code, http.StatusCreated)
+		}
+
+		if members := s.session(t, "ss-team").Spec.Members; len(members) != 1 || members[0] != other.Name {
+			t.Errorf("members = %v, want %s", members, other.Name)
+		}
+		progress := s.progress(t, other.Name)
+		if len(progress) != 1 || progress[0].Labels[util.SessionLabel] != "ss-team" || progress[0].Labels[util.ScheduledEventLabel] != "se-1" {
+			t.Errorf("progress of the new member = %+v", progress)
+		}
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		s := newAdminServer(t, teamSession(other.Name))
+
+		if code := join(s, "team-code"); code != http.StatusOK {
+			t.Errorf("join returned %d, want %d", code, http.StatusOK)
+		}
+		if members := s.session(t, "ss-team").Spec.Members; len(members) != 1 {
+			t.Errorf("members = %v, want a single entry", members)
+		}
+	})
+
+	t.Run("joined concurrently", func(t *testing.T) {
+		s := newAdminServer(t, teamSession())
+
+		// the cache has not seen the join that another request of the same user already wrote
+		s.client.PrependReactor("get", "sessions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, teamSession(other.Name), nil
+		})
+		updated := false
+		s.client.PrependReactor("update", "sessions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updated = true
+			return false, nil, nil
+		})
+
+		if code := join(s, "team-code"); code != http.StatusOK {
+			t.Errorf("join returned %d, want %d", code, http.StatusOK)
+		}
+		if updated || len(s.progress(t, other.Name)) != 0 {
+			t.Errorf("join added the member again, updated %v with %d progress", updated, len(s.progress(t, other.Name)))
+		}
+	})
+
+	t.Run("finished session", func(t *testing.T) {
+		ss := teamSession()
+		ss.Status = hfv1.SessionStatus{Finished: true}
+		s := newAdminServer(t, ss)
+
+		if code := join(s, "team-code"); code != http.StatusNotFound {
+			t.Errorf("join returned %d, want %d", code, http.StatusNotFound)
+		}
+	})
+}
+
+func Test_LeaveSession(t *testing.T) {
+	s := newAdminServer(t, teamSession(other.Name, "u-third"))
+
+	if code := s.put(t, learner, "/session/ss-team/leave", nil); code != http.StatusBadRequest {
+		t.Errorf("leave by the owner returned %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := s.put(t, admin, "/session/ss-team/leave", nil); code != http.StatusForbidden {
+		t.Errorf("leave by a stranger returned %d, want %d", code, http.StatusForbidden)
+	}
+
+	_, err := s.client.HobbyfarmV2().Progresses(util.GetReleaseNamespace()).Create(context.Background(), &hfv2.Progress{
+		ObjectMeta: metav1.ObjectMeta{Name: "progress-other", Namespace: util.GetReleaseNamespace(), Labels: map[string]string{
+			util.SessionLabel: "ss-team", util.UserLabel: other.Name, "finished": "false",
+		}},
+		Spec: hfv2.ProgressSpec{UserId: other.Name},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code := s.put(t, other, "/session/ss-team/leave", nil); code != http.StatusOK {
+		t.Fatalf("leave returned %d, want %d", code, http.StatusOK)
+	}
+	if members := s.session(t, "ss-team").Spec.Members; len(members) != 1 || members[0] != "u-third" {
+		t.Errorf("members = %v, want only u-third", members)
+	}
+	progress := s.progress(t, other.Name)
+	if len(progress) != 1 {
+		t.Fatalf("progress of the member that left = %+v", progress)
+	}
+	for _, p := range progress {
+		if !p.Spec.Finished {
+			t.Errorf("progress %s of the member that left is not finished", p.Name)
+		}
+	}
+}
@@ -20,6 +20,10 @@ const (
 	SettingAdminUIMOTD          SettingName = "motd-admin-ui"
 	SettingUIMOTD               SettingName = "motd-ui"
 	ScheduledEventRetentionTime SettingName = "scheduledevent-retention-time"
+	VMClaimQueueTimeout         SettingName = "vmclaim-queue-timeout"
+	XAPIEndpoint                SettingName = "xapi-lrs-endpoint"
+	XAPIActivityBase            SettingName = "xapi-activity-base"
+	LTIUIURL                    SettingName = "lti-ui-url"
 )
 
 type SettingName string
@@ -66,7 +70,13 @@ func WatchSettings(ctx context.Context,
 }
 
 func GetSetting(name SettingName) any {
-	var set, err = settings[string(name)].FromJSON(settings[string(name)].Value)
+	setting, ok := settings[string(name)]
+	if !ok {
+		glog.Errorf("error getting setting %s: setting not found", name)
+		return nil
+	}
+
+	var set, err = setting.FromJSON(setting.Value)
 	if err != nil {
 		glog.Errorf("error getting setting %s: %s", name, err.Error())
 		return nil
@@ -20,6 +20,8 @@ import (
 	v2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	"github.com/hobbyfarm/gargantua/v3/pkg/vmclient"
@@ -35,6 +37,9 @@ type ShellProxy struct {
 	hfClient   hfClientset.Interface
 	kubeClient kubernetes.Interface
 	ctx        context.Context
+
+	vmClaimLister hfListers.VirtualMachineClaimLister
+	sessionLister hfListers.SessionLister
 }
 
 type Service struct {
@@ -73,7 +78,7 @@ func init() {
 	SIGWINCH = regexp.MustCompile(`.*\[8;(.*);(.*)t`)
 }
 
-func NewShellProxy(authClient *authclient.AuthClient, vmClient *vmclient.VirtualMachineClient, hfClientSet hfClientset.Interface, kubeClient kubernetes.Interface, ctx context.Context) (*ShellProxy, error) {
+func NewShellProxy(authClient *authclient.AuthClient, vmClient *vmclient.VirtualMachineClient, hfClientSet hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory, kubeClient kubernetes.Interface, ctx context.Context) (*ShellProxy, error) {
 	shellProxy := ShellProxy{}
 
 	shellProxy.auth = authClient
@@ -81,6 +86,8 @@ func NewShellProxy(authClient *authclient.AuthClient, vmClient *vmclient.Virtual
 	shellProxy.hfClient = hfClientSet
 	shellProxy.kubeClient = kubeClient
 	shellProxy.ctx = ctx
+	shellProxy.vmClaimLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
+	shellProxy.sessionLister = hfInformerFactory.Hobbyfarm().V1().Sessions().Lister()
 
 	return &shellProxy, nil
 }
@@ -166,7 +173,7 @@ func (sp ShellProxy) proxy(w http.ResponseWriter, r *http.Request, user v2.User)
 		return
 	}
 
-	if vm.Spec.UserId != user.Name {
+	if !util.IsVirtualMachineMember(sp.vmClaimLister, sp.sessionLister, &vm, user.Name) {
 		// check if the user has access to user sessions
 		_, err := sp.auth.AuthGrantWS(
 			rbacclient.RbacRequest().
@@ -334,7 +341,7 @@ func (sp ShellProxy) ConnectGuacFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if vm.Spec.UserId != user.Name {
+	if !util.IsVirtualMachineMember(sp.vmClaimLister, sp.sessionLister, &vm, user.Name) {
 		util.ReturnHTTPMessage(w, r, 403, "forbidden", "you do not have access to shell")
 		return
 	}
@@ -509,7 +516,7 @@ func (sp ShellProxy) ConnectSSHFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if vm.Spec.UserId != user.Name {
+	if !util.IsVirtualMachineMember(sp.vmClaimLister, sp.sessionLister, &vm, user.Name) {
 		// check if the user has access to access user sessions
 		// TODO: add permission like 'virtualmachine/shell' similar to 'pod/exec'
 		_, err := sp.auth.AuthGrantWS(
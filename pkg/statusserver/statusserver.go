@@ -0,0 +1,375 @@
+package statusserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	EventAdded    = "added"
+	EventModified = "modified"
+	EventDeleted  = "deleted"
+
+	KindSession               = "session"
+	KindVirtualMachineClaim   = "virtualmachineclaim"
+	KindVirtualMachine        = "virtualmachine"
+	sessionPlural             = "sessions"
+	virtualMachineClaimPlural = "virtualmachineclaims"
+	virtualMachinePlural      = "virtualmachines"
+
+	watcherBufferSize = 64
+	pingInterval      = 30 * time.Second
+	writeTimeout      = 10 * time.Second
+)
+
+// StatusEvent is a single change pushed to a watching client
+type StatusEvent struct {
+	Type   string      `json:"type"`
+	Kind   string      `json:"kind"`
+	ID     string      `json:"id"`
+	Spec   interface{} `json:"spec"`
+	Status interface{} `json:"status"`
+
+	users          []string // owner and team members of the session the object belongs to
+	scheduledEvent string
+}
+
+type watcher struct {
+	match  func(e StatusEvent) bool
+	events chan StatusEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (w *watcher) close() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+type StatusServer struct {
+	auth *authclient.AuthClient
+
+	sessionLister hfListers.SessionLister
+	vmClaimLister hfListers.VirtualMachineClaimLister
+	vmLister      hfListers.VirtualMachineLister
+
+	mu       sync.RWMutex
+	watchers map[*watcher]struct{}
+}
+
+func NewStatusServer(authClient *authclient.AuthClient, hfInformerFactory hfInformers.SharedInformerFactory) (*StatusServer, error) {
+	s := StatusServer{}
+
+	s.auth = authClient
+	s.watchers = map[*watcher]struct{}{}
+
+	s.sessionLister = hfInformerFactory.Hobbyfarm().V1().Sessions().Lister()
+	s.vmClaimLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
+	s.vmLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachines().Lister()
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.broadcast(EventAdded, obj)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			s.update(old, new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			s.broadcast(EventDeleted, obj)
+		},
+	}
+
+	hfInformerFactory.Hobbyfarm().V1().Sessions().Informer().AddEventHandler(handler)
+	hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Informer().AddEventHandler(handler)
+	hfInformerFactory.Hobbyfarm().V1().VirtualMachines().Informer().AddEventHandler(handler)
+
+	return &s, nil
+}
+
+func (s *StatusServer) SetupRoutes(r *mux.Router) {
+	r.HandleFunc("/status/watch", s.WatchFunc).Methods("GET")
+	r.HandleFunc("/a/status/watch/scheduledevent/{se_id}", s.WatchScheduledEventFunc).Methods("GET")
+	glog.V(2).Infof("set up routes for status server")
+}
+
+// WatchFunc streams status changes of the sessions, vmclaims and vms owned by the calling user
+func (s *StatusServer) WatchFunc(w http.ResponseWriter, r *http.Request) {
+	user, err := s.auth.AuthWS(w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to watch status")
+		return
+	}
+
+	s.serve(w, r, func(e StatusEvent) bool {
+		for _, u := range e.users {
+			if u == user.Name {
+				return true
+			}
+		}
+		return false
+	})
+
+	glog.V(4).Infof("stopped status watch for user %s", user.Name)
+}
+
+// WatchScheduledEventFunc streams status changes of every session, vmclaim and vm belonging to a scheduled event
+func (s *StatusServer) WatchScheduledEventFunc(w http.ResponseWriter, r *http.Request) {
+	_, err := s.auth.AuthGrantWS(
+		rbacclient.RbacRequest().
+			HobbyfarmPermission(sessionPlural, rbacclient.VerbList).
+			HobbyfarmPermission(virtualMachineClaimPlural, rbacclient.VerbList).
+			HobbyfarmPermission(virtualMachinePlural, rbacclient.VerbList),
+		w, r)
+	if err != nil {
+		util.ReturnHTTPMessage(w, r, 403, "forbidden", "no access to watch status")
+		return
+	}
+
+	id := mux.Vars(r)["se_id"]
+	if len(id) == 0 {
+		util.ReturnHTTPMessage(w, r, 400, "badrequest", "no scheduledEvent id passed in")
+		return
+	}
+
+	s.serve(w, r, func(e StatusEvent) bool {
+		return e.scheduledEvent == id
+	})
+
+	glog.V(4).Infof("stopped status watch for scheduled event %s", id)
+}
+
+func (s *StatusServer) serve(w http.ResponseWriter, r *http.Request, match func(e StatusEvent) bool) {
+	var upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+
+	// same as the shell proxy, origins are checked by the CORS handler in front of us
+	upgrader.CheckOrigin = func(r *http.Request) bool {
+		return true
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied to the client
+		glog.Errorf("error upgrading status watch: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	wt := &watcher{
+		match:  match,
+		events: make(chan StatusEvent, watcherBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	// register before taking the snapshot so that no change gets lost in between.
+	// a change that shows up in both is sent twice, which is harmless.
+	s.mu.Lock()
+	s.watchers[wt] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, wt)
+		s.mu.Unlock()
+		wt.close()
+	}()
+
+	// we never expect anything from the client, but need to read to notice when it goes away
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				wt.close()
+				return
+			}
+		}
+	}()
+
+	for _, e := range s.snapshot() {
+		if !match(e) {
+			continue
+		}
+		if err := writeEvent(conn, e); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wt.done:
+			return
+		case e := <-wt.events:
+			if err := writeEvent(conn, e); err != nil {
+				glog.V(4).Infof("error writing status event: %v", err)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(conn *websocket.Conn, e StatusEvent) error {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return conn.WriteJSON(e)
+}
+
+// snapshot returns the current state of every watched object, so clients start from a complete picture
+func (s *StatusServer) snapshot() []StatusEvent {
+	var events []StatusEvent
+
+	sessions, err := s.sessionLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("error listing sessions for status watch: %v", err)
+	}
+	for _, ss := range sessions {
+		if e, ok := s.toEvent(EventAdded, ss); ok {
+			events = append(events, e)
+		}
+	}
+
+	vmClaims, err := s.vmClaimLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("error listing vmclaims for status watch: %v", err)
+	}
+	for _, vmc := range vmClaims {
+		if e, ok := s.toEvent(EventAdded, vmc); ok {
+			events = append(events, e)
+		}
+	}
+
+	vms, err := s.vmLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("error listing vms for status watch: %v", err)
+	}
+	for _, vm := range vms {
+		if e, ok := s.toEvent(EventAdded, vm); ok {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+func (s *StatusServer) update(old, new interface{}) {
+	oldMeta, err := meta.Accessor(old)
+	if err != nil {
+		return
+	}
+	newMeta, err := meta.Accessor(new)
+	if err != nil {
+		return
+	}
+
+	// periodic resyncs deliver unchanged objects, nobody needs to hear about those
+	if oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+		return
+	}
+
+	s.broadcast(EventModified, new)
+}
+
+func (s *StatusServer) broadcast(eventType string, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	e, ok := s.toEvent(eventType, obj)
+	if !ok {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for wt := range s.watchers {
+		if !wt.match(e) {
+			continue
+		}
+
+		select {
+		case wt.events <- e:
+		default:
+			// the client is not keeping up. drop it, it will reconnect and receive a fresh snapshot
+			glog.V(4).Infof("status watcher is too slow, closing connection")
+			wt.close()
+		}
+	}
+}
+
+// toEvent converts an informer object into an event. Team members of a session receive the events of its vmclaims and vms.
+func (s *StatusServer) toEvent(eventType string, obj interface{}) (StatusEvent, bool) {
+	switch o := obj.(type) {
+	case *hfv1.Session:
+		return StatusEvent{
+			Type:           eventType,
+			Kind:           KindSession,
+			ID:             o.Name,
+			Spec:           o.Spec,
+			Status:         o.Status,
+			users:          append([]string{o.Spec.UserId}, o.Spec.Members...),
+			scheduledEvent: o.Labels[util.ScheduledEventLabel],
+		}, true
+	case *hfv1.VirtualMachineClaim:
+		return StatusEvent{
+			Type:           eventType,
+			Kind:           KindVirtualMachineClaim,
+			ID:             o.Name,
+			Spec:           o.Spec,
+			Status:         o.Status,
+			users:          s.sessionUsers(o.Spec.UserId, o.Labels[util.SessionLabel]),
+			scheduledEvent: o.Labels[util.ScheduledEventLabel],
+		}, true
+	case *hfv1.VirtualMachine:
+		sessionId := ""
+		if vmc, err := s.vmClaimLister.VirtualMachineClaims(util.GetReleaseNamespace()).Get(o.Spec.VirtualMachineClaimId); err == nil {
+			sessionId = vmc.Labels[util.SessionLabel]
+		}
+		return StatusEvent{
+			Type:           eventType,
+			Kind:           KindVirtualMachine,
+			ID:             o.Name,
+			Spec:           o.Spec,
+			Status:         o.Status,
+			users:          s.sessionUsers(o.Spec.UserId, sessionId),
+			scheduledEvent: o.Labels[util.ScheduledEventLabel],
+		}, true
+	}
+
+	return StatusEvent{}, false
+}
+
+func (s *StatusServer) sessionUsers(owner string, sessionId string) []string {
+	users := []string{owner}
+	if sessionId == "" {
+		return users
+	}
+
+	ss, err := s.sessionLister.Sessions(util.GetReleaseNamespace()).Get(sessionId)
+	if err != nil {
+		return users
+	}
+
+	return append(users, ss.Spec.Members...)
+}
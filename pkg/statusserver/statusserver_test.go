@@ -0,0 +1,170 @@
+package statusserver
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// a team session of alice and bob in se-1 with a vmclaim and a vm, next to a session of carol in se-2
+func newTestServer(t *testing.T) *StatusServer {
+	ns := util.GetReleaseNamespace()
+	factory := hfInformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+
+	s, err := NewStatusServer(nil, factory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := factory.Hobbyfarm().V1().Sessions().Informer().GetIndexer()
+	vmClaims := factory.Hobbyfarm().V1().VirtualMachineClaims().Informer().GetIndexer()
+	vms := factory.Hobbyfarm().V1().VirtualMachines().Informer().GetIndexer()
+
+	for _, err := range []error{
+		sessions.Add(&hfv1.Session{
+			ObjectMeta: metav1.ObjectMeta{Name: "ss-team", Namespace: ns, Labels: map[string]string{util.ScheduledEventLabel: "se-1"}},
+			Spec:       hfv1.SessionSpec{UserId: "alice", Members: []string{"bob"}},
+		}),
+		sessions.Add(&hfv1.Session{
+			ObjectMeta: metav1.ObjectMeta{Name: "ss-carol", Namespace: ns, Labels: map[string]string{util.ScheduledEventLabel: "se-2"}},
+			Spec:       hfv1.SessionSpec{UserId: "carol"},
+		}),
+		vmClaims.Add(&hfv1.VirtualMachineClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmc-team", Namespace: ns, Labels: map[string]string{util.SessionLabel: "ss-team", util.ScheduledEventLabel: "se-1"}},
+			Spec:       hfv1.VirtualMachineClaimSpec{UserId: "alice"},
+		}),
+		vms.Add(&hfv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm-team", Namespace: ns, Labels: map[string]string{util.ScheduledEventLabel: "se-1"}},
+			Spec:       hfv1.VirtualMachineSpec{UserId: "alice", VirtualMachineClaimId: "vmc-team"},
+		}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s
+}
+
+func (s *StatusServer) watch(match func(e StatusEvent) bool, buffer int) *watcher {
+	wt := &watcher{match: match, events: make(chan StatusEvent, buffer), done: make(chan struct{})}
+	s.watchers[wt] = struct{}{}
+	return wt
+}
+
+func userMatch(user string) func(e StatusEvent) bool {
+	return func(e StatusEvent) bool {
+		for _, u := range e.users {
+			if u == user {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func Test_Snapshot(t *testing.T) {
+	s := newTestServer(t)
+
+	visible := map[string][]string{}
+	for _, e := range s.snapshot() {
+		if e.Type != EventAdded {
+			t.Errorf("snapshot event %s %s is of type %s", e.Kind, e.ID, e.Type)
+		}
+		for _, user := range []string{"alice", "bob", "carol", "dave"} {
+			if userMatch(user)(e) {
+				visible[user] = append(visible[user], e.ID)
+			}
+		}
+	}
+	for _, ids := range visible {
+		sort.Strings(ids)
+	}
+
+	// team members see the vmclaim and vm of the session, even though the owner is alice
+	want := map[string][]string{
+		"alice": {"ss-team", "vm-team", "vmc-team"},
+		"bob":   {"ss-team", "vm-team", "vmc-team"},
+		"carol": {"ss-carol"},
+	}
+	if !reflect.DeepEqual(visible, want) {
+		t.Errorf("visible = %v, want %v", visible, want)
+	}
+}
+
+func Test_ToEvent(t *testing.T) {
+	s := newTestServer(t)
+
+	// a vm whose vmclaim is gone only goes to its owner
+	orphan := &hfv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "vm-orphan", Labels: map[string]string{util.ScheduledEventLabel: "se-1"}},
+		Spec:       hfv1.VirtualMachineSpec{UserId: "alice", VirtualMachineClaimId: "vmc-gone"},
+		Status:     hfv1.VirtualMachineStatus{PublicIP: "203.0.113.7"},
+	}
+	e, ok := s.toEvent(EventDeleted, orphan)
+	if !ok || e.Type != EventDeleted || e.Kind != KindVirtualMachine || e.ID != "vm-orphan" || e.scheduledEvent != "se-1" {
+		t.Fatalf("toEvent() = %+v, %v", e, ok)
+	}
+	if !reflect.DeepEqual(e.users, []string{"alice"}) {
+		t.Errorf("users = %v, want only the owner", e.users)
+	}
+	if status, ok := e.Status.(hfv1.VirtualMachineStatus); !ok || status.PublicIP != "203.0.113.7" {
+		t.Errorf("Status = %+v", e.Status)
+	}
+
+	if _, ok := s.toEvent(EventAdded, &hfv1.Scenario{}); ok {
+		t.Error("toEvent() converted a scenario")
+	}
+}
+
+func Test_Broadcast(t *testing.T) {
+	s := newTestServer(t)
+
+	bob := s.watch(userMatch("bob"), 4)
+	carol := s.watch(userMatch("carol"), 4)
+	se1 := s.watch(func(e StatusEvent) bool { return e.scheduledEvent == "se-1" }, 4)
+	slow := s.watch(userMatch("alice"), 0)
+
+	vmc, err := s.vmClaimLister.VirtualMachineClaims(util.GetReleaseNamespace()).Get("vmc-team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := vmc.DeepCopy()
+	old.ResourceVersion = "1"
+	changed := vmc.DeepCopy()
+	changed.ResourceVersion = "2"
+	changed.Status.Ready = true
+
+	// a resync delivers the same resource version and is not passed on
+	s.update(old, old.DeepCopy())
+	s.update(old, changed)
+	// deleted objects may arrive as tombstones
+	s.broadcast(EventDeleted, cache.DeletedFinalStateUnknown{Key: "vmc-team", Obj: changed})
+
+	for name, wt := range map[string]*watcher{"bob": bob, "se-1": se1} {
+		if len(wt.events) != 2 {
+			t.Fatalf("%s received %d events, want 2", name, len(wt.events))
+		}
+		modified, deleted := <-wt.events, <-wt.events
+		if modified.Type != EventModified || !modified.Status.(hfv1.VirtualMachineClaimStatus).Ready || deleted.Type != EventDeleted {
+			t.Errorf("%s received %+v and %+v", name, modified, deleted)
+		}
+	}
+	if len(carol.events) != 0 {
+		t.Errorf("carol received %d events of another session", len(carol.events))
+	}
+
+	// a watcher that can not keep up is closed instead of blocking everyone else
+	select {
+	case <-slow.done:
+	default:
+		t.Error("slow watcher was not closed")
+	}
+}
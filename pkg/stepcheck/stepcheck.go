@@ -0,0 +1,259 @@
+package stepcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultSshUsername = "ubuntu"
+	defaultTimeout     = 30 * time.Second
+	maxTimeout         = 5 * time.Minute
+	maxOutputLength    = 1024
+)
+
+// Runner runs step checks on the vms of a session
+type Runner struct {
+	kubeClient  kubernetes.Interface
+	hfClientSet hfClientset.Interface
+	ctx         context.Context
+
+	sshDev     string
+	sshDevHost string
+	sshDevPort string
+}
+
+func NewRunner(kubeClient kubernetes.Interface, hfClientSet hfClientset.Interface, ctx context.Context) *Runner {
+	return &Runner{
+		kubeClient:  kubeClient,
+		hfClientSet: hfClientSet,
+		ctx:         ctx,
+		sshDev:      os.Getenv("SSH_DEV"),
+		sshDevHost:  os.Getenv("SSH_DEV_HOST"),
+		sshDevPort:  os.Getenv("SSH_DEV_PORT"),
+	}
+}
+
+// RunStep runs all checks of a scenario step against the vms of a session
+func (r *Runner) RunStep(ss *hfv1.Session, step int, checks []hfv1.StepCheck) []hfv1.StepCheckResult {
+	results := []hfv1.StepCheckResult{}
+
+	for i, check := range checks {
+		result := hfv1.StepCheckResult{
+			Step:  step,
+			Check: i,
+			Name:  check.Name,
+		}
+
+		vm, err := r.findVirtualMachine(ss, check.VirtualMachine)
+		if err == nil {
+			err = r.run(check, vm, &result)
+		}
+		if err != nil {
+			glog.V(4).Infof("check %d of step %d failed for session %s: %v", i, step, ss.Name, err)
+			result.Passed = false
+			result.Error = err.Error()
+		}
+
+		result.Timestamp = time.Now().Format(time.UnixDate)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Validate checks that a step check can be run
+func Validate(check hfv1.StepCheck) error {
+	if check.VirtualMachine == "" {
+		return fmt.Errorf("check has no vm_name")
+	}
+	if check.Command == "" {
+		return fmt.Errorf("check has no command")
+	}
+	if check.ExpectedOutput != "" {
+		if _, err := regexp.Compile(check.ExpectedOutput); err != nil {
+			return fmt.Errorf("invalid expected_output %s: %v", check.ExpectedOutput, err)
+		}
+	}
+	if check.Timeout != "" {
+		timeout, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %s: %v", check.Timeout, err)
+		}
+		if timeout <= 0 || timeout > maxTimeout {
+			return fmt.Errorf("timeout has to be between 0 and %s", maxTimeout)
+		}
+	}
+
+	return nil
+}
+
+// Passed returns true if the latest results of every check of a step passed
+func Passed(results []hfv1.StepCheckResult, step int, checkCount int) bool {
+	passed := map[int]bool{}
+	for _, result := range results {
+		if result.Step == step {
+			passed[result.Check] = result.Passed
+		}
+	}
+
+	for i := 0; i < checkCount; i++ {
+		if !passed[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Merge replaces the results of a step with newer ones
+func Merge(results []hfv1.StepCheckResult, step int, newResults []hfv1.StepCheckResult) []hfv1.StepCheckResult {
+	merged := []hfv1.StepCheckResult{}
+	for _, result := range results {
+		if result.Step != step {
+			merged = append(merged, result)
+		}
+	}
+
+	return append(merged, newResults...)
+}
+
+func (r *Runner) findVirtualMachine(ss *hfv1.Session, vmName string) (*hfv1.VirtualMachine, error) {
+	for _, vmcId := range ss.Spec.VmClaimSet {
+		vmc, err := r.hfClientSet.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Get(r.ctx, vmcId, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving vmclaim %s: %v", vmcId, err)
+		}
+
+		claimed, ok := vmc.Spec.VirtualMachines[vmName]
+		if !ok || claimed.VirtualMachineId == "" {
+			continue
+		}
+
+		vm, err := r.hfClientSet.HobbyfarmV1().VirtualMachines(util.GetReleaseNamespace()).Get(r.ctx, claimed.VirtualMachineId, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving vm %s: %v", claimed.VirtualMachineId, err)
+		}
+
+		return vm, nil
+	}
+
+	return nil, fmt.Errorf("no vm %s found in session", vmName)
+}
+
+func (r *Runner) run(check hfv1.StepCheck, vm *hfv1.VirtualMachine, result *hfv1.StepCheckResult) error {
+	if err := Validate(check); err != nil {
+		return err
+	}
+
+	timeout := defaultTimeout
+	if check.Timeout != "" {
+		timeout, _ = time.ParseDuration(check.Timeout)
+	}
+
+	if vm.Status.Status != hfv1.VmStatusRunning {
+		return fmt.Errorf("vm %s is not running", vm.Name)
+	}
+
+	secret, err := r.kubeClient.CoreV1().Secrets(util.GetReleaseNamespace()).Get(r.ctx, vm.Spec.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to find keypair secret for vm")
+	}
+
+	signer, err := ssh.ParsePrivateKey(secret.Data["private_key"])
+	if err != nil {
+		return fmt.Errorf("unable to parse private key")
+	}
+
+	sshUsername := vm.Spec.SshUsername
+	if len(sshUsername) < 1 {
+		sshUsername = defaultSshUsername
+	}
+
+	config := &ssh.ClientConfig{
+		User: sshUsername,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         timeout,
+	}
+
+	host, ok := vm.Annotations["sshEndpoint"]
+	if !ok {
+		host = vm.Status.PublicIP
+	}
+	port := "22"
+	if r.sshDev == "true" {
+		if r.sshDevHost != "" {
+			host = r.sshDevHost
+		}
+		if r.sshDevPort != "" {
+			port = r.sshDevPort
+		}
+	}
+
+	sshConn, err := ssh.Dial("tcp", net.JoinHostPort(host, port), config)
+	if err != nil {
+		return fmt.Errorf("could not establish ssh session to vm: %v", err)
+	}
+	defer sshConn.Close()
+
+	sess, err := sshConn.NewSession()
+	if err != nil {
+		return fmt.Errorf("could not setup ssh session: %v", err)
+	}
+	defer sess.Close()
+
+	var output bytes.Buffer
+	sess.Stdout = &output
+	sess.Stderr = &output
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sess.Run(check.Command)
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		sshConn.Close()
+		return fmt.Errorf("check timed out after %s", timeout)
+	}
+
+	result.ExitCode = 0
+	if err != nil {
+		exitErr, ok := err.(*ssh.ExitError)
+		if !ok {
+			return fmt.Errorf("error running check: %v", err)
+		}
+		result.ExitCode = exitErr.ExitStatus()
+	}
+
+	out := output.String()
+	if len(out) > maxOutputLength {
+		out = out[:maxOutputLength]
+	}
+	result.Output = out
+
+	result.Passed = result.ExitCode == check.ExpectedExitCode
+	if result.Passed && check.ExpectedOutput != "" {
+		// already validated above
+		result.Passed = regexp.MustCompile(check.ExpectedOutput).MatchString(output.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package stepcheck
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   hfv1.StepCheck
+		wantErr bool
+	}{
+		{"valid", hfv1.StepCheck{VirtualMachine: "node", Command: "true"}, false},
+		{"valid with output and timeout", hfv1.StepCheck{VirtualMachine: "node", Command: "hostname", ExpectedOutput: "^node-[0-9]+$", Timeout: "1m"}, false},
+		{"no vm", hfv1.StepCheck{Command: "true"}, true},
+		{"no command", hfv1.StepCheck{VirtualMachine: "node"}, true},
+		{"invalid expected output", hfv1.StepCheck{VirtualMachine: "node", Command: "true", ExpectedOutput: "(unclosed"}, true},
+		{"invalid timeout", hfv1.StepCheck{VirtualMachine: "node", Command: "true", Timeout: "soon"}, true},
+		{"timeout too long", hfv1.StepCheck{VirtualMachine: "node", Command: "true", Timeout: "10m"}, true},
+		{"zero timeout", hfv1.StepCheck{VirtualMachine: "node", Command: "true", Timeout: "0s"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.check); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Passed(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []hfv1.StepCheckResult
+		step       int
+		checkCount int
+		want       bool
+	}{
+		{
+			name:       "step without checks",
+			step:       0,
+			checkCount: 0,
+			want:       true,
+		},
+		{
+			name:       "all checks passed",
+			results:    []hfv1.StepCheckResult{{Step: 1, Check: 0, Passed: true}, {Step: 1, Check: 1, Passed: true}},
+			step:       1,
+			checkCount: 2,
+			want:       true,
+		},
+		{
+			name:       "one check failed",
+			results:    []hfv1.StepCheckResult{{Step: 1, Check: 0, Passed: true}, {Step: 1, Check: 1, Passed: false}},
+			step:       1,
+			checkCount: 2,
+			want:       false,
+		},
+		{
+			name:       "one check was never run",
+			results:    []hfv1.StepCheckResult{{Step: 1, Check: 0, Passed: true}},
+			step:       1,
+			checkCount: 2,
+			want:       false,
+		},
+		{
+			name:       "results of other steps do not count",
+			results:    []hfv1.StepCheckResult{{Step: 0, Check: 0, Passed: true}},
+			step:       1,
+			checkCount: 1,
+			want:       false,
+		},
+		{
+			name:       "the latest result counts",
+			results:    []hfv1.StepCheckResult{{Step: 1, Check: 0, Passed: false}, {Step: 1, Check: 0, Passed: true}},
+			step:       1,
+			checkCount: 1,
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Passed(tt.results, tt.step, tt.checkCount); got != tt.want {
+				t.Errorf("Passed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	results := []hfv1.StepCheckResult{
+		{Step: 0, Check: 0, Passed: true},
+		{Step: 1, Check: 0, Passed: false},
+		{Step: 1, Check: 1, Passed: false},
+		{Step: 2, Check: 0, Passed: true},
+	}
+	newResults := []hfv1.StepCheckResult{{Step: 1, Check: 0, Passed: true}}
+
+	got := Merge(results, 1, newResults)
+	want := []hfv1.StepCheckResult{
+		{Step: 0, Check: 0, Passed: true},
+		{Step: 2, Check: 0, Passed: true},
+		{Step: 1, Check: 0, Passed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+	if len(results) != 4 || results[1].Passed {
+		t.Error("Merge() changed the previous results")
+	}
+
+	if got := Merge(nil, 0, nil); got == nil || len(got) != 0 {
+		t.Errorf("Merge() of nothing = %#v, want an empty list", got)
+	}
+}
+
+func Test_RunStepWithoutVirtualMachine(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	vmc := &hfv1.VirtualMachineClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmc-1", Namespace: util.GetReleaseNamespace()},
+		Spec: hfv1.VirtualMachineClaimSpec{
+			VirtualMachines: map[string]hfv1.VirtualMachineClaimVM{"node": {VirtualMachineId: ""}},
+		},
+	}
+	if _, err := client.HobbyfarmV1().VirtualMachineClaims(util.GetReleaseNamespace()).Create(ctx, vmc, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(nil, client, ctx)
+	ss := &hfv1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: "ss-1"},
+		Spec:       hfv1.SessionSpec{VmClaimSet: []string{"vmc-1"}},
+	}
+	checks := []hfv1.StepCheck{
+		{Name: "unassigned", VirtualMachine: "node", Command: "true"},
+		{Name: "unknown", VirtualMachine: "other", Command: "true"},
+	}
+
+	results := runner.RunStep(ss, 3, checks)
+	if len(results) != len(checks) {
+		t.Fatalf("RunStep() returned %d results, want %d", len(results), len(checks))
+	}
+	for i, result := range results {
+		if result.Step != 3 || result.Check != i || result.Name != checks[i].Name {
+			t.Errorf("result %d is %+v", i, result)
+		}
+		if result.Passed || result.Error == "" || result.Timestamp == "" {
+			t.Errorf("result %d passed without a vm: %+v", i, result)
+		}
+	}
+}
@@ -4,9 +4,15 @@ const (
 	AccessCodeLabel =		"hobbyfarm.io/accesscode"
 	OneTimeAccessCodeLabel ="hobbyfarm.io/otac"
 	ScheduledEventLabel = 	"hobbyfarm.io/scheduledevent"
+	ScheduledEventSeriesLabel =	"hobbyfarm.io/scheduledeventseries"
 	SessionLabel = 			"hobbyfarm.io/session"
 	UserLabel = 			"hobbyfarm.io/user"
 	RBACManagedLabel =		"rbac.hobbyfarm.io/managed"
 	EnvironmentLabel =		"hobbyfarm.io/environment"
 	VirtualMachineTemplate ="hobbyfarm.io/virtualmachinetemplate"
+	ScenarioLabel =			"hobbyfarm.io/scenario"
+	CourseLabel =			"hobbyfarm.io/course"
+	ContentSourceLabel =	"hobbyfarm.io/contentsource"
+	LTIIdentityLabel =		"hobbyfarm.io/lti-identity"
+	BoundLabel =			"bound"
 )
\ No newline at end of file
@@ -11,15 +11,20 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 	mrand "math/rand"
 
 	"github.com/golang/glog"
 	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
 	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/prewarm"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
 
 	"net/http"
@@ -136,6 +141,20 @@ func RandStringRunes(n int) string {
 	return string(b)
 }
 
+// RandSecretRunes is RandStringRunes backed by crypto/rand, for strings that grant access on their own
+func RandSecretRunes(n int) (string, error) {
+	b := make([]rune, n)
+	limit := big.NewInt(int64(len(letterRunes)))
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		b[i] = letterRunes[idx.Int64()]
+	}
+	return string(b), nil
+}
+
 // borrowed from longhorn
 func ResourceVersionAtLeast(curr, min string) bool {
 	if curr == "" || min == "" {
@@ -176,6 +195,32 @@ func GenKeyPair() (string, string, error) {
 	return string(public), private.String(), nil
 }
 
+// SigningKey returns the key kept under data in the named secret of the release namespace. The secret is created with
+// a key from generate the first time, so that every replica signs with the same key.
+func SigningKey(kubeClient kubernetes.Interface, name string, data string, generate func() ([]byte, error), ctx context.Context) ([]byte, error) {
+	secrets := kubeClient.CoreV1().Secrets(GetReleaseNamespace())
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		var key []byte
+		key, err = generate()
+		if err != nil {
+			return nil, err
+		}
+		secret, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       map[string][]byte{data: key},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// created by another replica in the meantime
+			secret, err = secrets.Get(ctx, name, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data[data], nil
+}
+
 func VerifyVM(vmLister hfListers.VirtualMachineLister, vm *hfv1.VirtualMachine) error {
 	var err error
 	glog.V(5).Infof("Verifying vm %s", vm.Name)
@@ -353,81 +398,99 @@ func Max(x, y int) int {
 // Calculates available virtualMachineTemplates for a given period (startString, endString) and environment
 // Returns a map with timestamps and corresponding availability of virtualmachines. Also returns the maximum available count of virtualmachinetemplates over the whole duration.
 func VirtualMachinesUsedDuringPeriod(hfClientset hfClientset.Interface, environment string, startString string, endString string, ctx context.Context) (map[time.Time]map[string]int, map[string]int, error) {
-	start, err := time.Parse(time.UnixDate, startString)
+	start, err := hfv2.ParseScheduledEventTime(startString, time.UTC)
 	if err != nil {
 		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error parsing start time %v", err)
 	}
 
+	end, err := hfv2.ParseScheduledEventTime(endString, time.UTC)
+	if err != nil {
+		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error parsing end time %v", err)
+	}
+
+	return VirtualMachinesUsedDuringPeriodExcept(hfClientset, environment, start.Time, end.Time, "", ctx)
+}
+
+// VirtualMachinesUsedDuringPeriodExcept works like VirtualMachinesUsedDuringPeriod but leaves out the scheduled event
+// with the given name, so that an event that is edited is not counted twice.
+func VirtualMachinesUsedDuringPeriodExcept(hfClientset hfClientset.Interface, environment string, start time.Time, end time.Time, except string, ctx context.Context) (map[time.Time]map[string]int, map[string]int, error) {
 	// We only want to calculate for the future. Otherwise old ( even finished ) events will be considered too.
 	if start.Before(time.Now()) {
 		start = time.Now()
 	}
 
-	end, err := time.Parse(time.UnixDate, endString)
+	scheduledEvents, err := hfClientset.HobbyfarmV2().ScheduledEvents(GetReleaseNamespace()).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error parsing end time %v", err)
+		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error retrieving scheduled events %v", err)
 	}
 
-	scheduledEvents, err := hfClientset.HobbyfarmV1().ScheduledEvents(GetReleaseNamespace()).List(ctx, metav1.ListOptions{})
+	environmentList, err := hfClientset.HobbyfarmV1().Environments(GetReleaseNamespace()).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error retrieving scheduled events %v", err)
+		return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error retrieving environments %v", err)
+	}
+	environments := map[string]hfv1.Environment{}
+	for _, env := range environmentList.Items {
+		environments[env.Name] = env
 	}
 
 	var timeRange []Range
-	var changingTimestamps []time.Time                        // All timestamps where number of virtualmachines changes (Begin or End of Scheduled Event)
+	changingTimestamps := []time.Time{start}                  // All timestamps where number of virtualmachines changes (Begin or End of Scheduled Event)
 	virtualMachineCount := make(map[time.Time]map[string]int) // Count of virtualmachines per VMTemplate for any given timestamp where a change happened
 	maximumVirtualMachineCount := make(map[string]int)        // Maximum VirtualMachine Count per VirtualMachineTemplate over all timestamps
 
 	for _, se := range scheduledEvents.Items {
+		if se.Name == except {
+			continue
+		}
 		// Scheduled Event uses the environment we are checking
 		if vmMapping, ok := se.Spec.RequiredVirtualMachines[environment]; ok {
-			seStart, err := time.Parse(time.UnixDate, se.Spec.StartTime)
-			if err != nil {
-				return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error parsing scheduled event start %v", err)
-			}
-			seEnd, err := time.Parse(time.UnixDate, se.Spec.EndTime)
-			if err != nil {
-				return map[time.Time]map[string]int{}, map[string]int{}, fmt.Errorf("error parsing scheduled event end %v", err)
-			}
+			// pre-warmed events use their virtual machines from the time they are provisioned
+			seStart := prewarm.ProvisionTime(se.Spec.StartTime.Time, prewarmSchedules(&se, environments))
+			seEnd := se.Spec.EndTime.Time
 			// Scheduled Event is withing our timerange. We consider it by adding it to our Ranges
-			if start.Equal(seStart) || end.Equal(seEnd) || (start.Before(seEnd) && end.After(seStart)) {
+			if start.Before(seEnd) && end.After(seStart) {
 				timeRange = append(timeRange, Range{Start: seStart, End: seEnd, VMMapping: vmMapping})
-				changingTimestamps = append(changingTimestamps, seStart)
-				changingTimestamps = append(changingTimestamps, seEnd)
-				virtualMachineCount[seStart] = make(map[string]int)
-				virtualMachineCount[seEnd] = make(map[string]int)
+				// Events that began before our period are counted from its start
+				if seStart.After(start) {
+					changingTimestamps = append(changingTimestamps, seStart)
+				}
+				if seEnd.Before(end) {
+					changingTimestamps = append(changingTimestamps, seEnd)
+				}
 				glog.V(4).Infof("Scheduled Event %s was within the time period", se.Name)
 			}
 		}
 	}
 
-	// Sort timestamps
+	// Sort timestamps, an event may end when another one begins
 	sortTime(changingTimestamps)
 
+	uniqueTimestamps := changingTimestamps[:0]
+	for _, timestamp := range changingTimestamps {
+		if _, ok := virtualMachineCount[timestamp]; !ok {
+			virtualMachineCount[timestamp] = make(map[string]int)
+			uniqueTimestamps = append(uniqueTimestamps, timestamp)
+		}
+	}
+	changingTimestamps = uniqueTimestamps
+
 	for _, eventRange := range timeRange {
 		// For any given Scheduled Event check if the timestamp is during the duration of our event. Add required Virtualmachine Counts to this timestamp.
+		// An event that ends at the timestamp another one begins does not overlap with it.
 		for _, timestamp := range changingTimestamps {
 			if eventRange.Start.After(timestamp) {
 				continue
 			}
-			if eventRange.End.Before(timestamp) {
+			if !eventRange.End.After(timestamp) {
 				break
 			}
 
 			// When we are here the timestamp is in the duration of this event.
 			for vmTemplateName, vmTemplateCount := range eventRange.VMMapping {
 				// VM Capacity for this timestamp
-				if currentVMCapacity, ok := virtualMachineCount[timestamp][vmTemplateName]; ok {
-					virtualMachineCount[timestamp][vmTemplateName] = currentVMCapacity + vmTemplateCount
-				} else {
-					virtualMachineCount[timestamp][vmTemplateName] = vmTemplateCount
-				}
+				virtualMachineCount[timestamp][vmTemplateName] += vmTemplateCount
 				// Highest VM Capacity over all timestamps
-				if maximumVMCapacity, ok := maximumVirtualMachineCount[vmTemplateName]; ok {
-					maximumVirtualMachineCount[vmTemplateName] = Max(maximumVMCapacity, virtualMachineCount[timestamp][vmTemplateName])
-				} else {
-					maximumVirtualMachineCount[vmTemplateName] = vmTemplateCount
-				}
+				maximumVirtualMachineCount[vmTemplateName] = Max(maximumVirtualMachineCount[vmTemplateName], virtualMachineCount[timestamp][vmTemplateName])
 			}
 
 		}
@@ -436,6 +499,93 @@ func VirtualMachinesUsedDuringPeriod(hfClientset hfClientset.Interface, environm
 	return virtualMachineCount, maximumVirtualMachineCount, nil
 }
 
+// CapacityShortfall returns how many virtual machines per environment and template are missing for a scheduled event,
+// given the events that overlap with it. It is empty if the event fits.
+func CapacityShortfall(hfClientset hfClientset.Interface, se *hfv2.ScheduledEvent, ctx context.Context) (map[string]map[string]int, error) {
+	shortfall := map[string]map[string]int{}
+
+	schedules, err := PrewarmSchedules(hfClientset, se, ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := prewarm.ProvisionTime(se.Spec.StartTime.Time, schedules)
+
+	for environment, vmtMap := range se.Spec.RequiredVirtualMachines {
+		_, maximumVirtualMachineCount, err := VirtualMachinesUsedDuringPeriodExcept(hfClientset, environment, start, se.Spec.EndTime.Time, se.Name, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		environmentFromK8s, err := hfClientset.HobbyfarmV1().Environments(GetReleaseNamespace()).Get(ctx, environment, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving environment %v", err)
+		}
+
+		for vmt, count := range vmtMap {
+			if count <= 0 {
+				continue
+			}
+			available := Max(environmentFromK8s.Spec.CountCapacity[vmt]-maximumVirtualMachineCount[vmt], 0)
+			if count > available {
+				if shortfall[environment] == nil {
+					shortfall[environment] = map[string]int{}
+				}
+				shortfall[environment][vmt] = count - available
+			}
+		}
+	}
+
+	return shortfall, nil
+}
+
+// PrewarmSchedules returns the pre-warming of a scheduled event in each of its environments. Events on demand do not
+// provision virtual machines ahead, they get no schedules.
+func PrewarmSchedules(hfClientset hfClientset.Interface, se *hfv2.ScheduledEvent, ctx context.Context) (map[string]prewarm.Schedule, error) {
+	schedules := map[string]prewarm.Schedule{}
+	if se.Spec.OnDemand {
+		return schedules, nil
+	}
+	for environment := range se.Spec.RequiredVirtualMachines {
+		environmentFromK8s, err := hfClientset.HobbyfarmV1().Environments(GetReleaseNamespace()).Get(ctx, environment, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving environment %v", err)
+		}
+		schedules[environment], err = prewarm.Resolve(se.Spec.Prewarm, environmentFromK8s.Spec.Prewarm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
+
+// prewarmSchedules works like PrewarmSchedules on environments that were listed before, invalid policies are ignored
+func prewarmSchedules(se *hfv2.ScheduledEvent, environments map[string]hfv1.Environment) map[string]prewarm.Schedule {
+	schedules := map[string]prewarm.Schedule{}
+	if se.Spec.OnDemand {
+		return schedules
+	}
+	for environment := range se.Spec.RequiredVirtualMachines {
+		schedule, err := prewarm.Resolve(se.Spec.Prewarm, environments[environment].Spec.Prewarm)
+		if err != nil {
+			glog.Errorf("invalid prewarm policy of scheduled event %s in environment %s: %v", se.Name, environment, err)
+		}
+		schedules[environment] = schedule
+	}
+	return schedules
+}
+
+// DescribeShortfall formats a capacity shortfall for an error message
+func DescribeShortfall(shortfall map[string]map[string]int) string {
+	var missing []string
+	for environment, vmtMap := range shortfall {
+		for vmt, count := range vmtMap {
+			missing = append(missing, fmt.Sprintf("%d %s in %s", count, vmt, environment))
+		}
+	}
+	sort.Strings(missing)
+	return strings.Join(missing, ", ")
+}
+
 func CountMachinesPerTemplateAndEnvironment(vmLister hfListers.VirtualMachineLister, template string, enviroment string) (int, error) {
 	vmLabels := labels.Set{
 		EnvironmentLabel:       enviroment,
@@ -521,3 +671,72 @@ func GetVMConfig(env *hfv1.Environment, vmt *hfv1.VirtualMachineTemplate) map[st
 
 	return config
 }
+
+// IsSessionMember returns true if the user owns the session or joined it as a team member
+func IsSessionMember(ss *hfv1.Session, userId string) bool {
+	if ss.Spec.UserId == userId {
+		return true
+	}
+
+	for _, member := range ss.Spec.Members {
+		if member == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsVMClaimMember returns true if the user owns the vmclaim or is a team member of the session it belongs to
+func IsVMClaimMember(sessionLister hfListers.SessionLister, vmc *hfv1.VirtualMachineClaim, userId string) bool {
+	if vmc.Spec.UserId == userId {
+		return true
+	}
+
+	sessionId, ok := vmc.Labels[SessionLabel]
+	if !ok {
+		return false
+	}
+
+	ss, err := sessionLister.Sessions(GetReleaseNamespace()).Get(sessionId)
+	if err != nil {
+		return false
+	}
+
+	return IsSessionMember(ss, userId)
+}
+
+// IsVirtualMachineMember returns true if the user owns the vm or is a team member of the session it is claimed by
+func IsVirtualMachineMember(vmClaimLister hfListers.VirtualMachineClaimLister, sessionLister hfListers.SessionLister, vm *hfv1.VirtualMachine, userId string) bool {
+	if vm.Spec.UserId == userId {
+		return true
+	}
+
+	if vm.Spec.VirtualMachineClaimId == "" {
+		return false
+	}
+
+	vmc, err := vmClaimLister.VirtualMachineClaims(GetReleaseNamespace()).Get(vm.Spec.VirtualMachineClaimId)
+	if err != nil {
+		return false
+	}
+
+	return IsVMClaimMember(sessionLister, vmc, userId)
+}
+
+// CategorySelector returns the label selector of a dynamic course category query.
+// Parts of a query are joined by &, a part starting with ! excludes the category, e.g. linux&!advanced
+func CategorySelector(categoryQuery string) (labels.Selector, error) {
+	categorySelectors := []string{}
+	categoryQueryParts := strings.Split(categoryQuery, "&")
+	for _, categoryQueryPart := range categoryQueryParts {
+		operator := "in"
+		if strings.HasPrefix(categoryQueryPart, "!") {
+			operator = "notin"
+			categoryQueryPart = categoryQueryPart[1:]
+		}
+		categorySelectors = append(categorySelectors, fmt.Sprintf("category-%s %s (true)", categoryQueryPart, operator))
+	}
+
+	return labels.Parse(strings.Join(categorySelectors, ","))
+}
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// the period is checked from now on, so all events of these tests lie in the future
+var periodBase = time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+
+func at(hour int) time.Time {
+	return periodBase.Add(time.Duration(hour) * time.Hour)
+}
+
+type usage struct {
+	name     string
+	from, to int
+	count    int
+	env      string
+	prewarm  string // lead time
+}
+
+func (u usage) event() *hfv2.ScheduledEvent {
+	se := &hfv2.ScheduledEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: u.name, Namespace: GetReleaseNamespace()},
+		Spec: hfv2.ScheduledEventSpec{
+			StartTime:               metav1.NewTime(at(u.from)),
+			EndTime:                 metav1.NewTime(at(u.to)),
+			RequiredVirtualMachines: map[string]map[string]int{u.env: {"small": u.count}},
+		},
+	}
+	if u.prewarm != "" {
+		se.Spec.Prewarm = &hfv1.PrewarmPolicy{LeadTime: u.prewarm}
+	}
+	return se
+}
+
+func environment(capacity int) *hfv1.Environment {
+	return &hfv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: GetReleaseNamespace()},
+		Spec:       hfv1.EnvironmentSpec{CountCapacity: map[string]int{"small": capacity}},
+	}
+}
+
+func clientWith(usages []usage) *fake.Clientset {
+	objects := []runtime.Object{environment(10)}
+	for _, u := range usages {
+		objects = append(objects, u.event())
+	}
+	return fake.NewSimpleClientset(objects...)
+}
+
+// VirtualMachinesUsedDuringPeriod is checked for the period from hour 10 to hour 20
+func Test_VirtualMachinesUsedDuringPeriod(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []usage
+		want   int // maximum of small virtual machines used
+	}{
+		{"no events", nil, 0},
+		{"event ending when the period starts", []usage{{"a", 5, 10, 3, "aws", ""}}, 0},
+		{"event starting when the period ends", []usage{{"a", 20, 25, 3, "aws", ""}}, 0},
+		{"event covering the period", []usage{{"a", 5, 25, 3, "aws", ""}}, 3},
+		{"event within the period", []usage{{"a", 12, 14, 3, "aws", ""}}, 3},
+		{"event of another environment", []usage{{"a", 12, 14, 3, "gcp", ""}}, 0},
+		{"back to back events", []usage{{"a", 10, 15, 3, "aws", ""}, {"b", 15, 20, 4, "aws", ""}}, 4},
+		{"overlapping events", []usage{{"a", 10, 15, 3, "aws", ""}, {"b", 14, 20, 4, "aws", ""}}, 7},
+		{"event that began before the period overlaps one within", []usage{{"a", 5, 12, 2, "aws", ""}, {"b", 11, 13, 3, "aws", ""}}, 5},
+		{"events overlapping before the period", []usage{{"a", 5, 11, 2, "aws", ""}, {"b", 6, 9, 3, "aws", ""}}, 2},
+		{"pre-warmed event starting after the period", []usage{{"a", 21, 25, 3, "aws", "2h"}}, 3},
+		{"pre-warmed event provisioned when the period ends", []usage{{"a", 22, 25, 3, "aws", "2h"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, maximum, err := VirtualMachinesUsedDuringPeriod(clientWith(tt.events), "aws",
+				at(10).Format(time.UnixDate), at(20).Format(time.UnixDate), context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if maximum["small"] != tt.want {
+				t.Errorf("maximum = %d, want %d (counts %v)", maximum["small"], tt.want, counts)
+			}
+			for timestamp := range counts {
+				if timestamp.Before(at(10)) || !timestamp.Before(at(20)) {
+					t.Errorf("count at %v is outside of the period", timestamp)
+				}
+			}
+		})
+	}
+}
+
+func Test_VirtualMachinesUsedDuringPeriodInvalidTime(t *testing.T) {
+	_, _, err := VirtualMachinesUsedDuringPeriod(clientWith(nil), "aws", "tomorrow", at(20).Format(time.UnixDate), context.Background())
+	if err == nil {
+		t.Error("VirtualMachinesUsedDuringPeriod() with an invalid start did not fail")
+	}
+}
+
+func Test_CapacityShortfall(t *testing.T) {
+	existing := []usage{{"running", 10, 15, 6, "aws", ""}}
+
+	tests := []struct {
+		name    string
+		event   usage
+		want    int // missing small virtual machines
+		wantErr bool
+	}{
+		{"fits next to the other event", usage{"new", 5, 10, 10, "aws", ""}, 0, false},
+		{"starts when the other event ends", usage{"new", 15, 20, 10, "aws", ""}, 0, false},
+		{"overlaps the other event", usage{"new", 12, 18, 5, "aws", ""}, 1, false},
+		{"is pre-warmed into the other event", usage{"new", 16, 20, 5, "aws", "2h"}, 1, false},
+		{"is the event itself", usage{"running", 10, 15, 8, "aws", ""}, 0, false},
+		{"exceeds the capacity on its own", usage{"new", 20, 25, 12, "aws", ""}, 2, false},
+		{"uses an environment that does not exist", usage{"new", 20, 25, 1, "gcp", ""}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortfall, err := CapacityShortfall(clientWith(existing), tt.event.event(), context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CapacityShortfall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := shortfall[tt.event.env]["small"]; got != tt.want {
+				t.Errorf("shortfall = %v, want %d small missing", shortfall, tt.want)
+			}
+			if tt.want == 0 && len(shortfall) > 0 {
+				t.Errorf("shortfall = %v, want none", shortfall)
+			}
+		})
+	}
+}
+
+func Test_DescribeShortfall(t *testing.T) {
+	shortfall := map[string]map[string]int{
+		"gcp": {"small": 1},
+		"aws": {"large": 2, "small": 3},
+	}
+	if got, want := DescribeShortfall(shortfall), "1 small in gcp, 2 large in aws, 3 small in aws"; got != want {
+		t.Errorf("DescribeShortfall() = %q, want %q", got, want)
+	}
+}
+
+func Test_SigningKey(t *testing.T) {
+	ctx := context.Background()
+	generated := 0
+	generate := func() ([]byte, error) {
+		generated++
+		return []byte{byte(generated)}, nil
+	}
+
+	kubeClient := kubefake.NewSimpleClientset()
+	first, err := SigningKey(kubeClient, "signing-key", "key", generate, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SigningKey(kubeClient, "signing-key", "key", generate, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated != 1 || !bytes.Equal(first, second) {
+		t.Errorf("keys %v and %v after %d generated, want the stored key to be reused", first, second, generated)
+	}
+
+	// another replica creates the secret between the lookup and the create
+	racing := kubefake.NewSimpleClientset()
+	racing.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		stored := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "signing-key", Namespace: GetReleaseNamespace()},
+			Data:       map[string][]byte{"key": []byte("stored first")},
+		}
+		if err := racing.Tracker().Add(stored); err != nil {
+			return true, nil, err
+		}
+		return false, nil, nil
+	})
+	key, err := SigningKey(racing, "signing-key", "key", generate, ctx)
+	if err != nil || string(key) != "stored first" {
+		t.Errorf("SigningKey() = %q, %v, want the key stored first", key, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	"k8s.io/client-go/tools/cache"
@@ -25,6 +26,7 @@ type VMClaimServer struct {
 	hfClientSet hfClientset.Interface
 
 	vmClaimIndexer cache.Indexer
+	sessionLister  hfListers.SessionLister
 }
 
 func NewVMClaimServer(authClient *authclient.AuthClient, hfClientset hfClientset.Interface, hfInformerFactory hfInformers.SharedInformerFactory) (*VMClaimServer, error) {
@@ -37,6 +39,7 @@ func NewVMClaimServer(authClient *authclient.AuthClient, hfClientset hfClientset
 	indexers := map[string]cache.IndexFunc{idIndex: vmcIdIndexer}
 	inf.AddIndexers(indexers)
 	vmcs.vmClaimIndexer = inf.GetIndexer()
+	vmcs.sessionLister = hfInformerFactory.Hobbyfarm().V1().Sessions().Lister()
 
 	return &vmcs, nil
 }
@@ -103,7 +106,7 @@ func (vmcs VMClaimServer) GetVMClaimFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if vmc.Spec.UserId != user.Name {
+	if !util.IsVMClaimMember(vmcs.sessionLister, &vmc, user.Name) {
 		_, err := vmcs.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
 		if err != nil {
 			util.ReturnHTTPMessage(w, r, 403, "forbidden", "access denied to get vmclaim")
@@ -12,6 +12,7 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/authclient"
 	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
 	hfInformers "github.com/hobbyfarm/gargantua/v3/pkg/client/informers/externalversions"
+	hfListers "github.com/hobbyfarm/gargantua/v3/pkg/client/listers/hobbyfarm.io/v1"
 	"github.com/hobbyfarm/gargantua/v3/pkg/rbacclient"
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,9 @@ type VMServer struct {
 	hfClientSet hfClientset.Interface
 	ctx         context.Context
 	vmIndexer   cache.Indexer
+
+	vmClaimLister hfListers.VirtualMachineClaimLister
+	sessionLister hfListers.SessionLister
 }
 
 type PreparedVirtualMachine struct {
@@ -48,6 +52,8 @@ func NewVMServer(authClient *authclient.AuthClient, hfClientset hfClientset.Inte
 	indexers := map[string]cache.IndexFunc{idIndex: vmIdIndexer}
 	inf.AddIndexers(indexers)
 	vms.vmIndexer = inf.GetIndexer()
+	vms.vmClaimLister = hfInformerFactory.Hobbyfarm().V1().VirtualMachineClaims().Lister()
+	vms.sessionLister = hfInformerFactory.Hobbyfarm().V1().Sessions().Lister()
 
 	return &vms, nil
 }
@@ -88,7 +94,7 @@ func (vms VMServer) getWebinterfaces(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if the VM belongs to the User or User has RBAC-Rights to access VMs
-	if vm.Spec.UserId != user.Name {
+	if !util.IsVirtualMachineMember(vms.vmClaimLister, vms.sessionLister, &vm, user.Name) {
 		_, err := vms.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
 		if err != nil {
 			glog.Errorf("user forbidden from accessing vm id %s", vm.Name)
@@ -168,7 +174,7 @@ func (vms VMServer) GetVMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if vm.Spec.UserId != user.Name {
+	if !util.IsVirtualMachineMember(vms.vmClaimLister, vms.sessionLister, &vm, user.Name) {
 		_, err := vms.auth.AuthGrant(rbacclient.RbacRequest().HobbyfarmPermission(resourcePlural, rbacclient.VerbGet), w, r)
 		if err != nil {
 			glog.Errorf("user forbidden from accessing vm id %s", vm.Name)
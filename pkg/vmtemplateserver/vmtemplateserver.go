@@ -302,7 +302,7 @@ func (v VirtualMachineTemplateServer) DeleteFunc(w http.ResponseWriter, r *http.
 	}
 
 	// now check scheduledevents
-	scheduledEvents, err := v.hfClientSet.HobbyfarmV1().ScheduledEvents(util.GetReleaseNamespace()).List(v.ctx, metav1.ListOptions{})
+	scheduledEvents, err := v.hfClientSet.HobbyfarmV2().ScheduledEvents(util.GetReleaseNamespace()).List(v.ctx, metav1.ListOptions{})
 	if err != nil {
 		util.ReturnHTTPMessage(w, r, 500, "internalerror",
 			"error listing scheduled events while attempting vmt deletion")
@@ -311,20 +311,10 @@ func (v VirtualMachineTemplateServer) DeleteFunc(w http.ResponseWriter, r *http.
 
 	if len(scheduledEvents.Items) > 0 {
 		for _, v := range scheduledEvents.Items {
-			if v.Status.Finished != true {
+			if !v.Status.IsFinished() {
 				// unfinished SE. Is it going on now or in the future?
-				startTime, err := time.Parse(time.UnixDate, v.Spec.StartTime)
-				if err != nil {
-					util.ReturnHTTPMessage(w, r, 500, "internalerror",
-						"error parsing time while checking scheduledevent for conflict")
-					return
-				}
-				endTime, err := time.Parse(time.UnixDate, v.Spec.EndTime)
-				if err != nil {
-					util.ReturnHTTPMessage(w, r, 500, "internalerror",
-						"error parsing time while checking scheduledevent for conflict")
-					return
-				}
+				startTime := v.Spec.StartTime.Time
+				endTime := v.Spec.EndTime.Time
 
 				// if this starts in the future, or hasn't ended
 				if startTime.After(time.Now()) || endTime.After(time.Now()) {
@@ -0,0 +1,61 @@
+package progress
+
+import (
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Init() {
+	conversion.RegisterConverter(schema.GroupKind{
+		Group: "hobbyfarm.io",
+		Kind:  "progresses",
+	}, convert)
+}
+
+func convert(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
+	fromVersion := Object.GetAPIVersion()
+
+	if toVersion == fromVersion {
+		return nil, conversion.StatusFailureWithMessage("cannot convert from/to same version")
+	}
+
+	var converted runtime.Object
+	switch fromVersion {
+	case "hobbyfarm.io/v1":
+		switch toVersion {
+		case "hobbyfarm.io/v2":
+			in := &hfv1.Progress{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, in); err != nil {
+				return nil, conversion.StatusFailureWithMessage("error decoding progress %s: %v", Object.GetName(), err)
+			}
+			converted = hfv2.ConvertProgressFromV1(in)
+		default:
+			return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", toVersion)
+		}
+	case "hobbyfarm.io/v2":
+		switch toVersion {
+		case "hobbyfarm.io/v1":
+			in := &hfv2.Progress{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, in); err != nil {
+				return nil, conversion.StatusFailureWithMessage("error decoding progress %s: %v", Object.GetName(), err)
+			}
+			converted = hfv2.ConvertProgressToV1(in)
+		default:
+			return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", toVersion)
+		}
+	default:
+		return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", fromVersion)
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(converted)
+	if err != nil {
+		return nil, conversion.StatusFailureWithMessage("error encoding progress %s: %v", Object.GetName(), err)
+	}
+
+	return &unstructured.Unstructured{Object: object}, metav1.Status{Status: metav1.StatusSuccess}
+}
@@ -0,0 +1,61 @@
+package scheduledevent
+
+import (
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/conversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Init() {
+	conversion.RegisterConverter(schema.GroupKind{
+		Group: "hobbyfarm.io",
+		Kind:  "scheduledevents",
+	}, convert)
+}
+
+func convert(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
+	fromVersion := Object.GetAPIVersion()
+
+	if toVersion == fromVersion {
+		return nil, conversion.StatusFailureWithMessage("cannot convert from/to same version")
+	}
+
+	var converted runtime.Object
+	switch fromVersion {
+	case "hobbyfarm.io/v1":
+		switch toVersion {
+		case "hobbyfarm.io/v2":
+			in := &hfv1.ScheduledEvent{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, in); err != nil {
+				return nil, conversion.StatusFailureWithMessage("error decoding scheduled event %s: %v", Object.GetName(), err)
+			}
+			converted = hfv2.ConvertScheduledEventFromV1(in)
+		default:
+			return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", toVersion)
+		}
+	case "hobbyfarm.io/v2":
+		switch toVersion {
+		case "hobbyfarm.io/v1":
+			in := &hfv2.ScheduledEvent{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, in); err != nil {
+				return nil, conversion.StatusFailureWithMessage("error decoding scheduled event %s: %v", Object.GetName(), err)
+			}
+			converted = hfv2.ConvertScheduledEventToV1(in)
+		default:
+			return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", toVersion)
+		}
+	default:
+		return nil, conversion.StatusFailureWithMessage("unexpected version %v for conversion", fromVersion)
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(converted)
+	if err != nil {
+		return nil, conversion.StatusFailureWithMessage("error encoding scheduled event %s: %v", Object.GetName(), err)
+	}
+
+	return &unstructured.Unstructured{Object: object}, metav1.Status{Status: metav1.StatusSuccess}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/hobbyfarm/gargantua/v3/pkg/util"
 	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/admitters"
 	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/deserialize"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/validators/content"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/validators/revision"
 	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/validators/setting"
 	"github.com/pkg/errors"
 	"io"
@@ -41,7 +43,13 @@ type Validator interface {
 func SetupValidationServer(hfclient *hfClientset.Clientset, router *mux.Router) {
 	settingServer := setting.New(hfclient)
 
-	for _, f := range []Validator{settingServer} {
+	scenarioRevisionServer := revision.NewScenarioRevision()
+	courseRevisionServer := revision.NewCourseRevision()
+
+	scenarioServer := content.NewScenario(hfclient)
+	courseServer := content.NewCourse(hfclient)
+
+	for _, f := range []Validator{settingServer, scenarioRevisionServer, courseRevisionServer, scenarioServer, courseServer} {
 		deserialize.RegisterScheme(f.GVK().GroupVersion(), f.RegisterTypes()...)
 
 		handlers[f.GVK()] = admitters.Admitters{
@@ -60,6 +68,7 @@ func init() {
 
 func RegisterRoutes(router *mux.Router) {
 	for k := range handlers {
+		k := k // every route needs its own gvk
 		router.Path(fmt.Sprintf("/%s/%s/%s", k.Group, k.Version, k.Kind)).
 			HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 				dispatch(k, writer, request)
@@ -0,0 +1,118 @@
+package content
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	v12 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/lint"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/conversion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/deserialize"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/response"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Server rejects scenarios and courses that would only fail once a learner starts a session,
+// see lint for the checks
+type Server struct {
+	hfclient *hfClientset.Clientset
+	kind     string
+	types    []runtime.Object
+	lint     func(ctx context.Context, hfclient *hfClientset.Clientset, raw []byte) ([]lint.Issue, error)
+}
+
+func NewScenario(hfclient *hfClientset.Clientset) *Server {
+	return &Server{
+		hfclient: hfclient,
+		kind:     "scenarios",
+		types:    []runtime.Object{&v12.Scenario{}, &v12.ScenarioList{}},
+		lint: func(ctx context.Context, hfclient *hfClientset.Clientset, raw []byte) ([]lint.Issue, error) {
+			scenario := &v12.Scenario{}
+			if _, _, err := deserialize.Decode(raw, nil, scenario); err != nil {
+				return nil, err
+			}
+			return lint.Scenario(ctx, hfclient, scenario.Spec), nil
+		},
+	}
+}
+
+func NewCourse(hfclient *hfClientset.Clientset) *Server {
+	return &Server{
+		hfclient: hfclient,
+		kind:     "courses",
+		types:    []runtime.Object{&v12.Course{}, &v12.CourseList{}},
+		lint: func(ctx context.Context, hfclient *hfClientset.Clientset, raw []byte) ([]lint.Issue, error) {
+			course := &v12.Course{}
+			if _, _, err := deserialize.Decode(raw, nil, course); err != nil {
+				return nil, err
+			}
+			return lint.Course(ctx, hfclient, course.Spec), nil
+		},
+	}
+}
+
+func (s *Server) RegisterTypes() []runtime.Object {
+	return s.types
+}
+
+func (s *Server) GVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   v12.SchemeGroupVersion.Group,
+		Version: v12.SchemeGroupVersion.Version,
+		Kind:    s.kind,
+	}
+}
+
+func (s *Server) V1beta1Review(ctx context.Context, ar *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	resp := s.V1Review(ctx, conversion.ConvertAdmissionRequestToV1(ar))
+	return conversion.ConvertAdmissionResponseToV1beta1(resp)
+}
+
+func (s *Server) V1Review(ctx context.Context, ar *v1.AdmissionRequest) *v1.AdmissionResponse {
+	if ar.Operation == v1.Delete {
+		return &v1.AdmissionResponse{Allowed: true}
+	}
+
+	issues, err := s.lint(ctx, s.hfclient, ar.Object.Raw)
+	if err != nil {
+		glog.Errorf("error deserializing hobbyfarm.io/%s: %s", s.kind, err.Error())
+		return response.RespDenied("could not cast new object into hobbyfarm.io/%s", s.kind)
+	}
+
+	errs := lint.Errors(issues)
+
+	// content stored before this webhook existed may already be invalid, updates are only denied for new errors
+	if ar.Operation == v1.Update && len(errs) > 0 {
+		oldIssues, err := s.lint(ctx, s.hfclient, ar.OldObject.Raw)
+		if err != nil {
+			glog.Errorf("error deserializing hobbyfarm.io/%s: %s", s.kind, err.Error())
+			return response.RespDenied("could not cast old object into hobbyfarm.io/%s", s.kind)
+		}
+		errs = newIssues(errs, lint.Errors(oldIssues))
+	}
+
+	if len(errs) > 0 {
+		return response.RespDenied("invalid: %s", lint.Message(errs))
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
+
+func newIssues(issues []lint.Issue, old []lint.Issue) []lint.Issue {
+	existing := map[lint.Issue]bool{}
+	for _, issue := range old {
+		existing[issue] = true
+	}
+
+	added := []lint.Issue{}
+	for _, issue := range issues {
+		if !existing[issue] {
+			added = append(added, issue)
+		}
+	}
+	return added
+}
@@ -0,0 +1,58 @@
+package revision
+
+import (
+	"context"
+
+	v12 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/conversion"
+	"github.com/hobbyfarm/gargantua/v3/pkg/webhook/validation/response"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Server keeps scenario and course revisions immutable, they may only be created and deleted
+type Server struct {
+	kind  string
+	types []runtime.Object
+}
+
+func NewScenarioRevision() *Server {
+	return &Server{
+		kind:  "scenariorevisions",
+		types: []runtime.Object{&v12.ScenarioRevision{}, &v12.ScenarioRevisionList{}},
+	}
+}
+
+func NewCourseRevision() *Server {
+	return &Server{
+		kind:  "courserevisions",
+		types: []runtime.Object{&v12.CourseRevision{}, &v12.CourseRevisionList{}},
+	}
+}
+
+func (s *Server) RegisterTypes() []runtime.Object {
+	return s.types
+}
+
+func (s *Server) GVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   v12.SchemeGroupVersion.Group,
+		Version: v12.SchemeGroupVersion.Version,
+		Kind:    s.kind,
+	}
+}
+
+func (s *Server) V1beta1Review(ctx context.Context, ar *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	resp := s.V1Review(ctx, conversion.ConvertAdmissionRequestToV1(ar))
+	return conversion.ConvertAdmissionResponseToV1beta1(resp)
+}
+
+func (s *Server) V1Review(ctx context.Context, ar *v1.AdmissionRequest) *v1.AdmissionResponse {
+	if ar.Operation == v1.Update {
+		return response.RespDenied("%s are immutable", s.kind)
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
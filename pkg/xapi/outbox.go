@@ -0,0 +1,84 @@
+package xapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	hfv2 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v2"
+	hfClientset "github.com/hobbyfarm/gargantua/v3/pkg/client/clientset/versioned"
+	"github.com/hobbyfarm/gargantua/v3/pkg/settingclient"
+	"github.com/hobbyfarm/gargantua/v3/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CredentialsSecretName is the secret holding the basic auth credentials of the learning record store
+	CredentialsSecretName = "hobbyfarm-xapi-lrs"
+	CredentialsUsername   = "username"
+	CredentialsPassword   = "password"
+
+	defaultActivityBase = "https://hobbyfarm.io/xapi"
+)
+
+// Endpoint returns the configured learning record store, xAPI is disabled if it is empty
+func Endpoint() string {
+	if set := settingclient.GetSetting(settingclient.XAPIEndpoint); set != nil {
+		return set.(string)
+	}
+	return ""
+}
+
+// ActivityBase returns the prefix of all activity ids
+func ActivityBase() string {
+	if set := settingclient.GetSetting(settingclient.XAPIActivityBase); set != nil && set.(string) != "" {
+		return set.(string)
+	}
+	return defaultActivityBase
+}
+
+// Record puts a statement about a progress into the outbox. It only writes a learning record, which the learning
+// record controller sends later on, so a slow learning record store never delays the caller.
+func Record(ctx context.Context, hfClientSet hfClientset.Interface, verb string, progress *hfv2.Progress) {
+	if Endpoint() == "" {
+		return
+	}
+
+	now := time.Now()
+
+	record := &hfv1.LearningRecord{}
+	record.Name = util.GenerateResourceName("lr", util.RandStringRunes(16), 16)
+	record.Labels = map[string]string{
+		util.UserLabel: progress.Spec.UserId,
+	}
+	record.Spec = hfv1.LearningRecordSpec{
+		Verb:           verb,
+		UserId:         progress.Spec.UserId,
+		Progress:       progress.Name,
+		Session:        progress.Labels[util.SessionLabel],
+		ScheduledEvent: progress.Labels[util.ScheduledEventLabel],
+		Scenario:       progress.Spec.Scenario,
+		Course:         progress.Spec.Course,
+		Step:           progress.Spec.CurrentStep + 1,
+		TotalSteps:     progress.Spec.TotalStep,
+		Score:          progress.Spec.Score,
+		MaxScore:       progress.Spec.MaxScore,
+		Timestamp:      now.Format(time.UnixDate),
+	}
+
+	// completion is about the furthest step, not the one currently shown
+	if verb == VerbCompleted || verb == VerbTerminated {
+		record.Spec.Step = progress.Spec.MaxStep + 1
+	}
+	if verb == VerbTerminated {
+		started, finished := progress.Spec.Started, progress.Spec.LastUpdate
+		if !started.IsZero() && finished.After(started.Time) {
+			record.Spec.Duration = finished.Sub(started.Time).String()
+		}
+	}
+
+	if _, err := hfClientSet.HobbyfarmV1().LearningRecords(util.GetReleaseNamespace()).Create(ctx, record, metav1.CreateOptions{}); err != nil {
+		glog.Errorf("error recording xapi statement %s for progress %s: %v", verb, progress.Name, err)
+	}
+}
@@ -0,0 +1,275 @@
+package xapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+)
+
+const (
+	VerbLaunched   = "launched"
+	VerbProgressed = "progressed"
+	VerbCompleted  = "completed"
+	VerbTerminated = "terminated"
+
+	// Version is the xAPI version statements are sent with
+	Version = "1.0.3"
+
+	// ProgressExtension holds the percentage of steps reached, as defined by cmi5
+	ProgressExtension = "https://w3id.org/xapi/cmi5/result/extensions/progress"
+
+	activityTypeScenario       = "http://adlnet.gov/expapi/activities/lesson"
+	activityTypeCourse         = "http://adlnet.gov/expapi/activities/course"
+	activityTypeScheduledEvent = "http://adlnet.gov/expapi/activities/meeting"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var verbs = map[string]Verb{
+	VerbLaunched:   {ID: "http://adlnet.gov/expapi/verbs/launched", Display: map[string]string{"en-US": "launched"}},
+	VerbProgressed: {ID: "http://adlnet.gov/expapi/verbs/progressed", Display: map[string]string{"en-US": "progressed"}},
+	VerbCompleted:  {ID: "http://adlnet.gov/expapi/verbs/completed", Display: map[string]string{"en-US": "completed"}},
+	VerbTerminated: {ID: "http://adlnet.gov/expapi/verbs/terminated", Display: map[string]string{"en-US": "terminated"}},
+}
+
+type Statement struct {
+	ID        string   `json:"id"`
+	Actor     Agent    `json:"actor"`
+	Verb      Verb     `json:"verb"`
+	Object    Activity `json:"object"`
+	Result    *Result  `json:"result,omitempty"`
+	Context   *Context `json:"context,omitempty"`
+	Timestamp string   `json:"timestamp,omitempty"`
+}
+
+// Agent identifies a learner either by email or by an account of this installation
+type Agent struct {
+	ObjectType string   `json:"objectType"`
+	Name       string   `json:"name,omitempty"`
+	Mbox       string   `json:"mbox,omitempty"`
+	Account    *Account `json:"account,omitempty"`
+}
+
+type Account struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+type Verb struct {
+	ID      string            `json:"id"`
+	Display map[string]string `json:"display"`
+}
+
+type Activity struct {
+	ObjectType string      `json:"objectType"`
+	ID         string      `json:"id"`
+	Definition *Definition `json:"definition,omitempty"`
+}
+
+type Definition struct {
+	Name map[string]string `json:"name,omitempty"`
+	Type string            `json:"type,omitempty"`
+}
+
+type Result struct {
+	Score      *Score         `json:"score,omitempty"`
+	Completion *bool          `json:"completion,omitempty"`
+	Duration   string         `json:"duration,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+type Score struct {
+	Scaled float64 `json:"scaled"`
+	Raw    int     `json:"raw"`
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+}
+
+type Context struct {
+	ContextActivities *ContextActivities `json:"contextActivities,omitempty"`
+	Extensions        map[string]any     `json:"extensions,omitempty"`
+}
+
+type ContextActivities struct {
+	Parent   []Activity `json:"parent,omitempty"`
+	Grouping []Activity `json:"grouping,omitempty"`
+}
+
+// NewAgent returns the actor of statements about a user. Users are identified by email, the user id is the fallback.
+func NewAgent(activityBase string, userId string, email string) Agent {
+	if email != "" {
+		return Agent{ObjectType: "Agent", Name: email, Mbox: "mailto:" + email}
+	}
+	return Agent{ObjectType: "Agent", Account: &Account{HomePage: activityBase, Name: userId}}
+}
+
+// NewStatement turns a learning record into a statement. The statement id is the uid of the record,
+// so a statement that is sent again after a failed attempt is not stored twice.
+func NewStatement(record *hfv1.LearningRecord, actor Agent, activityBase string, scenarioName string) (Statement, error) {
+	verb, ok := verbs[record.Spec.Verb]
+	if !ok {
+		return Statement{}, fmt.Errorf("unknown verb %s", record.Spec.Verb)
+	}
+
+	activityBase = strings.TrimSuffix(activityBase, "/")
+	statement := Statement{
+		ID:     string(record.UID),
+		Actor:  actor,
+		Verb:   verb,
+		Object: activity(activityBase, "scenarios", record.Spec.Scenario, activityTypeScenario, scenarioName),
+	}
+
+	if timestamp, err := time.Parse(time.UnixDate, record.Spec.Timestamp); err == nil {
+		statement.Timestamp = timestamp.Format(time.RFC3339)
+	}
+
+	contextActivities := &ContextActivities{}
+	if record.Spec.Course != "" {
+		contextActivities.Parent = []Activity{activity(activityBase, "courses", record.Spec.Course, activityTypeCourse, "")}
+	}
+	if record.Spec.ScheduledEvent != "" {
+		contextActivities.Grouping = []Activity{activity(activityBase, "scheduledevents", record.Spec.ScheduledEvent, activityTypeScheduledEvent, "")}
+	}
+	statement.Context = &Context{
+		ContextActivities: contextActivities,
+		Extensions: map[string]any{
+			activityBase + "/extensions/session":  record.Spec.Session,
+			activityBase + "/extensions/progress": record.Spec.Progress,
+		},
+	}
+
+	completed := record.Spec.TotalSteps > 0 && record.Spec.Step >= record.Spec.TotalSteps
+	switch record.Spec.Verb {
+	case VerbProgressed:
+		statement.Result = &Result{Extensions: map[string]any{
+			ProgressExtension:                 percent(record.Spec.Step, record.Spec.TotalSteps),
+			activityBase + "/extensions/step": record.Spec.Step,
+		}}
+	case VerbCompleted:
+		statement.Result = &Result{Completion: &completed, Score: score(record.Spec)}
+	case VerbTerminated:
+		statement.Result = &Result{Completion: &completed, Score: score(record.Spec)}
+		if duration, err := time.ParseDuration(record.Spec.Duration); err == nil {
+			statement.Result.Duration = isoDuration(duration)
+		}
+	}
+
+	return statement, nil
+}
+
+// StatusError is returned if the learning record store did not accept a statement
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("learning record store responded with %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable returns false if the learning record store rejected a statement, sending it again would fail as well
+func Retryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode == http.StatusRequestTimeout || statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+}
+
+// Client sends statements to the statements resource of a learning record store
+type Client struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+func NewClient(endpoint string, username string, password string) *Client {
+	return &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Send stores a statement under its id. A conflict means the store already holds the statement.
+func (c *Client) Send(ctx context.Context, statement Statement) error {
+	body, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+
+	target := fmt.Sprintf("%s/statements?statementId=%s", c.endpoint, url.QueryEscape(statement.ID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Experience-API-Version", Version)
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 || resp.StatusCode == http.StatusConflict {
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(message))}
+}
+
+func activity(activityBase string, kind string, id string, activityType string, name string) Activity {
+	a := Activity{
+		ObjectType: "Activity",
+		ID:         fmt.Sprintf("%s/%s/%s", activityBase, kind, url.PathEscape(id)),
+		Definition: &Definition{Type: activityType},
+	}
+	if name != "" {
+		a.Definition.Name = map[string]string{"en-US": name}
+	}
+	return a
+}
+
+func score(spec hfv1.LearningRecordSpec) *Score {
+	if spec.MaxScore <= 0 {
+		return nil
+	}
+	return &Score{
+		Scaled: float64(spec.Score) / float64(spec.MaxScore),
+		Raw:    spec.Score,
+		Min:    0,
+		Max:    spec.MaxScore,
+	}
+}
+
+func percent(step int, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return min(100, step*100/total)
+}
+
+// isoDuration formats a duration the way xAPI expects it, e.g. PT1H2M3S
+func isoDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+	return fmt.Sprintf("PT%dH%dM%dS", hours, minutes, seconds)
+}
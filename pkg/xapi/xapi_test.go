@@ -0,0 +1,161 @@
+package xapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hfv1 "github.com/hobbyfarm/gargantua/v3/pkg/apis/hobbyfarm.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testRecord(verb string) *hfv1.LearningRecord {
+	return &hfv1.LearningRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "lr-test", UID: "3f2b7c1e-8a4d-4f6b-9c2e-1d5a7b9e0f12"},
+		Spec: hfv1.LearningRecordSpec{
+			Verb:           verb,
+			UserId:         "u-1",
+			Progress:       "progress-1",
+			Session:        "ss-1",
+			ScheduledEvent: "se-1",
+			Scenario:       "sc-1",
+			Course:         "c-1",
+			Step:           4,
+			TotalSteps:     4,
+			Score:          3,
+			MaxScore:       4,
+			Duration:       "1h2m3s",
+			Timestamp:      "Mon Jan  2 15:04:05 UTC 2006",
+		},
+	}
+}
+
+// lrs is a stand-in for a learning record store that stores statements by id
+type lrs struct {
+	status     int
+	statements map[string]Statement
+	requests   int
+}
+
+func (l *lrs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.requests++
+	if username, password, ok := r.BasicAuth(); !ok || username != "key" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPut || r.URL.Path != "/xapi/statements" || r.Header.Get("X-Experience-API-Version") != Version {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if l.status != 0 {
+		w.WriteHeader(l.status)
+		return
+	}
+
+	statement := Statement{}
+	if err := json.NewDecoder(r.Body).Decode(&statement); err != nil || statement.ID != r.URL.Query().Get("statementId") {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if _, ok := l.statements[statement.ID]; ok {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	l.statements[statement.ID] = statement
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func Test_Send(t *testing.T) {
+	store := &lrs{statements: map[string]Statement{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	record := testRecord(VerbCompleted)
+	statement, err := NewStatement(record, NewAgent("https://example.com/xapi", "u-1", "user@example.com"), "https://example.com/xapi/", "Intro")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(server.URL+"/xapi/", "key", "secret")
+	if err = client.Send(context.Background(), statement); err != nil {
+		t.Fatalf("error sending statement: %v", err)
+	}
+	// sending again after an attempt that seemed to fail must not fail or store the statement twice
+	if err = client.Send(context.Background(), statement); err != nil {
+		t.Fatalf("error sending statement again: %v", err)
+	}
+	if len(store.statements) != 1 {
+		t.Fatalf("expected 1 stored statement, got %d", len(store.statements))
+	}
+
+	stored := store.statements[string(record.UID)]
+	if stored.Verb.ID != "http://adlnet.gov/expapi/verbs/completed" {
+		t.Errorf("unexpected verb %s", stored.Verb.ID)
+	}
+	if stored.Actor.Mbox != "mailto:user@example.com" {
+		t.Errorf("unexpected actor %+v", stored.Actor)
+	}
+	if stored.Object.ID != "https://example.com/xapi/scenarios/sc-1" || stored.Object.Definition.Name["en-US"] != "Intro" {
+		t.Errorf("unexpected object %+v", stored.Object)
+	}
+	if parent := stored.Context.ContextActivities.Parent; len(parent) != 1 || parent[0].ID != "https://example.com/xapi/courses/c-1" {
+		t.Errorf("unexpected parent %+v", parent)
+	}
+	if stored.Result == nil || stored.Result.Completion == nil || !*stored.Result.Completion || stored.Result.Score.Scaled != 0.75 {
+		t.Errorf("unexpected result %+v", stored.Result)
+	}
+	if stored.Timestamp != "2006-01-02T15:04:05Z" {
+		t.Errorf("unexpected timestamp %s", stored.Timestamp)
+	}
+}
+
+func Test_SendErrors(t *testing.T) {
+	store := &lrs{statements: map[string]Statement{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	statement, err := NewStatement(testRecord(VerbTerminated), NewAgent("https://example.com/xapi", "u-1", ""), "https://example.com/xapi", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Actor.Account == nil || statement.Actor.Account.Name != "u-1" {
+		t.Errorf("expected an account actor, got %+v", statement.Actor)
+	}
+	if statement.Result.Duration != "PT1H2M3S" {
+		t.Errorf("unexpected duration %s", statement.Result.Duration)
+	}
+
+	tests := []struct {
+		name      string
+		client    *Client
+		status    int
+		retryable bool
+	}{
+		{"unauthorized", NewClient(server.URL+"/xapi", "key", "wrong"), 0, false},
+		{"unavailable", NewClient(server.URL+"/xapi", "key", "secret"), http.StatusServiceUnavailable, true},
+		{"throttled", NewClient(server.URL+"/xapi", "key", "secret"), http.StatusTooManyRequests, true},
+		{"unreachable", NewClient("http://127.0.0.1:1/xapi", "key", "secret"), 0, true},
+	}
+	for _, test := range tests {
+		store.status = test.status
+		err := test.client.Send(context.Background(), statement)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		if Retryable(err) != test.retryable {
+			t.Errorf("%s: expected retryable %v for %v", test.name, test.retryable, err)
+		}
+	}
+	if len(store.statements) != 0 {
+		t.Errorf("expected no stored statement, got %d", len(store.statements))
+	}
+}
+
+func Test_NewStatementUnknownVerb(t *testing.T) {
+	if _, err := NewStatement(testRecord("attempted"), Agent{}, "https://example.com/xapi", ""); err == nil {
+		t.Error("expected an error for an unknown verb")
+	}
+}